@@ -0,0 +1,1193 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.2
+// 	protoc        (unknown)
+// source: pdf/v1/pdf.proto
+
+package pdfv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CompressRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Data:
+	//
+	//	*CompressRequest_Options
+	//	*CompressRequest_Chunk
+	Data isCompressRequest_Data `protobuf_oneof:"data"`
+}
+
+func (x *CompressRequest) Reset() {
+	*x = CompressRequest{}
+	mi := &file_pdf_v1_pdf_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompressRequest) ProtoMessage() {}
+
+func (x *CompressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_v1_pdf_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompressRequest.ProtoReflect.Descriptor instead.
+func (*CompressRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_v1_pdf_proto_rawDescGZIP(), []int{0}
+}
+
+func (m *CompressRequest) GetData() isCompressRequest_Data {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (x *CompressRequest) GetOptions() *CompressOptions {
+	if x, ok := x.GetData().(*CompressRequest_Options); ok {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *CompressRequest) GetChunk() []byte {
+	if x, ok := x.GetData().(*CompressRequest_Chunk); ok {
+		return x.Chunk
+	}
+	return nil
+}
+
+type isCompressRequest_Data interface {
+	isCompressRequest_Data()
+}
+
+type CompressRequest_Options struct {
+	Options *CompressOptions `protobuf:"bytes,1,opt,name=options,proto3,oneof"`
+}
+
+type CompressRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*CompressRequest_Options) isCompressRequest_Data() {}
+
+func (*CompressRequest_Chunk) isCompressRequest_Data() {}
+
+type CompressOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// file_name names the result, e.g. "report.pdf" gives "compressed_report.pdf"
+	FileName string `protobuf:"bytes,1,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+}
+
+func (x *CompressOptions) Reset() {
+	*x = CompressOptions{}
+	mi := &file_pdf_v1_pdf_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompressOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompressOptions) ProtoMessage() {}
+
+func (x *CompressOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_v1_pdf_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompressOptions.ProtoReflect.Descriptor instead.
+func (*CompressOptions) Descriptor() ([]byte, []int) {
+	return file_pdf_v1_pdf_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CompressOptions) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+type SplitRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Data:
+	//
+	//	*SplitRequest_Options
+	//	*SplitRequest_Chunk
+	Data isSplitRequest_Data `protobuf_oneof:"data"`
+}
+
+func (x *SplitRequest) Reset() {
+	*x = SplitRequest{}
+	mi := &file_pdf_v1_pdf_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SplitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SplitRequest) ProtoMessage() {}
+
+func (x *SplitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_v1_pdf_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SplitRequest.ProtoReflect.Descriptor instead.
+func (*SplitRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_v1_pdf_proto_rawDescGZIP(), []int{2}
+}
+
+func (m *SplitRequest) GetData() isSplitRequest_Data {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (x *SplitRequest) GetOptions() *SplitOptions {
+	if x, ok := x.GetData().(*SplitRequest_Options); ok {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *SplitRequest) GetChunk() []byte {
+	if x, ok := x.GetData().(*SplitRequest_Chunk); ok {
+		return x.Chunk
+	}
+	return nil
+}
+
+type isSplitRequest_Data interface {
+	isSplitRequest_Data()
+}
+
+type SplitRequest_Options struct {
+	Options *SplitOptions `protobuf:"bytes,1,opt,name=options,proto3,oneof"`
+}
+
+type SplitRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*SplitRequest_Options) isSplitRequest_Data() {}
+
+func (*SplitRequest_Chunk) isSplitRequest_Data() {}
+
+type SplitOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FileName string `protobuf:"bytes,1,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	// Types that are assignable to Mode:
+	//
+	//	*SplitOptions_Ranges
+	//	*SplitOptions_FixedRange
+	//	*SplitOptions_RemovePages
+	//	*SplitOptions_NamedMode
+	Mode isSplitOptions_Mode `protobuf_oneof:"mode"`
+	// part_name names the parts in the zip of a fixed_range split. It may use {base},
+	// {index}, {from}, {to} and {bookmark}, numbers may be padded as in {index:3}.
+	PartName string `protobuf:"bytes,5,opt,name=part_name,json=partName,proto3" json:"part_name,omitempty"`
+	// zip_name names the zip of a fixed_range split, it may use {base}
+	ZipName string `protobuf:"bytes,6,opt,name=zip_name,json=zipName,proto3" json:"zip_name,omitempty"`
+	// params holds the parameters of a named_mode by the name of the HTTP API
+	Params map[string]string `protobuf:"bytes,8,rep,name=params,proto3" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *SplitOptions) Reset() {
+	*x = SplitOptions{}
+	mi := &file_pdf_v1_pdf_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SplitOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SplitOptions) ProtoMessage() {}
+
+func (x *SplitOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_v1_pdf_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SplitOptions.ProtoReflect.Descriptor instead.
+func (*SplitOptions) Descriptor() ([]byte, []int) {
+	return file_pdf_v1_pdf_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SplitOptions) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (m *SplitOptions) GetMode() isSplitOptions_Mode {
+	if m != nil {
+		return m.Mode
+	}
+	return nil
+}
+
+func (x *SplitOptions) GetRanges() string {
+	if x, ok := x.GetMode().(*SplitOptions_Ranges); ok {
+		return x.Ranges
+	}
+	return ""
+}
+
+func (x *SplitOptions) GetFixedRange() int32 {
+	if x, ok := x.GetMode().(*SplitOptions_FixedRange); ok {
+		return x.FixedRange
+	}
+	return 0
+}
+
+func (x *SplitOptions) GetRemovePages() string {
+	if x, ok := x.GetMode().(*SplitOptions_RemovePages); ok {
+		return x.RemovePages
+	}
+	return ""
+}
+
+func (x *SplitOptions) GetNamedMode() string {
+	if x, ok := x.GetMode().(*SplitOptions_NamedMode); ok {
+		return x.NamedMode
+	}
+	return ""
+}
+
+func (x *SplitOptions) GetPartName() string {
+	if x != nil {
+		return x.PartName
+	}
+	return ""
+}
+
+func (x *SplitOptions) GetZipName() string {
+	if x != nil {
+		return x.ZipName
+	}
+	return ""
+}
+
+func (x *SplitOptions) GetParams() map[string]string {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+type isSplitOptions_Mode interface {
+	isSplitOptions_Mode()
+}
+
+type SplitOptions_Ranges struct {
+	// ranges lists the pages to keep, e.g. "1-3,5"
+	Ranges string `protobuf:"bytes,2,opt,name=ranges,proto3,oneof"`
+}
+
+type SplitOptions_FixedRange struct {
+	// fixed_range is the number of pages per part
+	FixedRange int32 `protobuf:"varint,3,opt,name=fixed_range,json=fixedRange,proto3,oneof"`
+}
+
+type SplitOptions_RemovePages struct {
+	// remove_pages lists the pages to drop, e.g. "2,4-6"
+	RemovePages string `protobuf:"bytes,4,opt,name=remove_pages,json=removePages,proto3,oneof"`
+}
+
+type SplitOptions_NamedMode struct {
+	// named_mode picks any split mode of the operation registry, its parameters go
+	// in params
+	NamedMode string `protobuf:"bytes,7,opt,name=named_mode,json=namedMode,proto3,oneof"`
+}
+
+func (*SplitOptions_Ranges) isSplitOptions_Mode() {}
+
+func (*SplitOptions_FixedRange) isSplitOptions_Mode() {}
+
+func (*SplitOptions_RemovePages) isSplitOptions_Mode() {}
+
+func (*SplitOptions_NamedMode) isSplitOptions_Mode() {}
+
+type RemovePagesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Data:
+	//
+	//	*RemovePagesRequest_Options
+	//	*RemovePagesRequest_Chunk
+	Data isRemovePagesRequest_Data `protobuf_oneof:"data"`
+}
+
+func (x *RemovePagesRequest) Reset() {
+	*x = RemovePagesRequest{}
+	mi := &file_pdf_v1_pdf_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePagesRequest) ProtoMessage() {}
+
+func (x *RemovePagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_v1_pdf_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePagesRequest.ProtoReflect.Descriptor instead.
+func (*RemovePagesRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_v1_pdf_proto_rawDescGZIP(), []int{4}
+}
+
+func (m *RemovePagesRequest) GetData() isRemovePagesRequest_Data {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (x *RemovePagesRequest) GetOptions() *RemovePagesOptions {
+	if x, ok := x.GetData().(*RemovePagesRequest_Options); ok {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *RemovePagesRequest) GetChunk() []byte {
+	if x, ok := x.GetData().(*RemovePagesRequest_Chunk); ok {
+		return x.Chunk
+	}
+	return nil
+}
+
+type isRemovePagesRequest_Data interface {
+	isRemovePagesRequest_Data()
+}
+
+type RemovePagesRequest_Options struct {
+	Options *RemovePagesOptions `protobuf:"bytes,1,opt,name=options,proto3,oneof"`
+}
+
+type RemovePagesRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*RemovePagesRequest_Options) isRemovePagesRequest_Data() {}
+
+func (*RemovePagesRequest_Chunk) isRemovePagesRequest_Data() {}
+
+type RemovePagesOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FileName string `protobuf:"bytes,1,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	// pages lists the pages to drop, e.g. "2,4-6"
+	Pages string `protobuf:"bytes,2,opt,name=pages,proto3" json:"pages,omitempty"`
+}
+
+func (x *RemovePagesOptions) Reset() {
+	*x = RemovePagesOptions{}
+	mi := &file_pdf_v1_pdf_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePagesOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePagesOptions) ProtoMessage() {}
+
+func (x *RemovePagesOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_v1_pdf_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePagesOptions.ProtoReflect.Descriptor instead.
+func (*RemovePagesOptions) Descriptor() ([]byte, []int) {
+	return file_pdf_v1_pdf_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RemovePagesOptions) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *RemovePagesOptions) GetPages() string {
+	if x != nil {
+		return x.Pages
+	}
+	return ""
+}
+
+type ProcessRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Data:
+	//
+	//	*ProcessRequest_Options
+	//	*ProcessRequest_Chunk
+	Data isProcessRequest_Data `protobuf_oneof:"data"`
+}
+
+func (x *ProcessRequest) Reset() {
+	*x = ProcessRequest{}
+	mi := &file_pdf_v1_pdf_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessRequest) ProtoMessage() {}
+
+func (x *ProcessRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_v1_pdf_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessRequest.ProtoReflect.Descriptor instead.
+func (*ProcessRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_v1_pdf_proto_rawDescGZIP(), []int{6}
+}
+
+func (m *ProcessRequest) GetData() isProcessRequest_Data {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (x *ProcessRequest) GetOptions() *ProcessOptions {
+	if x, ok := x.GetData().(*ProcessRequest_Options); ok {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *ProcessRequest) GetChunk() []byte {
+	if x, ok := x.GetData().(*ProcessRequest_Chunk); ok {
+		return x.Chunk
+	}
+	return nil
+}
+
+type isProcessRequest_Data interface {
+	isProcessRequest_Data()
+}
+
+type ProcessRequest_Options struct {
+	Options *ProcessOptions `protobuf:"bytes,1,opt,name=options,proto3,oneof"`
+}
+
+type ProcessRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*ProcessRequest_Options) isProcessRequest_Data() {}
+
+func (*ProcessRequest_Chunk) isProcessRequest_Data() {}
+
+type ProcessOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FileName string `protobuf:"bytes,1,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	// operation names the processor, e.g. "compress"
+	Operation string `protobuf:"bytes,2,opt,name=operation,proto3" json:"operation,omitempty"`
+	// params holds the parameters of the operation by the name of the HTTP API
+	Params map[string]string `protobuf:"bytes,3,rep,name=params,proto3" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *ProcessOptions) Reset() {
+	*x = ProcessOptions{}
+	mi := &file_pdf_v1_pdf_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessOptions) ProtoMessage() {}
+
+func (x *ProcessOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_v1_pdf_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessOptions.ProtoReflect.Descriptor instead.
+func (*ProcessOptions) Descriptor() ([]byte, []int) {
+	return file_pdf_v1_pdf_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ProcessOptions) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *ProcessOptions) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *ProcessOptions) GetParams() map[string]string {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+type PageCountRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Data:
+	//
+	//	*PageCountRequest_Options
+	//	*PageCountRequest_Chunk
+	Data isPageCountRequest_Data `protobuf_oneof:"data"`
+}
+
+func (x *PageCountRequest) Reset() {
+	*x = PageCountRequest{}
+	mi := &file_pdf_v1_pdf_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PageCountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PageCountRequest) ProtoMessage() {}
+
+func (x *PageCountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_v1_pdf_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PageCountRequest.ProtoReflect.Descriptor instead.
+func (*PageCountRequest) Descriptor() ([]byte, []int) {
+	return file_pdf_v1_pdf_proto_rawDescGZIP(), []int{8}
+}
+
+func (m *PageCountRequest) GetData() isPageCountRequest_Data {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (x *PageCountRequest) GetOptions() *PageCountOptions {
+	if x, ok := x.GetData().(*PageCountRequest_Options); ok {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *PageCountRequest) GetChunk() []byte {
+	if x, ok := x.GetData().(*PageCountRequest_Chunk); ok {
+		return x.Chunk
+	}
+	return nil
+}
+
+type isPageCountRequest_Data interface {
+	isPageCountRequest_Data()
+}
+
+type PageCountRequest_Options struct {
+	Options *PageCountOptions `protobuf:"bytes,1,opt,name=options,proto3,oneof"`
+}
+
+type PageCountRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*PageCountRequest_Options) isPageCountRequest_Data() {}
+
+func (*PageCountRequest_Chunk) isPageCountRequest_Data() {}
+
+type PageCountOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *PageCountOptions) Reset() {
+	*x = PageCountOptions{}
+	mi := &file_pdf_v1_pdf_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PageCountOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PageCountOptions) ProtoMessage() {}
+
+func (x *PageCountOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_v1_pdf_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PageCountOptions.ProtoReflect.Descriptor instead.
+func (*PageCountOptions) Descriptor() ([]byte, []int) {
+	return file_pdf_v1_pdf_proto_rawDescGZIP(), []int{9}
+}
+
+type PageCountResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pages int32 `protobuf:"varint,1,opt,name=pages,proto3" json:"pages,omitempty"`
+}
+
+func (x *PageCountResponse) Reset() {
+	*x = PageCountResponse{}
+	mi := &file_pdf_v1_pdf_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PageCountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PageCountResponse) ProtoMessage() {}
+
+func (x *PageCountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_v1_pdf_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PageCountResponse.ProtoReflect.Descriptor instead.
+func (*PageCountResponse) Descriptor() ([]byte, []int) {
+	return file_pdf_v1_pdf_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PageCountResponse) GetPages() int32 {
+	if x != nil {
+		return x.Pages
+	}
+	return 0
+}
+
+type FileChunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Data:
+	//
+	//	*FileChunk_Info
+	//	*FileChunk_Chunk
+	Data isFileChunk_Data `protobuf_oneof:"data"`
+}
+
+func (x *FileChunk) Reset() {
+	*x = FileChunk{}
+	mi := &file_pdf_v1_pdf_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileChunk) ProtoMessage() {}
+
+func (x *FileChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_v1_pdf_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileChunk.ProtoReflect.Descriptor instead.
+func (*FileChunk) Descriptor() ([]byte, []int) {
+	return file_pdf_v1_pdf_proto_rawDescGZIP(), []int{11}
+}
+
+func (m *FileChunk) GetData() isFileChunk_Data {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (x *FileChunk) GetInfo() *FileInfo {
+	if x, ok := x.GetData().(*FileChunk_Info); ok {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *FileChunk) GetChunk() []byte {
+	if x, ok := x.GetData().(*FileChunk_Chunk); ok {
+		return x.Chunk
+	}
+	return nil
+}
+
+type isFileChunk_Data interface {
+	isFileChunk_Data()
+}
+
+type FileChunk_Info struct {
+	Info *FileInfo `protobuf:"bytes,1,opt,name=info,proto3,oneof"`
+}
+
+type FileChunk_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*FileChunk_Info) isFileChunk_Data() {}
+
+func (*FileChunk_Chunk) isFileChunk_Data() {}
+
+type FileInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FileName string `protobuf:"bytes,1,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	// content_type is application/pdf or application/zip
+	ContentType string `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	// size is -1 when it is not known up front
+	Size int64 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	// cache_status is HIT or MISS when the result cache is enabled
+	CacheStatus string `protobuf:"bytes,4,opt,name=cache_status,json=cacheStatus,proto3" json:"cache_status,omitempty"`
+}
+
+func (x *FileInfo) Reset() {
+	*x = FileInfo{}
+	mi := &file_pdf_v1_pdf_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileInfo) ProtoMessage() {}
+
+func (x *FileInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_pdf_v1_pdf_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileInfo.ProtoReflect.Descriptor instead.
+func (*FileInfo) Descriptor() ([]byte, []int) {
+	return file_pdf_v1_pdf_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FileInfo) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *FileInfo) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *FileInfo) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FileInfo) GetCacheStatus() string {
+	if x != nil {
+		return x.CacheStatus
+	}
+	return ""
+}
+
+var File_pdf_v1_pdf_proto protoreflect.FileDescriptor
+
+var file_pdf_v1_pdf_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x70, 0x64, 0x66, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x64, 0x66, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x06, 0x70, 0x64, 0x66, 0x2e, 0x76, 0x31, 0x22, 0x66, 0x0a, 0x0f, 0x43, 0x6f,
+	0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x33, 0x0a,
+	0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x70, 0x64, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x48, 0x00, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x16, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x48, 0x00, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x42, 0x06, 0x0a, 0x04, 0x64, 0x61,
+	0x74, 0x61, 0x22, 0x2e, 0x0a, 0x0f, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x4f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x4e, 0x61,
+	0x6d, 0x65, 0x22, 0x60, 0x0a, 0x0c, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x30, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x64, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x6c,
+	0x69, 0x74, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x48, 0x00, 0x52, 0x07, 0x6f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x12, 0x16, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x42, 0x06, 0x0a, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x22, 0xe3, 0x02, 0x0a, 0x0c, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x4f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x18, 0x0a, 0x06, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x00, 0x52, 0x06, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0b,
+	0x66, 0x69, 0x78, 0x65, 0x64, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x48, 0x00, 0x52, 0x0a, 0x66, 0x69, 0x78, 0x65, 0x64, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12,
+	0x23, 0x0a, 0x0c, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x73, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0b, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x50,
+	0x61, 0x67, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0a, 0x6e, 0x61, 0x6d, 0x65, 0x64, 0x5f, 0x6d, 0x6f,
+	0x64, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65,
+	0x64, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x72, 0x74, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x72, 0x74, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x7a, 0x69, 0x70, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x7a, 0x69, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x38, 0x0a,
+	0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e,
+	0x70, 0x64, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x6c, 0x69, 0x74, 0x4f, 0x70, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x50, 0x61, 0x72, 0x61, 0x6d,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
+	0x38, 0x01, 0x42, 0x06, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x22, 0x6c, 0x0a, 0x12, 0x52, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x50, 0x61, 0x67, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x36, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x64, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76,
+	0x65, 0x50, 0x61, 0x67, 0x65, 0x73, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x48, 0x00, 0x52,
+	0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x16, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e,
+	0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b,
+	0x42, 0x06, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x47, 0x0a, 0x12, 0x52, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x50, 0x61, 0x67, 0x65, 0x73, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1b,
+	0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70,
+	0x61, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x70, 0x61, 0x67, 0x65,
+	0x73, 0x22, 0x64, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x32, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x64, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72,
+	0x6f, 0x63, 0x65, 0x73, 0x73, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x48, 0x00, 0x52, 0x07,
+	0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x16, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x42,
+	0x06, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0xc2, 0x01, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x63,
+	0x65, 0x73, 0x73, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x69,
+	0x6c, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66,
+	0x69, 0x6c, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6f, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3a, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x70, 0x64, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x50, 0x61,
+	0x72, 0x61, 0x6d, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d,
+	0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x68, 0x0a, 0x10,
+	0x50, 0x61, 0x67, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x34, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x70, 0x64, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x67, 0x65, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x48, 0x00, 0x52, 0x07, 0x6f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x16, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x42, 0x06,
+	0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x12, 0x0a, 0x10, 0x50, 0x61, 0x67, 0x65, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x29, 0x0a, 0x11, 0x50, 0x61,
+	0x67, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x70, 0x61, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05,
+	0x70, 0x61, 0x67, 0x65, 0x73, 0x22, 0x53, 0x0a, 0x09, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x75,
+	0x6e, 0x6b, 0x12, 0x26, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x10, 0x2e, 0x70, 0x64, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x6e,
+	0x66, 0x6f, 0x48, 0x00, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x16, 0x0a, 0x05, 0x63, 0x68,
+	0x75, 0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x05, 0x63, 0x68, 0x75,
+	0x6e, 0x6b, 0x42, 0x06, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x81, 0x01, 0x0a, 0x08, 0x46,
+	0x69, 0x6c, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65,
+	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x5f,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x63,
+	0x61, 0x63, 0x68, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x63, 0x61, 0x63, 0x68, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x32, 0xbe,
+	0x02, 0x0a, 0x0a, 0x50, 0x64, 0x66, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3a, 0x0a,
+	0x08, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x12, 0x17, 0x2e, 0x70, 0x64, 0x66, 0x2e,
+	0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x11, 0x2e, 0x70, 0x64, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65,
+	0x43, 0x68, 0x75, 0x6e, 0x6b, 0x28, 0x01, 0x30, 0x01, 0x12, 0x34, 0x0a, 0x05, 0x53, 0x70, 0x6c,
+	0x69, 0x74, 0x12, 0x14, 0x2e, 0x70, 0x64, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x6c, 0x69,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x70, 0x64, 0x66, 0x2e, 0x76,
+	0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x28, 0x01, 0x30, 0x01, 0x12,
+	0x40, 0x0a, 0x0b, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x50, 0x61, 0x67, 0x65, 0x73, 0x12, 0x1a,
+	0x2e, 0x70, 0x64, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x50, 0x61,
+	0x67, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x70, 0x64, 0x66,
+	0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x28, 0x01, 0x30,
+	0x01, 0x12, 0x42, 0x0a, 0x09, 0x50, 0x61, 0x67, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x18,
+	0x2e, 0x70, 0x64, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x67, 0x65, 0x43, 0x6f, 0x75, 0x6e,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x64, 0x66, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x61, 0x67, 0x65, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x28, 0x01, 0x12, 0x38, 0x0a, 0x07, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73,
+	0x12, 0x16, 0x2e, 0x70, 0x64, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x70, 0x64, 0x66, 0x2e, 0x76,
+	0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x28, 0x01, 0x30, 0x01, 0x42,
+	0x33, 0x5a, 0x31, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x78,
+	0x63, 0x6f, 0x64, 0x65, 0x63, 0x2f, 0x67, 0x6f, 0x2d, 0x63, 0x6c, 0x65, 0x61, 0x6e, 0x2d, 0x61,
+	0x72, 0x63, 0x68, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x64, 0x66, 0x2f, 0x76, 0x31, 0x3b, 0x70,
+	0x64, 0x66, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pdf_v1_pdf_proto_rawDescOnce sync.Once
+	file_pdf_v1_pdf_proto_rawDescData = file_pdf_v1_pdf_proto_rawDesc
+)
+
+func file_pdf_v1_pdf_proto_rawDescGZIP() []byte {
+	file_pdf_v1_pdf_proto_rawDescOnce.Do(func() {
+		file_pdf_v1_pdf_proto_rawDescData = protoimpl.X.CompressGZIP(file_pdf_v1_pdf_proto_rawDescData)
+	})
+	return file_pdf_v1_pdf_proto_rawDescData
+}
+
+var file_pdf_v1_pdf_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_pdf_v1_pdf_proto_goTypes = []any{
+	(*CompressRequest)(nil),    // 0: pdf.v1.CompressRequest
+	(*CompressOptions)(nil),    // 1: pdf.v1.CompressOptions
+	(*SplitRequest)(nil),       // 2: pdf.v1.SplitRequest
+	(*SplitOptions)(nil),       // 3: pdf.v1.SplitOptions
+	(*RemovePagesRequest)(nil), // 4: pdf.v1.RemovePagesRequest
+	(*RemovePagesOptions)(nil), // 5: pdf.v1.RemovePagesOptions
+	(*ProcessRequest)(nil),     // 6: pdf.v1.ProcessRequest
+	(*ProcessOptions)(nil),     // 7: pdf.v1.ProcessOptions
+	(*PageCountRequest)(nil),   // 8: pdf.v1.PageCountRequest
+	(*PageCountOptions)(nil),   // 9: pdf.v1.PageCountOptions
+	(*PageCountResponse)(nil),  // 10: pdf.v1.PageCountResponse
+	(*FileChunk)(nil),          // 11: pdf.v1.FileChunk
+	(*FileInfo)(nil),           // 12: pdf.v1.FileInfo
+	nil,                        // 13: pdf.v1.SplitOptions.ParamsEntry
+	nil,                        // 14: pdf.v1.ProcessOptions.ParamsEntry
+}
+var file_pdf_v1_pdf_proto_depIdxs = []int32{
+	1,  // 0: pdf.v1.CompressRequest.options:type_name -> pdf.v1.CompressOptions
+	3,  // 1: pdf.v1.SplitRequest.options:type_name -> pdf.v1.SplitOptions
+	13, // 2: pdf.v1.SplitOptions.params:type_name -> pdf.v1.SplitOptions.ParamsEntry
+	5,  // 3: pdf.v1.RemovePagesRequest.options:type_name -> pdf.v1.RemovePagesOptions
+	7,  // 4: pdf.v1.ProcessRequest.options:type_name -> pdf.v1.ProcessOptions
+	14, // 5: pdf.v1.ProcessOptions.params:type_name -> pdf.v1.ProcessOptions.ParamsEntry
+	9,  // 6: pdf.v1.PageCountRequest.options:type_name -> pdf.v1.PageCountOptions
+	12, // 7: pdf.v1.FileChunk.info:type_name -> pdf.v1.FileInfo
+	0,  // 8: pdf.v1.PdfService.Compress:input_type -> pdf.v1.CompressRequest
+	2,  // 9: pdf.v1.PdfService.Split:input_type -> pdf.v1.SplitRequest
+	4,  // 10: pdf.v1.PdfService.RemovePages:input_type -> pdf.v1.RemovePagesRequest
+	8,  // 11: pdf.v1.PdfService.PageCount:input_type -> pdf.v1.PageCountRequest
+	6,  // 12: pdf.v1.PdfService.Process:input_type -> pdf.v1.ProcessRequest
+	11, // 13: pdf.v1.PdfService.Compress:output_type -> pdf.v1.FileChunk
+	11, // 14: pdf.v1.PdfService.Split:output_type -> pdf.v1.FileChunk
+	11, // 15: pdf.v1.PdfService.RemovePages:output_type -> pdf.v1.FileChunk
+	10, // 16: pdf.v1.PdfService.PageCount:output_type -> pdf.v1.PageCountResponse
+	11, // 17: pdf.v1.PdfService.Process:output_type -> pdf.v1.FileChunk
+	13, // [13:18] is the sub-list for method output_type
+	8,  // [8:13] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_pdf_v1_pdf_proto_init() }
+func file_pdf_v1_pdf_proto_init() {
+	if File_pdf_v1_pdf_proto != nil {
+		return
+	}
+	file_pdf_v1_pdf_proto_msgTypes[0].OneofWrappers = []any{
+		(*CompressRequest_Options)(nil),
+		(*CompressRequest_Chunk)(nil),
+	}
+	file_pdf_v1_pdf_proto_msgTypes[2].OneofWrappers = []any{
+		(*SplitRequest_Options)(nil),
+		(*SplitRequest_Chunk)(nil),
+	}
+	file_pdf_v1_pdf_proto_msgTypes[3].OneofWrappers = []any{
+		(*SplitOptions_Ranges)(nil),
+		(*SplitOptions_FixedRange)(nil),
+		(*SplitOptions_RemovePages)(nil),
+		(*SplitOptions_NamedMode)(nil),
+	}
+	file_pdf_v1_pdf_proto_msgTypes[4].OneofWrappers = []any{
+		(*RemovePagesRequest_Options)(nil),
+		(*RemovePagesRequest_Chunk)(nil),
+	}
+	file_pdf_v1_pdf_proto_msgTypes[6].OneofWrappers = []any{
+		(*ProcessRequest_Options)(nil),
+		(*ProcessRequest_Chunk)(nil),
+	}
+	file_pdf_v1_pdf_proto_msgTypes[8].OneofWrappers = []any{
+		(*PageCountRequest_Options)(nil),
+		(*PageCountRequest_Chunk)(nil),
+	}
+	file_pdf_v1_pdf_proto_msgTypes[11].OneofWrappers = []any{
+		(*FileChunk_Info)(nil),
+		(*FileChunk_Chunk)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pdf_v1_pdf_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pdf_v1_pdf_proto_goTypes,
+		DependencyIndexes: file_pdf_v1_pdf_proto_depIdxs,
+		MessageInfos:      file_pdf_v1_pdf_proto_msgTypes,
+	}.Build()
+	File_pdf_v1_pdf_proto = out.File
+	file_pdf_v1_pdf_proto_rawDesc = nil
+	file_pdf_v1_pdf_proto_goTypes = nil
+	file_pdf_v1_pdf_proto_depIdxs = nil
+}
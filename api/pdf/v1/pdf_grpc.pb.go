@@ -0,0 +1,264 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: pdf/v1/pdf.proto
+
+package pdfv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	PdfService_Compress_FullMethodName    = "/pdf.v1.PdfService/Compress"
+	PdfService_Split_FullMethodName       = "/pdf.v1.PdfService/Split"
+	PdfService_RemovePages_FullMethodName = "/pdf.v1.PdfService/RemovePages"
+	PdfService_PageCount_FullMethodName   = "/pdf.v1.PdfService/PageCount"
+	PdfService_Process_FullMethodName     = "/pdf.v1.PdfService/Process"
+)
+
+// PdfServiceClient is the client API for PdfService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// PdfService offers the PDF operations of the HTTP API to other services. Documents
+// are streamed in: the first message of a call carries the options, every following
+// message a chunk of the document. Results are streamed back the same way, the first
+// message describes the result and the chunks follow.
+type PdfServiceClient interface {
+	// Compress optimizes the document
+	Compress(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CompressRequest, FileChunk], error)
+	// Split keeps the pages of the given ranges, cuts the document into parts of a fixed
+	// number of pages or drops the given pages. More than one part comes as a zip.
+	Split(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SplitRequest, FileChunk], error)
+	// RemovePages drops the given pages
+	RemovePages(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[RemovePagesRequest, FileChunk], error)
+	// PageCount counts the pages of the document
+	PageCount(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[PageCountRequest, PageCountResponse], error)
+	// Process runs a processor of the operation registry, such as compress, by name
+	Process(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ProcessRequest, FileChunk], error)
+}
+
+type pdfServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPdfServiceClient(cc grpc.ClientConnInterface) PdfServiceClient {
+	return &pdfServiceClient{cc}
+}
+
+func (c *pdfServiceClient) Compress(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CompressRequest, FileChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PdfService_ServiceDesc.Streams[0], PdfService_Compress_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CompressRequest, FileChunk]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PdfService_CompressClient = grpc.BidiStreamingClient[CompressRequest, FileChunk]
+
+func (c *pdfServiceClient) Split(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SplitRequest, FileChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PdfService_ServiceDesc.Streams[1], PdfService_Split_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SplitRequest, FileChunk]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PdfService_SplitClient = grpc.BidiStreamingClient[SplitRequest, FileChunk]
+
+func (c *pdfServiceClient) RemovePages(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[RemovePagesRequest, FileChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PdfService_ServiceDesc.Streams[2], PdfService_RemovePages_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[RemovePagesRequest, FileChunk]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PdfService_RemovePagesClient = grpc.BidiStreamingClient[RemovePagesRequest, FileChunk]
+
+func (c *pdfServiceClient) PageCount(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[PageCountRequest, PageCountResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PdfService_ServiceDesc.Streams[3], PdfService_PageCount_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PageCountRequest, PageCountResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PdfService_PageCountClient = grpc.ClientStreamingClient[PageCountRequest, PageCountResponse]
+
+func (c *pdfServiceClient) Process(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ProcessRequest, FileChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PdfService_ServiceDesc.Streams[4], PdfService_Process_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ProcessRequest, FileChunk]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PdfService_ProcessClient = grpc.BidiStreamingClient[ProcessRequest, FileChunk]
+
+// PdfServiceServer is the server API for PdfService service.
+// All implementations must embed UnimplementedPdfServiceServer
+// for forward compatibility.
+//
+// PdfService offers the PDF operations of the HTTP API to other services. Documents
+// are streamed in: the first message of a call carries the options, every following
+// message a chunk of the document. Results are streamed back the same way, the first
+// message describes the result and the chunks follow.
+type PdfServiceServer interface {
+	// Compress optimizes the document
+	Compress(grpc.BidiStreamingServer[CompressRequest, FileChunk]) error
+	// Split keeps the pages of the given ranges, cuts the document into parts of a fixed
+	// number of pages or drops the given pages. More than one part comes as a zip.
+	Split(grpc.BidiStreamingServer[SplitRequest, FileChunk]) error
+	// RemovePages drops the given pages
+	RemovePages(grpc.BidiStreamingServer[RemovePagesRequest, FileChunk]) error
+	// PageCount counts the pages of the document
+	PageCount(grpc.ClientStreamingServer[PageCountRequest, PageCountResponse]) error
+	// Process runs a processor of the operation registry, such as compress, by name
+	Process(grpc.BidiStreamingServer[ProcessRequest, FileChunk]) error
+	mustEmbedUnimplementedPdfServiceServer()
+}
+
+// UnimplementedPdfServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPdfServiceServer struct{}
+
+func (UnimplementedPdfServiceServer) Compress(grpc.BidiStreamingServer[CompressRequest, FileChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method Compress not implemented")
+}
+func (UnimplementedPdfServiceServer) Split(grpc.BidiStreamingServer[SplitRequest, FileChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method Split not implemented")
+}
+func (UnimplementedPdfServiceServer) RemovePages(grpc.BidiStreamingServer[RemovePagesRequest, FileChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method RemovePages not implemented")
+}
+func (UnimplementedPdfServiceServer) PageCount(grpc.ClientStreamingServer[PageCountRequest, PageCountResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method PageCount not implemented")
+}
+func (UnimplementedPdfServiceServer) Process(grpc.BidiStreamingServer[ProcessRequest, FileChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method Process not implemented")
+}
+func (UnimplementedPdfServiceServer) mustEmbedUnimplementedPdfServiceServer() {}
+func (UnimplementedPdfServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafePdfServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PdfServiceServer will
+// result in compilation errors.
+type UnsafePdfServiceServer interface {
+	mustEmbedUnimplementedPdfServiceServer()
+}
+
+func RegisterPdfServiceServer(s grpc.ServiceRegistrar, srv PdfServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPdfServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PdfService_ServiceDesc, srv)
+}
+
+func _PdfService_Compress_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PdfServiceServer).Compress(&grpc.GenericServerStream[CompressRequest, FileChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PdfService_CompressServer = grpc.BidiStreamingServer[CompressRequest, FileChunk]
+
+func _PdfService_Split_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PdfServiceServer).Split(&grpc.GenericServerStream[SplitRequest, FileChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PdfService_SplitServer = grpc.BidiStreamingServer[SplitRequest, FileChunk]
+
+func _PdfService_RemovePages_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PdfServiceServer).RemovePages(&grpc.GenericServerStream[RemovePagesRequest, FileChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PdfService_RemovePagesServer = grpc.BidiStreamingServer[RemovePagesRequest, FileChunk]
+
+func _PdfService_PageCount_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PdfServiceServer).PageCount(&grpc.GenericServerStream[PageCountRequest, PageCountResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PdfService_PageCountServer = grpc.ClientStreamingServer[PageCountRequest, PageCountResponse]
+
+func _PdfService_Process_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PdfServiceServer).Process(&grpc.GenericServerStream[ProcessRequest, FileChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PdfService_ProcessServer = grpc.BidiStreamingServer[ProcessRequest, FileChunk]
+
+// PdfService_ServiceDesc is the grpc.ServiceDesc for PdfService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PdfService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pdf.v1.PdfService",
+	HandlerType: (*PdfServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Compress",
+			Handler:       _PdfService_Compress_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Split",
+			Handler:       _PdfService_Split_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "RemovePages",
+			Handler:       _PdfService_RemovePages_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "PageCount",
+			Handler:       _PdfService_PageCount_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Process",
+			Handler:       _PdfService_Process_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "pdf/v1/pdf.proto",
+}
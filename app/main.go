@@ -1,34 +1,91 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
 	"database/sql"
+	"expvar"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/labstack/echo/v4"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
 	echoSwagger "github.com/swaggo/echo-swagger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 
+	pdfv1 "github.com/bxcodec/go-clean-arch/api/pdf/v1"
 	"github.com/bxcodec/go-clean-arch/internal/helper"
 	"github.com/bxcodec/go-clean-arch/internal/repository"
 	mysqlRepo "github.com/bxcodec/go-clean-arch/internal/repository/mysql"
+	s3Repo "github.com/bxcodec/go-clean-arch/internal/repository/s3"
 	"github.com/bxcodec/go-clean-arch/pdf"
 
 	"github.com/bxcodec/go-clean-arch/article"
-	_ "github.com/bxcodec/go-clean-arch/docs"
+	"github.com/bxcodec/go-clean-arch/docs"
+	"github.com/bxcodec/go-clean-arch/download"
 	"github.com/bxcodec/go-clean-arch/internal/rest"
 	"github.com/bxcodec/go-clean-arch/internal/rest/middleware"
+	"github.com/bxcodec/go-clean-arch/internal/rpc"
+	"github.com/bxcodec/go-clean-arch/internal/workers"
+	"github.com/bxcodec/go-clean-arch/job"
+	"github.com/bxcodec/go-clean-arch/resumable"
+	"github.com/bxcodec/go-clean-arch/webhook"
 	"github.com/joho/godotenv"
 )
 
 const (
-	defaultTimeout = 30
-	defaultAddress = ":9090"
+	defaultTimeout      = 30
+	defaultAddress      = ":9090"
+	defaultGrpcAddress  = ":9091"
+	defaultAdminAddress = "127.0.0.1:9092"
+
+	defaultWorkspaceTTL   = time.Hour
+	defaultJanitorPeriod  = 10 * time.Minute
+	defaultWorkspaceQuota = 0
+
+	defaultLinkTTL = 24 * time.Hour
+
+	defaultCacheMemoryBytes = 64 << 20
+	defaultCacheMemoryEntry = 4 << 20
+	defaultCacheTTL         = 6 * time.Hour
+
+	defaultMaxUpload     = 256 << 20
+	defaultBatchMaxFiles = 10000
+
+	defaultAdmissionQueue = 64
+	defaultAdmissionWait  = 30 * time.Second
+
+	defaultJobRetention = 5 * time.Minute
+
+	defaultUploadTTL = 24 * time.Hour
+
+	defaultWorkerMaxCPU    = time.Minute
+	defaultWorkerMaxMemory = 1 << 30
+
+	defaultSourceTimeout   = 60 * time.Second
+	defaultSourceRedirects = 3
+
+	defaultAsyncTimeout     = 30 * time.Minute
+	defaultWebhookTimeout   = 10 * time.Second
+	defaultWebhookAttempts  = 6
+	defaultWebhookBaseDelay = time.Second
+	defaultWebhookMaxDelay  = 5 * time.Minute
 )
 
 func init() {
@@ -91,19 +148,98 @@ func main() {
 	authorRepo := mysqlRepo.NewAuthorRepository(dbConn)
 	articleRepo := mysqlRepo.NewArticleRepository(dbConn)
 
-	pdfApi := &repository.PdfCpuApiImpl{}
+	workspaces, err := repository.NewWorkspaces(os.Getenv("WORKSPACE_ROOT"), getEnvInt64("WORKSPACE_QUOTA_BYTES", defaultWorkspaceQuota))
+	if err != nil {
+		log.Fatal("failed to prepare workspaces ", err)
+	}
+	janitor := workers.NewJanitor(workspaces,
+		getEnvDuration("WORKSPACE_TTL", defaultWorkspaceTTL),
+		getEnvDuration("WORKSPACE_JANITOR_INTERVAL", defaultJanitorPeriod))
+	go janitor.Run(context.Background())
+	expvar.Publish("workspaces", expvar.Func(func() any { return janitor.Stats() }))
+
+	pdfApi := newPdfApi()
 	fileHelper := &repository.FileHelperImpl{}
-	pdfRepo := repository.NewPdfRepository(pdfApi, fileHelper)
+	pdfRepo := repository.NewPdfRepository(pdfApi, fileHelper, workspaces,
+		repository.WithParallelism(int(getEnvInt64("SPLIT_PARALLELISM", int64(runtime.NumCPU())))))
 
 	// Build service Layer
 	svc := article.NewService(articleRepo, authorRepo)
 	rest.NewArticleHandler(e, svc)
 
-	pdfSvc := pdf.NewService(pdfRepo)
-	rest.NewPdfHandler(e, pdfSvc)
+	linkTTL := getEnvDuration("DOWNLOAD_LINK_TTL", defaultLinkTTL)
+	downloadSvc := download.NewService(newResultStore(linkTTL), downloadSecret(), linkTTL)
+	rest.NewDownloadHandler(e, downloadSvc)
+
+	pdfOpts := []pdf.Option{}
+	if cacheDiskBytes := getEnvInt64("CACHE_DISK_BYTES", 0); cacheDiskBytes > 0 {
+		cacheDir := os.Getenv("CACHE_DIR")
+		if cacheDir == "" {
+			cacheDir = filepath.Join(os.TempDir(), "pdf-cache")
+		}
+		cache, err := repository.NewResultCache(repository.CacheConfig{
+			Dir:            cacheDir,
+			MaxDiskBytes:   cacheDiskBytes,
+			MaxMemoryBytes: getEnvInt64("CACHE_MEMORY_BYTES", defaultCacheMemoryBytes),
+			MaxMemoryEntry: getEnvInt64("CACHE_MEMORY_ENTRY_BYTES", defaultCacheMemoryEntry),
+			TTL:            getEnvDuration("CACHE_TTL", defaultCacheTTL),
+		})
+		if err != nil {
+			log.Fatal("failed to prepare result cache ", err)
+		}
+		pdfOpts = append(pdfOpts, pdf.WithCache(cache))
+	}
+
+	if capacity := getEnvInt64("ADMISSION_CAPACITY", int64(runtime.NumCPU())*pdf.DefaultCosts["compress"]); capacity > 0 {
+		admission := pdf.NewAdmission(pdf.AdmissionConfig{
+			Capacity: capacity,
+			MaxQueue: int(getEnvInt64("ADMISSION_QUEUE", defaultAdmissionQueue)),
+			MaxWait:  getEnvDuration("ADMISSION_MAX_WAIT", defaultAdmissionWait),
+		})
+		expvar.Publish("admission", expvar.Func(func() any { return admission.Stats() }))
+		pdfOpts = append(pdfOpts, pdf.WithAdmission(admission))
+	}
+
+	pdfOpts = append(pdfOpts, pdf.WithBatchLimits(pdf.BatchLimits{
+		Parallelism:   int(getEnvInt64("BATCH_PARALLELISM", int64(runtime.NumCPU()))),
+		MaxEntries:    int(getEnvInt64("BATCH_MAX_FILES", defaultBatchMaxFiles)),
+		MaxEntryBytes: getEnvInt64("BATCH_MAX_FILE_BYTES", defaultMaxUpload),
+	}))
+
+	jobSvc := job.NewService(getEnvDuration("JOB_RETENTION", defaultJobRetention))
+	rest.NewJobHandler(e, jobSvc)
+
+	pdfSvc := pdf.NewService(pdfRepo, pdfOpts...)
+	maxCompressUpload := getEnvInt64("MAX_COMPRESS_UPLOAD_BYTES", defaultMaxUpload)
+	maxSplitUpload := getEnvInt64("MAX_SPLIT_UPLOAD_BYTES", defaultMaxUpload)
+	auditLog := pdf.NewAuditLog(mysqlRepo.NewAuditRepository(dbConn), pdfSvc)
+	rest.NewAuditHandler(e, auditLog)
+
+	pdfHandlerOpts := []rest.PdfHandlerOption{
+		rest.WithDownloadLinks(downloadSvc),
+		rest.WithUploadLimits(rest.UploadLimits{
+			Compress: maxCompressUpload,
+			Split:    maxSplitUpload,
+		}),
+		rest.WithSourceFetcher(newSourceFetcher(workspaces)),
+		rest.WithJobs(jobSvc),
+		rest.WithResumableUploads(newResumableUploads(e)),
+		rest.WithAudit(auditLog),
+	}
+	if webhooks := newWebhookService(dbConn); webhooks != nil {
+		pdfHandlerOpts = append(pdfHandlerOpts, rest.WithWebhooks(webhooks, getEnvDuration("ASYNC_TIMEOUT", defaultAsyncTimeout)))
+	}
+	rest.NewPdfHandler(e, pdfSvc, pdfHandlerOpts...)
+
+	// gRPC
+	go serveGrpc(pdfSvc, rpc.UploadLimits{Compress: maxCompressUpload, Split: maxSplitUpload})
 
-	// Swagger
-	e.GET("/swagger/*", echoSwagger.WrapHandler)
+	// Swagger, with the operations registered by other packages
+	rest.RegisterOperationDocs("pdf", docs.SwaggerInfo, pdf.DefaultRegistry)
+	e.GET("/swagger/*", echoSwagger.EchoWrapHandler(echoSwagger.InstanceName("pdf")))
+
+	// Runtime stats
+	go serveAdmin()
 
 	// Start Server
 	address := os.Getenv("SERVER_ADDRESS")
@@ -112,3 +248,223 @@ func main() {
 	}
 	log.Fatal(e.Start(address)) //nolint
 }
+
+// serveGrpc offers the PDF service over gRPC on GRPC_ADDRESS, next to the health and
+// reflection services for load balancers and tooling
+func serveGrpc(pdfSvc rpc.PdfService, limits rpc.UploadLimits) {
+	address := os.Getenv("GRPC_ADDRESS")
+	if address == "" {
+		address = defaultGrpcAddress
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Fatal("failed to listen for grpc ", err)
+	}
+
+	server := grpc.NewServer()
+	rpc.NewPdfServer(server, pdfSvc, rpc.WithUploadLimits(limits))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(pdfv1.PdfService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	log.Fatal(server.Serve(listener))
+}
+
+// serveAdmin offers the runtime stats at /debug/vars on ADMIN_ADDRESS, apart from the
+// public listener as they include the command line and memory stats
+func serveAdmin() {
+	address := os.Getenv("ADMIN_ADDRESS")
+	if address == "" {
+		address = defaultAdminAddress
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+	log.Fatal(http.ListenAndServe(address, mux)) //nolint
+}
+
+// newResultStore picks the backend for download links from RESULT_STORE. Local results
+// are purged by a janitor once every link to them expired, buckets are expected to
+// carry their own lifecycle rule.
+func newResultStore(linkTTL time.Duration) download.ResultStore {
+	if os.Getenv("RESULT_STORE") == "s3" {
+		client, err := minio.New(os.Getenv("S3_ENDPOINT"), &minio.Options{
+			Creds:  credentials.NewStaticV4(os.Getenv("S3_ACCESS_KEY"), os.Getenv("S3_SECRET_KEY"), ""),
+			Secure: os.Getenv("S3_USE_SSL") == "true",
+			Region: os.Getenv("S3_REGION"),
+		})
+		if err != nil {
+			log.Fatal("failed to create s3 client ", err)
+		}
+		return s3Repo.NewResultStore(client, os.Getenv("S3_BUCKET"))
+	}
+
+	dir := os.Getenv("RESULT_DIR")
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "pdf-results")
+	}
+	store, err := repository.NewLocalResultStore(dir)
+	if err != nil {
+		log.Fatal("failed to prepare result store ", err)
+	}
+
+	janitor := workers.NewJanitor(store, linkTTL, getEnvDuration("WORKSPACE_JANITOR_INTERVAL", defaultJanitorPeriod))
+	go janitor.Run(context.Background())
+	expvar.Publish("results", expvar.Func(func() any { return janitor.Stats() }))
+	return store
+}
+
+// newPdfApi runs pdfcpu in PDF_WORKERS worker processes, or in process when it is 0.
+// Workers exit with the server, their requests pipe closes with it.
+func newPdfApi() repository.PdfCpuApi {
+	size := int(getEnvInt64("PDF_WORKERS", 0))
+	if size <= 0 {
+		return &repository.PdfCpuApiImpl{}
+	}
+
+	command := os.Getenv("PDF_WORKER_COMMAND")
+	if command == "" {
+		executable, err := os.Executable()
+		if err != nil {
+			log.Fatal("failed to locate the pdf worker ", err)
+		}
+		command = filepath.Join(filepath.Dir(executable), "pdfworker")
+	}
+	pool, err := repository.NewWorkerPool(repository.WorkerConfig{
+		Command: command,
+		Size:    size,
+		Limits: repository.WorkerLimits{
+			CPUTime: getEnvDuration("PDF_WORKER_MAX_CPU", defaultWorkerMaxCPU),
+			Memory:  getEnvInt64("PDF_WORKER_MAX_MEMORY_BYTES", defaultWorkerMaxMemory),
+		},
+	})
+	if err != nil {
+		log.Fatal("failed to start pdf workers ", err)
+	}
+	return pool
+}
+
+// newResumableUploads serves tus uploads from UPLOAD_DIR. A janitor removes uploads
+// nobody wrote to for UPLOAD_TTL, finished or not.
+func newResumableUploads(e *echo.Echo) rest.ResumableUploadService {
+	dir := os.Getenv("UPLOAD_DIR")
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "pdf-uploads")
+	}
+	store, err := repository.NewLocalUploadStore(dir)
+	if err != nil {
+		log.Fatal("failed to prepare upload store ", err)
+	}
+
+	ttl := getEnvDuration("UPLOAD_TTL", defaultUploadTTL)
+	janitor := workers.NewJanitor(store, ttl, getEnvDuration("WORKSPACE_JANITOR_INTERVAL", defaultJanitorPeriod))
+	go janitor.Run(context.Background())
+	expvar.Publish("uploads", expvar.Func(func() any { return janitor.Stats() }))
+
+	maxSize := getEnvInt64("MAX_RESUMABLE_UPLOAD_BYTES", defaultMaxUpload)
+	uploads := resumable.NewService(store, resumable.Config{MaxSize: maxSize, TTL: ttl})
+
+	algorithms := make([]string, 0, len(resumable.ChecksumAlgorithms))
+	for algorithm := range resumable.ChecksumAlgorithms {
+		algorithms = append(algorithms, algorithm)
+	}
+	slices.Sort(algorithms)
+	rest.NewTusHandler(e, uploads, maxSize, algorithms)
+	return uploads
+}
+
+// newSourceFetcher enables source_url when SOURCE_URL_ENABLED is true. Private
+// networks stay denied unless listed in SOURCE_URL_ALLOWED_NETWORKS.
+func newSourceFetcher(workspaces *repository.Workspaces) rest.SourceFetcher {
+	if os.Getenv("SOURCE_URL_ENABLED") != "true" {
+		return nil
+	}
+
+	networks, err := repository.ParseCIDRs(getEnvList("SOURCE_URL_ALLOWED_NETWORKS")...)
+	if err != nil {
+		log.Fatal("failed to parse SOURCE_URL_ALLOWED_NETWORKS ", err)
+	}
+	return repository.NewSourceFetcher(repository.OutboundConfig{
+		AllowedHosts:    getEnvList("SOURCE_URL_ALLOWED_HOSTS"),
+		AllowedNetworks: networks,
+		Timeout:         getEnvDuration("SOURCE_URL_TIMEOUT", defaultSourceTimeout),
+		MaxRedirects:    int(getEnvInt64("SOURCE_URL_MAX_REDIRECTS", defaultSourceRedirects)),
+	}, workspaces)
+}
+
+// newWebhookService enables callback_url when WEBHOOK_SECRET is set, receivers need
+// it to verify the signature. Callback urls are restricted like source urls.
+func newWebhookService(dbConn *sql.DB) rest.WebhookService {
+	secret := os.Getenv("WEBHOOK_SECRET")
+	if secret == "" {
+		return nil
+	}
+
+	networks, err := repository.ParseCIDRs(getEnvList("WEBHOOK_ALLOWED_NETWORKS")...)
+	if err != nil {
+		log.Fatal("failed to parse WEBHOOK_ALLOWED_NETWORKS ", err)
+	}
+	poster := repository.NewWebhookPoster(repository.OutboundConfig{
+		AllowedHosts:    getEnvList("WEBHOOK_ALLOWED_HOSTS"),
+		AllowedNetworks: networks,
+		Timeout:         getEnvDuration("WEBHOOK_TIMEOUT", defaultWebhookTimeout),
+	})
+	return webhook.NewService(poster, mysqlRepo.NewWebhookRepository(dbConn), webhook.Config{
+		Secret:      []byte(secret),
+		MaxAttempts: int(getEnvInt64("WEBHOOK_MAX_ATTEMPTS", defaultWebhookAttempts)),
+		BaseDelay:   getEnvDuration("WEBHOOK_BASE_DELAY", defaultWebhookBaseDelay),
+		MaxDelay:    getEnvDuration("WEBHOOK_MAX_DELAY", defaultWebhookMaxDelay),
+	})
+}
+
+// downloadSecret signs download links. Without DOWNLOAD_SECRET a random one is used,
+// which invalidates every link on restart.
+func downloadSecret() []byte {
+	if secret := os.Getenv("DOWNLOAD_SECRET"); secret != "" {
+		return []byte(secret)
+	}
+
+	log.Println("DOWNLOAD_SECRET is not set, download links will not survive a restart")
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.Fatal("failed to generate download secret ", err)
+	}
+	return secret
+}
+
+func getEnvInt64(key string, fallback int64) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		log.Printf("failed to parse %s, using default %d", key, fallback)
+		return fallback
+	}
+	return parsed
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("failed to parse %s, using default %s", key, fallback)
+		return fallback
+	}
+	return parsed
+}
+
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -123,6 +123,53 @@ LOCK TABLES `category` WRITE;
 INSERT INTO `category` VALUES (1,'Makanan','food','2017-05-18 13:50:19','2017-05-18 13:50:19'),(2,'Kehidupan','life','2017-05-18 13:50:19','2017-05-18 13:50:19'),(3,'Kasih Sayang','love','2017-05-18 13:50:19','2017-05-18 13:50:19');
 /*!40000 ALTER TABLE `category` ENABLE KEYS */;
 UNLOCK TABLES;
+--
+-- Table structure for table `webhook_dead_letter`
+--
+
+DROP TABLE IF EXISTS `webhook_dead_letter`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `webhook_dead_letter` (
+  `id` int(11) unsigned NOT NULL AUTO_INCREMENT,
+  `job_id` varchar(64) COLLATE utf8_unicode_ci NOT NULL,
+  `callback_url` varchar(2048) COLLATE utf8_unicode_ci NOT NULL,
+  `payload` text COLLATE utf8_unicode_ci NOT NULL,
+  `attempts` int(11) NOT NULL,
+  `last_error` text COLLATE utf8_unicode_ci NOT NULL,
+  `created_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `job_id` (`job_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `pdf_audit_log`
+--
+
+DROP TABLE IF EXISTS `pdf_audit_log`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `pdf_audit_log` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `caller` varchar(255) COLLATE utf8_unicode_ci NOT NULL,
+  `operation` varchar(64) COLLATE utf8_unicode_ci NOT NULL,
+  `params` text COLLATE utf8_unicode_ci NOT NULL,
+  `file_name` varchar(1024) COLLATE utf8_unicode_ci NOT NULL,
+  `input_sha256` char(64) COLLATE utf8_unicode_ci NOT NULL,
+  `input_size` bigint(20) NOT NULL,
+  `page_count` int(11) NOT NULL,
+  `output_size` bigint(20) NOT NULL,
+  `duration_ms` bigint(20) NOT NULL,
+  `outcome` varchar(16) COLLATE utf8_unicode_ci NOT NULL,
+  `error` text COLLATE utf8_unicode_ci NOT NULL,
+  `created_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `created_at` (`created_at`),
+  KEY `caller` (`caller`,`created_at`),
+  KEY `input_sha256` (`input_sha256`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
 /*!40103 SET TIME_ZONE=@OLD_TIME_ZONE */;
 
 /*!40101 SET SQL_MODE=@OLD_SQL_MODE */;
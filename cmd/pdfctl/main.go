@@ -0,0 +1,421 @@
+// Command pdfctl runs the PDF operations of the server on local files. It wraps the
+// same pdf.Service and repository.PdfRepository, so a document behaves the same on
+// the command line as it does over HTTP.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository"
+	"github.com/bxcodec/go-clean-arch/pdf"
+)
+
+const usageFooter = `
+Inputs may be glob patterns, "-" reads a single document from stdin. Results are
+written next to their input, to stdout for stdin, or wherever -o points to.
+Run "pdfctl <command> -h" for the flags of a command.
+`
+
+const stdio = "-"
+
+// usage lists the commands, the processors and split modes come from the registry
+func usage() string {
+	var b strings.Builder
+	b.WriteString("Usage: pdfctl <command> [flags] <file|glob|->...\n\nCommands:\n")
+	for _, op := range pdf.DefaultRegistry.List(pdf.KindProcess) {
+		fmt.Fprintf(&b, "  %-9s %s, for every input\n", op.Name, op.Description)
+	}
+	fmt.Fprintf(&b, "  %-9s split every input (-mode %s)\n", "split", strings.Join(operationNames(pdf.KindSplit), "|"))
+	fmt.Fprintf(&b, "  %-9s print the page count of every input\n", "count")
+	fmt.Fprintf(&b, "  %-9s join the inputs, in order, into one document\n", "merge")
+	b.WriteString(usageFooter)
+	return b.String()
+}
+
+func operationNames(kind string) []string {
+	var names []string
+	for _, op := range pdf.DefaultRegistry.List(kind) {
+		names = append(names, op.Name)
+	}
+	return names
+}
+
+// paramFlags defines a flag for every parameter of operations and returns the values
+// by parameter name. Operations sharing a parameter share its flag.
+func paramFlags(flags *flag.FlagSet, operations []pdf.OperationInfo) func(name string) string {
+	flagNames := map[string]string{}
+	for _, op := range operations {
+		for _, param := range op.Params {
+			if _, ok := flagNames[param.Name]; ok {
+				continue
+			}
+			flagNames[param.Name] = param.Flag
+			switch param.Type {
+			case "integer":
+				flags.Int(param.Flag, 0, param.Doc)
+			case "boolean":
+				flags.Bool(param.Flag, false, param.Doc)
+			default:
+				flags.String(param.Flag, "", param.Doc)
+			}
+		}
+	}
+
+	// a flag that was not given leaves the parameter to the operation
+	return func(name string) string {
+		var value string
+		flags.Visit(func(f *flag.Flag) {
+			if f.Name == flagNames[name] {
+				value = f.Value.String()
+			}
+		})
+		return value
+	}
+}
+
+// usageError is a mistake in the command line rather than in a document
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+type cli struct {
+	svc      *pdf.Service
+	stdin    io.Reader
+	stdout   io.Writer
+	stderr   io.Writer
+	output   string
+	parallel int
+
+	mu sync.Mutex
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+// run executes the command line args and returns the exit code: 0 on success, 1 when
+// an input failed and 2 for an invalid command line
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage())
+		return 2
+	}
+
+	name, args := args[0], args[1:]
+	flags := flag.NewFlagSet("pdfctl "+name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	output := flags.String("o", "", "output file or directory, - for stdout")
+	parallel := flags.Int("j", runtime.NumCPU(), "number of inputs processed at the same time")
+	workspaceRoot := flags.String("workspace", "", "directory for temporary files (default: system temp dir)")
+
+	// split and the processors of the registry run an operation, its parameters are flags
+	kind, operation := pdf.KindProcess, name
+	var mode *string
+	var values func(name string) string
+	switch name {
+	case "split":
+		kind = pdf.KindSplit
+		mode = flags.String("mode", pdf.SplitModeRanges, "split mode: "+strings.Join(operationNames(pdf.KindSplit), ", "))
+		values = paramFlags(flags, pdf.DefaultRegistry.List(pdf.KindSplit))
+	case "count", "merge":
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage())
+		return 0
+	default:
+		op, ok := pdf.DefaultRegistry.Lookup(pdf.KindProcess, name)
+		if !ok {
+			fmt.Fprintf(stderr, "pdfctl: unknown command %q\n\n%s", name, usage())
+			return 2
+		}
+		values = paramFlags(flags, []pdf.OperationInfo{op})
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if *parallel <= 0 {
+		*parallel = 1
+	}
+	if mode != nil {
+		operation = *mode
+	}
+
+	workspaces, err := repository.NewWorkspaces(*workspaceRoot, 0)
+	if err != nil {
+		fmt.Fprintf(stderr, "pdfctl: %v\n", err)
+		return 1
+	}
+	pdfRepo := repository.NewPdfRepository(&repository.PdfCpuApiImpl{}, &repository.FileHelperImpl{}, workspaces,
+		repository.WithParallelism(runtime.NumCPU()))
+
+	c := &cli{
+		svc:      pdf.NewService(pdfRepo),
+		stdin:    stdin,
+		stdout:   stdout,
+		stderr:   stderr,
+		output:   *output,
+		parallel: *parallel,
+	}
+
+	inputs, err := expandInputs(flags.Args())
+	if err == nil {
+		switch name {
+		case "count":
+			err = c.count(ctx, inputs)
+		case "merge":
+			err = c.merge(ctx, inputs)
+		default:
+			err = c.run(ctx, inputs, kind, operation, values)
+		}
+	}
+
+	var usageErr usageError
+	switch {
+	case errors.As(err, &usageErr):
+		fmt.Fprintf(stderr, "pdfctl %s: %v\n", name, err)
+		flags.Usage()
+		return 2
+	case err != nil:
+		c.errorf("%v", err)
+		return 1
+	}
+	return 0
+}
+
+// expandInputs resolves the glob patterns of the command line in order. A pattern
+// matching nothing is an error so that a typo does not pass silently.
+func expandInputs(patterns []string) ([]string, error) {
+	if len(patterns) == 0 {
+		return nil, usageError("no input given")
+	}
+
+	inputs := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if pattern == stdio {
+			if slices.Contains(inputs, stdio) {
+				return nil, usageError("stdin can only be read once")
+			}
+			inputs = append(inputs, pattern)
+			continue
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, usageError(fmt.Sprintf("invalid pattern %q", pattern))
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("%s: no such file", pattern)
+		}
+		inputs = append(inputs, matches...)
+	}
+	return inputs, nil
+}
+
+// run prepares the operation of the registry with the parameters given as flags and
+// runs it on every input
+func (c *cli) run(ctx context.Context, inputs []string, kind, name string, values func(name string) string) error {
+	processor, err := pdf.DefaultRegistry.Prepare(c.svc, kind, name, values)
+	var param pdf.ParamError
+	switch {
+	case errors.Is(err, pdf.ErrUnknownOperation):
+		return usageError(fmt.Sprintf("invalid split mode %q", name))
+	case errors.As(err, &param):
+		return usageError(err.Error())
+	case err != nil:
+		return err
+	}
+	return c.process(ctx, inputs, processor)
+}
+
+// process runs processor on every input, at most c.parallel at a time. A failed input
+// is reported and does not stop the others.
+func (c *cli) process(ctx context.Context, inputs []string, processor domain.PdfProcessor) error {
+	many := len(inputs) > 1
+	if many && c.output == stdio {
+		return usageError("-o - takes a single input")
+	}
+	if many && c.output == "" && slices.Contains(inputs, stdio) {
+		return usageError("stdin among other inputs needs -o")
+	}
+	if many && c.output != "" {
+		if err := os.MkdirAll(c.output, 0o755); err != nil {
+			return err
+		}
+	}
+
+	return c.forEach(ctx, inputs, func(ctx context.Context, _ int, input string) error {
+		name, file, err := c.open(ctx, input)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		result, err := processor(ctx, name, file)
+		if err != nil {
+			return err
+		}
+		return c.write(c.destination(input, result.Name), result)
+	})
+}
+
+func (c *cli) count(ctx context.Context, inputs []string) error {
+	counts := make([]int, len(inputs))
+	err := c.forEach(ctx, inputs, func(ctx context.Context, i int, input string) error {
+		_, file, err := c.open(ctx, input)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		counts[i], err = c.svc.PageCount(ctx, file)
+		return err
+	})
+
+	for i, input := range inputs {
+		switch {
+		case counts[i] == 0:
+		case len(inputs) == 1:
+			fmt.Fprintln(c.stdout, counts[i])
+		default:
+			fmt.Fprintf(c.stdout, "%d\t%s\n", counts[i], input)
+		}
+	}
+	return err
+}
+
+// merge joins inputs into -o, merged.pdf when it is not set
+func (c *cli) merge(ctx context.Context, inputs []string) error {
+	if len(inputs) < 2 {
+		return usageError("merge takes at least two inputs")
+	}
+
+	files := make([]io.ReadSeeker, 0, len(inputs))
+	for _, input := range inputs {
+		_, file, err := c.open(ctx, input)
+		if err != nil {
+			return fmt.Errorf("%s: %w", input, err)
+		}
+		defer file.Close()
+		files = append(files, file)
+	}
+
+	output := c.output
+	if output == "" {
+		output = "merged.pdf"
+	}
+	result, err := c.svc.MergePdf(ctx, filepath.Base(output), files)
+	if err != nil {
+		return err
+	}
+	return c.write(output, result)
+}
+
+func (c *cli) forEach(ctx context.Context, inputs []string, fn func(ctx context.Context, i int, input string) error) error {
+	var g errgroup.Group
+	g.SetLimit(c.parallel)
+
+	failed := make([]bool, len(inputs))
+	for i, input := range inputs {
+		g.Go(func() error {
+			if err := fn(ctx, i, input); err != nil {
+				failed[i] = true
+				c.errorf("%s: %v", input, err)
+			}
+			return nil
+		})
+	}
+	g.Wait()
+
+	if n := len(slices.DeleteFunc(failed, func(f bool) bool { return !f })); n > 0 {
+		return fmt.Errorf("%d of %d inputs failed", n, len(inputs))
+	}
+	return nil
+}
+
+// open returns the content of input and the file name the service names its result
+// after. Stdin is spooled to a workspace as the operations need to seek.
+func (c *cli) open(ctx context.Context, input string) (string, io.ReadSeekCloser, error) {
+	if input == stdio {
+		content, _, err := c.svc.Spool(ctx, c.stdin)
+		return "stdin.pdf", content, err
+	}
+
+	file, err := os.Open(input)
+	return filepath.Base(input), file, err
+}
+
+// destination returns where the result called name of input is written to
+func (c *cli) destination(input, name string) string {
+	switch {
+	case c.output == stdio:
+		return stdio
+	case c.output != "":
+		if info, err := os.Stat(c.output); err == nil && info.IsDir() {
+			return filepath.Join(c.output, name)
+		}
+		return c.output
+	case input == stdio:
+		return stdio
+	default:
+		return filepath.Join(filepath.Dir(input), name)
+	}
+}
+
+// write copies result to path, or stdout for "-", and prints the path of a written
+// file. A partly written file is removed.
+func (c *cli) write(path string, result domain.PdfFile) error {
+	defer result.Content.Close()
+
+	if path == stdio {
+		_, err := io.Copy(c.stdout, result.Content)
+		return err
+	}
+
+	output, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(output, result.Content); err != nil {
+		output.Close()
+		os.Remove(path)
+		return err
+	}
+	if err := output.Close(); err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fmt.Fprintln(c.stdout, path)
+	return nil
+}
+
+func (c *cli) errorf(format string, args ...any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fmt.Fprintf(c.stderr, "pdfctl: "+format+"\n", args...)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/pdf"
+)
+
+// firstPagesParams are the parameters of a split mode registered the way other
+// packages add theirs
+type firstPagesParams struct {
+	Pages int `param:"first_pages" doc:"number of pages to keep"`
+}
+
+func init() {
+	pdf.Register(pdf.DefaultRegistry, pdf.Operation[firstPagesParams]{
+		Kind:        pdf.KindSplit,
+		Name:        "first",
+		Description: "keep the first pages",
+		Run: func(ctx context.Context, exec pdf.Executor, p firstPagesParams, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+			return exec.SplitPdfByRanges(ctx, fileName, file, pdf.FixedRanges(p.Pages, p.Pages)[0])
+		},
+	})
+}
+
+func TestRun(t *testing.T) {
+	pdfContent, err := os.ReadFile("../../internal/resource/test.pdf")
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	for _, name := range []string{"a.pdf", "b.pdf"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), pdfContent, 0o600))
+	}
+
+	pdfctl := func(stdin []byte, args ...string) (int, string, string) {
+		var stdout, stderr bytes.Buffer
+		args = append(args[:1:1], append([]string{"-workspace", filepath.Join(dir, "workspaces")}, args[1:]...)...)
+		code := run(context.TODO(), args, bytes.NewReader(stdin), &stdout, &stderr)
+		return code, stdout.String(), stderr.String()
+	}
+
+	t.Run("when counting a glob should print the pages of every match", func(t *testing.T) {
+		code, stdout, _ := pdfctl(nil, "count", filepath.Join(dir, "*.pdf"))
+
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "12\t"+filepath.Join(dir, "a.pdf")+"\n12\t"+filepath.Join(dir, "b.pdf")+"\n", stdout)
+	})
+
+	t.Run("when splitting files should write the results next to them", func(t *testing.T) {
+		code, stdout, stderr := pdfctl(nil, "split", "-ranges", "1-2", "-j", "2", filepath.Join(dir, "a.pdf"), filepath.Join(dir, "b.pdf"))
+
+		require.Equal(t, 0, code, stderr)
+		assert.ElementsMatch(t, []string{filepath.Join(dir, "split_a.pdf"), filepath.Join(dir, "split_b.pdf")},
+			strings.Fields(stdout))
+
+		code, stdout, _ = pdfctl(nil, "count", filepath.Join(dir, "split_a.pdf"))
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "2\n", stdout)
+	})
+
+	t.Run("when a split mode was registered should take its parameters as flags", func(t *testing.T) {
+		code, stdout, stderr := pdfctl(nil, "split", "-mode", "first", "-first-pages", "3", "-o", filepath.Join(dir, "first.pdf"), filepath.Join(dir, "a.pdf"))
+		require.Equal(t, 0, code, stderr)
+
+		code, stdout, _ = pdfctl(nil, "count", strings.TrimSpace(stdout))
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "3\n", stdout)
+
+		_, usage, _ := pdfctl(nil, "help")
+		assert.Contains(t, usage, "-mode ranges|fixed_range|remove_pages|first")
+	})
+
+	t.Run("when merging to stdout should write one document", func(t *testing.T) {
+		code, merged, stderr := pdfctl(nil, "merge", "-o", "-", filepath.Join(dir, "a.pdf"), filepath.Join(dir, "b.pdf"))
+		require.Equal(t, 0, code, stderr)
+
+		code, stdout, _ := pdfctl([]byte(merged), "count", "-")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "24\n", stdout)
+	})
+
+	t.Run("when an input is not a pdf should report it and fail", func(t *testing.T) {
+		code, _, stderr := pdfctl([]byte("not a pdf"), "count", "-")
+
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "pdfctl: -:")
+	})
+
+	t.Run("when the command line is invalid should exit with status 2", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"bogus"},
+			{"count"},
+			{"split", "-mode", "fixed_range", filepath.Join(dir, "a.pdf")},
+			{"split", "-ranges", "3-1", filepath.Join(dir, "a.pdf")},
+			{"merge", filepath.Join(dir, "a.pdf")},
+			{"compress", "-o", "-", filepath.Join(dir, "*.pdf")},
+		} {
+			code, _, _ := pdfctl(nil, args...)
+
+			assert.Equal(t, 2, code, args)
+		}
+	})
+
+	entries, _ := os.ReadDir(filepath.Join(dir, "workspaces"))
+	assert.Empty(t, entries, "every workspace should be removed")
+}
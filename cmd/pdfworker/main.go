@@ -0,0 +1,31 @@
+// Command pdfworker runs pdfcpu for the server in a process of its own, so a document
+// that crashes pdfcpu or never finishes only takes the worker down. It is started by
+// repository.WorkerPool and talks to it over file descriptors 3 and 4.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/bxcodec/go-clean-arch/internal/repository"
+)
+
+func main() {
+	maxCPU := flag.Duration("max-cpu", 0, "CPU time a single call may use, 0 for no limit")
+	maxMemory := flag.Int64("max-memory", 0, "bytes of memory the worker may use, 0 for no limit")
+	flag.Parse()
+
+	requests := os.NewFile(3, "requests")
+	responses := os.NewFile(4, "responses")
+	if requests == nil || responses == nil {
+		fmt.Fprintln(os.Stderr, "pdfworker: is started by the server, not on its own")
+		os.Exit(2)
+	}
+
+	limits := repository.WorkerLimits{CPUTime: *maxCPU, Memory: *maxMemory}
+	if err := repository.ServeWorker(requests, responses, &repository.PdfCpuApiImpl{}, limits); err != nil {
+		fmt.Fprintln(os.Stderr, "pdfworker:", err)
+		os.Exit(1)
+	}
+}
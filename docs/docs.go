@@ -15,40 +15,330 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/process/compress": {
+        "/audit/pdf": {
+            "get": {
+                "description": "Lists every document processed by /process/*, oldest first. Follow the X-Cursor header to get the next page.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "List the audit log of the PDF operations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Address of the client",
+                        "name": "caller",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operation, e.g. 'compress' or 'split'",
+                        "name": "operation",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "'succeeded', 'failed' or 'aborted'",
+                        "name": "outcome",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hex SHA-256 of the input document",
+                        "name": "sha256",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only records from this RFC 3339 time on",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only records before this RFC 3339 time",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "X-Cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Records per page (default 10)",
+                        "name": "num",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/domain.PdfAuditRecord"
+                            }
+                        },
+                        "headers": {
+                            "X-Cursor": {
+                                "type": "string",
+                                "description": "Cursor of the next page, empty on the last one"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to read the audit log",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/downloads/{token}": {
+            "get": {
+                "description": "Streams a result published with response = link, as long as its link has not expired",
+                "produces": [
+                    "application/pdf",
+                    " application/zip"
+                ],
+                "tags": [
+                    "PDF"
+                ],
+                "summary": "Download a stored result",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Signed download token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stored file",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown or invalid link",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "410": {
+                        "description": "Link expired",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{id}/events": {
+            "get": {
+                "description": "Streams Server-Sent Events with the progress of the job started with the same job_id. A job may be followed before it starts. The stream ends with a done event, or when the request times out, in which case the client reconnects and gets the current state.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "PDF"
+                ],
+                "summary": "Follow the progress of a PDF job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job id given as job_id, or returned in X-Job-ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "progress and done events",
+                        "schema": {
+                            "$ref": "#/definitions/domain.JobProgress"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid job id",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/process/collate": {
             "post": {
-                "description": "This API compresses the provided PDF file and returns the compressed version.",
+                "description": "Assembles one document of pages of several uploads, each named by its form field, in the order of the selector. With preset=interleave the uploads odd and even hold the fronts and the backs of sheets scanned one side at a time, the backs last to first, and are joined into a duplex document. Up to 16 files share 16 times the upload limit of compress.",
                 "consumes": [
                     "multipart/form-data"
                 ],
+                "produces": [
+                    "application/pdf"
+                ],
                 "tags": [
                     "PDF"
                 ],
-                "summary": "Compress a PDF file",
+                "summary": "Collate pages of several PDF files",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pages to take, e.g. a:1-3,b:1,a:4-,c:even for uploads a, b and c. A name alone takes every page. Required without preset.",
+                        "name": "selector",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to 'interleave' to join the uploads odd and even instead of using a selector",
+                        "name": "preset",
+                        "in": "formData"
+                    },
                     {
                         "type": "file",
-                        "description": "PDF file",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
+                        "description": "Fronts of the sheets with preset=interleave",
+                        "name": "odd",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Backs of the sheets, last to first, with preset=interleave",
+                        "name": "even",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "URL to download the document selected first from instead of uploading it",
+                        "name": "source_url",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Id of a complete resumable upload from /uploads to use as the document selected first instead of uploading it",
+                        "name": "upload_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to 'link' to receive a JSON download link instead of the file",
+                        "name": "response",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Id of 16 to 64 letters, digits, '-' or '_' to follow the progress at /jobs/{id}/events",
+                        "name": "job_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Answer right away with 202 and post the result, signed with HMAC-SHA256, to this URL when done",
+                        "name": "callback_url",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Compressed PDF file",
+                        "description": "Collated PDF file",
                         "schema": {
                             "type": "file"
+                        },
+                        "headers": {
+                            "X-Job-ID": {
+                                "type": "string",
+                                "description": "Id of the job"
+                            }
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted, the result goes to callback_url",
+                        "schema": {
+                            "$ref": "#/definitions/rest.JobAccepted"
                         }
                     },
                     "400": {
-                        "description": "File type is invalid",
+                        "description": "Invalid parameters, batch, a selected file is missing or source_url not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "upload_id is unknown or expired",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "job_id is already taken or upload_id is not complete",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "413": {
+                        "description": "A file exceeds the upload limit",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "415": {
+                        "description": "A file is not a PDF document",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "422": {
+                        "description": "A document crashed pdfcpu or ran out of its worker limits",
                         "schema": {
                             "$ref": "#/definitions/rest.ResponseError"
                         }
                     },
                     "500": {
-                        "description": "Failed to compress PDF",
+                        "description": "Failed to collate PDF",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "502": {
+                        "description": "source_url could not be fetched",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "503": {
+                        "description": "Too busy, retry after the Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds to wait before retrying"
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Processing took longer than the request timeout",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "507": {
+                        "description": "Not enough scratch space",
                         "schema": {
                             "$ref": "#/definitions/rest.ResponseError"
                         }
@@ -56,78 +346,1330 @@ const docTemplate = `{
                 }
             }
         },
-        "/process/split": {
+        "/process/compare": {
             "post": {
-                "description": "This API splits the provided PDF file based on the specified split mode and range",
+                "description": "Reports what changed from the original file to the revised one per page: pages added or removed, changed text, size or rotation, and the document information that differs. Pages are matched by their content, so inserted and removed pages don't show as changes of the pages after them. Both files share the upload limit of compress.",
                 "consumes": [
                     "multipart/form-data"
                 ],
                 "produces": [
-                    "application/pdf",
-                    " application/zip"
+                    "application/json",
+                    " application/zip",
+                    " application/gzip",
+                    " multipart/mixed"
                 ],
                 "tags": [
                     "PDF"
                 ],
-                "summary": "Split a PDF file",
+                "summary": "Compare two PDF files",
                 "parameters": [
                     {
                         "type": "file",
-                        "description": "PDF file to be split",
+                        "description": "Original PDF file",
                         "name": "file",
-                        "in": "formData",
-                        "required": true
+                        "in": "formData"
                     },
                     {
                         "type": "string",
-                        "description": "Split mode (e.g., 'ranges', 'fixed_range', 'remove_pages')",
-                        "name": "split_mode",
+                        "description": "URL to download the original from instead of uploading file",
+                        "name": "source_url",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Id of a complete resumable upload from /uploads to use as the original instead of uploading file",
+                        "name": "upload_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Revised PDF file",
+                        "name": "revised",
                         "in": "formData",
                         "required": true
                     },
+                    {
+                        "type": "boolean",
+                        "description": "Also return the revised file with the added and changed pages stamped, in a zip with the report as comparison.json",
+                        "name": "annotate",
+                        "in": "formData"
+                    },
                     {
                         "type": "string",
-                        "description": "Page ranges when split_mode = 'ranges' (e.g., '1','5','1-5')",
-                        "name": "ranges",
+                        "description": "Set to 'link' to receive a JSON download link instead of the file",
+                        "name": "response",
                         "in": "formData"
                     },
                     {
                         "type": "string",
-                        "description": "Remove pages when split_mode = 'remove_pages' (e.g., '1','5','1-5')",
-                        "name": "remove_page",
+                        "description": "Format of the result with annotate: 'zip' (default), 'tar.gz' or 'multipart' for multipart/mixed. Without it the Accept header decides.",
+                        "name": "format",
                         "in": "formData"
                     },
                     {
-                        "type": "integer",
-                        "description": "Fixed range when split_mode = fixed_range (e.g., '2', '1')",
-                        "name": "fixed_range",
+                        "type": "string",
+                        "description": "Id of 16 to 64 letters, digits, '-' or '_' to follow the progress at /jobs/{id}/events",
+                        "name": "job_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Answer right away with 202 and post the result, signed with HMAC-SHA256, to this URL when done",
+                        "name": "callback_url",
                         "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Split PDF file",
+                        "description": "Comparison, or a zip of it and the annotated revision",
                         "schema": {
-                            "type": "file"
+                            "$ref": "#/definitions/domain.PdfComparison"
+                        },
+                        "headers": {
+                            "X-Job-ID": {
+                                "type": "string",
+                                "description": "Id of the job"
+                            }
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted, the result goes to callback_url",
+                        "schema": {
+                            "$ref": "#/definitions/rest.JobAccepted"
                         }
                     },
                     "400": {
-                        "description": "Invalid input or file type",
+                        "description": "Invalid parameters, missing file or source_url not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "upload_id is unknown or expired",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "job_id is already taken or upload_id is not complete",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "413": {
+                        "description": "A file exceeds the upload limit",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "415": {
+                        "description": "A file is not a PDF document",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "422": {
+                        "description": "A document crashed pdfcpu or ran out of its worker limits",
                         "schema": {
                             "$ref": "#/definitions/rest.ResponseError"
                         }
                     },
                     "500": {
-                        "description": "Failed to split PDF",
+                        "description": "Failed to compare PDF",
                         "schema": {
                             "$ref": "#/definitions/rest.ResponseError"
                         }
-                    }
-                }
-            }
-        }
-    },
-    "definitions": {
+                    },
+                    "502": {
+                        "description": "source_url could not be fetched",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "503": {
+                        "description": "Too busy, retry after the Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds to wait before retrying"
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Processing took longer than the request timeout",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "507": {
+                        "description": "Not enough scratch space",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/process/compress": {
+            "post": {
+                "description": "This API compresses the provided PDF file and returns the compressed version.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/pdf",
+                    " application/zip",
+                    " application/gzip",
+                    " multipart/mixed"
+                ],
+                "tags": [
+                    "PDF"
+                ],
+                "summary": "Compress a PDF file",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "PDF file, or a zip of PDF files with batch=true",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "URL to download the document from instead of uploading file",
+                        "name": "source_url",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Id of a complete resumable upload from /uploads to process instead of uploading file",
+                        "name": "upload_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Compress every PDF of the uploaded zip and return a zip of results with a report.json",
+                        "name": "batch",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to 'link' to receive a JSON download link instead of the file",
+                        "name": "response",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format of a result with several files: 'zip' (default), 'tar.gz' or 'multipart' for multipart/mixed. Without it the Accept header decides.",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Id of 16 to 64 letters, digits, '-' or '_' to follow the progress at /jobs/{id}/events",
+                        "name": "job_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Answer right away with 202 and post the result, signed with HMAC-SHA256, to this URL when done",
+                        "name": "callback_url",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Compressed PDF file",
+                        "schema": {
+                            "type": "file"
+                        },
+                        "headers": {
+                            "X-Cache": {
+                                "type": "string",
+                                "description": "HIT or MISS when the result cache is enabled"
+                            },
+                            "X-Job-ID": {
+                                "type": "string",
+                                "description": "Id of the job"
+                            }
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted, the result goes to callback_url",
+                        "schema": {
+                            "$ref": "#/definitions/rest.JobAccepted"
+                        }
+                    },
+                    "400": {
+                        "description": "File is missing or source_url is not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "upload_id is unknown or expired",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "job_id is already taken or upload_id is not complete",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "413": {
+                        "description": "File exceeds the upload limit",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "415": {
+                        "description": "File is not a PDF document or zip archive",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "422": {
+                        "description": "The document crashed pdfcpu or ran out of its worker limits",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to compress PDF",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "502": {
+                        "description": "source_url could not be fetched",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "503": {
+                        "description": "Too busy, retry after the Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds to wait before retrying"
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Processing took longer than the request timeout",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "507": {
+                        "description": "Not enough scratch space",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/process/insert": {
+            "post": {
+                "description": "Inserts the selected pages of the source file into the target file before or after one of its pages, or at its end. Both files share the upload limit of compress.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "PDF"
+                ],
+                "summary": "Insert pages of a PDF file into another",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "PDF file to insert into",
+                        "name": "target",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "URL to download the target from instead of uploading it",
+                        "name": "source_url",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Id of a complete resumable upload from /uploads to use as the target instead of uploading it",
+                        "name": "upload_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "PDF file to take the pages from",
+                        "name": "source",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pages of the source to insert in order, e.g. 1-3,5, all when empty",
+                        "name": "source_pages",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Where to insert: 'before' or 'after' page, or 'end' (default)",
+                        "name": "position",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page of the target the position refers to, required with before and after",
+                        "name": "page",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to 'link' to receive a JSON download link instead of the file",
+                        "name": "response",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Id of 16 to 64 letters, digits, '-' or '_' to follow the progress at /jobs/{id}/events",
+                        "name": "job_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Answer right away with 202 and post the result, signed with HMAC-SHA256, to this URL when done",
+                        "name": "callback_url",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "PDF file with the pages inserted",
+                        "schema": {
+                            "type": "file"
+                        },
+                        "headers": {
+                            "X-Job-ID": {
+                                "type": "string",
+                                "description": "Id of the job"
+                            }
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted, the result goes to callback_url",
+                        "schema": {
+                            "$ref": "#/definitions/rest.JobAccepted"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters, batch, missing file or source_url not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "upload_id is unknown or expired",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "job_id is already taken or upload_id is not complete",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "413": {
+                        "description": "A file exceeds the upload limit",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "415": {
+                        "description": "A file is not a PDF document",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "422": {
+                        "description": "A document crashed pdfcpu or ran out of its worker limits",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to insert pages",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "502": {
+                        "description": "source_url could not be fetched",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "503": {
+                        "description": "Too busy, retry after the Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds to wait before retrying"
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Processing took longer than the request timeout",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "507": {
+                        "description": "Not enough scratch space",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/process/split": {
+            "post": {
+                "description": "This API splits the provided PDF file based on the specified split mode and range",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/pdf",
+                    " application/zip",
+                    " application/gzip",
+                    " multipart/mixed"
+                ],
+                "tags": [
+                    "PDF"
+                ],
+                "summary": "Split a PDF file",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "PDF file to be split, or a zip of PDF files with batch=true",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "URL to download the document from instead of uploading file",
+                        "name": "source_url",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Id of a complete resumable upload from /uploads to process instead of uploading file",
+                        "name": "upload_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Split mode (e.g., 'ranges', 'fixed_range', 'remove_pages')",
+                        "name": "split_mode",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page ranges when split_mode = 'ranges' (e.g., '1','5','1-5')",
+                        "name": "ranges",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Remove pages when split_mode = 'remove_pages' (e.g., '1','5','1-5')",
+                        "name": "remove_page",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Fixed range when split_mode = fixed_range (e.g., '2', '1')",
+                        "name": "fixed_range",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the parts in the zip of split_mode = fixed_range, may use {base}, {index}, {from}, {to} and {bookmark}, numbers may be padded as in {index:3} (default 'split_part_{index}.pdf')",
+                        "name": "part_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the zip of split_mode = fixed_range, may use {base} (default 'split_\u003cfile name\u003e.zip')",
+                        "name": "zip_name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Split every PDF of the uploaded zip and return a zip of results with a report.json",
+                        "name": "batch",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to 'link' to receive a JSON download link instead of the file",
+                        "name": "response",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format of a result with several files: 'zip' (default), 'tar.gz' or 'multipart' for multipart/mixed. Without it the Accept header decides.",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Id of 16 to 64 letters, digits, '-' or '_' to follow the progress at /jobs/{id}/events",
+                        "name": "job_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Answer right away with 202 and post the result, signed with HMAC-SHA256, to this URL when done",
+                        "name": "callback_url",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Split PDF file, or the parts with a manifest.json as a zip, tar.gz or multipart/mixed",
+                        "schema": {
+                            "type": "file"
+                        },
+                        "headers": {
+                            "X-Cache": {
+                                "type": "string",
+                                "description": "HIT or MISS when the result cache is enabled"
+                            },
+                            "X-Job-ID": {
+                                "type": "string",
+                                "description": "Id of the job"
+                            }
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted, the result goes to callback_url",
+                        "schema": {
+                            "$ref": "#/definitions/rest.JobAccepted"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input, missing file or source_url not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "upload_id is unknown or expired",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "job_id is already taken or upload_id is not complete",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "413": {
+                        "description": "File exceeds the upload limit",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "415": {
+                        "description": "File is not a PDF document or zip archive",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "422": {
+                        "description": "The document crashed pdfcpu or ran out of its worker limits",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to split PDF",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "502": {
+                        "description": "source_url could not be fetched",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "503": {
+                        "description": "Too busy, retry after the Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds to wait before retrying"
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Processing took longer than the request timeout",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "507": {
+                        "description": "Not enough scratch space",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/process/{operation}": {
+            "post": {
+                "description": "Runs a processor of the operation registry, such as compress, on the provided PDF file. Processors share the upload limit of compress, their parameters are listed in this document.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/pdf",
+                    " application/zip",
+                    " application/gzip",
+                    " multipart/mixed"
+                ],
+                "tags": [
+                    "PDF"
+                ],
+                "summary": "Run a registered operation on a PDF file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of the operation",
+                        "name": "operation",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "PDF file, or a zip of PDF files with batch=true",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "URL to download the document from instead of uploading file",
+                        "name": "source_url",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Id of a complete resumable upload from /uploads to process instead of uploading file",
+                        "name": "upload_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Process every PDF of the uploaded zip and return a zip of results with a report.json",
+                        "name": "batch",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to 'link' to receive a JSON download link instead of the file",
+                        "name": "response",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Format of a result with several files: 'zip' (default), 'tar.gz' or 'multipart' for multipart/mixed. Without it the Accept header decides.",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Id of 16 to 64 letters, digits, '-' or '_' to follow the progress at /jobs/{id}/events",
+                        "name": "job_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Answer right away with 202 and post the result, signed with HMAC-SHA256, to this URL when done",
+                        "name": "callback_url",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Processed PDF file",
+                        "schema": {
+                            "type": "file"
+                        },
+                        "headers": {
+                            "X-Cache": {
+                                "type": "string",
+                                "description": "HIT or MISS when the result cache is enabled"
+                            },
+                            "X-Job-ID": {
+                                "type": "string",
+                                "description": "Id of the job"
+                            }
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted, the result goes to callback_url",
+                        "schema": {
+                            "$ref": "#/definitions/rest.JobAccepted"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid parameters, missing file or source_url not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown operation, or upload_id is unknown or expired",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "job_id is already taken or upload_id is not complete",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "413": {
+                        "description": "File exceeds the upload limit",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "415": {
+                        "description": "File is not a PDF document or zip archive",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "422": {
+                        "description": "The document crashed pdfcpu or ran out of its worker limits",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to process PDF",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "502": {
+                        "description": "source_url could not be fetched",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "503": {
+                        "description": "Too busy, retry after the Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds to wait before retrying"
+                            }
+                        }
+                    },
+                    "504": {
+                        "description": "Processing took longer than the request timeout",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "507": {
+                        "description": "Not enough scratch space",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    }
+                }
+            }
+        },
+        "/uploads": {
+            "post": {
+                "description": "Registers an upload of Upload-Length bytes. The chunks go to the returned Location with PATCH, and once complete its id may be sent as upload_id to the /process endpoints.",
+                "tags": [
+                    "Upload"
+                ],
+                "summary": "Create a resumable upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "1.0.0",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Size of the document in bytes",
+                        "name": "Upload-Length",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated keys with base64 values, e.g. filename and filetype",
+                        "name": "Upload-Metadata",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the upload"
+                            },
+                            "Upload-Expires": {
+                                "type": "string",
+                                "description": "When the upload is removed unless written to"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Upload-Length or Upload-Metadata is invalid",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "412": {
+                        "description": "Unsupported Tus-Resumable version",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "413": {
+                        "description": "Upload-Length exceeds Tus-Max-Size",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    }
+                }
+            },
+            "options": {
+                "description": "Lists the tus version, extensions, size limit and checksum algorithms of the upload endpoints",
+                "tags": [
+                    "Upload"
+                ],
+                "summary": "Discover the tus protocol support",
+                "responses": {
+                    "204": {
+                        "description": "Supported",
+                        "headers": {
+                            "Tus-Checksum-Algorithm": {
+                                "type": "string",
+                                "description": "Supported checksum algorithms"
+                            },
+                            "Tus-Extension": {
+                                "type": "string",
+                                "description": "Supported extensions"
+                            },
+                            "Tus-Max-Size": {
+                                "type": "integer",
+                                "description": "Largest upload accepted"
+                            },
+                            "Tus-Version": {
+                                "type": "string",
+                                "description": "Supported versions"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/uploads/{id}": {
+            "delete": {
+                "description": "Removes an upload that is no longer needed, complete or not",
+                "tags": [
+                    "Upload"
+                ],
+                "summary": "Delete a resumable upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "1.0.0",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upload id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Deleted"
+                    },
+                    "404": {
+                        "description": "Unknown or expired upload",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "423": {
+                        "description": "Another request is writing to the upload",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    }
+                }
+            },
+            "head": {
+                "description": "Tells where to resume an upload",
+                "tags": [
+                    "Upload"
+                ],
+                "summary": "Get the offset of a resumable upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "1.0.0",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upload id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Upload found",
+                        "headers": {
+                            "Upload-Length": {
+                                "type": "integer",
+                                "description": "Size of the document in bytes"
+                            },
+                            "Upload-Offset": {
+                                "type": "integer",
+                                "description": "Bytes received so far"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown or expired upload"
+                    }
+                }
+            },
+            "patch": {
+                "description": "Appends the body at Upload-Offset, which has to be the current offset. With Upload-Checksum the chunk is dropped unless it matches.",
+                "consumes": [
+                    "application/offset+octet-stream"
+                ],
+                "tags": [
+                    "Upload"
+                ],
+                "summary": "Send a chunk of a resumable upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "1.0.0",
+                        "name": "Tus-Resumable",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset the chunk starts at",
+                        "name": "Upload-Offset",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Algorithm and base64 digest of the chunk, e.g. 'sha1 Kq5sNclPz7QV2+lfQIuc6R7oRu0='",
+                        "name": "Upload-Checksum",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Upload id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Chunk stored",
+                        "headers": {
+                            "Upload-Offset": {
+                                "type": "integer",
+                                "description": "Bytes received so far"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Upload-Offset or Upload-Checksum",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown or expired upload",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "409": {
+                        "description": "Upload-Offset is not the offset of the upload",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "413": {
+                        "description": "Chunk runs past Upload-Length",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/offset+octet-stream",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "423": {
+                        "description": "Another request is writing to the upload",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    },
+                    "460": {
+                        "description": "Chunk does not match Upload-Checksum",
+                        "schema": {
+                            "$ref": "#/definitions/rest.ResponseError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "domain.JobProgress": {
+            "type": "object",
+            "properties": {
+                "done": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "pages": {
+                    "type": "integer"
+                },
+                "percent": {
+                    "type": "integer"
+                },
+                "step": {
+                    "type": "string"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.PdfAuditRecord": {
+            "type": "object",
+            "properties": {
+                "caller": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "input_sha256": {
+                    "type": "string"
+                },
+                "input_size": {
+                    "type": "integer"
+                },
+                "operation": {
+                    "type": "string"
+                },
+                "outcome": {
+                    "type": "string"
+                },
+                "output_size": {
+                    "type": "integer"
+                },
+                "page_count": {
+                    "type": "integer"
+                },
+                "params": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "domain.PdfComparison": {
+            "type": "object",
+            "properties": {
+                "identical": {
+                    "type": "boolean"
+                },
+                "metadata": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.PdfMetadataDiff"
+                    }
+                },
+                "original": {
+                    "type": "string"
+                },
+                "original_pages": {
+                    "type": "integer"
+                },
+                "pages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.PdfPageDiff"
+                    }
+                },
+                "revised": {
+                    "type": "string"
+                },
+                "revised_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.PdfMetadataDiff": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "original": {
+                    "type": "string"
+                },
+                "revised": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.PdfPageDiff": {
+            "type": "object",
+            "properties": {
+                "change": {
+                    "type": "string"
+                },
+                "differences": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "original": {
+                    "$ref": "#/definitions/domain.PdfPageSide"
+                },
+                "revised": {
+                    "$ref": "#/definitions/domain.PdfPageSide"
+                },
+                "text_added": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "text_removed": {
+                    "description": "TextRemoved and TextAdded are the lines only the original or the revision has",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "domain.PdfPageSide": {
+            "type": "object",
+            "properties": {
+                "height": {
+                    "type": "number"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "rotation": {
+                    "type": "integer"
+                },
+                "width": {
+                    "type": "number"
+                }
+            }
+        },
+        "rest.JobAccepted": {
+            "type": "object",
+            "properties": {
+                "events_url": {
+                    "type": "string"
+                },
+                "job_id": {
+                    "type": "string"
+                }
+            }
+        },
         "rest.ResponseError": {
             "type": "object",
             "properties": {
@@ -0,0 +1,88 @@
+package domain
+
+// Kinds of streams reported in PdfAnalysis.Streams
+const (
+	StreamImage   = "image"
+	StreamFont    = "font"
+	StreamContent = "content"
+	StreamForm    = "form"
+	StreamMeta    = "metadata"
+	// StreamStructure are the object and cross reference streams holding the file together
+	StreamStructure = "structure"
+	StreamOther     = "other"
+)
+
+// Settings a PdfSaving reports on. Compress is what /process/compress does, flate and
+// jpeg are settings of the application exporting the document.
+const (
+	// SettingCompress optimizes the document: duplicate resources are shared and unused
+	// objects dropped
+	SettingCompress = "compress"
+	// SettingFlate compresses the streams stored without any filter
+	SettingFlate = "flate"
+	// SettingJpeg stores the images compressed without loss as JPEG
+	SettingJpeg = "jpeg"
+	// SettingNone is recommended when no setting saves enough to be worth it
+	SettingNone = "none"
+)
+
+// PdfAnalysis is how a document is built and what would make it smaller. Sizes are in
+// bytes as stored in the file.
+type PdfAnalysis struct {
+	Name     string `json:"name"`
+	FileSize int64  `json:"file_size"`
+	Pages    int    `json:"pages"`
+	Objects  int    `json:"objects"`
+	// Streams sums the streams by kind, one of the Stream constants
+	Streams       map[string]PdfStreamStats `json:"streams"`
+	Duplicates    []PdfDuplicate            `json:"duplicates"`
+	Unused        PdfUnused                 `json:"unused"`
+	LargestImages []PdfImageStats           `json:"largest_images"`
+	// Savings lists what every setting would save, the most first
+	Savings []PdfSaving `json:"savings"`
+	// Recommendation is the setting saving the most, or SettingNone
+	Recommendation string `json:"recommendation"`
+}
+
+// PdfStreamStats counts streams and their bytes
+type PdfStreamStats struct {
+	Count int   `json:"count"`
+	Bytes int64 `json:"bytes"`
+}
+
+// PdfDuplicate is a resource stored more than once with the same bytes. Wasted is
+// what all but one of the copies take.
+type PdfDuplicate struct {
+	Kind    string `json:"kind"`
+	Objects []int  `json:"objects"`
+	Bytes   int64  `json:"bytes"`
+	Wasted  int64  `json:"wasted"`
+}
+
+// PdfUnused are the objects nothing in the document refers to. Bytes counts their
+// streams and, roughly, the objects themselves.
+type PdfUnused struct {
+	Objects []int `json:"objects"`
+	Bytes   int64 `json:"bytes"`
+}
+
+// PdfImageStats describes an embedded image and the pages that draw it directly
+type PdfImageStats struct {
+	Object           int    `json:"object"`
+	Width            int    `json:"width"`
+	Height           int    `json:"height"`
+	BitsPerComponent int    `json:"bits_per_component"`
+	ColorSpace       string `json:"color_space"`
+	Filter           string `json:"filter"`
+	Bytes            int64  `json:"bytes"`
+	Pages            []int  `json:"pages"`
+}
+
+// PdfSaving is what a setting, one of the Setting constants, would save
+type PdfSaving struct {
+	Setting string `json:"setting"`
+	Bytes   int64  `json:"bytes"`
+	// Estimated is set when Bytes leaves out images, beyond the largest ones or too
+	// large to decode
+	Estimated bool `json:"estimated"`
+}
@@ -0,0 +1,39 @@
+package domain
+
+// PageRef is a page of one of the documents a new one is assembled from, by the index
+// of the document and the number of the page
+type PageRef struct {
+	Document int
+	Page     int
+}
+
+// Where inserted pages go, reported in InsertPosition.Where
+const (
+	InsertBefore = "before"
+	InsertAfter  = "after"
+	InsertEnd    = "end"
+)
+
+// InsertPosition is where pages are inserted into a document: before or after Page,
+// or at the end, which needs no Page
+type InsertPosition struct {
+	Where string
+	Page  int
+}
+
+// Pages of a run a PageSelection keeps, reported in PageSelection.Parity
+const (
+	PagesOdd  = "odd"
+	PagesEven = "even"
+)
+
+// PageSelection is a run of pages of one of the named documents a collation takes
+// pages from
+type PageSelection struct {
+	Document string
+	First    int
+	// Last page of the run, zero runs to the end of the document
+	Last int
+	// Parity keeps only the odd or the even pages of the run, empty keeps them all
+	Parity string
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// Outcomes of a document in the audit log, reported in PdfAuditRecord.Outcome
+const (
+	AuditSucceeded = "succeeded"
+	AuditFailed    = "failed"
+	// AuditAborted is a result that was produced but not delivered to the end, e.g.
+	// because the client went away
+	AuditAborted = "aborted"
+)
+
+// PdfAuditCall is who asked for an operation and how
+type PdfAuditCall struct {
+	// Caller is the address of the client
+	Caller    string
+	Operation string
+	Params    map[string]string
+}
+
+// PdfAuditRecord is a document that went through a PDF operation. OutputSize counts
+// the bytes of the result that were delivered, PageCount is zero when the input
+// could not be read as a PDF. CreatedAt is when the document was done with.
+type PdfAuditRecord struct {
+	ID          int64             `json:"id"`
+	Caller      string            `json:"caller"`
+	Operation   string            `json:"operation"`
+	Params      map[string]string `json:"params"`
+	FileName    string            `json:"file_name"`
+	InputSHA256 string            `json:"input_sha256"`
+	InputSize   int64             `json:"input_size"`
+	PageCount   int               `json:"page_count"`
+	OutputSize  int64             `json:"output_size"`
+	DurationMs  int64             `json:"duration_ms"`
+	Outcome     string            `json:"outcome"`
+	Error       string            `json:"error,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// PdfAuditFilter narrows a listing of the audit log, zero values match everything.
+// From is inclusive and To exclusive.
+type PdfAuditFilter struct {
+	Caller      string
+	Operation   string
+	Outcome     string
+	InputSHA256 string
+	From        time.Time
+	To          time.Time
+}
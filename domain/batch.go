@@ -0,0 +1,19 @@
+package domain
+
+// BatchReport is written as report.json into the result of a batch
+type BatchReport struct {
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Files     []BatchFileReport `json:"files"`
+}
+
+// BatchFileReport is the outcome for one file of a batch. Output is the path of the
+// result inside the returned zip.
+type BatchFileReport struct {
+	Input      string `json:"input"`
+	Output     string `json:"output,omitempty"`
+	Success    bool   `json:"success"`
+	Error      string `json:"error,omitempty"`
+	InputSize  int64  `json:"input_size"`
+	OutputSize int64  `json:"output_size"`
+}
@@ -0,0 +1,74 @@
+package domain
+
+// PdfDocumentInfo describes the pages and metadata of a document for comparisons
+type PdfDocumentInfo struct {
+	Pages    []PdfPageInfo     `json:"pages"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// PdfPageInfo describes a page. Width and Height are the size of the crop box in
+// points, Text lists the lines the page draws and ContentHash is the hex SHA-256 of
+// its content streams.
+type PdfPageInfo struct {
+	Width       float64  `json:"width"`
+	Height      float64  `json:"height"`
+	Rotation    int      `json:"rotation"`
+	Text        []string `json:"text"`
+	ContentHash string   `json:"content_hash"`
+}
+
+// Changes of a page reported in PdfPageDiff.Change
+const (
+	PageAdded   = "added"
+	PageRemoved = "removed"
+	PageChanged = "changed"
+)
+
+// Differences of a changed page reported in PdfPageDiff.Differences
+const (
+	DiffText = "text"
+	// DiffContent is a change of the graphics or images of a page whose text is the same
+	DiffContent  = "content"
+	DiffSize     = "size"
+	DiffRotation = "rotation"
+)
+
+// PdfComparison is what changed from an original document to its revision. Pages
+// lists the pages that differ in reading order, a removed page comes where it was.
+type PdfComparison struct {
+	Original      string            `json:"original"`
+	Revised       string            `json:"revised"`
+	OriginalPages int               `json:"original_pages"`
+	RevisedPages  int               `json:"revised_pages"`
+	Identical     bool              `json:"identical"`
+	Pages         []PdfPageDiff     `json:"pages"`
+	Metadata      []PdfMetadataDiff `json:"metadata"`
+}
+
+// PdfPageDiff is a page that was added, removed or changed. Original is nil for an
+// added page and Revised for a removed one.
+type PdfPageDiff struct {
+	Change      string       `json:"change"`
+	Original    *PdfPageSide `json:"original,omitempty"`
+	Revised     *PdfPageSide `json:"revised,omitempty"`
+	Differences []string     `json:"differences,omitempty"`
+	// TextRemoved and TextAdded are the lines only the original or the revision has
+	TextRemoved []string `json:"text_removed,omitempty"`
+	TextAdded   []string `json:"text_added,omitempty"`
+}
+
+// PdfPageSide is a page of one of the compared documents
+type PdfPageSide struct {
+	Page     int     `json:"page"`
+	Width    float64 `json:"width"`
+	Height   float64 `json:"height"`
+	Rotation int     `json:"rotation"`
+}
+
+// PdfMetadataDiff is an entry of the document information that differs, empty on the
+// side that doesn't have it
+type PdfMetadataDiff struct {
+	Key      string `json:"key"`
+	Original string `json:"original"`
+	Revised  string `json:"revised"`
+}
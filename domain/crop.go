@@ -0,0 +1,52 @@
+package domain
+
+// Page boxes a crop can set, reported in CropOptions.Box
+const (
+	BoxCrop  = "crop"
+	BoxTrim  = "trim"
+	BoxBleed = "bleed"
+)
+
+// PdfRect is a rectangle of user space in points, from its lower left to its upper
+// right corner
+type PdfRect struct {
+	LLX float64 `json:"llx"`
+	LLY float64 `json:"lly"`
+	URX float64 `json:"urx"`
+	URY float64 `json:"ury"`
+}
+
+// Empty reports whether r holds nothing
+func (r PdfRect) Empty() bool {
+	return r.URX <= r.LLX || r.URY <= r.LLY
+}
+
+// PdfPageBoxes are the boxes in effect on a page. A trim or bleed box that isn't set
+// is the crop box, which in turn defaults to the media box.
+type PdfPageBoxes struct {
+	Media    PdfRect `json:"media"`
+	Crop     PdfRect `json:"crop"`
+	Trim     PdfRect `json:"trim"`
+	Bleed    PdfRect `json:"bleed"`
+	Rotation int     `json:"rotation"`
+}
+
+// PdfMargins are distances in points from the edges of a page as it is displayed,
+// that is after its rotation
+type PdfMargins struct {
+	Top    float64
+	Right  float64
+	Bottom float64
+	Left   float64
+}
+
+// CropOptions tells which box of which pages a crop sets. Without Auto the box is the
+// crop box of the page less Margins, with it the bounds of what the page draws plus
+// Margins, still within the crop box.
+type CropOptions struct {
+	Box string
+	// Pages to set the box on, every page when empty
+	Pages   []int
+	Auto    bool
+	Margins PdfMargins
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// DownloadLink is a signed, expiring reference to a stored result
+type DownloadLink struct {
+	URL       string    `json:"url"`
+	Token     string    `json:"-"`
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
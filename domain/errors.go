@@ -1,6 +1,9 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
 	// ErrInternalServerError will throw if any the Internal Server Error happen
@@ -11,4 +14,45 @@ var (
 	ErrConflict = errors.New("your Item already exist")
 	// ErrBadParamInput will throw if the given request-body or params is not valid
 	ErrBadParamInput = errors.New("given Param is not valid")
+	// ErrLinkExpired will throw if a download link is used after it expired
+	ErrLinkExpired = errors.New("your download link has expired")
+	// ErrStorageFull will throw if there is no scratch space left to process the request
+	ErrStorageFull = errors.New("not enough scratch space, try again later")
+	// ErrMissingFile will throw if a request comes without the document to process
+	ErrMissingFile = errors.New("a PDF file is required")
+	// ErrUnsupportedMediaType will throw if an upload is not a PDF document
+	ErrUnsupportedMediaType = errors.New("only PDF documents are supported")
+	// ErrFileTooLarge will throw if an upload exceeds the size limit of the endpoint
+	ErrFileTooLarge = errors.New("the uploaded file is too large")
+	// ErrTooManyFiles will throw if a batch holds more files than allowed
+	ErrTooManyFiles = errors.New("the archive holds too many files")
+	// ErrForbiddenSource will throw if a source url points somewhere the server may not fetch from
+	ErrForbiddenSource = errors.New("the source url is not allowed")
+	// ErrSourceUnavailable will throw if a source url could not be downloaded
+	ErrSourceUnavailable = errors.New("the source url could not be fetched")
+	// ErrOverloaded will throw if the server is too busy to take more work
+	ErrOverloaded = errors.New("the server is busy, try again later")
+	// ErrOffsetMismatch will throw if a chunk does not start where the upload stopped
+	ErrOffsetMismatch = errors.New("the chunk does not start at the offset of the upload")
+	// ErrChecksumMismatch will throw if a chunk does not match the checksum sent with it
+	ErrChecksumMismatch = errors.New("the chunk does not match its checksum")
+	// ErrUploadLocked will throw if another request is writing to the same upload
+	ErrUploadLocked = errors.New("the upload is being written by another request")
+	// ErrUploadIncomplete will throw if an upload is processed before all of it arrived
+	ErrUploadIncomplete = errors.New("the upload is not complete")
+	// ErrUnprocessable will throw if a document crashed pdfcpu or ran out of the resources it may use
+	ErrUnprocessable = errors.New("the document could not be processed")
 )
+
+// OverloadedError is an ErrOverloaded that tells the client when to come back
+type OverloadedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *OverloadedError) Error() string {
+	return ErrOverloaded.Error()
+}
+
+func (e *OverloadedError) Is(target error) bool {
+	return target == ErrOverloaded
+}
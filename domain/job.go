@@ -0,0 +1,50 @@
+package domain
+
+import "context"
+
+// Steps a PDF job goes through, reported in JobProgress.Step
+const (
+	StepPending    = "pending"
+	StepQueued     = "queued"
+	StepCached     = "cached"
+	StepOptimizing = "optimizing"
+	StepSplitting  = "splitting"
+	StepMerging    = "merging"
+	StepComparing  = "comparing"
+	StepAnalyzing  = "analyzing"
+	StepZipping    = "zipping"
+	StepBatch      = "batch"
+	StepDone       = "done"
+	StepFailed     = "failed"
+)
+
+// JobProgress is a snapshot of a PDF job. Pages and Percent count the work of the
+// current step, TotalPages is what the step has to get through.
+type JobProgress struct {
+	JobID      string `json:"job_id"`
+	Step       string `json:"step"`
+	Pages      int    `json:"pages"`
+	TotalPages int    `json:"total_pages"`
+	Percent    int    `json:"percent"`
+	Done       bool   `json:"done"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ProgressFunc receives the progress reported along a context
+type ProgressFunc func(step string, done, total int)
+
+type progressKey struct{}
+
+// WithProgress returns a context whose progress goes to report. A nil report mutes the
+// progress of everything run with the context.
+func WithProgress(ctx context.Context, report ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, report)
+}
+
+// ReportProgress tells whoever follows ctx that done out of total units of step are
+// finished. It does nothing when nobody follows.
+func ReportProgress(ctx context.Context, step string, done, total int) {
+	if report, ok := ctx.Value(progressKey{}).(ProgressFunc); ok && report != nil {
+		report(step, done, total)
+	}
+}
@@ -1,13 +1,66 @@
 package domain
 
+import (
+	"context"
+	"io"
+)
+
+// Cache statuses of a PdfFile
+const (
+	CacheHit  = "HIT"
+	CacheMiss = "MISS"
+)
+
+// PdfFile is a processed document. Content is streamed from disk and must be
+// closed by the caller; Size is -1 when the length is not known upfront.
+// CacheStatus is empty unless the result went through a cache.
 type PdfFile struct {
-	Name    string
-	Content []byte
+	Name        string
+	Content     io.ReadCloser
+	Size        int64
+	CacheStatus string
 }
 
+// SplitPdfFile picks the split mode, the parameters of the mode are read by the
+// operation registered under it
 type SplitPdfFile struct {
-	SplitMode   string `form:"split_mode" validate:"required"`
-	Ranges      string `form:"ranges"`
-	FixedRange  int    `form:"fixed_range"`
-	RemovePages string `form:"remove_page"`
+	SplitMode string `form:"split_mode" validate:"required"`
+}
+
+// SplitNaming names the parts of a split and the zip holding them. Empty templates
+// keep the default names.
+type SplitNaming struct {
+	// Part may use {base}, {index}, {from}, {to} and {bookmark}
+	Part string
+	// Archive may use {base}
+	Archive string
+}
+
+// SplitManifest describes the parts of a split zip, it is added as manifest.json
+type SplitManifest struct {
+	Parts []SplitManifestPart `json:"parts"`
 }
+
+// SplitManifestPart is one part of a split. Pages lists the pages of the source it
+// holds, e.g. "1-5".
+type SplitManifestPart struct {
+	FileName  string `json:"file_name"`
+	Index     int    `json:"index"`
+	From      int    `json:"from"`
+	To        int    `json:"to"`
+	Pages     string `json:"pages"`
+	PageCount int    `json:"page_count"`
+	Size      int64  `json:"size"`
+	SHA256    string `json:"sha256"`
+}
+
+// Bookmark is an outline entry of a document, flattened in document order. Level is
+// zero for top level entries.
+type Bookmark struct {
+	Title string
+	Page  int
+	Level int
+}
+
+// PdfProcessor applies one operation to a document, e.g. to every file of a batch
+type PdfProcessor func(ctx context.Context, fileName string, file io.ReadSeeker) (PdfFile, error)
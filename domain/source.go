@@ -0,0 +1,18 @@
+package domain
+
+import "io"
+
+// SpooledContent is a document on disk that can be read from any position, as zip
+// archives need. Closing it removes it.
+type SpooledContent interface {
+	io.ReadSeekCloser
+	io.ReaderAt
+}
+
+// SourceFile is a document downloaded from a source url
+type SourceFile struct {
+	Name        string
+	ContentType string
+	Content     SpooledContent
+	Size        int64
+}
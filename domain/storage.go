@@ -0,0 +1,7 @@
+package domain
+
+// DiskUsage summarises the scratch space held on disk
+type DiskUsage struct {
+	Entries int   `json:"entries"`
+	Bytes   int64 `json:"bytes"`
+}
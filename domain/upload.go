@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// ResumableUpload is a document sent in chunks, so a client can resume where it
+// stopped after losing its connection
+type ResumableUpload struct {
+	ID     string
+	Length int64
+	Offset int64
+	// Metadata is what the client sent along, e.g. filename and filetype
+	Metadata  map[string]string
+	UpdatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// Complete reports whether every byte of the upload arrived
+func (u ResumableUpload) Complete() bool {
+	return u.Offset == u.Length
+}
+
+// Checksum is the digest of a chunk computed by the client with Algorithm
+type Checksum struct {
+	Algorithm string
+	Sum       []byte
+}
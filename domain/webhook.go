@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// Outcomes of a job reported in JobResult.Status
+const (
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// JobResult is posted to the callback url of a job once it finished
+type JobResult struct {
+	JobID      string        `json:"job_id"`
+	Status     string        `json:"status"`
+	Error      string        `json:"error,omitempty"`
+	Name       string        `json:"name,omitempty"`
+	Size       int64         `json:"size,omitempty"`
+	Checksum   string        `json:"checksum,omitempty"`
+	Download   *DownloadLink `json:"download,omitempty"`
+	FinishedAt time.Time     `json:"finished_at"`
+}
+
+// WebhookDeadLetter is a webhook that could not be delivered after every attempt
+type WebhookDeadLetter struct {
+	ID          int64     `json:"id"`
+	JobID       string    `json:"job_id"`
+	CallbackURL string    `json:"callback_url"`
+	Payload     string    `json:"payload"`
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"last_error"`
+	CreatedAt   time.Time `json:"created_at"`
+}
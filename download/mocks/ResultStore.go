@@ -0,0 +1,95 @@
+// Code generated by mockery v2.50.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ResultStore is an autogenerated mock type for the ResultStore type
+type ResultStore struct {
+	mock.Mock
+}
+
+// Get provides a mock function with given fields: ctx, key
+func (_m *ResultStore) Get(ctx context.Context, key string) (io.ReadCloser, int64, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 io.ReadCloser
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (io.ReadCloser, int64, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) io.ReadCloser); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) int64); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, key)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Put provides a mock function with given fields: ctx, key, content, size
+func (_m *ResultStore) Put(ctx context.Context, key string, content io.Reader, size int64) (int64, error) {
+	ret := _m.Called(ctx, key, content, size)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Put")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader, int64) (int64, error)); ok {
+		return rf(ctx, key, content, size)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader, int64) int64); ok {
+		r0 = rf(ctx, key, content, size)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.Reader, int64) error); ok {
+		r1 = rf(ctx, key, content, size)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewResultStore creates a new instance of ResultStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewResultStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ResultStore {
+	mock := &ResultStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,133 @@
+package download
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// ResultStore represent the storage backend for processed documents
+//
+//go:generate mockery --name ResultStore
+type ResultStore interface {
+	// Put stores content under key. size is -1 when unknown; the stored size is returned.
+	Put(ctx context.Context, key string, content io.Reader, size int64) (int64, error)
+	Get(ctx context.Context, key string) (io.ReadCloser, int64, error)
+}
+
+type Service struct {
+	store  ResultStore
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewService will create a download service whose links are signed with secret and valid for ttl
+func NewService(store ResultStore, secret []byte, ttl time.Duration) *Service {
+	return &Service{
+		store:  store,
+		secret: secret,
+		ttl:    ttl,
+	}
+}
+
+// claims is what a token vouches for. It is kept short since it ends up in a URL.
+type claims struct {
+	Key     string `json:"k"`
+	Name    string `json:"n"`
+	Expires int64  `json:"e"`
+}
+
+// Publish stores the content of file and returns a link to it. The caller still owns
+// file.Content and has to close it.
+func (s *Service) Publish(ctx context.Context, file domain.PdfFile) (domain.DownloadLink, error) {
+	key := uuid.NewString()
+	size, err := s.store.Put(ctx, key, file.Content, file.Size)
+	if err != nil {
+		return domain.DownloadLink{}, fmt.Errorf("failed to store result: %w", err)
+	}
+
+	expiresAt := time.Now().Add(s.ttl).Truncate(time.Second)
+	token, err := s.sign(claims{Key: key, Name: file.Name, Expires: expiresAt.Unix()})
+	if err != nil {
+		return domain.DownloadLink{}, err
+	}
+
+	return domain.DownloadLink{
+		Token:     token,
+		Name:      file.Name,
+		Size:      size,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// Open verifies token and returns the stored file. A token that was tampered with is
+// reported as domain.ErrNotFound so that it does not reveal anything about the store.
+func (s *Service) Open(ctx context.Context, token string) (domain.PdfFile, error) {
+	c, err := s.verify(token)
+	if err != nil {
+		return domain.PdfFile{}, err
+	}
+	if time.Now().Unix() > c.Expires {
+		return domain.PdfFile{}, domain.ErrLinkExpired
+	}
+
+	content, size, err := s.store.Get(ctx, c.Key)
+	if err != nil {
+		return domain.PdfFile{}, err
+	}
+
+	return domain.PdfFile{
+		Name:    c.Name,
+		Content: content,
+		Size:    size,
+	}, nil
+}
+
+func (s *Service) sign(c claims) (string, error) {
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.mac(encoded)), nil
+}
+
+func (s *Service) verify(token string) (claims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return claims{}, domain.ErrNotFound
+	}
+
+	expected, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, s.mac(encoded)) {
+		return claims{}, domain.ErrNotFound
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return claims{}, domain.ErrNotFound
+	}
+
+	var c claims
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return claims{}, domain.ErrNotFound
+	}
+	return c, nil
+}
+
+func (s *Service) mac(encoded string) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(encoded))
+	return h.Sum(nil)
+}
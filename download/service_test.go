@@ -0,0 +1,105 @@
+package download_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/download"
+	"github.com/bxcodec/go-clean-arch/download/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublish(t *testing.T) {
+	t.Run("when store success should return a signed link", func(t *testing.T) {
+		mockStore := new(mocks.ResultStore)
+		service := download.NewService(mockStore, []byte("secret"), time.Hour)
+
+		mockStore.On("Put", mock.Anything, mock.AnythingOfType("string"), mock.Anything, int64(-1)).Return(int64(3), nil).Once()
+
+		actual, err := service.Publish(context.TODO(), domain.PdfFile{
+			Name:    "split_test.pdf.zip",
+			Content: io.NopCloser(bytes.NewReader([]byte{1, 2, 3})),
+			Size:    -1,
+		})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, actual.Token)
+		assert.Equal(t, "split_test.pdf.zip", actual.Name)
+		assert.Equal(t, int64(3), actual.Size)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), actual.ExpiresAt, time.Minute)
+	})
+
+	t.Run("when store failed should return error", func(t *testing.T) {
+		mockStore := new(mocks.ResultStore)
+		service := download.NewService(mockStore, []byte("secret"), time.Hour)
+
+		mockStore.On("Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(int64(0), fmt.Errorf("Put Error")).Once()
+
+		_, err := service.Publish(context.TODO(), domain.PdfFile{Name: "test.pdf", Content: io.NopCloser(bytes.NewReader(nil))})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestOpen(t *testing.T) {
+	publish := func(t *testing.T, service *download.Service, mockStore *mocks.ResultStore) (string, *string) {
+		var key string
+		mockStore.On("Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { key = args.String(1) }).
+			Return(int64(1), nil).Once()
+
+		link, err := service.Publish(context.TODO(), domain.PdfFile{Name: "test.pdf", Content: io.NopCloser(bytes.NewReader([]byte{1})), Size: 1})
+		require.NoError(t, err)
+		return link.Token, &key
+	}
+
+	t.Run("when token is valid should return the stored file", func(t *testing.T) {
+		mockStore := new(mocks.ResultStore)
+		service := download.NewService(mockStore, []byte("secret"), time.Hour)
+		token, key := publish(t, service, mockStore)
+
+		mockStore.On("Get", mock.Anything, *key).Return(io.NopCloser(bytes.NewReader([]byte{1})), int64(1), nil).Once()
+
+		actual, err := service.Open(context.TODO(), token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "test.pdf", actual.Name)
+		assert.Equal(t, int64(1), actual.Size)
+	})
+
+	t.Run("when token was tampered with should return ErrNotFound", func(t *testing.T) {
+		mockStore := new(mocks.ResultStore)
+		service := download.NewService(mockStore, []byte("secret"), time.Hour)
+		token, _ := publish(t, service, mockStore)
+
+		other := download.NewService(mockStore, []byte("other secret"), time.Hour)
+		_, err := other.Open(context.TODO(), token)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		_, err = service.Open(context.TODO(), strings.Replace(token, ".", "x.", 1))
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		_, err = service.Open(context.TODO(), "garbage")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		mockStore.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+	})
+
+	t.Run("when link expired should return ErrLinkExpired", func(t *testing.T) {
+		mockStore := new(mocks.ResultStore)
+		service := download.NewService(mockStore, []byte("secret"), -time.Minute)
+		token, _ := publish(t, service, mockStore)
+
+		_, err := service.Open(context.TODO(), token)
+
+		assert.ErrorIs(t, err, domain.ErrLinkExpired)
+		mockStore.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,427 @@
+package repository
+
+import (
+	"cmp"
+	"compress/zlib"
+	"context"
+	"crypto/sha256"
+	"image"
+	"image/jpeg"
+	"slices"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// maxLargestImages is how many images an analysis lists
+const maxLargestImages = 10
+
+// maxJpegSamples bounds the images encoded as JPEG to estimate what the jpeg setting
+// saves, the largest ones are taken
+const maxJpegSamples = 10
+
+// jpegQuality is the quality the jpeg setting is estimated at
+const jpegQuality = 75
+
+// losslessFilters are the filters an image may be stored with to be worth storing as JPEG
+var losslessFilters = []string{"FlateDecode", "LZWDecode", "RunLengthDecode"}
+
+// fontFileKeys are the entries of a font descriptor that hold the font program
+var fontFileKeys = []string{"FontFile", "FontFile2", "FontFile3"}
+
+// analyzer walks the objects of a document once to sum them up
+type analyzer struct {
+	pdfCtx *model.Context
+	// reached are the objects the trailer leads to
+	reached map[int]bool
+	// fontFiles and contents are streams known by what refers to them
+	fontFiles map[int]bool
+	contents  map[int]bool
+}
+
+func analyze(ctx context.Context, pdfCtx *model.Context) (domain.PdfAnalysis, error) {
+	a := &analyzer{pdfCtx: pdfCtx, reached: map[int]bool{}, fontFiles: map[int]bool{}, contents: map[int]bool{}}
+	if err := a.reach(ctx); err != nil {
+		return domain.PdfAnalysis{}, err
+	}
+
+	analysis := domain.PdfAnalysis{
+		FileSize: pdfCtx.Read.FileSize,
+		Pages:    pdfCtx.PageCount,
+		Streams:  map[string]domain.PdfStreamStats{},
+		Unused:   domain.PdfUnused{Objects: []int{}},
+	}
+
+	objNrs := make([]int, 0, len(pdfCtx.Table))
+	for objNr, entry := range pdfCtx.Table {
+		if objNr > 0 && !entry.Free && entry.Object != nil {
+			objNrs = append(objNrs, objNr)
+		}
+	}
+	slices.Sort(objNrs)
+
+	kinds := make(map[int]string)
+	var flateSaved int64
+	for _, objNr := range objNrs {
+		if err := ctx.Err(); err != nil {
+			return domain.PdfAnalysis{}, err
+		}
+		analysis.Objects++
+
+		obj := pdfCtx.Table[objNr].Object
+		sd := streamDict(obj)
+		kind := a.kind(objNr, obj)
+		if sd != nil {
+			kinds[objNr] = kind
+			stats := analysis.Streams[kind]
+			stats.Count++
+			stats.Bytes += int64(len(sd.Raw))
+			analysis.Streams[kind] = stats
+
+			if kind != domain.StreamStructure && len(sd.FilterPipeline) == 0 {
+				flateSaved += max(int64(len(sd.Raw))-deflatedSize(sd.Raw), 0)
+			}
+		}
+
+		if !a.reached[objNr] && kind != domain.StreamStructure {
+			analysis.Unused.Objects = append(analysis.Unused.Objects, objNr)
+			analysis.Unused.Bytes += int64(len(obj.PDFString()))
+			if sd != nil {
+				analysis.Unused.Bytes += int64(len(sd.Raw))
+			}
+		}
+	}
+
+	analysis.Duplicates = a.duplicates(objNrs, kinds)
+	images, err := a.images(ctx, objNrs, kinds)
+	if err != nil {
+		return domain.PdfAnalysis{}, err
+	}
+	analysis.LargestImages = images[:min(len(images), maxLargestImages)]
+
+	jpegSaved, estimated := a.jpegSavings(images)
+	analysis.Savings = []domain.PdfSaving{
+		{Setting: domain.SettingFlate, Bytes: flateSaved},
+		{Setting: domain.SettingJpeg, Bytes: jpegSaved, Estimated: estimated},
+	}
+	return analysis, nil
+}
+
+// reach follows every reference from the trailer and notes the font programs and page
+// contents on the way
+func (a *analyzer) reach(ctx context.Context) error {
+	var pending []int
+	for _, ref := range []*types.IndirectRef{a.pdfCtx.Root, a.pdfCtx.Info, a.pdfCtx.Encrypt} {
+		if ref != nil {
+			pending = append(pending, ref.ObjectNumber.Value())
+		}
+	}
+
+	for len(pending) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		objNr := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if a.reached[objNr] {
+			continue
+		}
+		a.reached[objNr] = true
+
+		entry, ok := a.pdfCtx.Table[objNr]
+		if !ok || entry.Free || entry.Object == nil {
+			continue
+		}
+		a.noteRoles(entry.Object)
+		pending = append(pending, references(entry.Object)...)
+	}
+	return nil
+}
+
+// noteRoles remembers the streams obj names as font programs or page contents
+func (a *analyzer) noteRoles(obj types.Object) {
+	d, ok := obj.(types.Dict)
+	if !ok {
+		return
+	}
+	for _, key := range fontFileKeys {
+		if ref, ok := d[key].(types.IndirectRef); ok {
+			a.fontFiles[ref.ObjectNumber.Value()] = true
+		}
+	}
+	if d.Type() != nil && *d.Type() == "Page" {
+		for _, objNr := range references(d["Contents"]) {
+			a.contents[objNr] = true
+		}
+	}
+}
+
+// kind is the Stream constant for the stream obj, or the empty string for other objects
+func (a *analyzer) kind(objNr int, obj types.Object) string {
+	switch obj.(type) {
+	case types.ObjectStreamDict, types.XRefStreamDict:
+		return domain.StreamStructure
+	}
+	sd := streamDict(obj)
+	if sd == nil {
+		return ""
+	}
+
+	switch {
+	case a.fontFiles[objNr]:
+		return domain.StreamFont
+	case a.contents[objNr]:
+		return domain.StreamContent
+	}
+	subtype := sd.Subtype()
+	switch {
+	case subtype != nil && *subtype == "Image":
+		return domain.StreamImage
+	case subtype != nil && *subtype == "Form":
+		return domain.StreamForm
+	case sd.Type() != nil && *sd.Type() == "Metadata":
+		return domain.StreamMeta
+	}
+	return domain.StreamOther
+}
+
+// duplicates groups the images, fonts and forms stored with the same bytes, the most
+// wasteful first
+func (a *analyzer) duplicates(objNrs []int, kinds map[int]string) []domain.PdfDuplicate {
+	type key struct {
+		kind string
+		hash [sha256.Size]byte
+	}
+	groups := make(map[key][]int)
+	var order []key
+	for _, objNr := range objNrs {
+		kind := kinds[objNr]
+		if kind != domain.StreamImage && kind != domain.StreamFont && kind != domain.StreamForm {
+			continue
+		}
+		k := key{kind: kind, hash: sha256.Sum256(streamDict(a.pdfCtx.Table[objNr].Object).Raw)}
+		if _, ok := groups[k]; !ok {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], objNr)
+	}
+
+	duplicates := []domain.PdfDuplicate{}
+	for _, k := range order {
+		group := groups[k]
+		if len(group) < 2 {
+			continue
+		}
+		size := int64(len(streamDict(a.pdfCtx.Table[group[0]].Object).Raw))
+		duplicates = append(duplicates, domain.PdfDuplicate{Kind: k.kind, Objects: group, Bytes: size, Wasted: size * int64(len(group)-1)})
+	}
+	slices.SortStableFunc(duplicates, func(x, y domain.PdfDuplicate) int { return cmp.Compare(y.Wasted, x.Wasted) })
+	return duplicates
+}
+
+// images describes every image, the largest first, with the pages whose resources
+// lead to it
+func (a *analyzer) images(ctx context.Context, objNrs []int, kinds map[int]string) ([]domain.PdfImageStats, error) {
+	pages := make(map[int][]int)
+	for pageNr := 1; pageNr <= a.pdfCtx.PageCount; pageNr++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		pageDict, _, inherited, err := a.pdfCtx.PageDict(pageNr, false)
+		if err != nil {
+			return nil, err
+		}
+		roots := []types.Object{pageDict["Resources"]}
+		if inherited != nil && inherited.Resources != nil {
+			roots = append(roots, inherited.Resources)
+		}
+		for objNr := range a.resources(roots) {
+			if kinds[objNr] == domain.StreamImage {
+				pages[objNr] = append(pages[objNr], pageNr)
+			}
+		}
+	}
+
+	images := []domain.PdfImageStats{}
+	for _, objNr := range objNrs {
+		if kinds[objNr] != domain.StreamImage {
+			continue
+		}
+		sd := streamDict(a.pdfCtx.Table[objNr].Object)
+		colorSpace, _ := sd.Find("ColorSpace")
+		stats := domain.PdfImageStats{
+			Object:     objNr,
+			ColorSpace: a.colorSpace(colorSpace),
+			Filter:     strings.Join(filterNames(sd), ","),
+			Bytes:      int64(len(sd.Raw)),
+			Pages:      pages[objNr],
+		}
+		if v := sd.IntEntry("Width"); v != nil {
+			stats.Width = *v
+		}
+		if v := sd.IntEntry("Height"); v != nil {
+			stats.Height = *v
+		}
+		if v := sd.IntEntry("BitsPerComponent"); v != nil {
+			stats.BitsPerComponent = *v
+		}
+		images = append(images, stats)
+	}
+	slices.SortStableFunc(images, func(x, y domain.PdfImageStats) int { return cmp.Compare(y.Bytes, x.Bytes) })
+	return images, nil
+}
+
+// resources lists the objects reached from roots
+func (a *analyzer) resources(roots []types.Object) map[int]bool {
+	seen := make(map[int]bool)
+	var pending []int
+	for _, root := range roots {
+		pending = append(pending, references(root)...)
+	}
+	for len(pending) > 0 {
+		objNr := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if seen[objNr] {
+			continue
+		}
+		seen[objNr] = true
+		if entry, ok := a.pdfCtx.Table[objNr]; ok && !entry.Free && entry.Object != nil {
+			pending = append(pending, references(entry.Object)...)
+		}
+	}
+	return seen
+}
+
+// jpegSavings encodes the largest images stored losslessly as JPEG and sums up how
+// much smaller they get. estimated is set when images were left out, beyond the
+// largest ones or too large to decode.
+func (a *analyzer) jpegSavings(images []domain.PdfImageStats) (saved int64, estimated bool) {
+	encoded := 0
+	for _, img := range images {
+		sd := streamDict(a.pdfCtx.Table[img.Object].Object)
+		if !jpegCandidate(sd, img) {
+			continue
+		}
+		if !decodable(sd) {
+			estimated = true
+			continue
+		}
+		if encoded == maxJpegSamples {
+			return saved, true
+		}
+		encoded++
+
+		extracted, err := pdfcpu.ExtractImage(a.pdfCtx, sd, false, "", img.Object, false)
+		if err != nil || extracted == nil {
+			continue
+		}
+		decoded, _, err := image.Decode(extracted)
+		if err != nil {
+			continue
+		}
+		var size countingWriter
+		if err := jpeg.Encode(&size, decoded, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			continue
+		}
+		saved += max(img.Bytes-int64(size), 0)
+	}
+	return saved, estimated
+}
+
+// jpegCandidate tells whether an image keeps what JPEG stores: 8 bits of direct colors
+// compressed without loss, neither a mask nor a palette
+func jpegCandidate(sd *types.StreamDict, img domain.PdfImageStats) bool {
+	if img.BitsPerComponent != 8 || strings.HasPrefix(img.ColorSpace, "Indexed") {
+		return false
+	}
+	if mask := sd.BooleanEntry("ImageMask"); mask != nil && *mask {
+		return false
+	}
+	for _, filter := range filterNames(sd) {
+		if !slices.Contains(losslessFilters, filter) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *analyzer) colorSpace(obj types.Object) string {
+	obj, _ = a.pdfCtx.Dereference(obj)
+	switch cs := obj.(type) {
+	case types.Name:
+		return cs.Value()
+	case types.Array:
+		if len(cs) > 0 {
+			if name, ok := cs[0].(types.Name); ok {
+				return name.Value()
+			}
+		}
+	}
+	return ""
+}
+
+func filterNames(sd *types.StreamDict) []string {
+	names := make([]string, len(sd.FilterPipeline))
+	for i, filter := range sd.FilterPipeline {
+		names[i] = filter.Name
+	}
+	return names
+}
+
+// references lists the objects obj refers to, looking into its dictionaries and arrays
+func references(obj types.Object) []int {
+	var objNrs []int
+	var walk func(types.Object)
+	walk = func(obj types.Object) {
+		switch o := obj.(type) {
+		case types.IndirectRef:
+			objNrs = append(objNrs, o.ObjectNumber.Value())
+		case types.Dict:
+			for _, v := range o {
+				walk(v)
+			}
+		case types.Array:
+			for _, v := range o {
+				walk(v)
+			}
+		case types.StreamDict:
+			walk(o.Dict)
+		}
+	}
+	walk(obj)
+	return objNrs
+}
+
+// streamDict is obj as a stream, nil when it isn't one
+func streamDict(obj types.Object) *types.StreamDict {
+	switch o := obj.(type) {
+	case types.StreamDict:
+		return &o
+	case types.ObjectStreamDict:
+		return &o.StreamDict
+	case types.XRefStreamDict:
+		return &o.StreamDict
+	}
+	return nil
+}
+
+// deflatedSize is the size of data compressed as FlateDecode does
+func deflatedSize(data []byte) int64 {
+	var size countingWriter
+	w := zlib.NewWriter(&size)
+	w.Write(data)
+	w.Close()
+	return int64(size)
+}
+
+// countingWriter counts what is written to it and drops it
+type countingWriter int64
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+	return len(p), nil
+}
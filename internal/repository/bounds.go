@@ -0,0 +1,459 @@
+package repository
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	_ "golang.org/x/image/tiff"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// Text is measured without its fonts: a glyph is taken to be half as wide as the font
+// size, and to reach from below the baseline by descent up to ascent
+const (
+	glyphWidth = 0.5
+	ascent     = 0.8
+	descent    = -0.2
+)
+
+// whiteLevel is the luminance, out of 0xffff, from which a pixel of an image counts
+// as paper
+const whiteLevel = 0xe000
+
+// inkShare is the share of the pixels of a row or column of an image that have to be
+// darker than paper for it to count as content rather than specks of dust
+const inkShare = 0.005
+
+// maxSamples bounds the pixels read along a side of an image, larger ones are sampled
+const maxSamples = 1000
+
+// maxDecodedPixels bounds the pixels of an image that is decoded. A small stream can
+// hold a huge image, so the size is read off its dictionary before extracting it.
+const maxDecodedPixels = 1 << 25
+
+// maxFormDepth bounds how deep form XObjects drawing each other are followed
+const maxFormDepth = 8
+
+var unitSquare = domain.PdfRect{LLX: 0, LLY: 0, URX: 1, URY: 1}
+
+// matrix is an affine transformation [a b c d e f] as PDF writes them
+type matrix [6]float64
+
+var identity = matrix{1, 0, 0, 1, 0, 0}
+
+// then returns m followed by n
+func (m matrix) then(n matrix) matrix {
+	return matrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+func (m matrix) apply(x, y float64) (float64, float64) {
+	return m[0]*x + m[2]*y + m[4], m[1]*x + m[3]*y + m[5]
+}
+
+func translation(x, y float64) matrix {
+	return matrix{1, 0, 0, 1, x, y}
+}
+
+// bounds grows to hold what is added to it
+type bounds struct {
+	rect domain.PdfRect
+	set  bool
+}
+
+func (b *bounds) add(x, y float64) {
+	if !b.set {
+		b.rect, b.set = domain.PdfRect{LLX: x, LLY: y, URX: x, URY: y}, true
+		return
+	}
+	b.rect.LLX, b.rect.LLY = min(b.rect.LLX, x), min(b.rect.LLY, y)
+	b.rect.URX, b.rect.URY = max(b.rect.URX, x), max(b.rect.URY, y)
+}
+
+// addRect adds r as m transforms it
+func (b *bounds) addRect(m matrix, r domain.PdfRect) {
+	for _, corner := range [][2]float64{{r.LLX, r.LLY}, {r.URX, r.LLY}, {r.LLX, r.URY}, {r.URX, r.URY}} {
+		b.add(m.apply(corner[0], corner[1]))
+	}
+}
+
+// graphicsState is the part of the graphics state that decides where and whether
+// something is drawn
+type graphicsState struct {
+	ctm         matrix
+	whiteFill   bool
+	whiteStroke bool
+	fontSize    float64
+	leading     float64
+	scale       float64
+	// textMode is the text rendering mode, 3 and 7 draw nothing
+	textMode int
+}
+
+// boundsReader finds the content bounds of the pages of a document. Paths and text
+// painted in white count as paper, and so do the white borders of images. Clipping is
+// not followed, the bounds may come out larger than what is visible.
+type boundsReader struct {
+	pdfCtx *model.Context
+	// inks caches the inked part of images by object number, scans share them
+	inks map[int]*domain.PdfRect
+}
+
+func newBoundsReader(pdfCtx *model.Context) *boundsReader {
+	return &boundsReader{pdfCtx: pdfCtx, inks: map[int]*domain.PdfRect{}}
+}
+
+// page returns the bounds of what page pageNr draws, false when it draws nothing
+func (r *boundsReader) page(pageNr int) (domain.PdfRect, bool, error) {
+	pageDict, _, inherited, err := r.pdfCtx.PageDict(pageNr, false)
+	if err != nil {
+		return domain.PdfRect{}, false, err
+	}
+	content, err := r.pdfCtx.PageContent(pageDict)
+	if errors.Is(err, model.ErrNoContent) {
+		return domain.PdfRect{}, false, nil
+	}
+	if err != nil {
+		return domain.PdfRect{}, false, err
+	}
+
+	var b bounds
+	r.content(content, inherited.Resources, identity, 0, &b)
+	return b.rect, b.set, nil
+}
+
+// content adds what a content stream draws with resources, ctm places it on the page
+func (r *boundsReader) content(content []byte, resources types.Dict, ctm matrix, depth int, b *bounds) {
+	xObjects := r.dict(resources["XObject"])
+	state := graphicsState{ctm: ctm, scale: 1}
+	var stack []graphicsState
+	var path bounds
+	textMatrix, lineMatrix := identity, identity
+
+	var operands []contentOperand
+	number := func(i int) float64 {
+		if i < len(operands) {
+			return operands[i].number
+		}
+		return 0
+	}
+	point := func(i int) {
+		path.add(state.ctm.apply(number(i), number(i+1)))
+	}
+	paint := func(white bool) {
+		if path.set && !white {
+			b.add(path.rect.LLX, path.rect.LLY)
+			b.add(path.rect.URX, path.rect.URY)
+		}
+		path = bounds{}
+	}
+	nextLine := func(x, y float64) {
+		lineMatrix = translation(x, y).then(lineMatrix)
+		textMatrix = lineMatrix
+	}
+	show := func(operand contentOperand) {
+		advance := func(width float64) {
+			textMatrix = translation(width*state.scale, 0).then(textMatrix)
+		}
+		draw := func(text string) {
+			width := float64(len([]rune(text))) * glyphWidth * state.fontSize
+			if state.textMode != 3 && state.textMode != 7 && !(state.whiteFill && state.textMode == 0) {
+				glyphs := domain.PdfRect{LLX: 0, LLY: descent * state.fontSize, URX: width * state.scale, URY: ascent * state.fontSize}
+				b.addRect(textMatrix.then(state.ctm), glyphs)
+			}
+			advance(width)
+		}
+		if operand.isString {
+			draw(operand.text)
+		}
+		for _, element := range operand.array {
+			if element.isString {
+				draw(element.text)
+			} else {
+				advance(-element.number / 1000 * state.fontSize)
+			}
+		}
+	}
+
+	s := &contentScanner{content: content}
+	for {
+		token, ok := s.next()
+		if !ok {
+			break
+		}
+		if !token.operator {
+			operands = append(operands, token.operand)
+			continue
+		}
+
+		switch token.text {
+		case "q":
+			stack = append(stack, state)
+		case "Q":
+			if len(stack) > 0 {
+				state, stack = stack[len(stack)-1], stack[:len(stack)-1]
+			}
+		case "cm":
+			if len(operands) == 6 {
+				state.ctm = matrix{number(0), number(1), number(2), number(3), number(4), number(5)}.then(state.ctm)
+			}
+		case "g":
+			state.whiteFill = number(0) >= 1
+		case "G":
+			state.whiteStroke = number(0) >= 1
+		case "rg":
+			state.whiteFill = number(0) >= 1 && number(1) >= 1 && number(2) >= 1
+		case "RG":
+			state.whiteStroke = number(0) >= 1 && number(1) >= 1 && number(2) >= 1
+		case "k":
+			state.whiteFill = number(0) <= 0 && number(1) <= 0 && number(2) <= 0 && number(3) <= 0
+		case "K":
+			state.whiteStroke = number(0) <= 0 && number(1) <= 0 && number(2) <= 0 && number(3) <= 0
+		case "cs", "sc", "scn":
+			state.whiteFill = false
+		case "CS", "SC", "SCN":
+			state.whiteStroke = false
+		case "m", "l":
+			point(0)
+		case "c":
+			point(0)
+			point(2)
+			point(4)
+		case "v", "y":
+			point(0)
+			point(2)
+		case "re":
+			x, y, w, h := number(0), number(1), number(2), number(3)
+			path.addRect(state.ctm, domain.PdfRect{LLX: min(x, x+w), LLY: min(y, y+h), URX: max(x, x+w), URY: max(y, y+h)})
+		case "S", "s":
+			paint(state.whiteStroke)
+		case "f", "F", "f*":
+			paint(state.whiteFill)
+		case "B", "B*", "b", "b*":
+			paint(state.whiteFill && state.whiteStroke)
+		case "n":
+			paint(true)
+		case "BT":
+			textMatrix, lineMatrix = identity, identity
+		case "Tf":
+			state.fontSize = number(len(operands) - 1)
+		case "TL":
+			state.leading = number(0)
+		case "Tz":
+			state.scale = number(0) / 100
+		case "Tr":
+			state.textMode = int(number(0))
+		case "Td":
+			nextLine(number(0), number(1))
+		case "TD":
+			state.leading = -number(1)
+			nextLine(number(0), number(1))
+		case "Tm":
+			if len(operands) == 6 {
+				lineMatrix = matrix{number(0), number(1), number(2), number(3), number(4), number(5)}
+				textMatrix = lineMatrix
+			}
+		case "T*":
+			nextLine(0, -state.leading)
+		case "Tj", "TJ":
+			if len(operands) > 0 {
+				show(operands[0])
+			}
+		case "'", "\"":
+			nextLine(0, -state.leading)
+			if len(operands) > 0 {
+				show(operands[len(operands)-1])
+			}
+		case "BI":
+			s.skipInlineImage()
+			b.addRect(state.ctm, unitSquare)
+		case "Do":
+			if len(operands) > 0 && xObjects != nil {
+				r.xObject(xObjects[operands[0].name], resources, state.ctm, depth, b)
+			}
+		}
+		operands = operands[:0]
+	}
+}
+
+// xObject adds what an image or form XObject draws
+func (r *boundsReader) xObject(obj types.Object, resources types.Dict, ctm matrix, depth int, b *bounds) {
+	if obj == nil {
+		return
+	}
+	sd, _, err := r.pdfCtx.DereferenceStreamDict(obj)
+	if err != nil || sd == nil {
+		return
+	}
+
+	subtype := sd.Dict.NameEntry("Subtype")
+	switch {
+	case subtype == nil:
+	case *subtype == "Image":
+		if ink := r.imageInk(obj, sd); ink != nil {
+			b.addRect(ctm, *ink)
+		}
+	case *subtype == "Form" && depth < maxFormDepth:
+		if err := sd.Decode(); err != nil {
+			return
+		}
+		// a form without resources of its own uses those of what draws it
+		if own := r.dict(sd.Dict["Resources"]); own != nil {
+			resources = own
+		}
+		var form bounds
+		r.content(sd.Content, resources, identity, depth+1, &form)
+		if !form.set {
+			return
+		}
+
+		ink := form.rect
+		if bbox := r.rect(sd.Dict["BBox"]); bbox != nil {
+			ink = intersect(ink, *bbox)
+		}
+		if ink.Empty() {
+			return
+		}
+		placement := identity
+		if m := sd.Dict.ArrayEntry("Matrix"); len(m) == 6 {
+			for i, value := range m {
+				placement[i], _ = r.pdfCtx.DereferenceNumber(value)
+			}
+		}
+		b.addRect(placement.then(ctm), ink)
+	}
+}
+
+// imageInk is the part of the unit square an image is drawn in that isn't paper,
+// nil when it's all paper. Images that can't be decoded, or are too large to be, count
+// as a whole.
+func (r *boundsReader) imageInk(obj types.Object, sd *types.StreamDict) *domain.PdfRect {
+	objNr := 0
+	if ref, ok := obj.(types.IndirectRef); ok {
+		objNr = ref.ObjectNumber.Value()
+		if ink, ok := r.inks[objNr]; ok {
+			return ink
+		}
+	}
+
+	whole := unitSquare
+	ink := &whole
+	if decodable(sd) {
+		if img, err := pdfcpu.ExtractImage(r.pdfCtx, sd, false, "", objNr, false); err == nil && img != nil {
+			if decoded, _, err := image.Decode(img); err == nil {
+				ink = inkBounds(decoded)
+			}
+		}
+	}
+
+	if objNr > 0 {
+		r.inks[objNr] = ink
+	}
+	return ink
+}
+
+// decodable tells whether the image of sd declares a size small enough to decode
+func decodable(sd *types.StreamDict) bool {
+	width, height := sd.IntEntry("Width"), sd.IntEntry("Height")
+	return width != nil && height != nil && *width > 0 && *height > 0 &&
+		int64(*width)*int64(*height) <= maxDecodedPixels
+}
+
+// inkBounds finds the rows and columns of img that aren't paper and returns them as a
+// part of the unit square, whose top holds the first row
+func inkBounds(img image.Image) *domain.PdfRect {
+	area := img.Bounds()
+	width, height := area.Dx(), area.Dy()
+	if width == 0 || height == 0 {
+		return nil
+	}
+
+	step := max(1, max(width, height)/maxSamples)
+	rows, cols := make([]int, height), make([]int, width)
+	for y := area.Min.Y; y < area.Max.Y; y += step {
+		for x := area.Min.X; x < area.Max.X; x += step {
+			if !isPaper(img.At(x, y)) {
+				rows[y-area.Min.Y]++
+				cols[x-area.Min.X]++
+			}
+		}
+	}
+
+	top, bottom := inked(rows, inkShare*float64(width/step))
+	left, right := inked(cols, inkShare*float64(height/step))
+	if top < 0 || left < 0 {
+		return nil
+	}
+	return &domain.PdfRect{
+		LLX: float64(left) / float64(width),
+		LLY: 1 - float64(min(bottom+step, height))/float64(height),
+		URX: float64(min(right+step, width)) / float64(width),
+		URY: 1 - float64(top)/float64(height),
+	}
+}
+
+// inked returns the first and last index of counts above limit, -1 when there is none
+func inked(counts []int, limit float64) (int, int) {
+	first, last := -1, -1
+	for i, count := range counts {
+		if float64(count) > limit {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	return first, last
+}
+
+// isPaper reports whether c, put on white paper, is about as light as the paper
+func isPaper(c color.Color) bool {
+	r, g, b, a := c.RGBA()
+	// the colors are premultiplied, what is transparent shows the paper
+	paper := 0xffff - a
+	luminance := (299*(r+paper) + 587*(g+paper) + 114*(b+paper)) / 1000
+	return luminance >= whiteLevel
+}
+
+func intersect(a, b domain.PdfRect) domain.PdfRect {
+	return domain.PdfRect{LLX: max(a.LLX, b.LLX), LLY: max(a.LLY, b.LLY), URX: min(a.URX, b.URX), URY: min(a.URY, b.URY)}
+}
+
+func (r *boundsReader) dict(obj types.Object) types.Dict {
+	if obj == nil {
+		return nil
+	}
+	d, err := r.pdfCtx.DereferenceDict(obj)
+	if err != nil {
+		return nil
+	}
+	return d
+}
+
+func (r *boundsReader) rect(obj types.Object) *domain.PdfRect {
+	if obj == nil {
+		return nil
+	}
+	a, err := r.pdfCtx.DereferenceArray(obj)
+	if err != nil || len(a) != 4 {
+		return nil
+	}
+	rect, err := r.pdfCtx.RectForArray(a)
+	if err != nil {
+		return nil
+	}
+	return &domain.PdfRect{LLX: rect.LL.X, LLY: rect.LL.Y, URX: rect.UR.X, URY: rect.UR.Y}
+}
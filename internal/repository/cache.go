@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// CacheConfig sizes the tiers of a ResultCache. Results up to MaxMemoryEntry bytes
+// are also kept in memory; everything is kept on disk.
+type CacheConfig struct {
+	Dir            string
+	MaxDiskBytes   int64
+	MaxMemoryBytes int64
+	MaxMemoryEntry int64
+	TTL            time.Duration
+}
+
+// ResultCache is a two tier LRU cache of operation results: a small in-memory tier
+// for hot, small results in front of a larger on-disk tier. Both evict the least
+// recently used entries first and drop entries older than the TTL.
+type ResultCache struct {
+	config CacheConfig
+
+	mu     sync.Mutex
+	memory *lru
+	disk   *lru
+}
+
+type cacheEntry struct {
+	key     string
+	size    int64
+	expires time.Time
+	data    []byte // memory tier only
+}
+
+// NewResultCache will create the cache directory if needed and index results left
+// there by a previous process
+func NewResultCache(config CacheConfig) (*ResultCache, error) {
+	if err := os.MkdirAll(config.Dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	c := &ResultCache{
+		config: config,
+		memory: newLRU(),
+		disk:   newLRU(),
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *ResultCache) Get(ctx context.Context, key string) (io.ReadCloser, int64, bool) {
+	if !isSafeKey(key) {
+		return nil, 0, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if entry, ok := c.memory.get(key); ok {
+		if now.Before(entry.expires) {
+			return io.NopCloser(bytes.NewReader(entry.data)), entry.size, true
+		}
+		c.memory.remove(key)
+	}
+
+	entry, ok := c.disk.get(key)
+	if !ok {
+		return nil, 0, false
+	}
+	if !now.Before(entry.expires) {
+		c.removeFromDisk(key)
+		return nil, 0, false
+	}
+
+	// an open file stays readable even if the entry is evicted while it is streamed
+	file, err := os.Open(c.path(key))
+	if err != nil {
+		c.disk.remove(key)
+		return nil, 0, false
+	}
+	return file, entry.size, true
+}
+
+func (c *ResultCache) Put(ctx context.Context, key string, content io.Reader) error {
+	if !isSafeKey(key) {
+		return domain.ErrBadParamInput
+	}
+
+	tmp, err := os.CreateTemp(c.config.Dir, ".put-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	memory := &cappedBuffer{limit: c.config.MaxMemoryEntry}
+	size, err := io.Copy(io.MultiWriter(tmp, memory), content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if size > c.config.MaxDiskBytes {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		return fmt.Errorf("failed to store cache file: %w", err)
+	}
+
+	expires := time.Now().Add(c.config.TTL)
+	c.disk.put(&cacheEntry{key: key, size: size, expires: expires})
+	for c.disk.bytes > c.config.MaxDiskBytes {
+		c.removeFromDisk(c.disk.oldest().key)
+	}
+
+	if !memory.overflow && size <= c.config.MaxMemoryBytes {
+		c.memory.put(&cacheEntry{key: key, size: size, expires: expires, data: memory.Bytes()})
+		for c.memory.bytes > c.config.MaxMemoryBytes {
+			c.memory.remove(c.memory.oldest().key)
+		}
+	}
+	return nil
+}
+
+func (c *ResultCache) removeFromDisk(key string) {
+	c.disk.remove(key)
+	c.memory.remove(key)
+	os.Remove(c.path(key))
+}
+
+func (c *ResultCache) path(key string) string {
+	return filepath.Join(c.config.Dir, key)
+}
+
+// load indexes files of a previous run, oldest first so they are evicted first
+func (c *ResultCache) load() error {
+	entries, err := os.ReadDir(c.config.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	type found struct {
+		name    string
+		size    int64
+		modTime time.Time
+	}
+	files := make([]found, 0, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() {
+			continue
+		}
+		if strings.HasPrefix(entry.Name(), ".") {
+			os.Remove(c.path(entry.Name()))
+			continue
+		}
+		files = append(files, found{name: entry.Name(), size: info.Size(), modTime: info.ModTime()})
+	}
+	slices.SortFunc(files, func(a, b found) int { return a.modTime.Compare(b.modTime) })
+
+	for _, f := range files {
+		c.disk.put(&cacheEntry{key: f.name, size: f.size, expires: f.modTime.Add(c.config.TTL)})
+	}
+	for c.disk.bytes > c.config.MaxDiskBytes {
+		c.removeFromDisk(c.disk.oldest().key)
+	}
+	return nil
+}
+
+// lru keeps entries in recency order with the most recently used at the front
+type lru struct {
+	order   *list.List
+	entries map[string]*list.Element
+	bytes   int64
+}
+
+func newLRU() *lru {
+	return &lru{order: list.New(), entries: map[string]*list.Element{}}
+}
+
+func (l *lru) get(key string) (*cacheEntry, bool) {
+	elem, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+	l.order.MoveToFront(elem)
+	return elem.Value.(*cacheEntry), true
+}
+
+func (l *lru) put(entry *cacheEntry) {
+	l.remove(entry.key)
+	l.entries[entry.key] = l.order.PushFront(entry)
+	l.bytes += entry.size
+}
+
+func (l *lru) remove(key string) {
+	elem, ok := l.entries[key]
+	if !ok {
+		return
+	}
+	l.order.Remove(elem)
+	delete(l.entries, key)
+	l.bytes -= elem.Value.(*cacheEntry).size
+}
+
+func (l *lru) oldest() *cacheEntry {
+	return l.order.Back().Value.(*cacheEntry)
+}
+
+// cappedBuffer buffers writes until limit is exceeded and then only discards them
+type cappedBuffer struct {
+	bytes.Buffer
+	limit    int64
+	overflow bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if !b.overflow && int64(b.Len()+len(p)) <= b.limit {
+		return b.Buffer.Write(p)
+	}
+	b.overflow = true
+	b.Reset()
+	return len(p), nil
+}
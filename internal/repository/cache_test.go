@@ -0,0 +1,126 @@
+package repository_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCache(t *testing.T, dir string) *repository.ResultCache {
+	cache, err := repository.NewResultCache(repository.CacheConfig{
+		Dir:            dir,
+		MaxDiskBytes:   8,
+		MaxMemoryBytes: 4,
+		MaxMemoryEntry: 2,
+		TTL:            time.Hour,
+	})
+	require.NoError(t, err)
+	return cache
+}
+
+func readCached(t *testing.T, cache *repository.ResultCache, key string) ([]byte, bool) {
+	content, _, ok := cache.Get(context.TODO(), key)
+	if !ok {
+		return nil, false
+	}
+	defer content.Close()
+	data, err := io.ReadAll(content)
+	require.NoError(t, err)
+	return data, true
+}
+
+func TestResultCache(t *testing.T) {
+	t.Run("when put success should be readable with get", func(t *testing.T) {
+		cache := newTestCache(t, t.TempDir())
+
+		assert.NoError(t, cache.Put(context.TODO(), "small", bytes.NewReader([]byte{1})))
+		assert.NoError(t, cache.Put(context.TODO(), "large", bytes.NewReader([]byte{1, 2, 3})))
+
+		data, ok := readCached(t, cache, "small")
+		assert.True(t, ok)
+		assert.Equal(t, []byte{1}, data)
+		data, ok = readCached(t, cache, "large")
+		assert.True(t, ok)
+		assert.Equal(t, []byte{1, 2, 3}, data)
+	})
+
+	t.Run("when disk limit is exceeded should evict least recently used", func(t *testing.T) {
+		cache := newTestCache(t, t.TempDir())
+
+		cache.Put(context.TODO(), "a", bytes.NewReader([]byte{1, 2, 3}))
+		cache.Put(context.TODO(), "b", bytes.NewReader([]byte{1, 2, 3}))
+		readCached(t, cache, "a")
+		cache.Put(context.TODO(), "c", bytes.NewReader([]byte{1, 2, 3}))
+
+		_, ok := readCached(t, cache, "a")
+		assert.True(t, ok)
+		_, ok = readCached(t, cache, "b")
+		assert.False(t, ok)
+		_, ok = readCached(t, cache, "c")
+		assert.True(t, ok)
+	})
+
+	t.Run("when result is larger than the disk limit should not store it", func(t *testing.T) {
+		dir := t.TempDir()
+		cache := newTestCache(t, dir)
+
+		assert.NoError(t, cache.Put(context.TODO(), "huge", bytes.NewReader(make([]byte, 9))))
+
+		_, ok := readCached(t, cache, "huge")
+		assert.False(t, ok)
+		entries, _ := os.ReadDir(dir)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("when entry is older than the ttl should miss", func(t *testing.T) {
+		cache, err := repository.NewResultCache(repository.CacheConfig{
+			Dir:            t.TempDir(),
+			MaxDiskBytes:   8,
+			MaxMemoryBytes: 4,
+			MaxMemoryEntry: 2,
+			TTL:            -time.Second,
+		})
+		require.NoError(t, err)
+
+		cache.Put(context.TODO(), "small", bytes.NewReader([]byte{1}))
+		cache.Put(context.TODO(), "large", bytes.NewReader([]byte{1, 2, 3}))
+
+		_, ok := readCached(t, cache, "small")
+		assert.False(t, ok)
+		_, ok = readCached(t, cache, "large")
+		assert.False(t, ok)
+	})
+
+	t.Run("when restarted should serve results left on disk", func(t *testing.T) {
+		dir := t.TempDir()
+		cache := newTestCache(t, dir)
+		cache.Put(context.TODO(), "result", bytes.NewReader([]byte{1, 2, 3}))
+		os.WriteFile(filepath.Join(dir, ".put-orphan"), []byte{1}, 0o600)
+
+		reloaded := newTestCache(t, dir)
+
+		data, ok := readCached(t, reloaded, "result")
+		assert.True(t, ok)
+		assert.Equal(t, []byte{1, 2, 3}, data)
+		assert.NoFileExists(t, filepath.Join(dir, ".put-orphan"))
+	})
+
+	t.Run("when key escapes the directory should reject it", func(t *testing.T) {
+		cache := newTestCache(t, t.TempDir())
+
+		err := cache.Put(context.TODO(), "../result", bytes.NewReader(nil))
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+
+		_, ok := readCached(t, cache, "../../etc/passwd")
+		assert.False(t, ok)
+	})
+}
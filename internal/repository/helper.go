@@ -2,6 +2,9 @@ package repository
 
 import (
 	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -28,3 +31,29 @@ func EncodeCursor(t time.Time) string {
 
 	return base64.StdEncoding.EncodeToString([]byte(timeString))
 }
+
+// DecodeIDCursor will decode a cursor made by EncodeIDCursor
+func DecodeIDCursor(encoded string) (time.Time, int64, error) {
+	byt, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	timeString, idString, found := strings.Cut(string(byt), ",")
+	if !found {
+		return time.Time{}, 0, errors.New("cursor has no id")
+	}
+	t, err := time.Parse(timeFormat, timeString)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	id, err := strconv.ParseInt(idString, 10, 64)
+	return t, id, err
+}
+
+// EncodeIDCursor will encode the position of a record for tables where created_at
+// is not unique, the id orders the records sharing it. EncodeCursor would skip records
+// sharing the created_at of the last one of a page.
+func EncodeIDCursor(t time.Time, id int64) string {
+	return base64.StdEncoding.EncodeToString([]byte(t.Format(timeFormat) + "," + strconv.FormatInt(id, 10)))
+}
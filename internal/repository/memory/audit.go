@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository"
+)
+
+// AuditRepository keeps the audit log in memory, for tests and setups without a
+// database. Records are lost with the process.
+type AuditRepository struct {
+	mu      sync.Mutex
+	records []domain.PdfAuditRecord
+}
+
+// NewAuditRepository will create an in-memory implementation of pdf.AuditRepository
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{}
+}
+
+func (m *AuditRepository) Store(ctx context.Context, record *domain.PdfAuditRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record.ID = int64(len(m.records)) + 1
+	stored := *record
+	stored.Params = maps.Clone(record.Params)
+	m.records = append(m.records, stored)
+	return nil
+}
+
+// Fetch pages by created_at and id like the MySQL implementation, records are kept
+// in the order they were stored
+func (m *AuditRepository) Fetch(ctx context.Context, filter domain.PdfAuditFilter, cursor string, num int64) ([]domain.PdfAuditRecord, string, error) {
+	after, afterID, err := repository.DecodeIDCursor(cursor)
+	if err != nil && cursor != "" {
+		return nil, "", domain.ErrBadParamInput
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res := make([]domain.PdfAuditRecord, 0)
+	for _, record := range m.records {
+		if int64(len(res)) == num {
+			break
+		}
+		if !beyond(record, after, afterID) || !matches(filter, record) {
+			continue
+		}
+		record.Params = maps.Clone(record.Params)
+		res = append(res, record)
+	}
+
+	nextCursor := ""
+	if len(res) == int(num) {
+		last := res[len(res)-1]
+		nextCursor = repository.EncodeIDCursor(last.CreatedAt, last.ID)
+	}
+	return res, nextCursor, nil
+}
+
+// beyond tells whether record comes after the one at createdAt with id. Cursors keep
+// milliseconds, as does the created_at column.
+func beyond(record domain.PdfAuditRecord, createdAt time.Time, id int64) bool {
+	recordCreatedAt := record.CreatedAt.Truncate(time.Millisecond)
+	return recordCreatedAt.After(createdAt) || recordCreatedAt.Equal(createdAt) && record.ID > id
+}
+
+func matches(filter domain.PdfAuditFilter, record domain.PdfAuditRecord) bool {
+	switch {
+	case filter.Caller != "" && record.Caller != filter.Caller,
+		filter.Operation != "" && record.Operation != filter.Operation,
+		filter.Outcome != "" && record.Outcome != filter.Outcome,
+		filter.InputSHA256 != "" && record.InputSHA256 != filter.InputSHA256,
+		!filter.From.IsZero() && record.CreatedAt.Before(filter.From),
+		!filter.To.IsZero() && !record.CreatedAt.Before(filter.To):
+		return false
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository/memory"
+)
+
+func TestAuditRepository(t *testing.T) {
+	start := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	repo := memory.NewAuditRepository()
+	for i, outcome := range []string{domain.AuditSucceeded, domain.AuditFailed, domain.AuditSucceeded, domain.AuditSucceeded} {
+		record := &domain.PdfAuditRecord{Operation: "compress", Outcome: outcome, CreatedAt: start.Add(time.Duration(i) * time.Minute)}
+		require.NoError(t, repo.Store(context.TODO(), record))
+		assert.Equal(t, int64(i+1), record.ID)
+	}
+
+	t.Run("when paging should follow the cursor to the end", func(t *testing.T) {
+		filter := domain.PdfAuditFilter{Outcome: domain.AuditSucceeded}
+
+		first, cursor, err := repo.Fetch(context.TODO(), filter, "", 2)
+		require.NoError(t, err)
+		second, last, err := repo.Fetch(context.TODO(), filter, cursor, 2)
+		require.NoError(t, err)
+
+		require.Len(t, first, 2)
+		assert.Equal(t, []int64{1, 3}, []int64{first[0].ID, first[1].ID})
+		require.Len(t, second, 1)
+		assert.Equal(t, int64(4), second[0].ID)
+		assert.Empty(t, last)
+	})
+
+	t.Run("when records share a millisecond across pages should return each of them once", func(t *testing.T) {
+		repo := memory.NewAuditRepository()
+		for range 3 {
+			require.NoError(t, repo.Store(context.TODO(), &domain.PdfAuditRecord{Operation: "compress", CreatedAt: start}))
+		}
+
+		first, cursor, err := repo.Fetch(context.TODO(), domain.PdfAuditFilter{}, "", 2)
+		require.NoError(t, err)
+		second, _, err := repo.Fetch(context.TODO(), domain.PdfAuditFilter{}, cursor, 2)
+		require.NoError(t, err)
+
+		require.Len(t, first, 2)
+		assert.Equal(t, []int64{1, 2}, []int64{first[0].ID, first[1].ID})
+		require.Len(t, second, 1)
+		assert.Equal(t, int64(3), second[0].ID)
+	})
+
+	t.Run("when filtered by time should include from and exclude to", func(t *testing.T) {
+		filter := domain.PdfAuditFilter{From: start.Add(time.Minute), To: start.Add(3 * time.Minute)}
+
+		list, _, err := repo.Fetch(context.TODO(), filter, "", 10)
+
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, []int64{2, 3}, []int64{list[0].ID, list[1].ID})
+	})
+
+	t.Run("when the cursor is invalid should return ErrBadParamInput", func(t *testing.T) {
+		_, _, err := repo.Fetch(context.TODO(), domain.PdfAuditFilter{}, "not a cursor", 10)
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	})
+}
@@ -3,10 +3,16 @@
 package mocks
 
 import (
+	context "context"
 	io "io"
 
-	model "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	domain "github.com/bxcodec/go-clean-arch/domain"
+
 	mock "github.com/stretchr/testify/mock"
+
+	model "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+
+	pdfcpu "github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 )
 
 // PdfCpuApi is an autogenerated mock type for the PdfCpuApi type
@@ -14,17 +20,133 @@ type PdfCpuApi struct {
 	mock.Mock
 }
 
-// MergeCreateFile provides a mock function with given fields: inFiles, outFile, dividerPage, conf
-func (_m *PdfCpuApi) MergeCreateFile(inFiles []string, outFile string, dividerPage bool, conf *model.Configuration) error {
-	ret := _m.Called(inFiles, outFile, dividerPage, conf)
+// Analyze provides a mock function with given fields: ctx, rs, conf
+func (_m *PdfCpuApi) Analyze(ctx context.Context, rs io.ReadSeeker, conf *model.Configuration) (domain.PdfAnalysis, error) {
+	ret := _m.Called(ctx, rs, conf)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Analyze")
+	}
+
+	var r0 domain.PdfAnalysis
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, *model.Configuration) (domain.PdfAnalysis, error)); ok {
+		return rf(ctx, rs, conf)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, *model.Configuration) domain.PdfAnalysis); ok {
+		r0 = rf(ctx, rs, conf)
+	} else {
+		r0 = ret.Get(0).(domain.PdfAnalysis)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.ReadSeeker, *model.Configuration) error); ok {
+		r1 = rf(ctx, rs, conf)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Bookmarks provides a mock function with given fields: ctx, rs, conf
+func (_m *PdfCpuApi) Bookmarks(ctx context.Context, rs io.ReadSeeker, conf *model.Configuration) ([]pdfcpu.Bookmark, error) {
+	ret := _m.Called(ctx, rs, conf)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Bookmarks")
+	}
+
+	var r0 []pdfcpu.Bookmark
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, *model.Configuration) ([]pdfcpu.Bookmark, error)); ok {
+		return rf(ctx, rs, conf)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, *model.Configuration) []pdfcpu.Bookmark); ok {
+		r0 = rf(ctx, rs, conf)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]pdfcpu.Bookmark)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.ReadSeeker, *model.Configuration) error); ok {
+		r1 = rf(ctx, rs, conf)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ContentBounds provides a mock function with given fields: ctx, rs, pages, conf
+func (_m *PdfCpuApi) ContentBounds(ctx context.Context, rs io.ReadSeeker, pages []int, conf *model.Configuration) (map[int]domain.PdfRect, error) {
+	ret := _m.Called(ctx, rs, pages, conf)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContentBounds")
+	}
+
+	var r0 map[int]domain.PdfRect
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, []int, *model.Configuration) (map[int]domain.PdfRect, error)); ok {
+		return rf(ctx, rs, pages, conf)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, []int, *model.Configuration) map[int]domain.PdfRect); ok {
+		r0 = rf(ctx, rs, pages, conf)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int]domain.PdfRect)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.ReadSeeker, []int, *model.Configuration) error); ok {
+		r1 = rf(ctx, rs, pages, conf)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Describe provides a mock function with given fields: ctx, rs, conf
+func (_m *PdfCpuApi) Describe(ctx context.Context, rs io.ReadSeeker, conf *model.Configuration) (domain.PdfDocumentInfo, error) {
+	ret := _m.Called(ctx, rs, conf)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Describe")
+	}
+
+	var r0 domain.PdfDocumentInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, *model.Configuration) (domain.PdfDocumentInfo, error)); ok {
+		return rf(ctx, rs, conf)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, *model.Configuration) domain.PdfDocumentInfo); ok {
+		r0 = rf(ctx, rs, conf)
+	} else {
+		r0 = ret.Get(0).(domain.PdfDocumentInfo)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.ReadSeeker, *model.Configuration) error); ok {
+		r1 = rf(ctx, rs, conf)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MergeCreateFile provides a mock function with given fields: ctx, inFiles, outFile, dividerPage, conf
+func (_m *PdfCpuApi) MergeCreateFile(ctx context.Context, inFiles []string, outFile string, dividerPage bool, conf *model.Configuration) error {
+	ret := _m.Called(ctx, inFiles, outFile, dividerPage, conf)
 
 	if len(ret) == 0 {
 		panic("no return value specified for MergeCreateFile")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func([]string, string, bool, *model.Configuration) error); ok {
-		r0 = rf(inFiles, outFile, dividerPage, conf)
+	if rf, ok := ret.Get(0).(func(context.Context, []string, string, bool, *model.Configuration) error); ok {
+		r0 = rf(ctx, inFiles, outFile, dividerPage, conf)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -32,17 +154,17 @@ func (_m *PdfCpuApi) MergeCreateFile(inFiles []string, outFile string, dividerPa
 	return r0
 }
 
-// Optimize provides a mock function with given fields: rs, w, conf
-func (_m *PdfCpuApi) Optimize(rs io.ReadSeeker, w io.Writer, conf *model.Configuration) error {
-	ret := _m.Called(rs, w, conf)
+// Optimize provides a mock function with given fields: ctx, rs, w, conf
+func (_m *PdfCpuApi) Optimize(ctx context.Context, rs io.ReadSeeker, w io.Writer, conf *model.Configuration) error {
+	ret := _m.Called(ctx, rs, w, conf)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Optimize")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(io.ReadSeeker, io.Writer, *model.Configuration) error); ok {
-		r0 = rf(rs, w, conf)
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, io.Writer, *model.Configuration) error); ok {
+		r0 = rf(ctx, rs, w, conf)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -50,9 +172,39 @@ func (_m *PdfCpuApi) Optimize(rs io.ReadSeeker, w io.Writer, conf *model.Configu
 	return r0
 }
 
-// PageCount provides a mock function with given fields: rs, conf
-func (_m *PdfCpuApi) PageCount(rs io.ReadSeeker, conf *model.Configuration) (int, error) {
-	ret := _m.Called(rs, conf)
+// PageBoxes provides a mock function with given fields: ctx, rs, conf
+func (_m *PdfCpuApi) PageBoxes(ctx context.Context, rs io.ReadSeeker, conf *model.Configuration) ([]domain.PdfPageBoxes, error) {
+	ret := _m.Called(ctx, rs, conf)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PageBoxes")
+	}
+
+	var r0 []domain.PdfPageBoxes
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, *model.Configuration) ([]domain.PdfPageBoxes, error)); ok {
+		return rf(ctx, rs, conf)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, *model.Configuration) []domain.PdfPageBoxes); ok {
+		r0 = rf(ctx, rs, conf)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.PdfPageBoxes)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.ReadSeeker, *model.Configuration) error); ok {
+		r1 = rf(ctx, rs, conf)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PageCount provides a mock function with given fields: ctx, rs, conf
+func (_m *PdfCpuApi) PageCount(ctx context.Context, rs io.ReadSeeker, conf *model.Configuration) (int, error) {
+	ret := _m.Called(ctx, rs, conf)
 
 	if len(ret) == 0 {
 		panic("no return value specified for PageCount")
@@ -60,17 +212,17 @@ func (_m *PdfCpuApi) PageCount(rs io.ReadSeeker, conf *model.Configuration) (int
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(io.ReadSeeker, *model.Configuration) (int, error)); ok {
-		return rf(rs, conf)
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, *model.Configuration) (int, error)); ok {
+		return rf(ctx, rs, conf)
 	}
-	if rf, ok := ret.Get(0).(func(io.ReadSeeker, *model.Configuration) int); ok {
-		r0 = rf(rs, conf)
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, *model.Configuration) int); ok {
+		r0 = rf(ctx, rs, conf)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(io.ReadSeeker, *model.Configuration) error); ok {
-		r1 = rf(rs, conf)
+	if rf, ok := ret.Get(1).(func(context.Context, io.ReadSeeker, *model.Configuration) error); ok {
+		r1 = rf(ctx, rs, conf)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -78,17 +230,35 @@ func (_m *PdfCpuApi) PageCount(rs io.ReadSeeker, conf *model.Configuration) (int
 	return r0, r1
 }
 
-// Split provides a mock function with given fields: rs, outDir, fileName, span, conf
-func (_m *PdfCpuApi) Split(rs io.ReadSeeker, outDir string, fileName string, span int, conf *model.Configuration) error {
-	ret := _m.Called(rs, outDir, fileName, span, conf)
+// SetPageBoxes provides a mock function with given fields: ctx, rs, w, box, rects, conf
+func (_m *PdfCpuApi) SetPageBoxes(ctx context.Context, rs io.ReadSeeker, w io.Writer, box string, rects map[int]domain.PdfRect, conf *model.Configuration) error {
+	ret := _m.Called(ctx, rs, w, box, rects, conf)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPageBoxes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, io.Writer, string, map[int]domain.PdfRect, *model.Configuration) error); ok {
+		r0 = rf(ctx, rs, w, box, rects, conf)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Split provides a mock function with given fields: ctx, rs, outDir, fileName, span, conf
+func (_m *PdfCpuApi) Split(ctx context.Context, rs io.ReadSeeker, outDir string, fileName string, span int, conf *model.Configuration) error {
+	ret := _m.Called(ctx, rs, outDir, fileName, span, conf)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Split")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(io.ReadSeeker, string, string, int, *model.Configuration) error); ok {
-		r0 = rf(rs, outDir, fileName, span, conf)
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, string, string, int, *model.Configuration) error); ok {
+		r0 = rf(ctx, rs, outDir, fileName, span, conf)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -96,17 +266,35 @@ func (_m *PdfCpuApi) Split(rs io.ReadSeeker, outDir string, fileName string, spa
 	return r0
 }
 
-// SplitByPageNr provides a mock function with given fields: rs, outDir, fileName, pageNrs, conf
-func (_m *PdfCpuApi) SplitByPageNr(rs io.ReadSeeker, outDir string, fileName string, pageNrs []int, conf *model.Configuration) error {
-	ret := _m.Called(rs, outDir, fileName, pageNrs, conf)
+// SplitByPageNr provides a mock function with given fields: ctx, rs, outDir, fileName, pageNrs, conf
+func (_m *PdfCpuApi) SplitByPageNr(ctx context.Context, rs io.ReadSeeker, outDir string, fileName string, pageNrs []int, conf *model.Configuration) error {
+	ret := _m.Called(ctx, rs, outDir, fileName, pageNrs, conf)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SplitByPageNr")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(io.ReadSeeker, string, string, []int, *model.Configuration) error); ok {
-		r0 = rf(rs, outDir, fileName, pageNrs, conf)
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, string, string, []int, *model.Configuration) error); ok {
+		r0 = rf(ctx, rs, outDir, fileName, pageNrs, conf)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StampPages provides a mock function with given fields: ctx, rs, w, stamps, conf
+func (_m *PdfCpuApi) StampPages(ctx context.Context, rs io.ReadSeeker, w io.Writer, stamps map[int]string, conf *model.Configuration) error {
+	ret := _m.Called(ctx, rs, w, stamps, conf)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StampPages")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, io.Writer, map[int]string, *model.Configuration) error); ok {
+		r0 = rf(ctx, rs, w, stamps, conf)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -0,0 +1,143 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository"
+)
+
+type AuditRepository struct {
+	Conn *sql.DB
+}
+
+// NewAuditRepository will create an implementation of pdf.AuditRepository
+func NewAuditRepository(conn *sql.DB) *AuditRepository {
+	return &AuditRepository{conn}
+}
+
+func (m *AuditRepository) Store(ctx context.Context, record *domain.PdfAuditRecord) (err error) {
+	params, err := json.Marshal(record.Params)
+	if err != nil {
+		return
+	}
+
+	query := `INSERT pdf_audit_log SET caller=? , operation=? , params=? , file_name=? , input_sha256=? , input_size=? , page_count=? , output_size=? , duration_ms=? , outcome=? , error=? , created_at=?`
+	stmt, err := m.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, record.Caller, record.Operation, string(params), record.FileName, record.InputSHA256, record.InputSize,
+		record.PageCount, record.OutputSize, record.DurationMs, record.Outcome, record.Error, record.CreatedAt)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	record.ID = lastID
+	return
+}
+
+// Fetch pages by created_at and id. Unlike the articles it doesn't use EncodeCursor:
+// audit records often share a millisecond, and a cursor of created_at alone would skip
+// those past the end of a page, so the cursor carries the id as well.
+func (m *AuditRepository) Fetch(ctx context.Context, filter domain.PdfAuditFilter, cursor string, num int64) (res []domain.PdfAuditRecord, nextCursor string, err error) {
+	decodedCursor, decodedID, err := repository.DecodeIDCursor(cursor)
+	if err != nil && cursor != "" {
+		return nil, "", domain.ErrBadParamInput
+	}
+
+	// records sharing a created_at are told apart by id
+	conditions := []string{"(created_at > ? OR (created_at = ? AND id > ?))"}
+	args := []interface{}{decodedCursor, decodedCursor, decodedID}
+	for _, match := range []struct{ column, value string }{
+		{"caller", filter.Caller},
+		{"operation", filter.Operation},
+		{"outcome", filter.Outcome},
+		{"input_sha256", filter.InputSHA256},
+	} {
+		if match.value != "" {
+			conditions = append(conditions, match.column+" = ?")
+			args = append(args, match.value)
+		}
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.From)
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.To)
+	}
+
+	query := `SELECT id, caller, operation, params, file_name, input_sha256, input_size, page_count, output_size, duration_ms, outcome, error, created_at
+  						FROM pdf_audit_log WHERE ` + strings.Join(conditions, " AND ") + ` ORDER BY created_at, id LIMIT ? `
+	args = append(args, num)
+
+	res, err = m.fetch(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(res) == int(num) {
+		last := res[len(res)-1]
+		nextCursor = repository.EncodeIDCursor(last.CreatedAt, last.ID)
+	}
+
+	return
+}
+
+func (m *AuditRepository) fetch(ctx context.Context, query string, args ...interface{}) (result []domain.PdfAuditRecord, err error) {
+	rows, err := m.Conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		logrus.Error(err)
+		return nil, err
+	}
+
+	defer func() {
+		errRow := rows.Close()
+		if errRow != nil {
+			logrus.Error(errRow)
+		}
+	}()
+
+	result = make([]domain.PdfAuditRecord, 0)
+	for rows.Next() {
+		r := domain.PdfAuditRecord{}
+		params := ""
+		err = rows.Scan(
+			&r.ID,
+			&r.Caller,
+			&r.Operation,
+			&params,
+			&r.FileName,
+			&r.InputSHA256,
+			&r.InputSize,
+			&r.PageCount,
+			&r.OutputSize,
+			&r.DurationMs,
+			&r.Outcome,
+			&r.Error,
+			&r.CreatedAt,
+		)
+		if err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		if err = json.Unmarshal([]byte(params), &r.Params); err != nil {
+			logrus.Error(err)
+			return nil, err
+		}
+		result = append(result, r)
+	}
+
+	return result, nil
+}
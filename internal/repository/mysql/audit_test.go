@@ -0,0 +1,71 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository"
+	mysqlRepo "github.com/bxcodec/go-clean-arch/internal/repository/mysql"
+)
+
+func TestStoreAuditRecord(t *testing.T) {
+	record := &domain.PdfAuditRecord{
+		Caller:      "203.0.113.7",
+		Operation:   "split",
+		Params:      map[string]string{"split_mode": "ranges", "ranges": "1-2"},
+		FileName:    "a.pdf",
+		InputSHA256: "86edbaa24831badfa0a8b04bb410141e2ee4182b6d0014493fe262a7a331c20b",
+		InputSize:   2048,
+		PageCount:   4,
+		OutputSize:  1024,
+		DurationMs:  120,
+		Outcome:     domain.AuditSucceeded,
+		CreatedAt:   time.Now(),
+	}
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	query := "INSERT pdf_audit_log SET caller=\\? , operation=\\? , params=\\? , file_name=\\? , input_sha256=\\? , input_size=\\? , page_count=\\? , output_size=\\? , duration_ms=\\? , outcome=\\? , error=\\? , created_at=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(record.Caller, record.Operation, `{"ranges":"1-2","split_mode":"ranges"}`, record.FileName, record.InputSHA256,
+		record.InputSize, record.PageCount, record.OutputSize, record.DurationMs, record.Outcome, "", record.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(9, 1))
+
+	a := mysqlRepo.NewAuditRepository(db)
+
+	err = a.Store(context.TODO(), record)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9), record.ID)
+}
+
+func TestFetchAuditRecords(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	createdAt := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	from := createdAt.Add(-time.Hour)
+	rows := sqlmock.NewRows([]string{"id", "caller", "operation", "params", "file_name", "input_sha256", "input_size",
+		"page_count", "output_size", "duration_ms", "outcome", "error", "created_at"}).
+		AddRow(1, "203.0.113.7", "compress", "{}", "a.pdf", "abc", 2048, 4, 1024, 120, domain.AuditSucceeded, "", createdAt).
+		AddRow(2, "203.0.113.7", "compress", `{"level":"high"}`, "b.pdf", "abc", 2048, 0, 0, 3, domain.AuditFailed, "broken", createdAt.Add(time.Second))
+
+	query := "SELECT id, caller, operation, params, file_name, input_sha256, input_size, page_count, output_size, duration_ms, outcome, error, created_at\\s+" +
+		"FROM pdf_audit_log WHERE \\(created_at > \\? OR \\(created_at = \\? AND id > \\?\\)\\) AND caller = \\? AND input_sha256 = \\? AND created_at >= \\? ORDER BY created_at, id LIMIT \\?"
+	mock.ExpectQuery(query).WithArgs(createdAt, createdAt, int64(1), "203.0.113.7", "abc", from, int64(2)).WillReturnRows(rows)
+
+	a := mysqlRepo.NewAuditRepository(db)
+
+	cursor := repository.EncodeIDCursor(createdAt, 1)
+	list, nextCursor, err := a.Fetch(context.TODO(), domain.PdfAuditFilter{Caller: "203.0.113.7", InputSHA256: "abc", From: from}, cursor, 2)
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+	assert.Equal(t, map[string]string{"level": "high"}, list[1].Params)
+	assert.Equal(t, repository.EncodeIDCursor(createdAt.Add(time.Second), 2), nextCursor)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,36 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+type WebhookRepository struct {
+	Conn *sql.DB
+}
+
+// NewWebhookRepository will create an implementation of webhook.DeadLetterRepository
+func NewWebhookRepository(conn *sql.DB) *WebhookRepository {
+	return &WebhookRepository{conn}
+}
+
+func (m *WebhookRepository) Store(ctx context.Context, letter *domain.WebhookDeadLetter) (err error) {
+	query := `INSERT webhook_dead_letter SET job_id=? , callback_url=? , payload=? , attempts=? , last_error=? , created_at=?`
+	stmt, err := m.Conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+
+	res, err := stmt.ExecContext(ctx, letter.JobID, letter.CallbackURL, letter.Payload, letter.Attempts, letter.LastError, letter.CreatedAt)
+	if err != nil {
+		return
+	}
+	lastID, err := res.LastInsertId()
+	if err != nil {
+		return
+	}
+	letter.ID = lastID
+	return
+}
@@ -0,0 +1,39 @@
+package mysql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	repository "github.com/bxcodec/go-clean-arch/internal/repository/mysql"
+)
+
+func TestStoreDeadLetter(t *testing.T) {
+	letter := &domain.WebhookDeadLetter{
+		JobID:       "job-1",
+		CallbackURL: "https://partner.example/hook",
+		Payload:     `{"job_id":"job-1"}`,
+		Attempts:    6,
+		LastError:   "webhook answered with status 503",
+		CreatedAt:   time.Now(),
+	}
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+
+	query := "INSERT webhook_dead_letter SET job_id=\\? , callback_url=\\? , payload=\\? , attempts=\\? , last_error=\\? , created_at=\\?"
+	prep := mock.ExpectPrepare(query)
+	prep.ExpectExec().WithArgs(letter.JobID, letter.CallbackURL, letter.Payload, letter.Attempts, letter.LastError, letter.CreatedAt).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+
+	a := repository.NewWebhookRepository(db)
+
+	err = a.Store(context.TODO(), letter)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), letter.ID)
+}
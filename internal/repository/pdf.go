@@ -1,24 +1,43 @@
 package repository
 
 import (
-	"bytes"
+	"context"
 	"fmt"
 	"io"
-	"mime/multipart"
 	"os"
-	"path/filepath"
+	"runtime"
 
-	"github.com/google/uuid"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/bxcodec/go-clean-arch/domain"
 )
 
 //go:generate mockery --name PdfCpuApi
 type PdfCpuApi interface {
-	Optimize(rs io.ReadSeeker, w io.Writer, conf *model.Configuration) error
-	PageCount(rs io.ReadSeeker, conf *model.Configuration) (int, error)
-	Split(rs io.ReadSeeker, outDir, fileName string, span int, conf *model.Configuration) error
-	SplitByPageNr(rs io.ReadSeeker, outDir, fileName string, pageNrs []int, conf *model.Configuration) error
-	MergeCreateFile(inFiles []string, outFile string, dividerPage bool, conf *model.Configuration) (err error)
+	Optimize(ctx context.Context, rs io.ReadSeeker, w io.Writer, conf *model.Configuration) error
+	PageCount(ctx context.Context, rs io.ReadSeeker, conf *model.Configuration) (int, error)
+	Split(ctx context.Context, rs io.ReadSeeker, outDir, fileName string, span int, conf *model.Configuration) error
+	SplitByPageNr(ctx context.Context, rs io.ReadSeeker, outDir, fileName string, pageNrs []int, conf *model.Configuration) error
+	MergeCreateFile(ctx context.Context, inFiles []string, outFile string, dividerPage bool, conf *model.Configuration) (err error)
+	Bookmarks(ctx context.Context, rs io.ReadSeeker, conf *model.Configuration) ([]pdfcpu.Bookmark, error)
+	// Describe reads the size, rotation, text and content hash of every page along with
+	// the document information
+	Describe(ctx context.Context, rs io.ReadSeeker, conf *model.Configuration) (domain.PdfDocumentInfo, error)
+	// StampPages writes rs to w with the text of stamps on top of the page it is keyed by
+	StampPages(ctx context.Context, rs io.ReadSeeker, w io.Writer, stamps map[int]string, conf *model.Configuration) error
+	// PageBoxes reads the boxes in effect on every page
+	PageBoxes(ctx context.Context, rs io.ReadSeeker, conf *model.Configuration) ([]domain.PdfPageBoxes, error)
+	// ContentBounds finds where the given pages draw in user space, pages that draw
+	// nothing are left out
+	ContentBounds(ctx context.Context, rs io.ReadSeeker, pages []int, conf *model.Configuration) (map[int]domain.PdfRect, error)
+	// SetPageBoxes writes rs to w with box, one of the domain.Box constants, set to the
+	// rectangle of the page it is keyed by
+	SetPageBoxes(ctx context.Context, rs io.ReadSeeker, w io.Writer, box string, rects map[int]domain.PdfRect, conf *model.Configuration) error
+	// Analyze sums up the objects and streams of rs and works out what the flate and
+	// jpeg settings would save
+	Analyze(ctx context.Context, rs io.ReadSeeker, conf *model.Configuration) (domain.PdfAnalysis, error)
 }
 
 type FileHelper interface {
@@ -26,97 +45,448 @@ type FileHelper interface {
 }
 
 type PdfRepository struct {
-	pdfCpuApi  PdfCpuApi
-	fileHelper FileHelper
+	pdfCpuApi   PdfCpuApi
+	fileHelper  FileHelper
+	workspaces  *Workspaces
+	parallelism int
 }
 
-func NewPdfRepository(pdfCpuApi PdfCpuApi, fileHelper FileHelper) *PdfRepository {
-	return &PdfRepository{
-		pdfCpuApi:  pdfCpuApi,
-		fileHelper: fileHelper,
+// PdfRepositoryOption configures the optional settings of a PdfRepository
+type PdfRepositoryOption func(*PdfRepository)
+
+// WithParallelism sets how many parts SplitRanges merges at the same time
+func WithParallelism(n int) PdfRepositoryOption {
+	return func(m *PdfRepository) {
+		if n > 0 {
+			m.parallelism = n
+		}
 	}
 }
 
-func (m *PdfRepository) Compress(file multipart.File) ([]byte, error) {
-	tempfile, err := os.CreateTemp("", "compress.*.pdf")
+func NewPdfRepository(pdfCpuApi PdfCpuApi, fileHelper FileHelper, workspaces *Workspaces, opts ...PdfRepositoryOption) *PdfRepository {
+	repo := &PdfRepository{
+		pdfCpuApi:   pdfCpuApi,
+		fileHelper:  fileHelper,
+		workspaces:  workspaces,
+		parallelism: runtime.NumCPU(),
+	}
+	for _, opt := range opts {
+		opt(repo)
+	}
+	return repo
+}
+
+func (m *PdfRepository) Compress(ctx context.Context, file io.ReadSeeker) (io.ReadCloser, int64, error) {
+	return m.writeResult("compressed.pdf", func(output io.Writer) error {
+		domain.ReportProgress(ctx, domain.StepOptimizing, 0, 1)
+		if err := m.pdfCpuApi.Optimize(ctx, file, output, nil); err != nil {
+			return fmt.Errorf("failed to optimize PDF: %w", err)
+		}
+		domain.ReportProgress(ctx, domain.StepOptimizing, 1, 1)
+		return nil
+	})
+}
+
+// writeResult has write fill a file named name in a new workspace that lives until
+// the result is closed
+func (m *PdfRepository) writeResult(name string, write func(output io.Writer) error) (io.ReadCloser, int64, error) {
+	workspace, err := m.workspaces.New()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	output, err := workspace.Create(name)
+	if err != nil {
+		workspace.Close()
+		return nil, 0, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	result := &workspaceFile{File: output, workspace: workspace}
+
+	if err := write(output); err != nil {
+		result.Close()
+		return nil, 0, err
+	}
+
+	size, err := rewind(output)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+		result.Close()
+		return nil, 0, fmt.Errorf("failed to read %s: %w", name, err)
 	}
-	defer func() {
-		tempfile.Close()
-		os.Remove(tempfile.Name())
-	}()
 
-	readSeeker, err := toReadSeeker(file)
+	return result, size, nil
+}
+
+// Split writes every page into its own file inside a workspace and merges the
+// requested pages back together. The workspace lives until the result is closed, or
+// is removed right away when ctx ends first.
+func (m *PdfRepository) Split(ctx context.Context, file io.ReadSeeker, pages []int) (io.ReadCloser, int64, error) {
+	workspace, err := m.workspaces.New()
 	if err != nil {
-		return nil, fmt.Errorf("failed to process input file: %w", err)
+		return nil, 0, err
+	}
+
+	if err := m.pdfCpuApi.Split(ctx, file, workspace.Dir(), "page.pdf", 1, nil); err != nil {
+		workspace.Close()
+		return nil, 0, err
+	}
+
+	outputPath := workspace.Path("output.pdf")
+	if err := m.pdfCpuApi.MergeCreateFile(ctx, pagePaths(workspace, pages), outputPath, false, nil); err != nil {
+		workspace.Close()
+		return nil, 0, fmt.Errorf("failed to merge pdf: %w", err)
 	}
 
-	if err := m.pdfCpuApi.Optimize(readSeeker, tempfile, nil); err != nil {
-		return nil, fmt.Errorf("failed to optimize PDF: %w", err)
+	output, err := m.fileHelper.Open(outputPath)
+	if err != nil {
+		workspace.Close()
+		return nil, 0, fmt.Errorf("failed to open output file: %w", err)
 	}
+	result := &workspaceFile{File: output, workspace: workspace}
 
-	compressedData, err := readTempFile(tempfile)
+	size, err := rewind(output)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read optimized file: %w", err)
+		result.Close()
+		return nil, 0, fmt.Errorf("failed to read output file: %w", err)
 	}
 
-	return compressedData, nil
+	return result, size, nil
 }
 
-func (m *PdfRepository) Split(file multipart.File, pages []int) ([]byte, error) {
-	readSeeker, err := toReadSeeker(file)
+// Merge copies every file into a workspace and merges them in order. The workspace
+// lives until the result is closed.
+func (m *PdfRepository) Merge(ctx context.Context, files []io.ReadSeeker) (io.ReadCloser, int64, error) {
+	workspace, err := m.workspaces.New()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	inputs := make([]string, 0, len(files))
+	for i, file := range files {
+		path, err := copyToWorkspace(ctx, workspace, fmt.Sprintf("input_%d.pdf", i+1), file)
+		if err != nil {
+			workspace.Close()
+			return nil, 0, err
+		}
+		inputs = append(inputs, path)
+	}
+
+	outputPath := workspace.Path("output.pdf")
+	if err := m.pdfCpuApi.MergeCreateFile(ctx, inputs, outputPath, false, nil); err != nil {
+		workspace.Close()
+		return nil, 0, fmt.Errorf("failed to merge pdf: %w", err)
+	}
+
+	output, err := m.fileHelper.Open(outputPath)
+	if err != nil {
+		workspace.Close()
+		return nil, 0, fmt.Errorf("failed to open output file: %w", err)
+	}
+	result := &workspaceFile{File: output, workspace: workspace}
+
+	size, err := rewind(output)
 	if err != nil {
-		return nil, err
+		result.Close()
+		return nil, 0, fmt.Errorf("failed to read output file: %w", err)
 	}
 
-	name := uuid.NewString()
-	if err := m.pdfCpuApi.Split(readSeeker, os.TempDir(), name+".pdf", 1, nil); err != nil {
-		return nil, err
+	return result, size, nil
+}
+
+// Assemble builds a document of pages of files in the given order. Like Split it
+// writes every page of the documents it takes pages from into its own file inside a
+// workspace and merges the pages back together. The workspace lives until the result
+// is closed.
+func (m *PdfRepository) Assemble(ctx context.Context, files []io.ReadSeeker, pages []domain.PageRef) (io.ReadCloser, int64, error) {
+	workspace, err := m.workspaces.New()
+	if err != nil {
+		return nil, 0, err
 	}
 
-	inFiles := make([]string, 0)
+	split := make([]bool, len(files))
+	paths := make([]string, 0, len(pages))
 	for _, page := range pages {
-		inFiles = append(inFiles, filepath.Join(os.TempDir(), fmt.Sprintf("%s_%d%s", name, page, ".pdf")))
+		if page.Document < 0 || page.Document >= len(files) {
+			workspace.Close()
+			return nil, 0, fmt.Errorf("document %d is out of range", page.Document)
+		}
+		name := fmt.Sprintf("document%d.pdf", page.Document+1)
+		if !split[page.Document] {
+			if err := m.pdfCpuApi.Split(ctx, files[page.Document], workspace.Dir(), name, 1, nil); err != nil {
+				workspace.Close()
+				return nil, 0, err
+			}
+			split[page.Document] = true
+		}
+		paths = append(paths, workspace.Path(fmt.Sprintf("document%d_%d.pdf", page.Document+1, page.Page)))
 	}
 
-	outputPath := filepath.Join(os.TempDir(), fmt.Sprintf("%s%s", name, ".pdf"))
-	if err := m.pdfCpuApi.MergeCreateFile(inFiles, outputPath, false, nil); err != nil {
-		return nil, fmt.Errorf("failed to merge pdf: %w", err)
+	outputPath := workspace.Path("output.pdf")
+	if err := m.pdfCpuApi.MergeCreateFile(ctx, paths, outputPath, false, nil); err != nil {
+		workspace.Close()
+		return nil, 0, fmt.Errorf("failed to merge pdf: %w", err)
 	}
 
 	output, err := m.fileHelper.Open(outputPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open output file: %w", err)
+		workspace.Close()
+		return nil, 0, fmt.Errorf("failed to open output file: %w", err)
+	}
+	result := &workspaceFile{File: output, workspace: workspace}
+
+	size, err := rewind(output)
+	if err != nil {
+		result.Close()
+		return nil, 0, fmt.Errorf("failed to read output file: %w", err)
+	}
+
+	return result, size, nil
+}
+
+// Bookmarks flattens the outline of file in document order
+func (m *PdfRepository) Bookmarks(ctx context.Context, file io.ReadSeeker) ([]domain.Bookmark, error) {
+	tree, err := m.pdfCpuApi.Bookmarks(ctx, file, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bookmarks: %w", err)
+	}
+
+	var bookmarks []domain.Bookmark
+	var flatten func(items []pdfcpu.Bookmark, level int)
+	flatten = func(items []pdfcpu.Bookmark, level int) {
+		for _, item := range items {
+			bookmarks = append(bookmarks, domain.Bookmark{Title: item.Title, Page: item.PageFrom, Level: level})
+			flatten(item.Kids, level+1)
+		}
+	}
+	flatten(tree, 0)
+	return bookmarks, nil
+}
+
+// Describe reads the pages and document information of file for comparisons
+func (m *PdfRepository) Describe(ctx context.Context, file io.ReadSeeker) (domain.PdfDocumentInfo, error) {
+	info, err := m.pdfCpuApi.Describe(ctx, file, nil)
+	if err != nil {
+		return domain.PdfDocumentInfo{}, fmt.Errorf("failed to describe pdf: %w", err)
+	}
+	return info, nil
+}
+
+// Analyze sums up how file is built and what the flate and jpeg settings would save
+func (m *PdfRepository) Analyze(ctx context.Context, file io.ReadSeeker) (domain.PdfAnalysis, error) {
+	analysis, err := m.pdfCpuApi.Analyze(ctx, file, nil)
+	if err != nil {
+		return domain.PdfAnalysis{}, fmt.Errorf("failed to analyze pdf: %w", err)
 	}
+	return analysis, nil
+}
+
+// Stamp writes a copy of file with the text of stamps on the pages they are keyed by
+// into a workspace that lives until the result is closed
+func (m *PdfRepository) Stamp(ctx context.Context, file io.ReadSeeker, stamps map[int]string) (io.ReadCloser, int64, error) {
+	return m.writeResult("stamped.pdf", func(output io.Writer) error {
+		if err := m.pdfCpuApi.StampPages(ctx, file, output, stamps, nil); err != nil {
+			return fmt.Errorf("failed to stamp PDF: %w", err)
+		}
+		return nil
+	})
+}
+
+// PageBoxes reads the boxes in effect on every page of file
+func (m *PdfRepository) PageBoxes(ctx context.Context, file io.ReadSeeker) ([]domain.PdfPageBoxes, error) {
+	boxes, err := m.pdfCpuApi.PageBoxes(ctx, file, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page boxes: %w", err)
+	}
+	return boxes, nil
+}
+
+// ContentBounds finds where the given pages of file draw, pages that draw nothing
+// are left out
+func (m *PdfRepository) ContentBounds(ctx context.Context, file io.ReadSeeker, pages []int) (map[int]domain.PdfRect, error) {
+	contentBounds, err := m.pdfCpuApi.ContentBounds(ctx, file, pages, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find content bounds: %w", err)
+	}
+	return contentBounds, nil
+}
+
+// SetBoxes writes a copy of file with box set to the rectangle of the page it is
+// keyed by into a workspace that lives until the result is closed
+func (m *PdfRepository) SetBoxes(ctx context.Context, file io.ReadSeeker, box string, rects map[int]domain.PdfRect) (io.ReadCloser, int64, error) {
+	return m.writeResult("boxed.pdf", func(output io.Writer) error {
+		if err := m.pdfCpuApi.SetPageBoxes(ctx, file, output, box, rects, nil); err != nil {
+			return fmt.Errorf("failed to set page boxes: %w", err)
+		}
+		return nil
+	})
+}
+
+func copyToWorkspace(ctx context.Context, workspace *Workspace, name string, content io.Reader) (string, error) {
+	output, err := workspace.Create(name)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer output.Close()
+
+	if _, err := io.Copy(output, contextReader{ctx: ctx, r: content}); err != nil {
+		return "", fmt.Errorf("failed to copy file: %w", err)
+	}
+	return output.Name(), nil
+}
+
+// Spool copies content into a workspace so it can be read more than once. The
+// workspace is removed when the returned file is closed.
+func (m *PdfRepository) Spool(ctx context.Context, content io.Reader) (domain.SpooledContent, int64, error) {
+	return spool(ctx, m.workspaces, "input.pdf", content)
+}
+
+func spool(ctx context.Context, workspaces *Workspaces, name string, content io.Reader) (*workspaceFile, int64, error) {
+	workspace, err := workspaces.New()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	output, err := workspace.Create(name)
+	if err != nil {
+		workspace.Close()
+		return nil, 0, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	result := &workspaceFile{File: output, workspace: workspace}
+
+	if _, err := io.Copy(output, contextReader{ctx: ctx, r: content}); err != nil {
+		result.Close()
+		return nil, 0, fmt.Errorf("failed to spool file: %w", err)
+	}
+
+	size, err := rewind(output)
+	if err != nil {
+		result.Close()
+		return nil, 0, fmt.Errorf("failed to read spooled file: %w", err)
+	}
+	return result, size, nil
+}
+
+// contextReader stops a copy once ctx ends
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
 
-	splitData, err := readTempFile(output)
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// SplitRanges writes every page into its own file once and merges each range into a
+// separate document, up to parallelism at a time. The parts are handed to emit in the
+// order of ranges and deleted once emit returns, so at most parallelism parts are on
+// disk besides the pages. The first error or the end of ctx stops all of it.
+func (m *PdfRepository) SplitRanges(ctx context.Context, file io.ReadSeeker, ranges [][]int, emit func(index int, part io.Reader, size int64) error) error {
+	workspace, err := m.workspaces.New()
 	if err != nil {
-		return nil, err
+		return err
+	}
+	defer workspace.Close()
+
+	if err := m.pdfCpuApi.Split(ctx, file, workspace.Dir(), "page.pdf", 1, nil); err != nil {
+		return err
+	}
+
+	type part struct {
+		path string
+		err  error
+		done chan struct{}
 	}
+	parts := make([]*part, len(ranges))
+	for i := range ranges {
+		parts[i] = &part{path: workspace.Path(fmt.Sprintf("part_%d.pdf", i+1)), done: make(chan struct{})}
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	// a slot is taken when a part starts and given back once it was emitted
+	slots := make(chan struct{}, m.parallelism)
+
+	g.Go(func() error {
+		for i, pages := range ranges {
+			select {
+			case slots <- struct{}{}:
+			case <-gctx.Done():
+				return gctx.Err()
+			}
+
+			g.Go(func() error {
+				defer close(parts[i].done)
+				// parts are merged side by side, progress is counted as they are emitted
+				mergeCtx := domain.WithProgress(gctx, nil)
+				parts[i].err = m.pdfCpuApi.MergeCreateFile(mergeCtx, pagePaths(workspace, pages), parts[i].path, false, nil)
+				if parts[i].err != nil {
+					return fmt.Errorf("failed to merge pages %v: %w", pages, parts[i].err)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
 
-	return splitData, nil
+	g.Go(func() error {
+		for i, p := range parts {
+			select {
+			case <-p.done:
+			case <-gctx.Done():
+				return gctx.Err()
+			}
+			if p.err != nil {
+				return nil // reported by the merge itself
+			}
+
+			if err := m.emitPart(p.path, i, emit); err != nil {
+				return err
+			}
+			os.Remove(p.path)
+			<-slots
+			domain.ReportProgress(ctx, domain.StepMerging, i+1, len(parts))
+		}
+		return nil
+	})
+
+	return g.Wait()
 }
 
-func (m *PdfRepository) PageCount(file multipart.File) (int, error) {
-	readSeeker, err := toReadSeeker(file)
+func (m *PdfRepository) emitPart(path string, index int, emit func(index int, part io.Reader, size int64) error) error {
+	output, err := m.fileHelper.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open output file: %w", err)
+	}
+	defer output.Close()
+
+	size, err := rewind(output)
 	if err != nil {
-		return 0, fmt.Errorf("failed to process input file: %w", err)
+		return fmt.Errorf("failed to read output file: %w", err)
 	}
-	return m.pdfCpuApi.PageCount(readSeeker, nil)
+	return emit(index, output, size)
 }
 
-func toReadSeeker(file multipart.File) (io.ReadSeeker, error) {
-	buffer := new(bytes.Buffer)
-	if _, err := io.Copy(buffer, file); err != nil {
-		return nil, err
+func pagePaths(workspace *Workspace, pages []int) []string {
+	paths := make([]string, 0, len(pages))
+	for _, page := range pages {
+		paths = append(paths, workspace.Path(fmt.Sprintf("page_%d.pdf", page)))
 	}
-	return bytes.NewReader(buffer.Bytes()), nil
+	return paths
 }
 
-func readTempFile(file *os.File) ([]byte, error) {
+func (m *PdfRepository) PageCount(ctx context.Context, file io.ReadSeeker) (int, error) {
+	return m.pdfCpuApi.PageCount(ctx, file, nil)
+}
+
+// rewind moves file back to its start so it can be streamed and reports its size.
+func rewind(file *os.File) (int64, error) {
 	if _, err := file.Seek(0, io.SeekStart); err != nil {
-		return nil, err
+		return 0, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
 	}
-	return io.ReadAll(file)
+	return info.Size(), nil
 }
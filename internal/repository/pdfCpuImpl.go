@@ -1,30 +1,410 @@
 package repository
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+
+	"github.com/bxcodec/go-clean-arch/domain"
 )
 
+// PdfCpuApiImpl calls pdfcpu in process. A single pdfcpu call can't be interrupted,
+// so ctx is checked before every call and between the pages of splits and merges.
 type PdfCpuApiImpl struct{}
 
-func (p *PdfCpuApiImpl) Optimize(rs io.ReadSeeker, w io.Writer, conf *model.Configuration) error {
+func (p *PdfCpuApiImpl) Optimize(ctx context.Context, rs io.ReadSeeker, w io.Writer, conf *model.Configuration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	return api.Optimize(rs, w, conf)
 }
 
-func (p *PdfCpuApiImpl) Split(rs io.ReadSeeker, outDir, fileName string, span int, conf *model.Configuration) error {
-	return api.Split(rs, outDir, fileName, span, conf)
+// Split writes spans of span pages to outDir the way api.Split names them, checking
+// ctx and reporting progress after every span. A span of zero splits along bookmarks
+// in one go.
+func (p *PdfCpuApiImpl) Split(ctx context.Context, rs io.ReadSeeker, outDir, fileName string, span int, conf *model.Configuration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if span <= 0 {
+		return api.Split(rs, outDir, fileName, span, conf)
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.SPLIT
+
+	pdfCtx, err := api.ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+
+	base := strings.TrimSuffix(filepath.Base(fileName), ".pdf")
+	for from := 1; from <= pdfCtx.PageCount; from += span {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		thru := min(from+span-1, pdfCtx.PageCount)
+		name := fmt.Sprintf("%s_%d-%d.pdf", base, from, thru)
+		if from == thru {
+			name = fmt.Sprintf("%s_%d.pdf", base, from)
+		}
+		if err := writePageSpan(pdfCtx, from, thru, filepath.Join(outDir, name)); err != nil {
+			return err
+		}
+		domain.ReportProgress(ctx, domain.StepSplitting, thru, pdfCtx.PageCount)
+	}
+	return nil
 }
 
-func (p *PdfCpuApiImpl) SplitByPageNr(rs io.ReadSeeker, outDir, fileName string, pageNrs []int, conf *model.Configuration) error {
+func (p *PdfCpuApiImpl) SplitByPageNr(ctx context.Context, rs io.ReadSeeker, outDir, fileName string, pageNrs []int, conf *model.Configuration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	return api.SplitByPageNr(rs, outDir, fileName, pageNrs, conf)
 }
 
-func (p *PdfCpuApiImpl) PageCount(rs io.ReadSeeker, conf *model.Configuration) (int, error) {
+func (p *PdfCpuApiImpl) PageCount(ctx context.Context, rs io.ReadSeeker, conf *model.Configuration) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
 	return api.PageCount(rs, conf)
 }
 
-func (p *PdfCpuApiImpl) MergeCreateFile(inFiles []string, outFile string, dividerPage bool, conf *model.Configuration) (err error) {
-	return api.MergeCreateFile(inFiles, outFile, dividerPage, conf)
+// MergeCreateFile merges inFiles into outFile like api.MergeCreateFile, checking ctx
+// before and reporting progress after every file is appended. outFile is removed when
+// the merge fails.
+func (p *PdfCpuApiImpl) MergeCreateFile(ctx context.Context, inFiles []string, outFile string, dividerPage bool, conf *model.Configuration) (err error) {
+	if len(inFiles) == 0 {
+		return fmt.Errorf("nothing to merge")
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.MERGECREATE
+	conf.ValidationMode = model.ValidationRelaxed
+
+	pdfCtx, err := readContextFile(inFiles[0], conf)
+	if err != nil {
+		return err
+	}
+	if conf.CreateBookmarks {
+		if err := pdfcpu.EnsureOutlines(pdfCtx, filepath.Base(inFiles[0]), false); err != nil {
+			return err
+		}
+	}
+	if pdfCtx.XRefTable.Version() < model.V20 {
+		pdfCtx.EnsureVersionForWriting()
+	}
+
+	domain.ReportProgress(ctx, domain.StepMerging, 1, len(inFiles))
+	for i, inFile := range inFiles[1:] {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		source, err := readContextFile(inFile, pdfCtx.Configuration)
+		if err != nil {
+			return err
+		}
+		if pdfCtx.XRefTable.Version() < model.V20 && source.XRefTable.Version() == model.V20 {
+			return pdfcpu.ErrUnsupportedVersion
+		}
+		if err := pdfcpu.MergeXRefTables(filepath.Base(inFile), source, pdfCtx, false, dividerPage); err != nil {
+			return err
+		}
+		domain.ReportProgress(ctx, domain.StepMerging, i+2, len(inFiles))
+	}
+
+	if err := api.OptimizeContext(pdfCtx); err != nil {
+		return err
+	}
+
+	output, err := os.Create(outFile)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := output.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(outFile)
+		}
+	}()
+
+	return api.WriteContext(pdfCtx, output)
+}
+
+func (p *PdfCpuApiImpl) Bookmarks(ctx context.Context, rs io.ReadSeeker, conf *model.Configuration) ([]pdfcpu.Bookmark, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return api.Bookmarks(rs, conf)
+}
+
+// Describe reads the pages one by one, checking ctx in between. The size of a page
+// is that of its crop box as it is displayed, turned by its rotation.
+func (p *PdfCpuApiImpl) Describe(ctx context.Context, rs io.ReadSeeker, conf *model.Configuration) (domain.PdfDocumentInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.PdfDocumentInfo{}, err
+	}
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+
+	pdfCtx, err := api.ReadAndValidate(rs, conf)
+	if err != nil {
+		return domain.PdfDocumentInfo{}, err
+	}
+	boundaries, err := pdfCtx.PageBoundaries(nil)
+	if err != nil {
+		return domain.PdfDocumentInfo{}, err
+	}
+
+	info := domain.PdfDocumentInfo{Pages: make([]domain.PdfPageInfo, 0, pdfCtx.PageCount), Metadata: documentInfo(pdfCtx)}
+	for i, boundary := range boundaries {
+		if err := ctx.Err(); err != nil {
+			return domain.PdfDocumentInfo{}, err
+		}
+
+		pageDict, _, _, err := pdfCtx.PageDict(i+1, false)
+		if err != nil {
+			return domain.PdfDocumentInfo{}, err
+		}
+		content, err := pdfCtx.PageContent(pageDict)
+		if err != nil && !errors.Is(err, model.ErrNoContent) {
+			return domain.PdfDocumentInfo{}, err
+		}
+
+		dim := boundary.CropBox().Dimensions()
+		if boundary.Rot%180 != 0 {
+			dim.Width, dim.Height = dim.Height, dim.Width
+		}
+		hash := sha256.Sum256(content)
+		info.Pages = append(info.Pages, domain.PdfPageInfo{
+			Width:       dim.Width,
+			Height:      dim.Height,
+			Rotation:    boundary.Rot,
+			Text:        pageText(content),
+			ContentHash: hex.EncodeToString(hash[:]),
+		})
+	}
+	return info, nil
+}
+
+// documentInfo lists the entries of the document information dictionary that are set
+func documentInfo(pdfCtx *model.Context) map[string]string {
+	xRefTable := pdfCtx.XRefTable
+	metadata := map[string]string{}
+	for key, value := range xRefTable.Properties {
+		metadata[key] = value
+	}
+	for key, value := range map[string]string{
+		"Title":        xRefTable.Title,
+		"Subject":      xRefTable.Subject,
+		"Author":       xRefTable.Author,
+		"Keywords":     xRefTable.Keywords,
+		"Creator":      xRefTable.Creator,
+		"Producer":     xRefTable.Producer,
+		"CreationDate": xRefTable.CreationDate,
+		"ModDate":      xRefTable.ModDate,
+	} {
+		if value != "" {
+			metadata[key] = value
+		}
+	}
+	return metadata
+}
+
+// stampStyle draws a stamp as a red label in the top left corner of the page
+const stampStyle = "font:Helvetica, points:12, fillcolor:#C00000, bgcolor:#FFF2A8, border:2 #C00000, margins:4, pos:tl, offset:12 -12, scale:1 abs, rotation:0, opacity:1"
+
+func (p *PdfCpuApiImpl) StampPages(ctx context.Context, rs io.ReadSeeker, w io.Writer, stamps map[int]string, conf *model.Configuration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	watermarks := make(map[int]*model.Watermark, len(stamps))
+	for page, text := range stamps {
+		wm, err := api.TextWatermark(text, stampStyle, true, false, types.POINTS)
+		if err != nil {
+			return err
+		}
+		watermarks[page] = wm
+	}
+	if len(watermarks) == 0 {
+		_, err := io.Copy(w, rs)
+		return err
+	}
+	return api.AddWatermarksMap(rs, w, watermarks, conf)
+}
+
+// Analyze walks the objects of rs, checking ctx in between
+func (p *PdfCpuApiImpl) Analyze(ctx context.Context, rs io.ReadSeeker, conf *model.Configuration) (domain.PdfAnalysis, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.PdfAnalysis{}, err
+	}
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+
+	pdfCtx, err := api.ReadAndValidate(rs, conf)
+	if err != nil {
+		return domain.PdfAnalysis{}, err
+	}
+	return analyze(ctx, pdfCtx)
+}
+
+// PageBoxes reads the boxes in effect on every page
+func (p *PdfCpuApiImpl) PageBoxes(ctx context.Context, rs io.ReadSeeker, conf *model.Configuration) ([]domain.PdfPageBoxes, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+
+	pdfCtx, err := api.ReadAndValidate(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+	boundaries, err := pdfCtx.PageBoundaries(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	boxes := make([]domain.PdfPageBoxes, len(boundaries))
+	for i, boundary := range boundaries {
+		boxes[i] = domain.PdfPageBoxes{
+			Media:    pdfRect(boundary.MediaBox()),
+			Crop:     pdfRect(boundary.CropBox()),
+			Trim:     pdfRect(boundary.TrimBox()),
+			Bleed:    pdfRect(boundary.BleedBox()),
+			Rotation: boundary.Rot,
+		}
+	}
+	return boxes, nil
+}
+
+// ContentBounds reads the pages one by one, checking ctx in between
+func (p *PdfCpuApiImpl) ContentBounds(ctx context.Context, rs io.ReadSeeker, pages []int, conf *model.Configuration) (map[int]domain.PdfRect, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+
+	pdfCtx, err := api.ReadAndValidate(rs, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := newBoundsReader(pdfCtx)
+	contentBounds := make(map[int]domain.PdfRect, len(pages))
+	for _, page := range pages {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if page < 1 || page > pdfCtx.PageCount {
+			return nil, fmt.Errorf("page %d is out of range", page)
+		}
+		rect, ok, err := reader.page(page)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			contentBounds[page] = rect
+		}
+	}
+	return contentBounds, nil
+}
+
+// pageBoxNames maps the boxes of domain to their page dictionary entries
+var pageBoxNames = map[string]string{
+	domain.BoxCrop:  "CropBox",
+	domain.BoxTrim:  "TrimBox",
+	domain.BoxBleed: "BleedBox",
+}
+
+func (p *PdfCpuApiImpl) SetPageBoxes(ctx context.Context, rs io.ReadSeeker, w io.Writer, box string, rects map[int]domain.PdfRect, conf *model.Configuration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	name, ok := pageBoxNames[box]
+	if !ok {
+		return fmt.Errorf("unknown page box %q", box)
+	}
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	conf.Cmd = model.ADDBOXES
+
+	pdfCtx, err := api.ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return err
+	}
+	for page, rect := range rects {
+		pageDict, _, _, err := pdfCtx.PageDict(page, false)
+		if err != nil {
+			return err
+		}
+		if pageDict == nil {
+			return fmt.Errorf("page %d is out of range", page)
+		}
+		pageDict.Update(name, types.NewRectangle(rect.LLX, rect.LLY, rect.URX, rect.URY).Array())
+	}
+	return api.Write(pdfCtx, w, conf)
+}
+
+func pdfRect(rect *types.Rectangle) domain.PdfRect {
+	if rect == nil {
+		return domain.PdfRect{}
+	}
+	return domain.PdfRect{LLX: rect.LL.X, LLY: rect.LL.Y, URX: rect.UR.X, URY: rect.UR.Y}
+}
+
+func readContextFile(name string, conf *model.Configuration) (*model.Context, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return api.ReadAndValidate(file, conf)
+}
+
+func writePageSpan(pdfCtx *model.Context, from, thru int, outPath string) error {
+	span, err := pdfcpu.ExtractPages(pdfCtx, api.PagesForPageRange(from, thru), false)
+	if err != nil {
+		return err
+	}
+
+	output, err := os.Create(outPath)
+	if err != nil {
+		return err
+	}
+	if err := api.WriteContext(span, output); err != nil {
+		output.Close()
+		return err
+	}
+	return output.Close()
 }
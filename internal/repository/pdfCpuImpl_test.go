@@ -0,0 +1,178 @@
+package repository_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPdfCpuApiImpl(t *testing.T) {
+	pdfCpuApi := &repository.PdfCpuApiImpl{}
+
+	t.Run("when split and merge success should keep the selected pages", func(t *testing.T) {
+		input, err := os.Open("../resource/test.pdf")
+		require.NoError(t, err)
+		defer input.Close()
+		dir := t.TempDir()
+
+		err = pdfCpuApi.Split(context.TODO(), input, dir, "page.pdf", 1, nil)
+		require.NoError(t, err)
+
+		output := filepath.Join(dir, "output.pdf")
+		err = pdfCpuApi.MergeCreateFile(context.TODO(), []string{filepath.Join(dir, "page_1.pdf"), filepath.Join(dir, "page_2.pdf")}, output, false, nil)
+		require.NoError(t, err)
+
+		count, err := api.PageCountFile(output)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("when describing should read the pages, their text and the metadata", func(t *testing.T) {
+		input, err := os.Open("../resource/test.pdf")
+		require.NoError(t, err)
+		defer input.Close()
+
+		info, err := pdfCpuApi.Describe(context.TODO(), input, nil)
+
+		require.NoError(t, err)
+		require.Len(t, info.Pages, 12)
+		assert.Equal(t, 504.0, info.Pages[0].Width)
+		assert.Equal(t, []string{"for enterprise"}, info.Pages[0].Text)
+		assert.Equal(t, []string{"Professional", "Asset Licensing", "for all your"}, info.Pages[1].Text[:3])
+		assert.Len(t, info.Pages[0].ContentHash, 64)
+		assert.Equal(t, "Adobe PDF Library 15.0", info.Metadata["Producer"])
+	})
+
+	t.Run("when stamping should change only the stamped pages", func(t *testing.T) {
+		input, err := os.Open("../resource/test.pdf")
+		require.NoError(t, err)
+		defer input.Close()
+		var stamped bytes.Buffer
+
+		err = pdfCpuApi.StampPages(context.TODO(), input, &stamped, map[int]string{2: "Changed: text"}, nil)
+		require.NoError(t, err)
+
+		_, err = input.Seek(0, io.SeekStart)
+		require.NoError(t, err)
+		original, err := pdfCpuApi.Describe(context.TODO(), input, nil)
+		require.NoError(t, err)
+		revised, err := pdfCpuApi.Describe(context.TODO(), bytes.NewReader(stamped.Bytes()), nil)
+		require.NoError(t, err)
+		require.Len(t, revised.Pages, 12)
+		assert.Equal(t, original.Pages[0].ContentHash, revised.Pages[0].ContentHash)
+		assert.NotEqual(t, original.Pages[1].ContentHash, revised.Pages[1].ContentHash)
+	})
+
+	t.Run("when a scan has white borders should set the box around the inked part", func(t *testing.T) {
+		// a 200 x 400 pixel scan with a black block in the middle half of it
+		scan := image.NewGray(image.Rect(0, 0, 200, 400))
+		for y := range 400 {
+			for x := range 200 {
+				shade := uint8(255)
+				if x >= 50 && x < 150 && y >= 100 && y < 300 {
+					shade = 0
+				}
+				scan.SetGray(x, y, color.Gray{Y: shade})
+			}
+		}
+		var encoded, input bytes.Buffer
+		require.NoError(t, png.Encode(&encoded, scan))
+		imp, err := pdfcpu.ParseImportDetails("dim:200 400, pos:bl", types.POINTS)
+		require.NoError(t, err)
+		require.NoError(t, api.ImportImages(nil, &input, []io.Reader{&encoded}, imp, nil))
+
+		contentBounds, err := pdfCpuApi.ContentBounds(context.TODO(), bytes.NewReader(input.Bytes()), []int{1}, nil)
+		require.NoError(t, err)
+		// the image is drawn at half its pixel size from the lower left corner
+		inked := domain.PdfRect{LLX: 25, LLY: 50, URX: 75, URY: 150}
+		assert.Equal(t, map[int]domain.PdfRect{1: inked}, contentBounds)
+
+		var output bytes.Buffer
+		err = pdfCpuApi.SetPageBoxes(context.TODO(), bytes.NewReader(input.Bytes()), &output, domain.BoxCrop, contentBounds, nil)
+		require.NoError(t, err)
+		boxes, err := pdfCpuApi.PageBoxes(context.TODO(), bytes.NewReader(output.Bytes()), nil)
+		require.NoError(t, err)
+		require.Len(t, boxes, 1)
+		assert.Equal(t, inked, boxes[0].Crop)
+		assert.Equal(t, domain.PdfRect{LLX: 0, LLY: 0, URX: 200, URY: 400}, boxes[0].Media)
+	})
+
+	t.Run("when analyzing should find duplicate images and unused objects", func(t *testing.T) {
+		// the same noise scanned twice, stored losslessly
+		scan := image.NewGray(image.Rect(0, 0, 100, 100))
+		for i := range scan.Pix {
+			scan.Pix[i] = uint8(i * 7919 % 251)
+		}
+		var encoded bytes.Buffer
+		require.NoError(t, png.Encode(&encoded, scan))
+		var imported bytes.Buffer
+		imp, err := pdfcpu.ParseImportDetails("dim:100 100, pos:bl", types.POINTS)
+		require.NoError(t, err)
+		require.NoError(t, api.ImportImages(nil, &imported, []io.Reader{bytes.NewReader(encoded.Bytes()), bytes.NewReader(encoded.Bytes())}, imp, nil))
+
+		// an incremental update adds an object nothing refers to
+		pdfCtx, err := api.ReadAndValidate(bytes.NewReader(imported.Bytes()), model.NewDefaultConfiguration())
+		require.NoError(t, err)
+		unused := *pdfCtx.Size
+		var prev int
+		_, err = fmt.Sscanf(string(imported.Bytes()[bytes.LastIndex(imported.Bytes(), []byte("startxref")):]), "startxref\n%d", &prev)
+		require.NoError(t, err)
+		input := bytes.NewBuffer(imported.Bytes())
+		offset := input.Len()
+		fmt.Fprintf(input, "%d 0 obj\n<</Note (left behind)>>\nendobj\n", unused)
+		xref := input.Len()
+		fmt.Fprintf(input, "xref\n%d 1\n%010d 00000 n \ntrailer\n<</Size %d/Root %d 0 R/Prev %d>>\nstartxref\n%d\n%%%%EOF\n",
+			unused, offset, unused+1, pdfCtx.Root.ObjectNumber.Value(), prev, xref)
+
+		analysis, err := pdfCpuApi.Analyze(context.TODO(), bytes.NewReader(input.Bytes()), nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(input.Len()), analysis.FileSize)
+		assert.Equal(t, 2, analysis.Pages)
+		assert.Equal(t, 2, analysis.Streams[domain.StreamImage].Count)
+		require.Len(t, analysis.Duplicates, 1)
+		assert.Equal(t, domain.StreamImage, analysis.Duplicates[0].Kind)
+		assert.Equal(t, analysis.Duplicates[0].Bytes, analysis.Duplicates[0].Wasted)
+		assert.Equal(t, []int{unused}, analysis.Unused.Objects)
+		require.Len(t, analysis.LargestImages, 2)
+		assert.Equal(t, "FlateDecode", analysis.LargestImages[0].Filter)
+		assert.ElementsMatch(t, [][]int{{1}, {2}}, [][]int{analysis.LargestImages[0].Pages, analysis.LargestImages[1].Pages})
+		assert.Len(t, analysis.Savings, 2)
+	})
+
+	t.Run("when context is canceled should not start", func(t *testing.T) {
+		input, err := os.Open("../resource/test.pdf")
+		require.NoError(t, err)
+		defer input.Close()
+		dir := t.TempDir()
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+
+		err = pdfCpuApi.Split(ctx, input, dir, "page.pdf", 1, nil)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		_, err = pdfCpuApi.PageCount(ctx, input, nil)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		output := filepath.Join(dir, "output.pdf")
+		err = pdfCpuApi.MergeCreateFile(ctx, []string{"../resource/test.pdf"}, output, false, nil)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.NoFileExists(t, output)
+	})
+}
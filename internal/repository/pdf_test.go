@@ -1,14 +1,23 @@
 package repository_test
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/bxcodec/go-clean-arch/domain"
 	"github.com/bxcodec/go-clean-arch/internal/repository"
 	"github.com/bxcodec/go-clean-arch/internal/repository/mocks"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCompressPdf(t *testing.T) {
@@ -17,20 +26,24 @@ func TestCompressPdf(t *testing.T) {
 
 	mockPdfCpuApi := new(mocks.PdfCpuApi)
 	mockFileHelper := new(mocks.FileHelper)
-	repo := repository.NewPdfRepository(mockPdfCpuApi, mockFileHelper)
+	workspaces, _ := repository.NewWorkspaces(t.TempDir(), 0)
+	repo := repository.NewPdfRepository(mockPdfCpuApi, mockFileHelper, workspaces)
 
-	t.Run("when compress success should return []byte", func(t *testing.T) {
-		mockPdfCpuApi.On("Optimize", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	t.Run("when compress success should return the optimized file", func(t *testing.T) {
+		mockPdfCpuApi.On("Optimize", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
 
-		_, err := repo.Compress(input)
+		actual, size, err := repo.Compress(context.TODO(), input)
 
 		assert.NoError(t, err)
+		assert.NotNil(t, actual)
+		assert.Equal(t, int64(0), size)
+		assert.NoError(t, actual.Close())
 	})
 
 	t.Run("when compress failed should be return error", func(t *testing.T) {
-		mockPdfCpuApi.On("Optimize", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("Compress Error")).Once()
+		mockPdfCpuApi.On("Optimize", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("Compress Error")).Once()
 
-		_, err := repo.Compress(input)
+		_, _, err := repo.Compress(context.TODO(), input)
 
 		assert.Error(t, err)
 	})
@@ -42,48 +55,69 @@ func TestSplitPdf(t *testing.T) {
 
 	mockPdfCpuApi := new(mocks.PdfCpuApi)
 	mockFileHelper := new(mocks.FileHelper)
-	repo := repository.NewPdfRepository(mockPdfCpuApi, mockFileHelper)
+	workspaces, _ := repository.NewWorkspaces(t.TempDir(), 0)
+	repo := repository.NewPdfRepository(mockPdfCpuApi, mockFileHelper, workspaces)
 
-	t.Run("when split success should return []byte", func(t *testing.T) {
-		mockPdfCpuApi.On("Split", mock.Anything, mock.Anything, mock.Anything, 1, mock.Anything).
+	t.Run("when split success should return the merged file", func(t *testing.T) {
+		mockPdfCpuApi.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything, 1, mock.Anything).
 			Return(nil).Once()
-		mockPdfCpuApi.On("MergeCreateFile", mock.Anything, mock.Anything, false, mock.Anything).
+		mockPdfCpuApi.On("MergeCreateFile", mock.Anything, mock.Anything, mock.Anything, false, mock.Anything).
 			Return(nil).Once()
 		mockFileHelper.On("Open", mock.Anything).Return(mockInput, nil).Once()
 
 		pages := []int{1, 2}
 
-		actual, err := repo.Split(mockInput, pages)
+		actual, _, err := repo.Split(context.TODO(), mockInput, pages)
 
 		assert.NoError(t, err, "Splitting the PDF should not return an error")
 		assert.NotNil(t, actual, "Split data should not be nil")
+
+		actual.Close()
+		usage, _ := workspaces.Usage()
+		assert.Equal(t, 0, usage.Entries, "Closing the result should remove its workspace")
 	})
 
 	t.Run("when split failed should return error", func(t *testing.T) {
-		mockPdfCpuApi.On("Split", mock.Anything, mock.Anything, mock.Anything, 1, mock.Anything).
+		mockPdfCpuApi.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything, 1, mock.Anything).
 			Return(fmt.Errorf("Split Error")).Once()
 
 		pages := []int{1, 2}
 
-		_, err := repo.Split(mockInput, pages)
+		_, _, err := repo.Split(context.TODO(), mockInput, pages)
 
 		assert.Error(t, err)
 	})
 
+	t.Run("when context ends during the merge should remove the workspace", func(t *testing.T) {
+		mockPdfCpuApi.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything, 1, mock.Anything).
+			Return(nil).Once()
+		mockPdfCpuApi.On("MergeCreateFile", mock.Anything, mock.Anything, mock.Anything, false, mock.Anything).
+			Return(context.Canceled).Once()
+
+		_, _, err := repo.Split(context.TODO(), mockInput, []int{1, 2})
+
+		assert.ErrorIs(t, err, context.Canceled)
+		usage, _ := workspaces.Usage()
+		assert.Equal(t, 0, usage.Entries)
+	})
+
 	t.Run("when open file failed should return error", func(t *testing.T) {
 
-		mockPdfCpuApi.On("Split", mock.Anything, mock.Anything, mock.Anything, 1, mock.Anything).
+		mockPdfCpuApi.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything, 1, mock.Anything).
 			Return(nil).Once()
-		mockPdfCpuApi.On("MergeCreateFile", mock.Anything, mock.Anything, false, mock.Anything).
+		mockPdfCpuApi.On("MergeCreateFile", mock.Anything, mock.Anything, mock.Anything, false, mock.Anything).
 			Return(nil).Once()
 		mockFileHelper.On("Open", mock.Anything).Return(nil, fmt.Errorf("Open File Error")).Once()
 
 		pages := []int{1, 2}
 
-		actual, err := repo.Split(mockInput, pages)
+		actual, _, err := repo.Split(context.TODO(), mockInput, pages)
 
 		assert.Error(t, err)
 		assert.Nil(t, actual)
+
+		usage, _ := workspaces.Usage()
+		assert.Equal(t, 0, usage.Entries, "A failed split should not leave its workspace behind")
 	})
 }
 
@@ -93,22 +127,286 @@ func TestPageCount(t *testing.T) {
 
 	mockPdfCpuApi := new(mocks.PdfCpuApi)
 	mockFileHelper := new(mocks.FileHelper)
-	repo := repository.NewPdfRepository(mockPdfCpuApi, mockFileHelper)
+	workspaces, _ := repository.NewWorkspaces(t.TempDir(), 0)
+	repo := repository.NewPdfRepository(mockPdfCpuApi, mockFileHelper, workspaces)
 
 	t.Run("when page count success should be return page number of pdf", func(t *testing.T) {
-		mockPdfCpuApi.On("PageCount", mock.Anything, mock.Anything).Return(1, nil).Once()
+		mockPdfCpuApi.On("PageCount", mock.Anything, mock.Anything, mock.Anything).Return(1, nil).Once()
 
-		actual, err := repo.PageCount(mockInput)
+		actual, err := repo.PageCount(context.TODO(), mockInput)
 
 		assert.NoError(t, err)
 		assert.Equal(t, 1, actual)
 	})
 
 	t.Run("when page count failed should be return error", func(t *testing.T) {
-		mockPdfCpuApi.On("PageCount", mock.Anything, mock.Anything).Return(0, fmt.Errorf("Error Page Count")).Once()
+		mockPdfCpuApi.On("PageCount", mock.Anything, mock.Anything, mock.Anything).Return(0, fmt.Errorf("Error Page Count")).Once()
+
+		_, err := repo.PageCount(context.TODO(), mockInput)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestSplitRanges(t *testing.T) {
+	input, _ := os.Open("../resource/test.pdf")
+	defer input.Close()
+
+	openFile := func(name string) (*os.File, error) { return os.Open(name) }
+
+	t.Run("when split success should emit every part in order", func(t *testing.T) {
+		mockPdfCpuApi := new(mocks.PdfCpuApi)
+		mockFileHelper := new(mocks.FileHelper)
+		workspaces, _ := repository.NewWorkspaces(t.TempDir(), 0)
+		repo := repository.NewPdfRepository(mockPdfCpuApi, mockFileHelper, workspaces, repository.WithParallelism(2))
+
+		var running, maxRunning atomic.Int32
+		mockPdfCpuApi.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything, 1, mock.Anything).Return(nil).Once()
+		mockPdfCpuApi.On("MergeCreateFile", mock.Anything, mock.Anything, mock.Anything, false, mock.Anything).Run(func(args mock.Arguments) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				current := maxRunning.Load()
+				if n <= current || maxRunning.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			// write the page count of the part so the test can tell parts apart
+			os.WriteFile(args.String(2), []byte{byte(len(args.Get(1).([]string)))}, 0o600)
+		}).Return(nil).Times(4)
+		mockFileHelper.On("Open", mock.Anything).Return(openFile).Times(4)
+
+		var emitted []byte
+		err := repo.SplitRanges(context.TODO(), input, [][]int{{1}, {2, 3}, {4, 5, 6}, {7, 8, 9, 10}}, func(index int, part io.Reader, size int64) error {
+			data, _ := io.ReadAll(part)
+			assert.Equal(t, int64(1), size)
+			emitted = append(emitted, data...)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{1, 2, 3, 4}, emitted)
+		assert.LessOrEqual(t, maxRunning.Load(), int32(2))
+		mockPdfCpuApi.AssertNumberOfCalls(t, "Split", 1)
+		usage, _ := workspaces.Usage()
+		assert.Equal(t, 0, usage.Entries)
+	})
+
+	t.Run("when split runs should report progress per emitted part only", func(t *testing.T) {
+		mockPdfCpuApi := new(mocks.PdfCpuApi)
+		mockFileHelper := new(mocks.FileHelper)
+		workspaces, _ := repository.NewWorkspaces(t.TempDir(), 0)
+		repo := repository.NewPdfRepository(mockPdfCpuApi, mockFileHelper, workspaces)
+
+		mockPdfCpuApi.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything, 1, mock.Anything).Return(nil).Once()
+		mockPdfCpuApi.On("MergeCreateFile", mock.Anything, mock.Anything, mock.Anything, false, mock.Anything).Run(func(args mock.Arguments) {
+			// parts merge side by side, their own progress is muted
+			domain.ReportProgress(args.Get(0).(context.Context), domain.StepSplitting, 1, 1)
+			os.WriteFile(args.String(2), []byte{1}, 0o600)
+		}).Return(nil).Times(2)
+		mockFileHelper.On("Open", mock.Anything).Return(openFile).Times(2)
+
+		var steps []string
+		ctx := domain.WithProgress(context.TODO(), func(step string, done, total int) {
+			steps = append(steps, fmt.Sprintf("%s %d/%d", step, done, total))
+		})
+		err := repo.SplitRanges(ctx, input, [][]int{{1}, {2}}, func(int, io.Reader, int64) error { return nil })
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"merging 1/2", "merging 2/2"}, steps)
+	})
+
+	t.Run("when a part fails should stop and remove the workspace", func(t *testing.T) {
+		mockPdfCpuApi := new(mocks.PdfCpuApi)
+		mockFileHelper := new(mocks.FileHelper)
+		workspaces, _ := repository.NewWorkspaces(t.TempDir(), 0)
+		repo := repository.NewPdfRepository(mockPdfCpuApi, mockFileHelper, workspaces, repository.WithParallelism(1))
+
+		mockPdfCpuApi.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything, 1, mock.Anything).Return(nil).Once()
+		mockPdfCpuApi.On("MergeCreateFile", mock.Anything, mock.Anything, mock.Anything, false, mock.Anything).Run(func(args mock.Arguments) {
+			os.WriteFile(args.String(2), []byte{1}, 0o600)
+		}).Return(nil).Once()
+		mockPdfCpuApi.On("MergeCreateFile", mock.Anything, mock.Anything, mock.Anything, false, mock.Anything).Return(fmt.Errorf("Merge Error")).Once()
+		mockFileHelper.On("Open", mock.Anything).Return(openFile)
+
+		emitted := 0
+		err := repo.SplitRanges(context.TODO(), input, [][]int{{1}, {2}, {3}}, func(index int, part io.Reader, size int64) error {
+			emitted++
+			return nil
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, emitted)
+		usage, _ := workspaces.Usage()
+		assert.Equal(t, 0, usage.Entries)
+	})
+
+	t.Run("when split fails should return error without emitting", func(t *testing.T) {
+		mockPdfCpuApi := new(mocks.PdfCpuApi)
+		workspaces, _ := repository.NewWorkspaces(t.TempDir(), 0)
+		repo := repository.NewPdfRepository(mockPdfCpuApi, new(mocks.FileHelper), workspaces)
+
+		mockPdfCpuApi.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything, 1, mock.Anything).Return(fmt.Errorf("Split Error")).Once()
+
+		err := repo.SplitRanges(context.TODO(), input, [][]int{{1}, {2}}, func(int, io.Reader, int64) error {
+			t.Fatal("nothing should be emitted")
+			return nil
+		})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestMerge(t *testing.T) {
+	openFile := func(name string) (*os.File, error) { return os.Open(name) }
+
+	t.Run("when merge success should pass every input in order", func(t *testing.T) {
+		mockPdfCpuApi := new(mocks.PdfCpuApi)
+		mockFileHelper := new(mocks.FileHelper)
+		workspaces, _ := repository.NewWorkspaces(t.TempDir(), 0)
+		repo := repository.NewPdfRepository(mockPdfCpuApi, mockFileHelper, workspaces)
+
+		mockPdfCpuApi.On("MergeCreateFile", mock.Anything, mock.Anything, mock.Anything, false, mock.Anything).Run(func(args mock.Arguments) {
+			var merged []byte
+			for _, input := range args.Get(1).([]string) {
+				data, _ := os.ReadFile(input)
+				merged = append(merged, data...)
+			}
+			os.WriteFile(args.String(2), merged, 0o600)
+		}).Return(nil).Once()
+		mockFileHelper.On("Open", mock.Anything).Return(openFile).Once()
+
+		actual, size, err := repo.Merge(context.TODO(), []io.ReadSeeker{strings.NewReader("a"), strings.NewReader("bc")})
+
+		require.NoError(t, err)
+		data, _ := io.ReadAll(actual)
+		assert.Equal(t, "abc", string(data))
+		assert.Equal(t, int64(3), size)
+		assert.NoError(t, actual.Close())
+		usage, _ := workspaces.Usage()
+		assert.Equal(t, 0, usage.Entries)
+	})
+
+	t.Run("when merge failed should remove the workspace", func(t *testing.T) {
+		mockPdfCpuApi := new(mocks.PdfCpuApi)
+		workspaces, _ := repository.NewWorkspaces(t.TempDir(), 0)
+		repo := repository.NewPdfRepository(mockPdfCpuApi, new(mocks.FileHelper), workspaces)
+
+		mockPdfCpuApi.On("MergeCreateFile", mock.Anything, mock.Anything, mock.Anything, false, mock.Anything).Return(fmt.Errorf("Merge Error")).Once()
 
-		_, err := repo.PageCount(mockInput)
+		_, _, err := repo.Merge(context.TODO(), []io.ReadSeeker{strings.NewReader("a")})
 
 		assert.Error(t, err)
+		usage, _ := workspaces.Usage()
+		assert.Equal(t, 0, usage.Entries)
+	})
+}
+
+func TestAssemble(t *testing.T) {
+	openFile := func(name string) (*os.File, error) { return os.Open(name) }
+
+	t.Run("when assemble success should split every document once and merge the pages in order", func(t *testing.T) {
+		mockPdfCpuApi := new(mocks.PdfCpuApi)
+		mockFileHelper := new(mocks.FileHelper)
+		workspaces, _ := repository.NewWorkspaces(t.TempDir(), 0)
+		repo := repository.NewPdfRepository(mockPdfCpuApi, mockFileHelper, workspaces)
+		target, source := strings.NewReader("target"), strings.NewReader("source")
+
+		mockPdfCpuApi.On("Split", mock.Anything, target, mock.Anything, "document1.pdf", 1, mock.Anything).Return(nil).Once()
+		mockPdfCpuApi.On("Split", mock.Anything, source, mock.Anything, "document2.pdf", 1, mock.Anything).Return(nil).Once()
+		mockPdfCpuApi.On("MergeCreateFile", mock.Anything, mock.Anything, mock.Anything, false, mock.Anything).Run(func(args mock.Arguments) {
+			var names []string
+			for _, input := range args.Get(1).([]string) {
+				names = append(names, filepath.Base(input))
+			}
+			os.WriteFile(args.String(2), []byte(strings.Join(names, ",")), 0o600)
+		}).Return(nil).Once()
+		mockFileHelper.On("Open", mock.Anything).Return(openFile).Once()
+
+		actual, _, err := repo.Assemble(context.TODO(), []io.ReadSeeker{target, source}, []domain.PageRef{
+			{Document: 0, Page: 1}, {Document: 1, Page: 2}, {Document: 0, Page: 2},
+		})
+
+		require.NoError(t, err)
+		data, _ := io.ReadAll(actual)
+		assert.Equal(t, "document1_1.pdf,document2_2.pdf,document1_2.pdf", string(data))
+		assert.NoError(t, actual.Close())
+		mockPdfCpuApi.AssertExpectations(t)
+		usage, _ := workspaces.Usage()
+		assert.Equal(t, 0, usage.Entries)
+	})
+
+	t.Run("when split failed should remove the workspace", func(t *testing.T) {
+		mockPdfCpuApi := new(mocks.PdfCpuApi)
+		workspaces, _ := repository.NewWorkspaces(t.TempDir(), 0)
+		repo := repository.NewPdfRepository(mockPdfCpuApi, new(mocks.FileHelper), workspaces)
+
+		mockPdfCpuApi.On("Split", mock.Anything, mock.Anything, mock.Anything, mock.Anything, 1, mock.Anything).Return(fmt.Errorf("Split Error")).Once()
+
+		_, _, err := repo.Assemble(context.TODO(), []io.ReadSeeker{strings.NewReader("a")}, []domain.PageRef{{Document: 0, Page: 1}})
+
+		assert.Error(t, err)
+		usage, _ := workspaces.Usage()
+		assert.Equal(t, 0, usage.Entries)
+	})
+}
+
+func TestBookmarks(t *testing.T) {
+	mockPdfCpuApi := new(mocks.PdfCpuApi)
+	workspaces, _ := repository.NewWorkspaces(t.TempDir(), 0)
+	repo := repository.NewPdfRepository(mockPdfCpuApi, new(mocks.FileHelper), workspaces)
+
+	t.Run("when bookmarks are nested should flatten them in document order", func(t *testing.T) {
+		mockPdfCpuApi.On("Bookmarks", mock.Anything, mock.Anything, mock.Anything).Return([]pdfcpu.Bookmark{
+			{Title: "Intro", PageFrom: 1, Kids: []pdfcpu.Bookmark{{Title: "Scope", PageFrom: 2}}},
+			{Title: "Details", PageFrom: 3},
+		}, nil).Once()
+
+		actual, err := repo.Bookmarks(context.TODO(), strings.NewReader("%PDF-1.7"))
+
+		require.NoError(t, err)
+		assert.Equal(t, []domain.Bookmark{
+			{Title: "Intro", Page: 1, Level: 0},
+			{Title: "Scope", Page: 2, Level: 1},
+			{Title: "Details", Page: 3, Level: 0},
+		}, actual)
+	})
+
+	t.Run("when reading bookmarks fails should return error", func(t *testing.T) {
+		mockPdfCpuApi.On("Bookmarks", mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("Bookmarks Error")).Once()
+
+		_, err := repo.Bookmarks(context.TODO(), strings.NewReader("%PDF-1.7"))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestSpool(t *testing.T) {
+	workspaces, _ := repository.NewWorkspaces(t.TempDir(), 0)
+	repo := repository.NewPdfRepository(new(mocks.PdfCpuApi), new(mocks.FileHelper), workspaces)
+
+	t.Run("when spool success should be readable until closed", func(t *testing.T) {
+		file, size, err := repo.Spool(context.TODO(), strings.NewReader("%PDF-1.7"))
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(8), size)
+		data, _ := io.ReadAll(file)
+		assert.Equal(t, "%PDF-1.7", string(data))
+
+		file.Close()
+		usage, _ := workspaces.Usage()
+		assert.Equal(t, 0, usage.Entries)
+	})
+
+	t.Run("when context is canceled should return error", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+
+		_, _, err := repo.Spool(ctx, strings.NewReader("%PDF-1.7"))
+
+		assert.ErrorIs(t, err, context.Canceled)
+		usage, _ := workspaces.Usage()
+		assert.Equal(t, 0, usage.Entries)
 	})
 }
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// LocalResultStore keeps processed documents as plain files in a directory
+type LocalResultStore struct {
+	dir string
+}
+
+// NewLocalResultStore will create dir if needed
+func NewLocalResultStore(dir string) (*LocalResultStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create result directory: %w", err)
+	}
+	return &LocalResultStore{dir: dir}, nil
+}
+
+// Put writes content to a temporary name first so a half written result is never served
+func (s *LocalResultStore) Put(ctx context.Context, key string, content io.Reader, size int64) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create result file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to write result file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, fmt.Errorf("failed to store result file: %w", err)
+	}
+	return written, nil
+}
+
+func (s *LocalResultStore) Get(ctx context.Context, key string) (io.ReadCloser, int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, 0, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, err
+	}
+	return file, info.Size(), nil
+}
+
+// Purge removes results stored longer than olderThan ago, i.e. once every link to them expired
+func (s *LocalResultStore) Purge(olderThan time.Duration) (int, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	deadline := time.Now().Add(-olderThan)
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() || info.ModTime().After(deadline) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, entry.Name())); err != nil && !os.IsNotExist(err) {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+func (s *LocalResultStore) Usage() (domain.DiskUsage, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return domain.DiskUsage{}, err
+	}
+
+	var usage domain.DiskUsage
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() {
+			continue
+		}
+		usage.Entries++
+		usage.Bytes += info.Size()
+	}
+	return usage, nil
+}
+
+func (s *LocalResultStore) path(key string) (string, error) {
+	if !isSafeKey(key) {
+		return "", domain.ErrBadParamInput
+	}
+	return filepath.Join(s.dir, key), nil
+}
+
+// isSafeKey reports whether key can be used as a file name without escaping its
+// directory. Names starting with a dot are reserved for files being written.
+func isSafeKey(key string) bool {
+	return key != "" && !strings.HasPrefix(key, ".") && filepath.Base(key) == key
+}
@@ -0,0 +1,73 @@
+package repository_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalResultStore(t *testing.T) {
+	t.Run("when put success should be readable with get", func(t *testing.T) {
+		store, err := repository.NewLocalResultStore(t.TempDir())
+		require.NoError(t, err)
+
+		size, err := store.Put(context.TODO(), "result", bytes.NewReader([]byte{1, 2, 3}), -1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), size)
+
+		content, actualSize, err := store.Get(context.TODO(), "result")
+		require.NoError(t, err)
+		defer content.Close()
+		data, _ := io.ReadAll(content)
+		assert.Equal(t, []byte{1, 2, 3}, data)
+		assert.Equal(t, int64(3), actualSize)
+	})
+
+	t.Run("when key is unknown should return ErrNotFound", func(t *testing.T) {
+		store, err := repository.NewLocalResultStore(t.TempDir())
+		require.NoError(t, err)
+
+		_, _, err = store.Get(context.TODO(), "missing")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("when key escapes the directory should return ErrBadParamInput", func(t *testing.T) {
+		store, err := repository.NewLocalResultStore(t.TempDir())
+		require.NoError(t, err)
+
+		_, err = store.Put(context.TODO(), "../result", bytes.NewReader(nil), 0)
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+
+		_, _, err = store.Get(context.TODO(), "../../etc/passwd")
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	})
+
+	t.Run("when purging should remove results older than the ttl", func(t *testing.T) {
+		dir := t.TempDir()
+		store, err := repository.NewLocalResultStore(dir)
+		require.NoError(t, err)
+
+		store.Put(context.TODO(), "old", bytes.NewReader([]byte{1}), 1)
+		store.Put(context.TODO(), "new", bytes.NewReader([]byte{1, 2}), 2)
+		old := time.Now().Add(-2 * time.Hour)
+		require.NoError(t, os.Chtimes(filepath.Join(dir, "old"), old, old))
+
+		purged, err := store.Purge(time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, purged)
+
+		usage, err := store.Usage()
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DiskUsage{Entries: 1, Bytes: 2}, usage)
+	})
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// infoSuffix names the file that describes an upload next to its data
+const infoSuffix = ".info"
+
+// LocalUploadStore keeps resumable uploads in a directory, the bytes received so far
+// in a file named after the upload and what the client declared in a .info next to it
+type LocalUploadStore struct {
+	dir string
+}
+
+// uploadInfo is what a .info file holds
+type uploadInfo struct {
+	Length   int64             `json:"length"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// NewLocalUploadStore will create dir if needed
+func NewLocalUploadStore(dir string) (*LocalUploadStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create upload directory: %w", err)
+	}
+	return &LocalUploadStore{dir: dir}, nil
+}
+
+// Create writes the .info first, an upload without one does not exist yet
+func (s *LocalUploadStore) Create(ctx context.Context, upload domain.ResumableUpload) error {
+	path, err := s.path(upload.ID)
+	if err != nil {
+		return err
+	}
+
+	info, err := json.Marshal(uploadInfo{Length: upload.Length, Metadata: upload.Metadata})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+infoSuffix, info, 0o600); err != nil {
+		return fmt.Errorf("failed to write upload info: %w", err)
+	}
+
+	data, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		os.Remove(path + infoSuffix)
+		return fmt.Errorf("failed to create upload file: %w", err)
+	}
+	return data.Close()
+}
+
+func (s *LocalUploadStore) Get(ctx context.Context, id string) (domain.ResumableUpload, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return domain.ResumableUpload{}, err
+	}
+
+	raw, err := os.ReadFile(path + infoSuffix)
+	if os.IsNotExist(err) {
+		return domain.ResumableUpload{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.ResumableUpload{}, err
+	}
+	var info uploadInfo
+	if err := json.Unmarshal(raw, &info); err != nil {
+		return domain.ResumableUpload{}, fmt.Errorf("failed to read upload info: %w", err)
+	}
+
+	stat, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return domain.ResumableUpload{}, domain.ErrNotFound
+	}
+	if err != nil {
+		return domain.ResumableUpload{}, err
+	}
+
+	return domain.ResumableUpload{
+		ID:        id,
+		Length:    info.Length,
+		Offset:    stat.Size(),
+		Metadata:  info.Metadata,
+		UpdatedAt: stat.ModTime(),
+	}, nil
+}
+
+// Append copies content to the end of the upload. The file is synced before the new
+// offset is reported, so a client never resumes past bytes a crash could lose.
+func (s *LocalUploadStore) Append(ctx context.Context, id string, offset int64, content io.Reader) (int64, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if os.IsNotExist(err) {
+		return 0, domain.ErrNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer data.Close()
+
+	stat, err := data.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if stat.Size() != offset {
+		return stat.Size(), domain.ErrOffsetMismatch
+	}
+	if _, err := data.Seek(offset, io.SeekStart); err != nil {
+		return offset, err
+	}
+
+	written, err := io.Copy(data, contextReader{ctx: ctx, r: content})
+	if syncErr := data.Sync(); err == nil {
+		err = syncErr
+	}
+	if err != nil {
+		return offset + written, fmt.Errorf("failed to write upload: %w", err)
+	}
+	// the modification time is the latest write, also for an empty chunk
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		return offset + written, err
+	}
+	return offset + written, nil
+}
+
+func (s *LocalUploadStore) Truncate(ctx context.Context, id string, offset int64) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	return os.Truncate(path, offset)
+}
+
+func (s *LocalUploadStore) Open(ctx context.Context, id string) (domain.SpooledContent, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, domain.ErrNotFound
+	}
+	return file, err
+}
+
+// Delete removes the .info last, so an upload is either complete or gone
+func (s *LocalUploadStore) Delete(ctx context.Context, id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Remove(path + infoSuffix); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Purge removes uploads not written to for olderThan, and .info files left without data
+func (s *LocalUploadStore) Purge(olderThan time.Duration) (int, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	deadline := time.Now().Add(-olderThan)
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() || info.ModTime().After(deadline) {
+			continue
+		}
+
+		id := strings.TrimSuffix(entry.Name(), infoSuffix)
+		if id != entry.Name() {
+			if _, err := os.Stat(filepath.Join(s.dir, id)); err == nil {
+				// the data decides, it is written to long after the .info
+				continue
+			}
+		}
+		if err := s.Delete(context.Background(), id); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+func (s *LocalUploadStore) Usage() (domain.DiskUsage, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return domain.DiskUsage{}, err
+	}
+
+	var usage domain.DiskUsage
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() || strings.HasSuffix(entry.Name(), infoSuffix) {
+			continue
+		}
+		usage.Entries++
+		usage.Bytes += info.Size()
+	}
+	return usage, nil
+}
+
+func (s *LocalUploadStore) path(id string) (string, error) {
+	if !isSafeKey(id) || strings.HasSuffix(id, infoSuffix) {
+		return "", domain.ErrNotFound
+	}
+	return filepath.Join(s.dir, id), nil
+}
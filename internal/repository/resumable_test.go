@@ -0,0 +1,106 @@
+package repository_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalUploadStore(t *testing.T) {
+	upload := domain.ResumableUpload{ID: "abc", Length: 6, Metadata: map[string]string{"filename": "scan.pdf"}}
+
+	t.Run("when chunks are appended should track the offset and read back whole", func(t *testing.T) {
+		store, err := repository.NewLocalUploadStore(t.TempDir())
+		require.NoError(t, err)
+		require.NoError(t, store.Create(context.TODO(), upload))
+
+		offset, err := store.Append(context.TODO(), "abc", 0, strings.NewReader("123"))
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), offset)
+		offset, err = store.Append(context.TODO(), "abc", 3, strings.NewReader("456"))
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), offset)
+
+		actual, err := store.Get(context.TODO(), "abc")
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), actual.Offset)
+		assert.Equal(t, int64(6), actual.Length)
+		assert.Equal(t, "scan.pdf", actual.Metadata["filename"])
+
+		content, err := store.Open(context.TODO(), "abc")
+		require.NoError(t, err)
+		defer content.Close()
+		data, _ := io.ReadAll(content)
+		assert.Equal(t, "123456", string(data))
+	})
+
+	t.Run("when the offset is not the stored size should return ErrOffsetMismatch", func(t *testing.T) {
+		store, err := repository.NewLocalUploadStore(t.TempDir())
+		require.NoError(t, err)
+		require.NoError(t, store.Create(context.TODO(), upload))
+
+		_, err = store.Append(context.TODO(), "abc", 2, strings.NewReader("123"))
+
+		assert.ErrorIs(t, err, domain.ErrOffsetMismatch)
+	})
+
+	t.Run("when truncated should resume from the offset", func(t *testing.T) {
+		store, err := repository.NewLocalUploadStore(t.TempDir())
+		require.NoError(t, err)
+		require.NoError(t, store.Create(context.TODO(), upload))
+		_, err = store.Append(context.TODO(), "abc", 0, strings.NewReader("12345"))
+		require.NoError(t, err)
+
+		require.NoError(t, store.Truncate(context.TODO(), "abc", 2))
+
+		actual, err := store.Get(context.TODO(), "abc")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), actual.Offset)
+	})
+
+	t.Run("when the id escapes the directory should return ErrNotFound", func(t *testing.T) {
+		store, err := repository.NewLocalUploadStore(t.TempDir())
+		require.NoError(t, err)
+
+		_, err = store.Get(context.TODO(), "../abc")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		_, err = store.Get(context.TODO(), "abc.info")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("when purged should remove uploads not written to since and keep the others", func(t *testing.T) {
+		dir := t.TempDir()
+		store, err := repository.NewLocalUploadStore(dir)
+		require.NoError(t, err)
+		require.NoError(t, store.Create(context.TODO(), upload))
+		require.NoError(t, store.Create(context.TODO(), domain.ResumableUpload{ID: "fresh", Length: 1}))
+
+		old := time.Now().Add(-2 * time.Hour)
+		require.NoError(t, os.Chtimes(filepath.Join(dir, "abc"), old, old))
+		require.NoError(t, os.Chtimes(filepath.Join(dir, "abc.info"), old, old))
+		// the .info of an upload in progress is as old as the upload itself
+		require.NoError(t, os.Chtimes(filepath.Join(dir, "fresh.info"), old, old))
+
+		purged, err := store.Purge(time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+
+		_, err = store.Get(context.TODO(), "abc")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		_, err = store.Get(context.TODO(), "fresh")
+		assert.NoError(t, err)
+
+		usage, err := store.Usage()
+		require.NoError(t, err)
+		assert.Equal(t, 1, usage.Entries)
+	})
+}
@@ -0,0 +1,60 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/minio/minio-go/v7"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// unknownSizePartSize bounds the buffer minio allocates per part when the size of a
+// result is not known upfront. Its default for that case is several hundred MB.
+const unknownSizePartSize = 16 << 20
+
+// ResultStore keeps processed documents in an S3 compatible bucket
+type ResultStore struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewResultStore will create an object that represent the download.ResultStore interface
+func NewResultStore(client *minio.Client, bucket string) *ResultStore {
+	return &ResultStore{
+		client: client,
+		bucket: bucket,
+	}
+}
+
+func (s *ResultStore) Put(ctx context.Context, key string, content io.Reader, size int64) (int64, error) {
+	opts := minio.PutObjectOptions{ContentType: "application/octet-stream"}
+	if size < 0 {
+		opts.PartSize = unknownSizePartSize
+	}
+
+	info, err := s.client.PutObject(ctx, s.bucket, key, content, size, opts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	return info.Size, nil
+}
+
+func (s *ResultStore) Get(ctx context.Context, key string) (io.ReadCloser, int64, error) {
+	object, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	info, err := object.Stat()
+	if err != nil {
+		object.Close()
+		if minio.ToErrorResponse(err).StatusCode == http.StatusNotFound {
+			return nil, 0, domain.ErrNotFound
+		}
+		return nil, 0, fmt.Errorf("failed to fetch %s: %w", key, err)
+	}
+	return object, info.Size, nil
+}
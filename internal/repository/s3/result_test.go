@@ -0,0 +1,106 @@
+package s3_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	s3Repo "github.com/bxcodec/go-clean-arch/internal/repository/s3"
+)
+
+// fakeS3 is a MinIO style stand-in that keeps objects in memory. It only speaks
+// the path style PUT, GET and HEAD object calls the result store needs.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[key] = body
+		w.Header().Set("ETag", `"etag"`)
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet, http.MethodHead:
+		body, ok := f.objects[key]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			if r.Method == http.MethodGet {
+				io.WriteString(w, `<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`)
+			}
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			w.Write(body)
+		}
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func newStore(t *testing.T) (*s3Repo.ResultStore, *fakeS3) {
+	fake := &fakeS3{objects: map[string][]byte{}}
+	srv := httptest.NewTLSServer(fake)
+	t.Cleanup(srv.Close)
+
+	endpoint, _ := url.Parse(srv.URL)
+	client, err := minio.New(endpoint.Host, &minio.Options{
+		Creds:     credentials.NewStaticV4("access", "secret", ""),
+		Secure:    true,
+		Region:    "us-east-1",
+		Transport: srv.Client().Transport,
+	})
+	require.NoError(t, err)
+
+	return s3Repo.NewResultStore(client, "results"), fake
+}
+
+func TestResultStore(t *testing.T) {
+	t.Run("when put success should be readable with get", func(t *testing.T) {
+		store, fake := newStore(t)
+
+		size, err := store.Put(context.TODO(), "result", bytes.NewReader([]byte{1, 2, 3}), 3)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), size)
+		assert.Equal(t, []byte{1, 2, 3}, fake.objects["results/result"])
+
+		content, actualSize, err := store.Get(context.TODO(), "result")
+		require.NoError(t, err)
+		defer content.Close()
+		data, _ := io.ReadAll(content)
+		assert.Equal(t, []byte{1, 2, 3}, data)
+		assert.Equal(t, int64(3), actualSize)
+	})
+
+	t.Run("when key is unknown should return ErrNotFound", func(t *testing.T) {
+		store, _ := newStore(t)
+
+		_, _, err := store.Get(context.TODO(), "missing")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// DeniedNetworks are never connected to unless listed in OutboundConfig.AllowedNetworks:
+// loopback, private, link-local, shared, documentation and otherwise reserved ranges,
+// and the NAT64, Teredo and 6to4 prefixes that carry IPv4 addresses inside them.
+var DeniedNetworks = mustParseCIDRs(
+	"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8", "169.254.0.0/16",
+	"172.16.0.0/12", "192.0.0.0/24", "192.0.2.0/24", "192.168.0.0/16", "198.18.0.0/15",
+	"198.51.100.0/24", "203.0.113.0/24", "224.0.0.0/4", "240.0.0.0/4",
+	"::/128", "::1/128", "64:ff9b::/96", "64:ff9b:1::/48", "2001::/32", "2001:db8::/32",
+	"2002::/16", "fc00::/7", "fe80::/10", "ff00::/8",
+)
+
+// OutboundConfig restricts where requests on behalf of clients may go
+type OutboundConfig struct {
+	// AllowedHosts limits the hosts, "*.example.com" matches any subdomain. Empty
+	// allows every host that doesn't resolve to a denied network.
+	AllowedHosts []string
+	// AllowedNetworks are connected to even if they are in DeniedNetworks
+	AllowedNetworks []*net.IPNet
+	Timeout         time.Duration
+	MaxRedirects    int
+}
+
+// outboundClient sends requests on behalf of clients. Every address is checked when
+// the connection is made, so neither redirects nor DNS can lead it to a denied network.
+type outboundClient struct {
+	config OutboundConfig
+	*http.Client
+}
+
+func newOutboundClient(config OutboundConfig) *outboundClient {
+	c := &outboundClient{config: config}
+
+	dialer := &net.Dialer{Timeout: config.Timeout, Control: c.checkAddress}
+	c.Client = &http.Client{
+		Timeout: config.Timeout,
+		Transport: &http.Transport{
+			// a proxy would make the connection on our behalf, past checkAddress
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   config.Timeout,
+			ResponseHeaderTimeout: config.Timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > config.MaxRedirects {
+				return fmt.Errorf("%w: too many redirects", domain.ErrSourceUnavailable)
+			}
+			return c.checkURL(req.URL)
+		},
+	}
+	return c
+}
+
+// SourceFetcher downloads documents into workspaces
+type SourceFetcher struct {
+	client     *outboundClient
+	workspaces *Workspaces
+}
+
+func NewSourceFetcher(config OutboundConfig, workspaces *Workspaces) *SourceFetcher {
+	return &SourceFetcher{client: newOutboundClient(config), workspaces: workspaces}
+}
+
+// Fetch downloads rawURL into a workspace that is removed when the content is closed.
+// Documents larger than maxBytes are rejected, zero means no limit.
+func (f *SourceFetcher) Fetch(ctx context.Context, rawURL string, maxBytes int64) (domain.SourceFile, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return domain.SourceFile{}, fmt.Errorf("%w: %s", domain.ErrForbiddenSource, err)
+	}
+	if err := f.client.checkURL(u); err != nil {
+		return domain.SourceFile{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return domain.SourceFile{}, fmt.Errorf("%w: %s", domain.ErrForbiddenSource, err)
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrForbiddenSource), errors.Is(err, domain.ErrSourceUnavailable):
+			return domain.SourceFile{}, err
+		case ctx.Err() != nil:
+			return domain.SourceFile{}, ctx.Err()
+		}
+		return domain.SourceFile{}, fmt.Errorf("%w: %s", domain.ErrSourceUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return domain.SourceFile{}, fmt.Errorf("%w: status %d", domain.ErrSourceUnavailable, resp.StatusCode)
+	}
+	if maxBytes > 0 && resp.ContentLength > maxBytes {
+		return domain.SourceFile{}, domain.ErrFileTooLarge
+	}
+
+	var body io.Reader = resp.Body
+	if maxBytes > 0 {
+		body = io.LimitReader(resp.Body, maxBytes+1)
+	}
+	content, size, err := spool(ctx, f.workspaces, "source", body)
+	if err != nil {
+		return domain.SourceFile{}, err
+	}
+	if maxBytes > 0 && size > maxBytes {
+		content.Close()
+		return domain.SourceFile{}, domain.ErrFileTooLarge
+	}
+
+	return domain.SourceFile{
+		Name:        sourceFileName(resp),
+		ContentType: resp.Header.Get("Content-Type"),
+		Content:     content,
+		Size:        size,
+	}, nil
+}
+
+func (c *outboundClient) checkURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: only http and https are supported", domain.ErrForbiddenSource)
+	}
+	if u.User != nil {
+		return fmt.Errorf("%w: credentials in the url are not supported", domain.ErrForbiddenSource)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return fmt.Errorf("%w: missing host", domain.ErrForbiddenSource)
+	}
+	if len(c.config.AllowedHosts) == 0 {
+		return nil
+	}
+	for _, allowed := range c.config.AllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed || (strings.HasPrefix(allowed, "*.") && strings.HasSuffix(host, allowed[1:])) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: host %s is not allowed", domain.ErrForbiddenSource, host)
+}
+
+// checkAddress runs right before a connection is made, on the resolved address
+func (c *outboundClient) checkAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("%w: unresolved address %s", domain.ErrForbiddenSource, host)
+	}
+
+	for _, allowed := range c.config.AllowedNetworks {
+		if allowed.Contains(ip) {
+			return nil
+		}
+	}
+	for _, denied := range DeniedNetworks {
+		if denied.Contains(ip) {
+			return fmt.Errorf("%w: address %s is not allowed", domain.ErrForbiddenSource, ip)
+		}
+	}
+	return nil
+}
+
+// sourceFileName prefers the name the server suggests over the last path segment
+func sourceFileName(resp *http.Response) string {
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		if name := path.Base(params["filename"]); params["filename"] != "" && name != "/" && name != "." {
+			return name
+		}
+	}
+	if name := path.Base(resp.Request.URL.Path); name != "/" && name != "." {
+		return name
+	}
+	return "document"
+}
+
+// ParseCIDRs parses a list of networks such as "10.1.0.0/16"
+func ParseCIDRs(cidrs ...string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks, err := ParseCIDRs(cidrs...)
+	if err != nil {
+		panic(err)
+	}
+	return networks
+}
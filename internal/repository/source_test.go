@@ -0,0 +1,129 @@
+package repository_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceFetcher(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/report", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `attachment; filename="statement.pdf"`)
+		io.WriteString(w, "%PDF-1.7")
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+		// no Content-Length, so the limit has to hold while reading
+		w.(http.Flusher).Flush()
+		io.WriteString(w, strings.Repeat("x", 64))
+	})
+	mux.HandleFunc("/missing", http.NotFound)
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/redirect", http.StatusFound)
+	})
+	mux.HandleFunc("/metadata", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data", http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	loopback, err := repository.ParseCIDRs("127.0.0.0/8")
+	require.NoError(t, err)
+	workspaces, _ := repository.NewWorkspaces(t.TempDir(), 0)
+	fetcher := repository.NewSourceFetcher(repository.OutboundConfig{
+		AllowedNetworks: loopback,
+		Timeout:         5 * time.Second,
+		MaxRedirects:    2,
+	}, workspaces)
+
+	t.Run("when download success should spool the document until closed", func(t *testing.T) {
+		source, err := fetcher.Fetch(context.TODO(), server.URL+"/report", 1024)
+		require.NoError(t, err)
+
+		assert.Equal(t, "statement.pdf", source.Name)
+		assert.Equal(t, "application/pdf", source.ContentType)
+		assert.Equal(t, int64(8), source.Size)
+		data, _ := io.ReadAll(source.Content)
+		assert.Equal(t, "%PDF-1.7", string(data))
+
+		source.Content.Close()
+		usage, _ := workspaces.Usage()
+		assert.Equal(t, 0, usage.Entries)
+	})
+
+	t.Run("when network is not allowed should return ErrForbiddenSource", func(t *testing.T) {
+		denying := repository.NewSourceFetcher(repository.OutboundConfig{Timeout: 5 * time.Second}, workspaces)
+
+		_, err := denying.Fetch(context.TODO(), server.URL+"/report", 0)
+
+		assert.ErrorIs(t, err, domain.ErrForbiddenSource)
+	})
+
+	t.Run("when redirect leads to a denied network should return ErrForbiddenSource", func(t *testing.T) {
+		_, err := fetcher.Fetch(context.TODO(), server.URL+"/metadata", 0)
+
+		assert.ErrorIs(t, err, domain.ErrForbiddenSource)
+	})
+
+	t.Run("when host is not allowed should return ErrForbiddenSource", func(t *testing.T) {
+		restricted := repository.NewSourceFetcher(repository.OutboundConfig{
+			AllowedHosts:    []string{"*.docs.internal"},
+			AllowedNetworks: loopback,
+		}, workspaces)
+
+		_, err := restricted.Fetch(context.TODO(), server.URL+"/report", 0)
+
+		assert.ErrorIs(t, err, domain.ErrForbiddenSource)
+	})
+
+	t.Run("when scheme is not http should return ErrForbiddenSource", func(t *testing.T) {
+		_, err := fetcher.Fetch(context.TODO(), "file:///etc/passwd", 0)
+
+		assert.ErrorIs(t, err, domain.ErrForbiddenSource)
+	})
+
+	t.Run("when redirects exceed the limit should return ErrSourceUnavailable", func(t *testing.T) {
+		_, err := fetcher.Fetch(context.TODO(), server.URL+"/redirect", 0)
+
+		assert.ErrorIs(t, err, domain.ErrSourceUnavailable)
+	})
+
+	t.Run("when server answers with an error should return ErrSourceUnavailable", func(t *testing.T) {
+		_, err := fetcher.Fetch(context.TODO(), server.URL+"/missing", 0)
+
+		assert.ErrorIs(t, err, domain.ErrSourceUnavailable)
+	})
+
+	t.Run("when document exceeds the limit should return ErrFileTooLarge", func(t *testing.T) {
+		_, err := fetcher.Fetch(context.TODO(), server.URL+"/large", 16)
+
+		assert.ErrorIs(t, err, domain.ErrFileTooLarge)
+		usage, _ := workspaces.Usage()
+		assert.Equal(t, 0, usage.Entries)
+	})
+}
+
+func TestDeniedNetworks(t *testing.T) {
+	for _, address := range []string{
+		"10.0.0.1", "192.0.0.8", "192.0.2.1", "198.18.0.1", "198.51.100.1", "203.0.113.7",
+		"64:ff9b::a9fe:a9fe", "64:ff9b:1::a00:1", "2001::1", "2001:db8::1", "2002:7f00:1::1",
+	} {
+		t.Run("when address is "+address+" should be denied", func(t *testing.T) {
+			ip := net.ParseIP(address)
+
+			assert.True(t, slices.ContainsFunc(repository.DeniedNetworks, func(network *net.IPNet) bool { return network.Contains(ip) }))
+		})
+	}
+}
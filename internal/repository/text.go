@@ -0,0 +1,302 @@
+package repository
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// kerningSpace is how far a TJ adjustment, in thousandths of an em, has to move the
+// next glyph to the right to read as a space
+const kerningSpace = -250
+
+// pageText lists the lines of text a content stream draws, in drawing order.
+// Strings are read as they are encoded, which reads well for the simple encodings of
+// most text fonts. Composite fonts come out garbled but still compare.
+func pageText(content []byte) []string {
+	s := &contentScanner{content: content}
+	var lines []string
+	var line strings.Builder
+	newLine := func() {
+		if text := strings.Join(strings.Fields(line.String()), " "); text != "" {
+			lines = append(lines, text)
+		}
+		line.Reset()
+	}
+
+	var operands []contentOperand
+	for {
+		token, ok := s.next()
+		if !ok {
+			break
+		}
+		if !token.operator {
+			operands = append(operands, token.operand)
+			continue
+		}
+
+		switch token.text {
+		case "BT", "ET", "T*", "Tm":
+			newLine()
+		case "Td", "TD":
+			if len(operands) == 2 && operands[1].number != 0 {
+				newLine()
+			}
+		case "Tj":
+			for _, operand := range operands {
+				line.WriteString(operand.text)
+			}
+		case "'", "\"":
+			newLine()
+			if len(operands) > 0 {
+				line.WriteString(operands[len(operands)-1].text)
+			}
+		case "TJ":
+			for _, operand := range operands {
+				for _, element := range operand.array {
+					if element.isString {
+						line.WriteString(element.text)
+					} else if element.number < kerningSpace {
+						line.WriteByte(' ')
+					}
+				}
+			}
+		case "BI":
+			s.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+	newLine()
+	return lines
+}
+
+// contentOperand is a string, a number, a name or an array of them. Dictionaries
+// don't matter for the content and are kept empty.
+type contentOperand struct {
+	isString bool
+	text     string
+	number   float64
+	name     string
+	array    []contentOperand
+}
+
+type contentToken struct {
+	operator bool
+	text     string
+	operand  contentOperand
+}
+
+// contentScanner splits a content stream into operands and operators
+type contentScanner struct {
+	content []byte
+	pos     int
+}
+
+func (s *contentScanner) next() (contentToken, bool) {
+	s.skipSpace()
+	if s.pos >= len(s.content) {
+		return contentToken{}, false
+	}
+
+	switch c := s.content[s.pos]; {
+	case c == '(':
+		return contentToken{operand: contentOperand{isString: true, text: decodeText(s.literalString())}}, true
+	case c == '<' && s.peek(1) == '<', c == '>' && s.peek(1) == '>':
+		s.pos += 2
+		return contentToken{}, true
+	case c == '<':
+		return contentToken{operand: contentOperand{isString: true, text: decodeText(s.hexString())}}, true
+	case c == '[':
+		s.pos++
+		var array []contentOperand
+		for {
+			s.skipSpace()
+			if s.pos >= len(s.content) || s.content[s.pos] == ']' {
+				s.pos++
+				return contentToken{operand: contentOperand{array: array}}, true
+			}
+			token, ok := s.next()
+			if !ok {
+				return contentToken{operand: contentOperand{array: array}}, true
+			}
+			array = append(array, token.operand)
+		}
+	case c == '/':
+		s.pos++
+		return contentToken{operand: contentOperand{name: s.regular()}}, true
+	case isDelimiter(c):
+		// a stray delimiter such as ')' or '{'
+		s.pos++
+		return contentToken{}, true
+	}
+
+	word := s.regular()
+	if number, err := strconv.ParseFloat(word, 64); err == nil {
+		return contentToken{operand: contentOperand{number: number}}, true
+	}
+	return contentToken{operator: true, text: word}, true
+}
+
+func (s *contentScanner) peek(offset int) byte {
+	if s.pos+offset < len(s.content) {
+		return s.content[s.pos+offset]
+	}
+	return 0
+}
+
+func (s *contentScanner) skipSpace() {
+	for s.pos < len(s.content) {
+		switch c := s.content[s.pos]; {
+		case isSpace(c):
+			s.pos++
+		case c == '%':
+			for s.pos < len(s.content) && s.content[s.pos] != '\n' && s.content[s.pos] != '\r' {
+				s.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// regular reads up to the next white space or delimiter
+func (s *contentScanner) regular() string {
+	start := s.pos
+	for s.pos < len(s.content) && !isSpace(s.content[s.pos]) && !isDelimiter(s.content[s.pos]) {
+		s.pos++
+	}
+	return string(s.content[start:s.pos])
+}
+
+// literalString reads a string in balanced parentheses and resolves its escapes
+func (s *contentScanner) literalString() []byte {
+	var out []byte
+	depth := 0
+	for s.pos++; s.pos < len(s.content); s.pos++ {
+		c := s.content[s.pos]
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				s.pos++
+				return out
+			}
+			depth--
+		case '\\':
+			s.pos++
+			if s.pos >= len(s.content) {
+				return out
+			}
+			c = s.content[s.pos]
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r', '\n':
+				// a line continuation
+				if c == '\r' && s.peek(1) == '\n' {
+					s.pos++
+				}
+				continue
+			default:
+				if c >= '0' && c <= '7' {
+					value := 0
+					for i := 0; i < 3 && s.pos < len(s.content) && s.content[s.pos] >= '0' && s.content[s.pos] <= '7'; i++ {
+						value = value*8 + int(s.content[s.pos]-'0')
+						s.pos++
+					}
+					s.pos--
+					c = byte(value)
+				}
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func (s *contentScanner) hexString() []byte {
+	var digits []byte
+	for s.pos++; s.pos < len(s.content) && s.content[s.pos] != '>'; s.pos++ {
+		if c := s.content[s.pos]; !isSpace(c) {
+			digits = append(digits, c)
+		}
+	}
+	s.pos++
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+
+	out := make([]byte, 0, len(digits)/2)
+	for i := 0; i < len(digits); i += 2 {
+		value, err := strconv.ParseUint(string(digits[i:i+2]), 16, 8)
+		if err != nil {
+			return out
+		}
+		out = append(out, byte(value))
+	}
+	return out
+}
+
+// skipInlineImage skips the data of an inline image up to its EI operator
+func (s *contentScanner) skipInlineImage() {
+	start := bytes.Index(s.content[s.pos:], []byte("ID"))
+	if start < 0 {
+		s.pos = len(s.content)
+		return
+	}
+	for i := s.pos + start + 3; i+2 <= len(s.content); i++ {
+		if s.content[i] == 'E' && s.content[i+1] == 'I' && isSpace(s.content[i-1]) &&
+			(i+2 == len(s.content) || isSpace(s.content[i+2])) {
+			s.pos = i + 2
+			return
+		}
+	}
+	s.pos = len(s.content)
+}
+
+// decodeText reads UTF-16 strings by their byte order mark and anything else byte by
+// byte, dropping control characters
+func decodeText(b []byte) string {
+	var runes []rune
+	if len(b) >= 2 && b[0] == 0xfe && b[1] == 0xff {
+		units := make([]uint16, 0, len(b)/2)
+		for i := 2; i+1 < len(b); i += 2 {
+			units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+		}
+		runes = utf16.Decode(units)
+	} else {
+		runes = make([]rune, 0, len(b))
+		for _, c := range b {
+			runes = append(runes, rune(c))
+		}
+	}
+
+	var text strings.Builder
+	for _, r := range runes {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+			text.WriteByte(' ')
+		case r >= 0x20 && (r < 0x7f || r > 0x9f):
+			text.WriteRune(r)
+		}
+	}
+	return text.String()
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == 0
+}
+
+func isDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
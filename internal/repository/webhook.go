@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// WebhookPoster sends webhooks with the same restrictions as source downloads, so a
+// callback url can't reach internal services either
+type WebhookPoster struct {
+	client *outboundClient
+}
+
+func NewWebhookPoster(config OutboundConfig) *WebhookPoster {
+	return &WebhookPoster{client: newOutboundClient(config)}
+}
+
+// Post sends body to rawURL. Anything but a 2xx answer is an error.
+func (p *WebhookPoster) Post(ctx context.Context, rawURL string, header http.Header, body []byte) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if err := p.client.checkURL(u); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header = header.Clone()
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// drain a little so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered with status %d", resp.StatusCode)
+	}
+	return nil
+}
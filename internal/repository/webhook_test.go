@@ -0,0 +1,56 @@
+package repository_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookPoster(t *testing.T) {
+	var received http.Header
+	var body []byte
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hook", func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header
+		body, _ = io.ReadAll(r.Body)
+	})
+	mux.HandleFunc("/failing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	loopback, _ := repository.ParseCIDRs("127.0.0.0/8")
+	poster := repository.NewWebhookPoster(repository.OutboundConfig{AllowedNetworks: loopback, Timeout: 5 * time.Second})
+
+	t.Run("when receiver accepts should post the body with the headers", func(t *testing.T) {
+		err := poster.Post(context.TODO(), server.URL+"/hook", http.Header{"X-Webhook-Signature": {"sha256=abc"}}, []byte(`{"status":"succeeded"}`))
+
+		require.NoError(t, err)
+		assert.Equal(t, "sha256=abc", received.Get("X-Webhook-Signature"))
+		assert.Equal(t, "application/json", received.Get("Content-Type"))
+		assert.Equal(t, `{"status":"succeeded"}`, string(body))
+	})
+
+	t.Run("when receiver fails should return error", func(t *testing.T) {
+		err := poster.Post(context.TODO(), server.URL+"/failing", http.Header{}, []byte(`{}`))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("when network is not allowed should return ErrForbiddenSource", func(t *testing.T) {
+		denying := repository.NewWebhookPoster(repository.OutboundConfig{Timeout: 5 * time.Second})
+
+		err := denying.Post(context.TODO(), server.URL+"/hook", http.Header{}, []byte(`{}`))
+
+		assert.ErrorIs(t, err, domain.ErrForbiddenSource)
+	})
+}
@@ -0,0 +1,639 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// Operations of the worker protocol, one per PdfCpuApi method
+const (
+	workerOptimize        = "optimize"
+	workerPageCount       = "page_count"
+	workerSplit           = "split"
+	workerSplitByPageNr   = "split_by_page_nr"
+	workerMergeCreateFile = "merge_create_file"
+	workerBookmarks       = "bookmarks"
+	workerDescribe        = "describe"
+	workerStampPages      = "stamp_pages"
+	workerPageBoxes       = "page_boxes"
+	workerContentBounds   = "content_bounds"
+	workerSetPageBoxes    = "set_page_boxes"
+	workerAnalyze         = "analyze"
+)
+
+// workerRequest is a PdfCpuApi call. Documents travel as paths, a worker reads and
+// writes the files of its parent.
+type workerRequest struct {
+	Op          string                 `json:"op"`
+	Input       string                 `json:"input,omitempty"`
+	Output      string                 `json:"output,omitempty"`
+	OutDir      string                 `json:"out_dir,omitempty"`
+	FileName    string                 `json:"file_name,omitempty"`
+	Span        int                    `json:"span,omitempty"`
+	PageNrs     []int                  `json:"page_nrs,omitempty"`
+	InFiles     []string               `json:"in_files,omitempty"`
+	DividerPage bool                   `json:"divider_page,omitempty"`
+	Stamps      map[int]string         `json:"stamps,omitempty"`
+	Box         string                 `json:"box,omitempty"`
+	Rects       map[int]domain.PdfRect `json:"rects,omitempty"`
+}
+
+// workerProgress is what domain.ReportProgress was called with in the worker
+type workerProgress struct {
+	Step  string `json:"step"`
+	Done  int    `json:"done"`
+	Total int    `json:"total"`
+}
+
+// workerResponse ends a call, or reports its progress when Progress is set
+type workerResponse struct {
+	Progress  *workerProgress         `json:"progress,omitempty"`
+	Pages     int                     `json:"pages,omitempty"`
+	Bookmarks []pdfcpu.Bookmark       `json:"bookmarks,omitempty"`
+	Document  *domain.PdfDocumentInfo `json:"document,omitempty"`
+	Boxes     []domain.PdfPageBoxes   `json:"boxes,omitempty"`
+	Bounds    map[int]domain.PdfRect  `json:"bounds,omitempty"`
+	Analysis  *domain.PdfAnalysis     `json:"analysis,omitempty"`
+	Error     string                  `json:"error,omitempty"`
+	// Unprocessable is set when pdfcpu panicked on the document
+	Unprocessable bool `json:"unprocessable,omitempty"`
+}
+
+// WorkerLimits caps the resources of a worker, zero means no limit
+type WorkerLimits struct {
+	// CPUTime is the processor time a single call may use
+	CPUTime time.Duration
+	// Memory is the memory the worker may hold
+	Memory int64
+}
+
+// args passes the limits to a worker on its command line
+func (l WorkerLimits) args() []string {
+	return []string{"-max-cpu", l.CPUTime.String(), "-max-memory", strconv.FormatInt(l.Memory, 10)}
+}
+
+// WorkerConfig describes a pool of worker processes
+type WorkerConfig struct {
+	// Command starts a worker, a program that calls ServeWorker such as pdfworker
+	Command string
+	Args    []string
+	// Size is the number of workers, and so of calls running at the same time
+	Size   int
+	Limits WorkerLimits
+	// TempDir holds copies of documents that are not files yet, empty means os.TempDir
+	TempDir string
+}
+
+// WorkerPool runs pdfcpu in child processes, so a document that crashes pdfcpu or
+// makes it spin only takes a worker down. A worker that crashed, ran out of its
+// limits or was abandoned because ctx ended is replaced on the next call, and the
+// call fails with domain.ErrUnprocessable or the error of ctx.
+//
+// Workers run with the configuration pdfcpu defaults to, conf is not passed on.
+type WorkerPool struct {
+	config WorkerConfig
+	// idle holds the workers waiting for a call, nil stands for one to start
+	idle chan *worker
+}
+
+// NewWorkerPool will start config.Size workers, so a missing command fails right away
+func NewWorkerPool(config WorkerConfig) (*WorkerPool, error) {
+	if config.Size <= 0 {
+		config.Size = 1
+	}
+	pool := &WorkerPool{
+		config: config,
+		idle:   make(chan *worker, config.Size),
+	}
+	for range config.Size {
+		w, err := startWorker(config)
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+		pool.idle <- w
+	}
+	return pool, nil
+}
+
+// Close stops the workers once their calls are done
+func (p *WorkerPool) Close() error {
+	for range len(p.idle) {
+		if w := <-p.idle; w != nil {
+			w.stop()
+		}
+	}
+	return nil
+}
+
+func (p *WorkerPool) Optimize(ctx context.Context, rs io.ReadSeeker, w io.Writer, conf *model.Configuration) error {
+	input, cleanup, err := p.inputFile(rs)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	output, err := os.CreateTemp(p.config.TempDir, "pdfworker-*.pdf")
+	if err != nil {
+		return fmt.Errorf("failed to create worker output: %w", err)
+	}
+	defer os.Remove(output.Name())
+	defer output.Close()
+
+	if _, err := p.call(ctx, workerRequest{Op: workerOptimize, Input: input, Output: output.Name()}); err != nil {
+		return err
+	}
+	_, err = io.Copy(w, output)
+	return err
+}
+
+func (p *WorkerPool) PageCount(ctx context.Context, rs io.ReadSeeker, conf *model.Configuration) (int, error) {
+	input, cleanup, err := p.inputFile(rs)
+	if err != nil {
+		return 0, err
+	}
+	defer cleanup()
+
+	resp, err := p.call(ctx, workerRequest{Op: workerPageCount, Input: input})
+	return resp.Pages, err
+}
+
+func (p *WorkerPool) Split(ctx context.Context, rs io.ReadSeeker, outDir, fileName string, span int, conf *model.Configuration) error {
+	input, cleanup, err := p.inputFile(rs)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	_, err = p.call(ctx, workerRequest{Op: workerSplit, Input: input, OutDir: outDir, FileName: fileName, Span: span})
+	return err
+}
+
+func (p *WorkerPool) SplitByPageNr(ctx context.Context, rs io.ReadSeeker, outDir, fileName string, pageNrs []int, conf *model.Configuration) error {
+	input, cleanup, err := p.inputFile(rs)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	_, err = p.call(ctx, workerRequest{Op: workerSplitByPageNr, Input: input, OutDir: outDir, FileName: fileName, PageNrs: pageNrs})
+	return err
+}
+
+// MergeCreateFile removes outFile when the merge fails, also when the worker died
+// halfway through writing it
+func (p *WorkerPool) MergeCreateFile(ctx context.Context, inFiles []string, outFile string, dividerPage bool, conf *model.Configuration) error {
+	_, err := p.call(ctx, workerRequest{Op: workerMergeCreateFile, InFiles: inFiles, Output: outFile, DividerPage: dividerPage})
+	if err != nil {
+		os.Remove(outFile)
+	}
+	return err
+}
+
+func (p *WorkerPool) Bookmarks(ctx context.Context, rs io.ReadSeeker, conf *model.Configuration) ([]pdfcpu.Bookmark, error) {
+	input, cleanup, err := p.inputFile(rs)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	resp, err := p.call(ctx, workerRequest{Op: workerBookmarks, Input: input})
+	return resp.Bookmarks, err
+}
+
+func (p *WorkerPool) Describe(ctx context.Context, rs io.ReadSeeker, conf *model.Configuration) (domain.PdfDocumentInfo, error) {
+	input, cleanup, err := p.inputFile(rs)
+	if err != nil {
+		return domain.PdfDocumentInfo{}, err
+	}
+	defer cleanup()
+
+	resp, err := p.call(ctx, workerRequest{Op: workerDescribe, Input: input})
+	if err != nil {
+		return domain.PdfDocumentInfo{}, err
+	}
+	if resp.Document == nil {
+		return domain.PdfDocumentInfo{}, errors.New("worker returned no document")
+	}
+	return *resp.Document, nil
+}
+
+func (p *WorkerPool) Analyze(ctx context.Context, rs io.ReadSeeker, conf *model.Configuration) (domain.PdfAnalysis, error) {
+	input, cleanup, err := p.inputFile(rs)
+	if err != nil {
+		return domain.PdfAnalysis{}, err
+	}
+	defer cleanup()
+
+	resp, err := p.call(ctx, workerRequest{Op: workerAnalyze, Input: input})
+	if err != nil {
+		return domain.PdfAnalysis{}, err
+	}
+	if resp.Analysis == nil {
+		return domain.PdfAnalysis{}, errors.New("worker returned no analysis")
+	}
+	return *resp.Analysis, nil
+}
+
+func (p *WorkerPool) StampPages(ctx context.Context, rs io.ReadSeeker, w io.Writer, stamps map[int]string, conf *model.Configuration) error {
+	input, cleanup, err := p.inputFile(rs)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	output, err := os.CreateTemp(p.config.TempDir, "pdfworker-*.pdf")
+	if err != nil {
+		return fmt.Errorf("failed to create worker output: %w", err)
+	}
+	defer os.Remove(output.Name())
+	defer output.Close()
+
+	if _, err := p.call(ctx, workerRequest{Op: workerStampPages, Input: input, Output: output.Name(), Stamps: stamps}); err != nil {
+		return err
+	}
+	_, err = io.Copy(w, output)
+	return err
+}
+
+func (p *WorkerPool) PageBoxes(ctx context.Context, rs io.ReadSeeker, conf *model.Configuration) ([]domain.PdfPageBoxes, error) {
+	input, cleanup, err := p.inputFile(rs)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	resp, err := p.call(ctx, workerRequest{Op: workerPageBoxes, Input: input})
+	return resp.Boxes, err
+}
+
+func (p *WorkerPool) ContentBounds(ctx context.Context, rs io.ReadSeeker, pages []int, conf *model.Configuration) (map[int]domain.PdfRect, error) {
+	input, cleanup, err := p.inputFile(rs)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	resp, err := p.call(ctx, workerRequest{Op: workerContentBounds, Input: input, PageNrs: pages})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Bounds == nil {
+		resp.Bounds = map[int]domain.PdfRect{}
+	}
+	return resp.Bounds, nil
+}
+
+func (p *WorkerPool) SetPageBoxes(ctx context.Context, rs io.ReadSeeker, w io.Writer, box string, rects map[int]domain.PdfRect, conf *model.Configuration) error {
+	input, cleanup, err := p.inputFile(rs)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	output, err := os.CreateTemp(p.config.TempDir, "pdfworker-*.pdf")
+	if err != nil {
+		return fmt.Errorf("failed to create worker output: %w", err)
+	}
+	defer os.Remove(output.Name())
+	defer output.Close()
+
+	if _, err := p.call(ctx, workerRequest{Op: workerSetPageBoxes, Input: input, Output: output.Name(), Box: box, Rects: rects}); err != nil {
+		return err
+	}
+	_, err = io.Copy(w, output)
+	return err
+}
+
+// inputFile returns the path a worker reads rs from. A file on disk is passed as is,
+// anything else is copied to TempDir first.
+func (p *WorkerPool) inputFile(rs io.ReadSeeker) (string, func(), error) {
+	if file, ok := rs.(interface {
+		Name() string
+		Stat() (os.FileInfo, error)
+	}); ok {
+		info, err := file.Stat()
+		if err == nil {
+			if onDisk, err := os.Stat(file.Name()); err == nil && os.SameFile(info, onDisk) {
+				return file.Name(), func() {}, nil
+			}
+		}
+	}
+
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return "", nil, fmt.Errorf("failed to rewind pdf: %w", err)
+	}
+	copied, err := os.CreateTemp(p.config.TempDir, "pdfworker-*.pdf")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create worker input: %w", err)
+	}
+	cleanup := func() { os.Remove(copied.Name()) }
+	_, err = io.Copy(copied, rs)
+	if closeErr := copied.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to write worker input: %w", err)
+	}
+	return copied.Name(), cleanup, nil
+}
+
+// call runs req on an idle worker, waiting for one as long as ctx allows
+func (p *WorkerPool) call(ctx context.Context, req workerRequest) (workerResponse, error) {
+	var w *worker
+	select {
+	case w = <-p.idle:
+	case <-ctx.Done():
+		return workerResponse{}, ctx.Err()
+	}
+
+	if w == nil || w.exited() {
+		if w != nil {
+			w.stop()
+		}
+		var err error
+		if w, err = startWorker(p.config); err != nil {
+			p.idle <- nil
+			return workerResponse{}, err
+		}
+	}
+
+	resp, healthy, err := w.call(ctx, req)
+	if !healthy {
+		w.stop()
+		w = nil
+	}
+	p.idle <- w
+	return resp, err
+}
+
+// worker is a child process serving calls one at a time
+type worker struct {
+	cmd       *exec.Cmd
+	requests  *os.File
+	responses *os.File
+	encoder   *json.Encoder
+	decoder   *json.Decoder
+	done      chan struct{}
+	waitErr   error
+}
+
+func startWorker(config WorkerConfig) (*worker, error) {
+	requestReader, requestWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	responseReader, responseWriter, err := os.Pipe()
+	if err != nil {
+		requestReader.Close()
+		requestWriter.Close()
+		return nil, err
+	}
+
+	args := append(slices.Clone(config.Args), config.Limits.args()...)
+	cmd := exec.Command(config.Command, args...)
+	// the protocol runs over descriptors 3 and 4, whatever pdfcpu prints goes to the log
+	cmd.ExtraFiles = []*os.File{requestReader, responseWriter}
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	err = cmd.Start()
+	requestReader.Close()
+	responseWriter.Close()
+	if err != nil {
+		requestWriter.Close()
+		responseReader.Close()
+		return nil, fmt.Errorf("failed to start pdf worker: %w", err)
+	}
+
+	w := &worker{
+		cmd:       cmd,
+		requests:  requestWriter,
+		responses: responseReader,
+		encoder:   json.NewEncoder(requestWriter),
+		decoder:   json.NewDecoder(responseReader),
+		done:      make(chan struct{}),
+	}
+	go func() {
+		w.waitErr = cmd.Wait()
+		close(w.done)
+	}()
+	return w, nil
+}
+
+// call sends req and waits for its response, forwarding progress to ctx. A worker that
+// is not healthy afterwards has to be stopped.
+func (w *worker) call(ctx context.Context, req workerRequest) (resp workerResponse, healthy bool, err error) {
+	if err := w.encoder.Encode(req); err != nil {
+		return workerResponse{}, false, w.crashed(err)
+	}
+
+	type result struct {
+		resp workerResponse
+		err  error
+	}
+	results := make(chan result, 1)
+	go func() {
+		for {
+			var resp workerResponse
+			if err := w.decoder.Decode(&resp); err != nil {
+				results <- result{err: err}
+				return
+			}
+			if resp.Progress != nil {
+				domain.ReportProgress(ctx, resp.Progress.Step, resp.Progress.Done, resp.Progress.Total)
+				continue
+			}
+			results <- result{resp: resp}
+			return
+		}
+	}()
+
+	select {
+	case r := <-results:
+		switch {
+		case r.err != nil:
+			return workerResponse{}, false, w.crashed(r.err)
+		case r.resp.Unprocessable:
+			return r.resp, true, fmt.Errorf("%w: %s", domain.ErrUnprocessable, r.resp.Error)
+		case r.resp.Error != "":
+			return r.resp, true, errors.New(r.resp.Error)
+		}
+		return r.resp, true, nil
+
+	case <-ctx.Done():
+		// a call can't be interrupted, the worker goes with it
+		w.stop()
+		<-results
+		return workerResponse{}, false, ctx.Err()
+	}
+}
+
+// crashed reports a worker that went away in the middle of a call
+func (w *worker) crashed(err error) error {
+	w.cmd.Process.Kill()
+	<-w.done
+	logrus.WithError(err).Errorf("pdf worker %d exited: %v", w.cmd.Process.Pid, w.waitErr)
+	return fmt.Errorf("%w: the worker exited", domain.ErrUnprocessable)
+}
+
+func (w *worker) exited() bool {
+	select {
+	case <-w.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (w *worker) stop() {
+	w.cmd.Process.Kill()
+	<-w.done
+	w.requests.Close()
+	w.responses.Close()
+}
+
+// ServeWorker answers the calls of a WorkerPool read from in with api, one at a time,
+// until in is closed. It applies limits to the calling process first.
+func ServeWorker(in io.Reader, out io.Writer, api PdfCpuApi, limits WorkerLimits) error {
+	if err := applyWorkerLimits(limits); err != nil {
+		return fmt.Errorf("failed to apply worker limits: %w", err)
+	}
+
+	decoder := json.NewDecoder(in)
+	encoder := json.NewEncoder(out)
+	for {
+		var req workerRequest
+		if err := decoder.Decode(&req); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("failed to read request: %w", err)
+		}
+
+		if err := limitJobCPU(limits.CPUTime); err != nil {
+			return fmt.Errorf("failed to limit cpu time: %w", err)
+		}
+		if err := encoder.Encode(serveRequest(api, encoder, req)); err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+	}
+}
+
+// serveRequest runs req with api. A panic of pdfcpu marks the document as
+// unprocessable and leaves the worker running.
+func serveRequest(api PdfCpuApi, encoder *json.Encoder, req workerRequest) (resp workerResponse) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "pdf worker: %s panicked: %v\n%s", req.Op, r, debug.Stack())
+			resp = workerResponse{Unprocessable: true, Error: fmt.Sprint(r)}
+		}
+	}()
+
+	ctx := domain.WithProgress(context.Background(), func(step string, done, total int) {
+		encoder.Encode(workerResponse{Progress: &workerProgress{Step: step, Done: done, Total: total}})
+	})
+
+	var err error
+	switch req.Op {
+	case workerOptimize:
+		err = withFiles(req.Input, req.Output, func(input *os.File, output *os.File) error {
+			return api.Optimize(ctx, input, output, nil)
+		})
+	case workerPageCount:
+		err = withFiles(req.Input, "", func(input *os.File, _ *os.File) error {
+			resp.Pages, err = api.PageCount(ctx, input, nil)
+			return err
+		})
+	case workerSplit:
+		err = withFiles(req.Input, "", func(input *os.File, _ *os.File) error {
+			return api.Split(ctx, input, req.OutDir, req.FileName, req.Span, nil)
+		})
+	case workerSplitByPageNr:
+		err = withFiles(req.Input, "", func(input *os.File, _ *os.File) error {
+			return api.SplitByPageNr(ctx, input, req.OutDir, req.FileName, req.PageNrs, nil)
+		})
+	case workerMergeCreateFile:
+		err = api.MergeCreateFile(ctx, req.InFiles, req.Output, req.DividerPage, nil)
+	case workerBookmarks:
+		err = withFiles(req.Input, "", func(input *os.File, _ *os.File) error {
+			resp.Bookmarks, err = api.Bookmarks(ctx, input, nil)
+			return err
+		})
+	case workerDescribe:
+		err = withFiles(req.Input, "", func(input *os.File, _ *os.File) error {
+			document, err := api.Describe(ctx, input, nil)
+			resp.Document = &document
+			return err
+		})
+	case workerStampPages:
+		err = withFiles(req.Input, req.Output, func(input *os.File, output *os.File) error {
+			return api.StampPages(ctx, input, output, req.Stamps, nil)
+		})
+	case workerPageBoxes:
+		err = withFiles(req.Input, "", func(input *os.File, _ *os.File) error {
+			resp.Boxes, err = api.PageBoxes(ctx, input, nil)
+			return err
+		})
+	case workerContentBounds:
+		err = withFiles(req.Input, "", func(input *os.File, _ *os.File) error {
+			resp.Bounds, err = api.ContentBounds(ctx, input, req.PageNrs, nil)
+			return err
+		})
+	case workerSetPageBoxes:
+		err = withFiles(req.Input, req.Output, func(input *os.File, output *os.File) error {
+			return api.SetPageBoxes(ctx, input, output, req.Box, req.Rects, nil)
+		})
+	case workerAnalyze:
+		err = withFiles(req.Input, "", func(input *os.File, _ *os.File) error {
+			analysis, err := api.Analyze(ctx, input, nil)
+			resp.Analysis = &analysis
+			return err
+		})
+	default:
+		err = fmt.Errorf("unknown operation %q", req.Op)
+	}
+
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	return resp
+}
+
+// withFiles opens input and, unless empty, creates output for fn
+func withFiles(input, output string, fn func(input *os.File, output *os.File) error) (err error) {
+	in, err := os.Open(input)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if output == "" {
+		return fn(in, nil)
+	}
+	out, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	return fn(in, out)
+}
@@ -0,0 +1,59 @@
+//go:build linux || darwin
+
+package repository
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"syscall"
+	"time"
+)
+
+// applyWorkerLimits caps the memory of the worker and ends it once a call used up
+// its CPU time, see limitJobCPU
+func applyWorkerLimits(limits WorkerLimits) error {
+	if limits.Memory > 0 {
+		// the garbage collector works harder near the limit before the kernel refuses memory
+		debug.SetMemoryLimit(limits.Memory)
+		limit := uint64(limits.Memory)
+		if err := syscall.Setrlimit(syscall.RLIMIT_DATA, &syscall.Rlimit{Cur: limit, Max: limit}); err != nil {
+			return err
+		}
+	}
+
+	if limits.CPUTime > 0 {
+		exceeded := make(chan os.Signal, 1)
+		signal.Notify(exceeded, syscall.SIGXCPU)
+		go func() {
+			<-exceeded
+			fmt.Fprintln(os.Stderr, "pdf worker: cpu time limit exceeded")
+			os.Exit(3)
+		}()
+	}
+	return nil
+}
+
+// limitJobCPU allows the next call max of CPU time on top of what the worker used so far
+func limitJobCPU(max time.Duration) error {
+	if max <= 0 {
+		return nil
+	}
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		return err
+	}
+	used := time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_CPU, &limit); err != nil {
+		return err
+	}
+	// the kernel counts whole seconds
+	limit.Cur = uint64((used + max + time.Second - 1) / time.Second)
+	if limit.Cur > limit.Max {
+		limit.Cur = limit.Max
+	}
+	return syscall.Setrlimit(syscall.RLIMIT_CPU, &limit)
+}
@@ -0,0 +1,21 @@
+//go:build !linux && !darwin
+
+package repository
+
+import (
+	"runtime/debug"
+	"time"
+)
+
+// applyWorkerLimits can only ask the garbage collector to keep to the memory limit
+// where rlimits are not available
+func applyWorkerLimits(limits WorkerLimits) error {
+	if limits.Memory > 0 {
+		debug.SetMemoryLimit(limits.Memory)
+	}
+	return nil
+}
+
+func limitJobCPU(max time.Duration) error {
+	return nil
+}
@@ -0,0 +1,181 @@
+package repository_test
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMain lets the test binary stand in for pdfworker when started with -pdfworker
+func TestMain(m *testing.M) {
+	if len(os.Args) > 1 && os.Args[1] == "-pdfworker" {
+		flags := flag.NewFlagSet("pdfworker", flag.ExitOnError)
+		maxCPU := flags.Duration("max-cpu", 0, "")
+		maxMemory := flags.Int64("max-memory", 0, "")
+		flags.Parse(os.Args[2:])
+
+		limits := repository.WorkerLimits{CPUTime: *maxCPU, Memory: *maxMemory}
+		if err := repository.ServeWorker(os.NewFile(3, "requests"), os.NewFile(4, "responses"), &misbehavingApi{}, limits); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// misbehavingApi counts the pages of a document that reads "panic", "exit", "spin" or
+// "alloc" by doing just that
+type misbehavingApi struct {
+	repository.PdfCpuApiImpl
+}
+
+func (a *misbehavingApi) PageCount(ctx context.Context, rs io.ReadSeeker, conf *model.Configuration) (int, error) {
+	content, _ := io.ReadAll(rs)
+	switch string(content) {
+	case "panic":
+		panic("broken document")
+	case "exit":
+		os.Exit(2)
+	case "spin":
+		for {
+		}
+	case "alloc":
+		var held [][]byte
+		for {
+			held = append(held, make([]byte, 1<<20))
+			for i := range held[len(held)-1] {
+				held[len(held)-1][i] = 1
+			}
+		}
+	}
+	rs.Seek(0, io.SeekStart)
+	return a.PdfCpuApiImpl.PageCount(ctx, rs, conf)
+}
+
+func TestWorkerPool(t *testing.T) {
+	newPool := func(t *testing.T, limits repository.WorkerLimits) *repository.WorkerPool {
+		pool, err := repository.NewWorkerPool(repository.WorkerConfig{
+			Command: os.Args[0],
+			Args:    []string{"-pdfworker"},
+			Size:    1,
+			Limits:  limits,
+			TempDir: t.TempDir(),
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { pool.Close() })
+		return pool
+	}
+	pageCount := func(pool *repository.WorkerPool, ctx context.Context, content string) (int, error) {
+		return pool.PageCount(ctx, strings.NewReader(content), nil)
+	}
+	testPdf := func(t *testing.T) *os.File {
+		input, err := os.Open("../resource/test.pdf")
+		require.NoError(t, err)
+		t.Cleanup(func() { input.Close() })
+		return input
+	}
+
+	t.Run("when the document is fine should run pdfcpu in the worker", func(t *testing.T) {
+		pool := newPool(t, repository.WorkerLimits{})
+		input := testPdf(t)
+		dir := t.TempDir()
+		var reported []string
+		ctx := domain.WithProgress(context.TODO(), func(step string, done, total int) {
+			reported = append(reported, fmt.Sprintf("%s %d/%d", step, done, total))
+		})
+
+		count, err := pool.PageCount(ctx, input, nil)
+		require.NoError(t, err)
+		require.NoError(t, pool.Split(ctx, input, dir, "page.pdf", 1, nil))
+		output := filepath.Join(dir, "output.pdf")
+		require.NoError(t, pool.MergeCreateFile(ctx, []string{filepath.Join(dir, "page_1.pdf"), filepath.Join(dir, "page_2.pdf")}, output, false, nil))
+
+		merged, err := api.PageCountFile(output)
+		require.NoError(t, err)
+		assert.Equal(t, 2, merged)
+		assert.Len(t, reported, count+2)
+		assert.Equal(t, fmt.Sprintf("%s 1/%d", domain.StepSplitting, count), reported[0])
+	})
+
+	t.Run("when optimizing a stream should copy the result back", func(t *testing.T) {
+		pool := newPool(t, repository.WorkerLimits{})
+		content, err := os.ReadFile("../resource/test.pdf")
+		require.NoError(t, err)
+		var output strings.Builder
+
+		require.NoError(t, pool.Optimize(context.TODO(), strings.NewReader(string(content)), &output, nil))
+
+		count, err := api.PageCount(strings.NewReader(output.String()), nil)
+		require.NoError(t, err)
+		assert.Greater(t, count, 0)
+	})
+
+	t.Run("when pdfcpu fails should return its error and keep the worker", func(t *testing.T) {
+		pool := newPool(t, repository.WorkerLimits{})
+
+		_, err := pageCount(pool, context.TODO(), "not a pdf")
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrUnprocessable)
+
+		_, err = pool.PageCount(context.TODO(), testPdf(t), nil)
+		assert.NoError(t, err)
+	})
+
+	for content, does := range map[string]string{"panic": "panics", "exit": "exits"} {
+		t.Run(fmt.Sprintf("when pdfcpu %s should return ErrUnprocessable and recover", does), func(t *testing.T) {
+			pool := newPool(t, repository.WorkerLimits{})
+
+			_, err := pageCount(pool, context.TODO(), content)
+			assert.ErrorIs(t, err, domain.ErrUnprocessable)
+
+			_, err = pool.PageCount(context.TODO(), testPdf(t), nil)
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("when a call uses up its cpu time should return ErrUnprocessable", func(t *testing.T) {
+		pool := newPool(t, repository.WorkerLimits{CPUTime: time.Second})
+
+		_, err := pageCount(pool, context.TODO(), "spin")
+		assert.ErrorIs(t, err, domain.ErrUnprocessable)
+
+		_, err = pool.PageCount(context.TODO(), testPdf(t), nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("when a call uses up the memory should return ErrUnprocessable", func(t *testing.T) {
+		pool := newPool(t, repository.WorkerLimits{Memory: 256 << 20})
+
+		_, err := pageCount(pool, context.TODO(), "alloc")
+		assert.ErrorIs(t, err, domain.ErrUnprocessable)
+
+		_, err = pool.PageCount(context.TODO(), testPdf(t), nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("when context is canceled should stop the worker and return the context error", func(t *testing.T) {
+		pool := newPool(t, repository.WorkerLimits{})
+		ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+		defer cancel()
+
+		_, err := pageCount(pool, ctx, "spin")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		_, err = pool.PageCount(context.TODO(), testPdf(t), nil)
+		assert.NoError(t, err)
+	})
+}
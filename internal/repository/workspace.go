@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+const workspacePrefix = "pdf-ws-"
+
+// Workspaces hands out per-operation scratch directories below a common root
+type Workspaces struct {
+	root  string
+	quota int64
+
+	mu sync.Mutex
+	// open are the workspaces handed out and not closed yet
+	open map[string]bool
+	// orphans are the bytes of the other workspaces as of the latest Usage, left behind
+	// by a previous process. orphaned is their sum.
+	orphans  map[string]int64
+	orphaned int64
+}
+
+// NewWorkspaces will create the root directory if needed. An empty root falls back to
+// the system temp dir and a quota of zero disables the disk usage limit.
+func NewWorkspaces(root string, quota int64) (*Workspaces, error) {
+	if root == "" {
+		root = filepath.Join(os.TempDir(), "pdf-workspaces")
+	}
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create workspace root: %w", err)
+	}
+
+	w := &Workspaces{
+		root:  root,
+		quota: quota,
+		open:  map[string]bool{},
+	}
+	// workspaces left behind by a previous process count against the quota too
+	if _, err := w.Usage(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// New creates an empty workspace. It fails with domain.ErrStorageFull once the
+// workspaces below root hold quota bytes or more.
+func (w *Workspaces) New() (*Workspace, error) {
+	if w.quota > 0 && w.inUse() >= w.quota {
+		return nil, domain.ErrStorageFull
+	}
+
+	dir, err := os.MkdirTemp(w.root, workspacePrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workspace: %w", err)
+	}
+	w.mu.Lock()
+	w.open[dir] = true
+	w.mu.Unlock()
+	return &Workspace{dir: dir, owner: w}, nil
+}
+
+// inUse adds what the open workspaces hold right now to the orphans. Only the open
+// ones are walked, there are about as many as operations running.
+func (w *Workspaces) inUse() int64 {
+	w.mu.Lock()
+	used := w.orphaned
+	open := make([]string, 0, len(w.open))
+	for dir := range w.open {
+		open = append(open, dir)
+	}
+	w.mu.Unlock()
+
+	for _, dir := range open {
+		used += dirSize(dir)
+	}
+	return used
+}
+
+// Usage reports how many workspaces exist below root and how many bytes they hold.
+// It walks all of them and refreshes the orphans New counts against the quota.
+func (w *Workspaces) Usage() (domain.DiskUsage, error) {
+	dirs, err := w.list()
+	if err != nil {
+		return domain.DiskUsage{}, err
+	}
+
+	usage := domain.DiskUsage{Entries: len(dirs)}
+	sizes := make(map[string]int64, len(dirs))
+	for _, dir := range dirs {
+		sizes[dir.path] = dirSize(dir.path)
+		usage.Bytes += sizes[dir.path]
+	}
+
+	w.mu.Lock()
+	w.orphans, w.orphaned = map[string]int64{}, 0
+	for dir, size := range sizes {
+		if !w.open[dir] {
+			w.orphans[dir] = size
+			w.orphaned += size
+		}
+	}
+	w.mu.Unlock()
+	return usage, nil
+}
+
+// Purge removes workspaces that have not been modified for longer than olderThan.
+// Anything that old was orphaned by a crash or a caller that never closed it. Open
+// workspaces are kept however old, their result may still be streaming.
+func (w *Workspaces) Purge(olderThan time.Duration) (int, error) {
+	dirs, err := w.list()
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	deadline := time.Now().Add(-olderThan)
+	for _, dir := range dirs {
+		if dir.modTime.After(deadline) || w.isOpen(dir.path) {
+			continue
+		}
+		if err := os.RemoveAll(dir.path); err != nil {
+			return purged, fmt.Errorf("failed to purge workspace %s: %w", dir.path, err)
+		}
+		w.removed(dir.path)
+		purged++
+	}
+	return purged, nil
+}
+
+func (w *Workspaces) isOpen(dir string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.open[dir]
+}
+
+// removed takes a workspace that is gone off the usage
+func (w *Workspaces) removed(dir string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.open, dir)
+	w.orphaned -= w.orphans[dir]
+	delete(w.orphans, dir)
+}
+
+type workspaceDir struct {
+	path    string
+	modTime time.Time
+}
+
+func (w *Workspaces) list() ([]workspaceDir, error) {
+	entries, err := os.ReadDir(w.root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspace root: %w", err)
+	}
+
+	dirs := make([]workspaceDir, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), workspacePrefix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// removed while listing
+			continue
+		}
+		dirs = append(dirs, workspaceDir{
+			path:    filepath.Join(w.root, entry.Name()),
+			modTime: info.ModTime(),
+		})
+	}
+	return dirs, nil
+}
+
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// Workspace is a scratch directory owned by a single operation. Close removes it
+// together with everything written into it.
+type Workspace struct {
+	dir   string
+	owner *Workspaces
+}
+
+func (w *Workspace) Dir() string {
+	return w.dir
+}
+
+func (w *Workspace) Path(name string) string {
+	return filepath.Join(w.dir, name)
+}
+
+func (w *Workspace) Create(name string) (*os.File, error) {
+	return os.Create(w.Path(name))
+}
+
+func (w *Workspace) Close() error {
+	// once closed the janitor may purge whatever RemoveAll left
+	err := os.RemoveAll(w.dir)
+	w.owner.removed(w.dir)
+	return err
+}
+
+// workspaceFile is an output file that takes its workspace with it once closed
+type workspaceFile struct {
+	*os.File
+	workspace *Workspace
+}
+
+func (f *workspaceFile) Close() error {
+	err := f.File.Close()
+	if rmErr := f.workspace.Close(); err == nil {
+		err = rmErr
+	}
+	return err
+}
@@ -0,0 +1,113 @@
+package repository_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspaces(t *testing.T) {
+	t.Run("when workspace closed should remove its directory", func(t *testing.T) {
+		workspaces, err := repository.NewWorkspaces(t.TempDir(), 0)
+		require.NoError(t, err)
+
+		workspace, err := workspaces.New()
+		require.NoError(t, err)
+		file, err := workspace.Create("page_1.pdf")
+		require.NoError(t, err)
+		file.Write([]byte("%PDF-1.7"))
+		file.Close()
+
+		usage, err := workspaces.Usage()
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DiskUsage{Entries: 1, Bytes: 8}, usage)
+
+		assert.NoError(t, workspace.Close())
+		_, err = os.Stat(workspace.Dir())
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("when quota is used up should return ErrStorageFull", func(t *testing.T) {
+		workspaces, err := repository.NewWorkspaces(t.TempDir(), 4)
+		require.NoError(t, err)
+
+		_, err = workspaces.Usage()
+		require.NoError(t, err)
+		workspace, err := workspaces.New()
+		require.NoError(t, err)
+		// written after the latest Usage, counted all the same
+		os.WriteFile(workspace.Path("page_1.pdf"), []byte("%PDF-1.7"), 0o600)
+
+		_, err = workspaces.New()
+		assert.ErrorIs(t, err, domain.ErrStorageFull)
+
+		// closing frees the space without another walk
+		require.NoError(t, workspace.Close())
+		freed, err := workspaces.New()
+		require.NoError(t, err)
+		freed.Close()
+	})
+
+	t.Run("when workspaces are left behind should count them against the quota", func(t *testing.T) {
+		root := t.TempDir()
+		orphan, err := os.MkdirTemp(root, "pdf-ws-")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(orphan+"/page_1.pdf", []byte("%PDF-1.7"), 0o600))
+
+		workspaces, err := repository.NewWorkspaces(root, 4)
+		require.NoError(t, err)
+		_, err = workspaces.New()
+		assert.ErrorIs(t, err, domain.ErrStorageFull)
+
+		purged, err := workspaces.Purge(0)
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		workspace, err := workspaces.New()
+		require.NoError(t, err)
+		workspace.Close()
+	})
+
+	t.Run("when purging should only remove workspaces older than the ttl", func(t *testing.T) {
+		root := t.TempDir()
+		workspaces, err := repository.NewWorkspaces(root, 0)
+		require.NoError(t, err)
+
+		// left behind by a previous process
+		stale, err := os.MkdirTemp(root, "pdf-ws-")
+		require.NoError(t, err)
+		old := time.Now().Add(-2 * time.Hour)
+		require.NoError(t, os.Chtimes(stale, old, old))
+
+		fresh, err := workspaces.New()
+		require.NoError(t, err)
+		defer fresh.Close()
+
+		// still open, its result may be streaming for longer than the ttl
+		streaming, err := workspaces.New()
+		require.NoError(t, err)
+		defer streaming.Close()
+		require.NoError(t, os.Chtimes(streaming.Dir(), old, old))
+
+		// unrelated directories under the root are left alone
+		require.NoError(t, os.Mkdir(root+"/keep", 0o700))
+		require.NoError(t, os.Chtimes(root+"/keep", old, old))
+
+		purged, err := workspaces.Purge(time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, purged)
+
+		_, err = os.Stat(stale)
+		assert.True(t, os.IsNotExist(err))
+		_, err = os.Stat(fresh.Dir())
+		assert.NoError(t, err)
+		_, err = os.Stat(streaming.Dir())
+		assert.NoError(t, err)
+		_, err = os.Stat(root + "/keep")
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,205 @@
+package rest
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// Formats of a result with more than one file, chosen with the format field or the
+// Accept header
+const (
+	FORMAT_ZIP       = "zip"
+	FORMAT_TAR_GZ    = "tar.gz"
+	FORMAT_MULTIPART = "multipart"
+)
+
+// formatMediaTypes maps the media types of the Accept header to a format
+var formatMediaTypes = map[string]string{
+	"application/zip":              FORMAT_ZIP,
+	"application/x-zip-compressed": FORMAT_ZIP,
+	"application/gzip":             FORMAT_TAR_GZ,
+	"application/x-gzip":           FORMAT_TAR_GZ,
+	"multipart/mixed":              FORMAT_MULTIPART,
+}
+
+// responseFormat picks the format of a multi-file result. The format field wins over
+// the Accept header, and zip is the default when neither names a known format.
+func responseFormat(c echo.Context) (string, error) {
+	switch format := c.FormValue("format"); format {
+	case "":
+		return negotiateFormat(c.Request().Header.Get(echo.HeaderAccept)), nil
+	case FORMAT_ZIP, FORMAT_TAR_GZ, FORMAT_MULTIPART:
+		return format, nil
+	default:
+		return "", paramError("Invalid format, use zip, tar.gz or multipart")
+	}
+}
+
+// negotiateFormat returns the format of the media type with the highest quality in
+// accept, the first one on a tie
+func negotiateFormat(accept string) string {
+	format, best := FORMAT_ZIP, 0.0
+	for _, offer := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(offer))
+		if err != nil {
+			continue
+		}
+		candidate, ok := formatMediaTypes[mediaType]
+		if !ok {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if quality > best {
+			format, best = candidate, quality
+		}
+	}
+	return format
+}
+
+// respondWithArchive sends the entries of a zip result as format. The zip is spooled
+// first, its entries can only be read from a file. Once the first byte is out, a
+// failure can only cut the response short.
+func (a *PdfHandler) respondWithArchive(c echo.Context, file domain.PdfFile, format string) error {
+	ctx := c.Request().Context()
+
+	archive, err := a.openArchive(ctx, file)
+	if err != nil {
+		return respondWithPdfError(c, err, "Failed to read the result")
+	}
+	defer archive.Close()
+
+	header := c.Response().Header()
+	header.Add(echo.HeaderVary, echo.HeaderAccept)
+
+	switch format {
+	case FORMAT_TAR_GZ:
+		header.Set(echo.HeaderContentType, "application/gzip")
+		header.Set(echo.HeaderContentDisposition, attachment(strings.TrimSuffix(file.Name, ".zip")+".tar.gz"))
+		c.Response().WriteHeader(http.StatusOK)
+		return writeTarGz(c.Response(), archive.File)
+
+	default:
+		parts := multipart.NewWriter(c.Response())
+		header.Set(echo.HeaderContentType, "multipart/mixed; boundary="+parts.Boundary())
+		c.Response().WriteHeader(http.StatusOK)
+		return writeMultipart(parts, archive.File)
+	}
+}
+
+// spooledArchive is a zip result on disk, removed once closed
+type spooledArchive struct {
+	*zip.Reader
+	io.Closer
+}
+
+func (a *PdfHandler) openArchive(ctx context.Context, file domain.PdfFile) (*spooledArchive, error) {
+	defer file.Content.Close()
+
+	spooled, size, err := a.Service.Spool(ctx, file.Content)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := zip.NewReader(spooled, size)
+	if err != nil {
+		spooled.Close()
+		return nil, fmt.Errorf("failed to open result zip: %w", err)
+	}
+	return &spooledArchive{Reader: reader, Closer: spooled}, nil
+}
+
+func writeTarGz(w io.Writer, entries []*zip.File) error {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for _, entry := range entries {
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+		err := tarWriter.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     entry.Name,
+			Size:     int64(entry.UncompressedSize64),
+			Mode:     0o644,
+			ModTime:  entry.Modified,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write tar header: %w", err)
+		}
+		if err := copyEntry(tarWriter, entry); err != nil {
+			return err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("failed to close tar writer: %w", err)
+	}
+	return gzipWriter.Close()
+}
+
+// writeMultipart sends every entry as a part with its own type, name and length
+func writeMultipart(parts *multipart.Writer, entries []*zip.File) error {
+	for _, entry := range entries {
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+		part, err := parts.CreatePart(textproto.MIMEHeader{
+			echo.HeaderContentType:        {entryContentType(entry.Name)},
+			echo.HeaderContentDisposition: {attachment(entry.Name)},
+			echo.HeaderContentLength:      {strconv.FormatUint(entry.UncompressedSize64, 10)},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create part: %w", err)
+		}
+		if err := copyEntry(part, entry); err != nil {
+			return err
+		}
+	}
+	return parts.Close()
+}
+
+func copyEntry(w io.Writer, entry *zip.File) error {
+	content, err := entry.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", entry.Name, err)
+	}
+	defer content.Close()
+
+	if _, err := io.Copy(w, content); err != nil {
+		return fmt.Errorf("failed to copy %s: %w", entry.Name, err)
+	}
+	return nil
+}
+
+func entryContentType(name string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".pdf":
+		return "application/pdf"
+	case ".json":
+		return "application/json"
+	case ".zip":
+		return "application/zip"
+	default:
+		return "application/octet-stream"
+	}
+}
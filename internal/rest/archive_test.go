@@ -0,0 +1,165 @@
+package rest_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rest"
+	"github.com/bxcodec/go-clean-arch/internal/rest/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseFormat(t *testing.T) {
+	pdfContent, err := os.ReadFile("../resource/test.pdf")
+	require.NoError(t, err)
+
+	zipOf := func(files map[string]string, names ...string) []byte {
+		var archive bytes.Buffer
+		zipWriter := zip.NewWriter(&archive)
+		for _, name := range names {
+			entry, _ := zipWriter.Create(name)
+			entry.Write([]byte(files[name]))
+		}
+		zipWriter.Close()
+		return archive.Bytes()
+	}
+	upload := zipOf(map[string]string{"test.pdf": string(pdfContent)}, "test.pdf")
+	result := zipOf(map[string]string{"test.pdf": "%PDF-1", "report.json": "{}"}, "test.pdf", "report.json")
+
+	// spool keeps what the handler spools in a file, as the repository does
+	spool := func(t *testing.T) func(context.Context, io.Reader) (domain.SpooledContent, int64, error) {
+		return func(_ context.Context, content io.Reader) (domain.SpooledContent, int64, error) {
+			file, err := os.Create(filepath.Join(t.TempDir(), "spooled"))
+			require.NoError(t, err)
+			size, err := io.Copy(file, content)
+			require.NoError(t, err)
+			_, err = file.Seek(0, io.SeekStart)
+			require.NoError(t, err)
+			return file, size, nil
+		}
+	}
+
+	serve := func(t *testing.T, format, accept string) (*httptest.ResponseRecorder, *mocks.PdfService) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("BatchPdf", mock.Anything, "dump.zip", mock.Anything, mock.Anything).Return(domain.PdfFile{
+			Name:    "batch_dump.zip",
+			Content: io.NopCloser(bytes.NewReader(result)),
+			Size:    int64(len(result)),
+		}, nil).Maybe()
+		mockPdfSvc.On("Spool", mock.Anything, mock.Anything).Return(spool(t)).Maybe()
+
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, _ := writer.CreateFormFile("file", "dump.zip")
+		part.Write(upload)
+		writer.WriteField("batch", "true")
+		if format != "" {
+			writer.WriteField("format", format)
+		}
+		writer.Close()
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/process/compress", &body)
+		req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+		if accept != "" {
+			req.Header.Set(echo.HeaderAccept, accept)
+		}
+		rec := httptest.NewRecorder()
+		handler := rest.PdfHandler{Service: mockPdfSvc}
+		require.NoError(t, handler.StartCompress(e.NewContext(req, rec)))
+		return rec, mockPdfSvc
+	}
+
+	t.Run("when no format is asked for should return the zip as is", func(t *testing.T) {
+		rec, mockPdfSvc := serve(t, "", "*/*")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/zip", rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, result, rec.Body.Bytes())
+		mockPdfSvc.AssertNotCalled(t, "Spool", mock.Anything, mock.Anything)
+	})
+
+	t.Run("when format is tar.gz should return the files as a tarball", func(t *testing.T) {
+		rec, _ := serve(t, rest.FORMAT_TAR_GZ, "")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/gzip", rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, `attachment; filename="batch_dump.tar.gz"`, rec.Header().Get(echo.HeaderContentDisposition))
+
+		gzipReader, err := gzip.NewReader(rec.Body)
+		require.NoError(t, err)
+		tarReader := tar.NewReader(gzipReader)
+		files := map[string]string{}
+		for {
+			header, err := tarReader.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			content, _ := io.ReadAll(tarReader)
+			files[header.Name] = string(content)
+		}
+		assert.Equal(t, map[string]string{"test.pdf": "%PDF-1", "report.json": "{}"}, files)
+	})
+
+	t.Run("when Accept prefers multipart/mixed should send a part per file", func(t *testing.T) {
+		rec, _ := serve(t, "", "application/zip;q=0.5, multipart/mixed, application/gzip;q=0.8")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mediaType, params, err := mime.ParseMediaType(rec.Header().Get(echo.HeaderContentType))
+		require.NoError(t, err)
+		assert.Equal(t, "multipart/mixed", mediaType)
+		assert.Equal(t, echo.HeaderAccept, rec.Header().Get(echo.HeaderVary))
+
+		parts := multipart.NewReader(rec.Body, params["boundary"])
+		first, err := parts.NextPart()
+		require.NoError(t, err)
+		assert.Equal(t, "application/pdf", first.Header.Get(echo.HeaderContentType))
+		assert.Equal(t, "test.pdf", first.FileName())
+		assert.Equal(t, "6", first.Header.Get(echo.HeaderContentLength))
+		content, _ := io.ReadAll(first)
+		assert.Equal(t, "%PDF-1", string(content))
+
+		second, err := parts.NextPart()
+		require.NoError(t, err)
+		assert.Equal(t, "application/json", second.Header.Get(echo.HeaderContentType))
+		assert.Equal(t, "report.json", second.FileName())
+
+		_, err = parts.NextPart()
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("when format field is set should win over Accept", func(t *testing.T) {
+		rec, _ := serve(t, rest.FORMAT_ZIP, "multipart/mixed")
+
+		assert.Equal(t, "application/zip", rec.Header().Get(echo.HeaderContentType))
+	})
+
+	t.Run("when Accept refuses a format with q=0 should not pick it", func(t *testing.T) {
+		rec, _ := serve(t, "", "multipart/mixed;q=0, application/gzip;q=0.1")
+
+		assert.Equal(t, "application/gzip", rec.Header().Get(echo.HeaderContentType))
+	})
+
+	t.Run("when format is unknown should return status 400 before processing", func(t *testing.T) {
+		rec, mockPdfSvc := serve(t, "rar", "")
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockPdfSvc.AssertNotCalled(t, "BatchPdf", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
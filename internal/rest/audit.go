@@ -0,0 +1,113 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/pdf"
+)
+
+// AuditService represent the audit log of the PDF operations
+//
+//go:generate mockery --name AuditService
+type AuditService interface {
+	Audited(op domain.PdfProcessor, call domain.PdfAuditCall) domain.PdfProcessor
+	Fetch(ctx context.Context, filter domain.PdfAuditFilter, cursor string, num int64) ([]domain.PdfAuditRecord, string, error)
+}
+
+// WithAudit records every document processed by the PDF endpoints in audit
+func WithAudit(audit AuditService) PdfHandlerOption {
+	return func(h *PdfHandler) {
+		h.Audit = audit
+	}
+}
+
+// audited records the documents op processes along with the caller and the values
+// of params, the parameters of the operation
+func (a *PdfHandler) audited(c echo.Context, operation string, params []pdf.Param, op domain.PdfProcessor) domain.PdfProcessor {
+	if a.Audit == nil {
+		return op
+	}
+
+	values := map[string]string{}
+	for _, param := range params {
+		if value := c.FormValue(param.Name); value != "" {
+			values[param.Name] = value
+		}
+	}
+	return a.Audit.Audited(op, domain.PdfAuditCall{Caller: c.RealIP(), Operation: operation, Params: values})
+}
+
+// AuditHandler represent the httphandler for the audit log
+type AuditHandler struct {
+	Service AuditService
+}
+
+// NewAuditHandler will initialize the audit/ resources endpoint
+func NewAuditHandler(e *echo.Echo, svc AuditService) {
+	handler := &AuditHandler{
+		Service: svc,
+	}
+	e.GET("/audit/pdf", handler.FetchPdfAudit)
+}
+
+// @Summary List the audit log of the PDF operations
+// @Description Lists every document processed by /process/*, oldest first. Follow the X-Cursor header to get the next page.
+// @Tags Audit
+// @Produce json
+// @Param caller query string false "Address of the client"
+// @Param operation query string false "Operation, e.g. 'compress' or 'split'"
+// @Param outcome query string false "'succeeded', 'failed' or 'aborted'"
+// @Param sha256 query string false "Hex SHA-256 of the input document"
+// @Param from query string false "Only records from this RFC 3339 time on"
+// @Param to query string false "Only records before this RFC 3339 time"
+// @Param cursor query string false "X-Cursor of the previous page"
+// @Param num query int false "Records per page (default 10)"
+// @Success 200 {array} domain.PdfAuditRecord
+// @Header 200 {string} X-Cursor "Cursor of the next page, empty on the last one"
+// @Failure 400 {object} ResponseError "Invalid filter or cursor"
+// @Failure 500 {object} ResponseError "Failed to read the audit log"
+// @Router /audit/pdf [get]
+func (a *AuditHandler) FetchPdfAudit(c echo.Context) error {
+	num, err := strconv.Atoi(c.QueryParam("num"))
+	if err != nil || num <= 0 {
+		num = defaultNum
+	}
+
+	filter := domain.PdfAuditFilter{
+		Caller:      c.QueryParam("caller"),
+		Operation:   c.QueryParam("operation"),
+		Outcome:     c.QueryParam("outcome"),
+		InputSHA256: c.QueryParam("sha256"),
+	}
+	for _, bound := range []struct {
+		name string
+		time *time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		value := c.QueryParam(bound.name)
+		if value == "" {
+			continue
+		}
+		if *bound.time, err = time.Parse(time.RFC3339, value); err != nil {
+			return c.JSON(http.StatusBadRequest, ResponseError{Message: bound.name + " must be an RFC 3339 time"})
+		}
+	}
+
+	records, nextCursor, err := a.Service.Fetch(c.Request().Context(), filter, c.QueryParam("cursor"), int64(num))
+	if err == domain.ErrBadParamInput {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+	}
+	if err != nil {
+		logrus.Error(err)
+		return c.JSON(http.StatusInternalServerError, ResponseError{Message: "Failed to read the audit log"})
+	}
+
+	c.Response().Header().Set(`X-Cursor`, nextCursor)
+	return c.JSON(http.StatusOK, records)
+}
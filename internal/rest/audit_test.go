@@ -0,0 +1,102 @@
+package rest_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/repository/memory"
+	"github.com/bxcodec/go-clean-arch/internal/rest"
+	"github.com/bxcodec/go-clean-arch/internal/rest/mocks"
+	"github.com/bxcodec/go-clean-arch/pdf"
+)
+
+func TestPdfAudit(t *testing.T) {
+	pdfContent, err := os.ReadFile("../resource/test.pdf")
+	require.NoError(t, err)
+
+	t.Run("when a document is processed should list it in the audit log", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("PageCount", mock.Anything, mock.Anything).Return(3, nil).Once()
+		audit := pdf.NewAuditLog(memory.NewAuditRepository(), mockPdfSvc)
+
+		e := echo.New()
+		rest.NewPdfHandler(e, mockPdfSvc, rest.WithAudit(audit))
+		rest.NewAuditHandler(e, audit)
+
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, _ := writer.CreateFormFile("file", "test.pdf")
+		part.Write(pdfContent)
+		writer.WriteField("text", "draft")
+		writer.WriteField("job_id", "not-an-operation-parameter")
+		writer.Close()
+		req := httptest.NewRequest(http.MethodPost, "/process/stamp", &body)
+		req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+		req.Header.Set(echo.HeaderXRealIP, "203.0.113.7")
+		e.ServeHTTP(httptest.NewRecorder(), req)
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/audit/pdf?operation=stamp&caller=203.0.113.7", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var records []domain.PdfAuditRecord
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &records))
+		require.Len(t, records, 1)
+		hash := sha256.Sum256(pdfContent)
+		assert.Equal(t, "203.0.113.7", records[0].Caller)
+		assert.Equal(t, map[string]string{"text": "draft"}, records[0].Params)
+		assert.Equal(t, "test.pdf", records[0].FileName)
+		assert.Equal(t, hex.EncodeToString(hash[:]), records[0].InputSHA256)
+		assert.Equal(t, int64(len(pdfContent)), records[0].InputSize)
+		assert.Equal(t, 3, records[0].PageCount)
+		assert.Equal(t, int64(len("draft")), records[0].OutputSize)
+		assert.Equal(t, domain.AuditSucceeded, records[0].Outcome)
+		assert.Empty(t, rec.Header().Get("X-Cursor"))
+	})
+
+	t.Run("when filtered should pass the filter and return the next cursor", func(t *testing.T) {
+		mockAudit := new(mocks.AuditService)
+		from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		filter := domain.PdfAuditFilter{Outcome: domain.AuditFailed, InputSHA256: "abc", From: from}
+		mockAudit.On("Fetch", mock.Anything, filter, "cursor-1", int64(2)).
+			Return([]domain.PdfAuditRecord{{ID: 1}, {ID: 2}}, "cursor-2", nil).Once()
+
+		e := echo.New()
+		rest.NewAuditHandler(e, mockAudit)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/audit/pdf?outcome=failed&sha256=abc&from=2026-01-01T00:00:00Z&cursor=cursor-1&num=2", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "cursor-2", rec.Header().Get("X-Cursor"))
+		mockAudit.AssertExpectations(t)
+	})
+
+	t.Run("when the time or cursor is invalid should return status 400", func(t *testing.T) {
+		mockAudit := new(mocks.AuditService)
+		mockAudit.On("Fetch", mock.Anything, mock.Anything, "bad", mock.Anything).Return(nil, "", domain.ErrBadParamInput).Once()
+
+		e := echo.New()
+		rest.NewAuditHandler(e, mockAudit)
+		for _, target := range []string{"/audit/pdf?to=yesterday", "/audit/pdf?cursor=bad"} {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code, target)
+		}
+		mockAudit.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,102 @@
+package rest_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/helper"
+	"github.com/bxcodec/go-clean-arch/internal/rest"
+	"github.com/bxcodec/go-clean-arch/internal/rest/mocks"
+	"github.com/go-playground/validator"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch(t *testing.T) {
+	pdfContent, err := os.ReadFile("../resource/test.pdf")
+	require.NoError(t, err)
+
+	var archive bytes.Buffer
+	zipWriter := zip.NewWriter(&archive)
+	entry, _ := zipWriter.Create("docs/test.pdf")
+	entry.Write(pdfContent)
+	zipWriter.Close()
+
+	createUpload := func(fileName string, content []byte, fields map[string]string) (*bytes.Buffer, string) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, _ := writer.CreateFormFile("file", fileName)
+		part.Write(content)
+		for name, value := range fields {
+			writer.WriteField(name, value)
+		}
+		writer.Close()
+		return &body, writer.FormDataContentType()
+	}
+
+	serve := func(handler *rest.PdfHandler, path string, body io.Reader, contentType string) *httptest.ResponseRecorder {
+		e := echo.New()
+		e.Validator = &helper.CustomValidator{Validator: validator.New()}
+		req := httptest.NewRequest(http.MethodPost, path, body)
+		req.Header.Set("Content-Type", contentType)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		var err error
+		if path == "/process/compress" {
+			err = handler.StartCompress(c)
+		} else {
+			err = handler.StartSplit(c)
+		}
+		require.NoError(t, err)
+		return rec
+	}
+
+	t.Run("when batch is set should process the zip and return a zip", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		handler := &rest.PdfHandler{Service: mockPdfSvc}
+
+		mockPdfSvc.On("BatchPdf", mock.Anything, "dump.zip", mock.MatchedBy(func(r *zip.Reader) bool {
+			return len(r.File) == 1 && r.File[0].Name == "docs/test.pdf"
+		}), mock.Anything).Return(domain.PdfFile{
+			Name:    "batch_dump.zip",
+			Content: io.NopCloser(bytes.NewReader([]byte{1})),
+			Size:    -1,
+		}, nil).Once()
+
+		body, contentType := createUpload("dump.zip", archive.Bytes(), map[string]string{"batch": "true"})
+		rec := serve(handler, "/process/compress", body, contentType)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/zip", rec.Header().Get(echo.HeaderContentType))
+		mockPdfSvc.AssertExpectations(t)
+	})
+
+	t.Run("when batch is set and upload is a pdf should return status 415", func(t *testing.T) {
+		handler := &rest.PdfHandler{Service: new(mocks.PdfService)}
+
+		body, contentType := createUpload("test.pdf", pdfContent, map[string]string{"batch": "true"})
+		rec := serve(handler, "/process/compress", body, contentType)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	})
+
+	t.Run("when split parameters are invalid should return status 400 before processing", func(t *testing.T) {
+		handler := &rest.PdfHandler{Service: new(mocks.PdfService)}
+
+		body, contentType := createUpload("dump.zip", archive.Bytes(), map[string]string{"batch": "true", "split_mode": "fixed_range", "fixed_range": "0"})
+		rec := serve(handler, "/process/split", body, contentType)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Fixed range must be greater than 0")
+	})
+}
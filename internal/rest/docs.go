@@ -0,0 +1,90 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/swaggo/swag"
+
+	"github.com/bxcodec/go-clean-arch/pdf"
+)
+
+// operationDocs is the Swagger document of base with the operations of the pdf
+// registry filled in, so operations registered by other packages are documented as
+// well as the built-in ones
+type operationDocs struct {
+	base     swag.Swagger
+	registry *pdf.Registry
+}
+
+// RegisterOperationDocs registers the document of base, completed with the operations
+// of registry, as the swag instance name
+func RegisterOperationDocs(name string, base swag.Swagger, registry *pdf.Registry) {
+	swag.Register(name, operationDocs{base: base, registry: registry})
+}
+
+func (d operationDocs) ReadDoc() string {
+	doc := d.base.ReadDoc()
+
+	var spec map[string]any
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		logrus.WithError(err).Error("failed to read the swagger document")
+		return doc
+	}
+	paths, _ := spec["paths"].(map[string]any)
+	d.document(paths, "/process/split", "split_mode", d.registry.List(pdf.KindSplit))
+	d.document(paths, "/process/{operation}", "operation", d.registry.List(pdf.KindProcess))
+
+	completed, err := json.Marshal(spec)
+	if err != nil {
+		logrus.WithError(err).Error("failed to write the swagger document")
+		return doc
+	}
+	return string(completed)
+}
+
+// document lists the names of operations as the values of the selector parameter of
+// the POST at path and adds the parameters of the operations it does not have yet
+func (d operationDocs) document(paths map[string]any, path, selector string, operations []pdf.OperationInfo) {
+	post, _ := paths[path].(map[string]any)["post"].(map[string]any)
+	if post == nil {
+		return
+	}
+	parameters, _ := post["parameters"].([]any)
+
+	names := make([]any, 0, len(operations))
+	descriptions := make([]string, 0, len(operations))
+	for _, op := range operations {
+		names = append(names, op.Name)
+		descriptions = append(descriptions, fmt.Sprintf("'%s' to %s", op.Name, op.Description))
+	}
+
+	documented := map[string]bool{}
+	for _, parameter := range parameters {
+		parameter, _ := parameter.(map[string]any)
+		name, _ := parameter["name"].(string)
+		documented[name] = true
+		if name == selector {
+			parameter["enum"] = names
+			parameter["description"] = strings.Join(descriptions, ", ")
+		}
+	}
+
+	for _, op := range operations {
+		for _, param := range op.Params {
+			if documented[param.Name] {
+				continue
+			}
+			documented[param.Name] = true
+			parameters = append(parameters, map[string]any{
+				"type":        param.Type,
+				"description": param.Doc,
+				"name":        param.Name,
+				"in":          "formData",
+			})
+		}
+	}
+	post["parameters"] = parameters
+}
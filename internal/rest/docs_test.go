@@ -0,0 +1,68 @@
+package rest_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/swaggo/swag"
+
+	"github.com/bxcodec/go-clean-arch/internal/rest"
+	"github.com/bxcodec/go-clean-arch/pdf"
+)
+
+// staticDoc is a Swagger document as swag generates it
+type staticDoc string
+
+func (d staticDoc) ReadDoc() string {
+	return string(d)
+}
+
+func TestRegisterOperationDocs(t *testing.T) {
+	base := staticDoc(`{"paths": {
+		"/process/split": {"post": {"parameters": [
+			{"name": "split_mode", "in": "formData", "type": "string"},
+			{"name": "ranges", "in": "formData", "type": "string", "description": "as annotated"}
+		]}},
+		"/process/{operation}": {"post": {"parameters": [
+			{"name": "operation", "in": "path", "type": "string"}
+		]}}
+	}}`)
+	rest.RegisterOperationDocs("operations-test", base, pdf.DefaultRegistry)
+
+	doc, err := swag.ReadDoc("operations-test")
+	require.NoError(t, err)
+	var spec struct {
+		Paths map[string]struct {
+			Post struct {
+				Parameters []map[string]any `json:"parameters"`
+			} `json:"post"`
+		} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(doc), &spec))
+	parameters := func(path string) map[string]map[string]any {
+		byName := map[string]map[string]any{}
+		for _, parameter := range spec.Paths[path].Post.Parameters {
+			byName[parameter["name"].(string)] = parameter
+		}
+		return byName
+	}
+
+	t.Run("when split modes are registered should list them and add their parameters", func(t *testing.T) {
+		split := parameters("/process/split")
+
+		assert.Equal(t, []any{"ranges", "fixed_range", "remove_pages"}, split["split_mode"]["enum"])
+		assert.Equal(t, "as annotated", split["ranges"]["description"])
+		assert.Equal(t, "integer", split["fixed_range"]["type"])
+		assert.Contains(t, split, "remove_page")
+	})
+
+	t.Run("when processors are registered should list them and add their parameters", func(t *testing.T) {
+		process := parameters("/process/{operation}")
+
+		assert.Contains(t, process["operation"]["enum"], "stamp")
+		assert.Equal(t, "Text of the stamp", process["text"]["description"])
+		assert.Equal(t, "formData", process["text"]["in"])
+	})
+}
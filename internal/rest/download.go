@@ -0,0 +1,62 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// DownloadService represent the usecases of stored results
+//
+//go:generate mockery --name DownloadService
+type DownloadService interface {
+	Publish(ctx context.Context, file domain.PdfFile) (domain.DownloadLink, error)
+	Open(ctx context.Context, token string) (domain.PdfFile, error)
+}
+
+// DownloadHandler represent the httphandler for stored results
+type DownloadHandler struct {
+	Service DownloadService
+}
+
+// NewDownloadHandler will initialize the downloads/ resources endpoint
+func NewDownloadHandler(e *echo.Echo, svc DownloadService) {
+	handler := &DownloadHandler{
+		Service: svc,
+	}
+	e.GET("/downloads/:token", handler.Download)
+}
+
+// @Summary Download a stored result
+// @Description Streams a result published with response = link, as long as its link has not expired
+// @Tags PDF
+// @Produce application/pdf, application/zip
+// @Param token path string true "Signed download token"
+// @Success 200 {file} string "Stored file"
+// @Failure 404 {object} ResponseError "Unknown or invalid link"
+// @Failure 410 {object} ResponseError "Link expired"
+// @Router /downloads/{token} [get]
+func (a *DownloadHandler) Download(c echo.Context) error {
+	file, err := a.Service.Open(c.Request().Context(), c.Param("token"))
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return c.JSON(http.StatusNotFound, ResponseError{Message: domain.ErrNotFound.Error()})
+	case errors.Is(err, domain.ErrLinkExpired):
+		return c.JSON(http.StatusGone, ResponseError{Message: domain.ErrLinkExpired.Error()})
+	case err != nil:
+		logrus.Error(err)
+		return c.JSON(http.StatusInternalServerError, ResponseError{Message: "Failed to open the file"})
+	}
+
+	return respondWithFile(c, file)
+}
+
+// downloadURL builds the absolute URL of a published link as seen by the client
+func downloadURL(c echo.Context, token string) string {
+	return c.Scheme() + "://" + c.Request().Host + "/downloads/" + token
+}
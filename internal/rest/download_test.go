@@ -0,0 +1,74 @@
+package rest_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rest"
+	"github.com/bxcodec/go-clean-arch/internal/rest/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownload(t *testing.T) {
+	newContext := func() (echo.Context, *httptest.ResponseRecorder) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/downloads/token", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/downloads/:token")
+		c.SetParamNames("token")
+		c.SetParamValues("token")
+		return c, rec
+	}
+
+	t.Run("when link is valid should stream the stored file", func(t *testing.T) {
+		mockLinks := new(mocks.DownloadService)
+		mockLinks.On("Open", mock.Anything, "token").Return(domain.PdfFile{
+			Name:    "split_test.pdf.zip",
+			Content: io.NopCloser(bytes.NewReader([]byte{1, 2})),
+			Size:    2,
+		}, nil).Once()
+
+		c, rec := newContext()
+		handler := rest.DownloadHandler{Service: mockLinks}
+
+		err := handler.Download(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/zip", rec.Header().Get(echo.HeaderContentType))
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "split_test.pdf.zip")
+		assert.Equal(t, []byte{1, 2}, rec.Body.Bytes())
+	})
+
+	for _, tc := range []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"when link is invalid should return status 404", domain.ErrNotFound, http.StatusNotFound},
+		{"when link expired should return status 410", domain.ErrLinkExpired, http.StatusGone},
+		{"when store failed should return status 500", fmt.Errorf("Get Error"), http.StatusInternalServerError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockLinks := new(mocks.DownloadService)
+			mockLinks.On("Open", mock.Anything, "token").Return(domain.PdfFile{}, tc.err).Once()
+
+			c, rec := newContext()
+			handler := rest.DownloadHandler{Service: mockLinks}
+
+			err := handler.Download(c)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.status, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// JobService represent the usecases of following PDF jobs
+//
+//go:generate mockery --name JobService
+type JobService interface {
+	Start(ctx context.Context, id string) (context.Context, error)
+	Finish(id string, err error)
+	Subscribe(ctx context.Context, id string) (<-chan domain.JobProgress, error)
+}
+
+// jobHeartbeat keeps idle event streams from being closed by proxies
+const jobHeartbeat = 15 * time.Second
+
+// jobIDPattern keeps client chosen ids hard to guess and safe to log
+var jobIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{16,64}$`)
+
+// JobHandler represent the httphandler for job progress
+type JobHandler struct {
+	Service JobService
+}
+
+// NewJobHandler will initialize the jobs/ resources endpoint
+func NewJobHandler(e *echo.Echo, svc JobService) {
+	handler := &JobHandler{
+		Service: svc,
+	}
+	e.GET("/jobs/:id/events", handler.Events)
+}
+
+// @Summary Follow the progress of a PDF job
+// @Description Streams Server-Sent Events with the progress of the job started with the same job_id. A job may be followed before it starts. The stream ends with a done event, or when the request times out, in which case the client reconnects and gets the current state.
+// @Tags PDF
+// @Produce text/event-stream
+// @Param id path string true "Job id given as job_id, or returned in X-Job-ID"
+// @Success 200 {object} domain.JobProgress "progress and done events"
+// @Failure 400 {object} ResponseError "Invalid job id"
+// @Router /jobs/{id}/events [get]
+func (a *JobHandler) Events(c echo.Context) error {
+	id := c.Param("id")
+	if !jobIDPattern.MatchString(id) {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: "Invalid job id"})
+	}
+
+	ctx := c.Request().Context()
+	updates, err := a.Service.Subscribe(ctx, id)
+	if err != nil {
+		return respondWithPdfError(c, err, "Failed to follow the job")
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	fmt.Fprint(res, "retry: 1000\n\n")
+	res.Flush()
+
+	heartbeat := time.NewTicker(jobHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case progress, ok := <-updates:
+			if !ok {
+				return nil
+			}
+			if err := writeJobEvent(res, progress); err != nil {
+				return nil
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func writeJobEvent(res *echo.Response, progress domain.JobProgress) error {
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+
+	event := "progress"
+	if progress.Done {
+		event = "done"
+	}
+	if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return err
+	}
+	res.Flush()
+	return nil
+}
+
+// startJob follows the request as job job_id, or a new id, when jobs are enabled.
+// The id is returned in X-Job-ID and finish has to be called with the outcome.
+func (a *PdfHandler) startJob(c echo.Context) (context.Context, string, func(err error, message string), error) {
+	ctx := c.Request().Context()
+
+	id := c.FormValue("job_id")
+	if id == "" {
+		id = uuid.NewString()
+	} else if !jobIDPattern.MatchString(id) {
+		return nil, "", nil, paramError("job_id must be 16 to 64 letters, digits, '-' or '_'")
+	}
+
+	finish := func(error, string) {}
+	if a.Jobs != nil {
+		var err error
+		ctx, err = a.Jobs.Start(ctx, id)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		finish = func(err error, message string) {
+			if err != nil {
+				// followers only learn what the client would
+				err = errors.New(pdfErrorMessage(err, message))
+			}
+			a.Jobs.Finish(id, err)
+		}
+	}
+
+	c.Response().Header().Set("X-Job-ID", id)
+	return ctx, id, finish, nil
+}
@@ -0,0 +1,137 @@
+package rest_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rest"
+	"github.com/bxcodec/go-clean-arch/internal/rest/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testJobID = "0123456789abcdef"
+
+func TestJobEvents(t *testing.T) {
+	newContext := func(id string) (echo.Context, *httptest.ResponseRecorder) {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/jobs/"+id+"/events", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/jobs/:id/events")
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		return c, rec
+	}
+
+	t.Run("when job runs should stream progress until done", func(t *testing.T) {
+		updates := make(chan domain.JobProgress, 2)
+		updates <- domain.JobProgress{JobID: testJobID, Step: domain.StepSplitting, Pages: 1, TotalPages: 2, Percent: 50}
+		updates <- domain.JobProgress{JobID: testJobID, Step: domain.StepDone, Percent: 100, Done: true}
+		close(updates)
+		mockJobs := new(mocks.JobService)
+		mockJobs.On("Subscribe", mock.Anything, testJobID).Return((<-chan domain.JobProgress)(updates), nil).Once()
+
+		c, rec := newContext(testJobID)
+		handler := rest.JobHandler{Service: mockJobs}
+
+		err := handler.Events(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/event-stream", rec.Header().Get(echo.HeaderContentType))
+		assert.Contains(t, rec.Body.String(), "event: progress\ndata: {\"job_id\":\""+testJobID+"\",\"step\":\"splitting\",\"pages\":1,\"total_pages\":2,\"percent\":50,\"done\":false}\n\n")
+		assert.Contains(t, rec.Body.String(), "event: done\n")
+	})
+
+	t.Run("when job id is invalid should return status 400", func(t *testing.T) {
+		c, rec := newContext("short")
+		handler := rest.JobHandler{Service: new(mocks.JobService)}
+
+		err := handler.Events(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestPdfJobs(t *testing.T) {
+	pdfContent, err := os.ReadFile("../resource/test.pdf")
+	require.NoError(t, err)
+
+	compress := func(handler rest.PdfHandler, jobID string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, _ := writer.CreateFormFile("file", "test.pdf")
+		part.Write(pdfContent)
+		if jobID != "" {
+			writer.WriteField("job_id", jobID)
+		}
+		writer.Close()
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/process/compress", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		rec := httptest.NewRecorder()
+		require.NoError(t, handler.StartCompress(e.NewContext(req, rec)))
+		return rec
+	}
+
+	t.Run("when job_id is given should follow the job until the result was sent", func(t *testing.T) {
+		mockJobs := new(mocks.JobService)
+		mockJobs.On("Start", mock.Anything, testJobID).Return(context.TODO(), nil).Once()
+		mockJobs.On("Finish", testJobID, nil).Once()
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("CompressPdf", mock.Anything, mock.Anything, mock.Anything).Return(domain.PdfFile{
+			Name:    "compress_test.pdf",
+			Content: io.NopCloser(bytes.NewReader([]byte{1})),
+			Size:    1,
+		}, nil).Once()
+
+		rec := compress(rest.PdfHandler{Service: mockPdfSvc, Jobs: mockJobs}, testJobID)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, testJobID, rec.Header().Get("X-Job-ID"))
+		mockJobs.AssertExpectations(t)
+	})
+
+	t.Run("when operation fails should finish the job with the client message", func(t *testing.T) {
+		mockJobs := new(mocks.JobService)
+		mockJobs.On("Start", mock.Anything, mock.Anything).Return(context.TODO(), nil).Once()
+		mockJobs.On("Finish", mock.Anything, mock.MatchedBy(func(err error) bool {
+			return err != nil && err.Error() == "Failed to compress pdf"
+		})).Once()
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("CompressPdf", mock.Anything, mock.Anything, mock.Anything).Return(domain.PdfFile{}, io.ErrUnexpectedEOF).Once()
+
+		rec := compress(rest.PdfHandler{Service: mockPdfSvc, Jobs: mockJobs}, "")
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.NotEmpty(t, rec.Header().Get("X-Job-ID"))
+		mockJobs.AssertExpectations(t)
+	})
+
+	t.Run("when job_id is taken should return status 409", func(t *testing.T) {
+		mockJobs := new(mocks.JobService)
+		mockJobs.On("Start", mock.Anything, testJobID).Return(nil, domain.ErrConflict).Once()
+
+		rec := compress(rest.PdfHandler{Service: new(mocks.PdfService), Jobs: mockJobs}, testJobID)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("when job_id is invalid should return status 400", func(t *testing.T) {
+		rec := compress(rest.PdfHandler{Service: new(mocks.PdfService), Jobs: new(mocks.JobService)}, "../../etc")
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,86 @@
+// Code generated by mockery v2.50.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/bxcodec/go-clean-arch/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditService is an autogenerated mock type for the AuditService type
+type AuditService struct {
+	mock.Mock
+}
+
+// Audited provides a mock function with given fields: op, call
+func (_m *AuditService) Audited(op domain.PdfProcessor, call domain.PdfAuditCall) domain.PdfProcessor {
+	ret := _m.Called(op, call)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Audited")
+	}
+
+	var r0 domain.PdfProcessor
+	if rf, ok := ret.Get(0).(func(domain.PdfProcessor, domain.PdfAuditCall) domain.PdfProcessor); ok {
+		r0 = rf(op, call)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(domain.PdfProcessor)
+		}
+	}
+
+	return r0
+}
+
+// Fetch provides a mock function with given fields: ctx, filter, cursor, num
+func (_m *AuditService) Fetch(ctx context.Context, filter domain.PdfAuditFilter, cursor string, num int64) ([]domain.PdfAuditRecord, string, error) {
+	ret := _m.Called(ctx, filter, cursor, num)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Fetch")
+	}
+
+	var r0 []domain.PdfAuditRecord
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PdfAuditFilter, string, int64) ([]domain.PdfAuditRecord, string, error)); ok {
+		return rf(ctx, filter, cursor, num)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PdfAuditFilter, string, int64) []domain.PdfAuditRecord); ok {
+		r0 = rf(ctx, filter, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.PdfAuditRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.PdfAuditFilter, string, int64) string); ok {
+		r1 = rf(ctx, filter, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, domain.PdfAuditFilter, string, int64) error); ok {
+		r2 = rf(ctx, filter, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewAuditService creates a new instance of AuditService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditService {
+	mock := &AuditService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
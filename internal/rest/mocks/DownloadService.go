@@ -0,0 +1,85 @@
+// Code generated by mockery v2.50.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/bxcodec/go-clean-arch/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DownloadService is an autogenerated mock type for the DownloadService type
+type DownloadService struct {
+	mock.Mock
+}
+
+// Open provides a mock function with given fields: ctx, token
+func (_m *DownloadService) Open(ctx context.Context, token string) (domain.PdfFile, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Open")
+	}
+
+	var r0 domain.PdfFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.PdfFile, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.PdfFile); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Get(0).(domain.PdfFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Publish provides a mock function with given fields: ctx, file
+func (_m *DownloadService) Publish(ctx context.Context, file domain.PdfFile) (domain.DownloadLink, error) {
+	ret := _m.Called(ctx, file)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 domain.DownloadLink
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PdfFile) (domain.DownloadLink, error)); ok {
+		return rf(ctx, file)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PdfFile) domain.DownloadLink); ok {
+		r0 = rf(ctx, file)
+	} else {
+		r0 = ret.Get(0).(domain.DownloadLink)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.PdfFile) error); ok {
+		r1 = rf(ctx, file)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDownloadService creates a new instance of DownloadService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDownloadService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DownloadService {
+	mock := &DownloadService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
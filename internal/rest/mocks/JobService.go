@@ -0,0 +1,94 @@
+// Code generated by mockery v2.50.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/bxcodec/go-clean-arch/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// JobService is an autogenerated mock type for the JobService type
+type JobService struct {
+	mock.Mock
+}
+
+// Finish provides a mock function with given fields: id, err
+func (_m *JobService) Finish(id string, err error) {
+	_m.Called(id, err)
+}
+
+// Start provides a mock function with given fields: ctx, id
+func (_m *JobService) Start(ctx context.Context, id string) (context.Context, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Start")
+	}
+
+	var r0 context.Context
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (context.Context, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) context.Context); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(context.Context)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Subscribe provides a mock function with given fields: ctx, id
+func (_m *JobService) Subscribe(ctx context.Context, id string) (<-chan domain.JobProgress, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Subscribe")
+	}
+
+	var r0 <-chan domain.JobProgress
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (<-chan domain.JobProgress, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan domain.JobProgress); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan domain.JobProgress)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewJobService creates a new instance of JobService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewJobService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *JobService {
+	mock := &JobService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -4,11 +4,13 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 
 	domain "github.com/bxcodec/go-clean-arch/domain"
+
 	mock "github.com/stretchr/testify/mock"
 
-	multipart "mime/multipart"
+	zip "archive/zip"
 )
 
 // PdfService is an autogenerated mock type for the PdfService type
@@ -16,8 +18,120 @@ type PdfService struct {
 	mock.Mock
 }
 
+// AnalyzePdf provides a mock function with given fields: ctx, fileName, file
+func (_m *PdfService) AnalyzePdf(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+	ret := _m.Called(ctx, fileName, file)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AnalyzePdf")
+	}
+
+	var r0 domain.PdfFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker) (domain.PdfFile, error)); ok {
+		return rf(ctx, fileName, file)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker) domain.PdfFile); ok {
+		r0 = rf(ctx, fileName, file)
+	} else {
+		r0 = ret.Get(0).(domain.PdfFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.ReadSeeker) error); ok {
+		r1 = rf(ctx, fileName, file)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BatchPdf provides a mock function with given fields: ctx, fileName, archive, process
+func (_m *PdfService) BatchPdf(ctx context.Context, fileName string, archive *zip.Reader, process domain.PdfProcessor) (domain.PdfFile, error) {
+	ret := _m.Called(ctx, fileName, archive, process)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BatchPdf")
+	}
+
+	var r0 domain.PdfFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *zip.Reader, domain.PdfProcessor) (domain.PdfFile, error)); ok {
+		return rf(ctx, fileName, archive, process)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *zip.Reader, domain.PdfProcessor) domain.PdfFile); ok {
+		r0 = rf(ctx, fileName, archive, process)
+	} else {
+		r0 = ret.Get(0).(domain.PdfFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *zip.Reader, domain.PdfProcessor) error); ok {
+		r1 = rf(ctx, fileName, archive, process)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CollatePdf provides a mock function with given fields: ctx, outputName, documents, selections
+func (_m *PdfService) CollatePdf(ctx context.Context, outputName string, documents map[string]io.ReadSeeker, selections []domain.PageSelection) (domain.PdfFile, error) {
+	ret := _m.Called(ctx, outputName, documents, selections)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CollatePdf")
+	}
+
+	var r0 domain.PdfFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]io.ReadSeeker, []domain.PageSelection) (domain.PdfFile, error)); ok {
+		return rf(ctx, outputName, documents, selections)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, map[string]io.ReadSeeker, []domain.PageSelection) domain.PdfFile); ok {
+		r0 = rf(ctx, outputName, documents, selections)
+	} else {
+		r0 = ret.Get(0).(domain.PdfFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, map[string]io.ReadSeeker, []domain.PageSelection) error); ok {
+		r1 = rf(ctx, outputName, documents, selections)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ComparePdf provides a mock function with given fields: ctx, originalName, original, revisedName, revised, annotate
+func (_m *PdfService) ComparePdf(ctx context.Context, originalName string, original io.ReadSeeker, revisedName string, revised io.ReadSeeker, annotate bool) (domain.PdfFile, error) {
+	ret := _m.Called(ctx, originalName, original, revisedName, revised, annotate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ComparePdf")
+	}
+
+	var r0 domain.PdfFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, string, io.ReadSeeker, bool) (domain.PdfFile, error)); ok {
+		return rf(ctx, originalName, original, revisedName, revised, annotate)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, string, io.ReadSeeker, bool) domain.PdfFile); ok {
+		r0 = rf(ctx, originalName, original, revisedName, revised, annotate)
+	} else {
+		r0 = ret.Get(0).(domain.PdfFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.ReadSeeker, string, io.ReadSeeker, bool) error); ok {
+		r1 = rf(ctx, originalName, original, revisedName, revised, annotate)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CompressPdf provides a mock function with given fields: ctx, fileName, file
-func (_m *PdfService) CompressPdf(ctx context.Context, fileName string, file multipart.File) (domain.PdfFile, error) {
+func (_m *PdfService) CompressPdf(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
 	ret := _m.Called(ctx, fileName, file)
 
 	if len(ret) == 0 {
@@ -26,16 +140,16 @@ func (_m *PdfService) CompressPdf(ctx context.Context, fileName string, file mul
 
 	var r0 domain.PdfFile
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, multipart.File) (domain.PdfFile, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker) (domain.PdfFile, error)); ok {
 		return rf(ctx, fileName, file)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, multipart.File) domain.PdfFile); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker) domain.PdfFile); ok {
 		r0 = rf(ctx, fileName, file)
 	} else {
 		r0 = ret.Get(0).(domain.PdfFile)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, multipart.File) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.ReadSeeker) error); ok {
 		r1 = rf(ctx, fileName, file)
 	} else {
 		r1 = ret.Error(1)
@@ -44,8 +158,92 @@ func (_m *PdfService) CompressPdf(ctx context.Context, fileName string, file mul
 	return r0, r1
 }
 
+// CropPdf provides a mock function with given fields: ctx, fileName, file, options
+func (_m *PdfService) CropPdf(ctx context.Context, fileName string, file io.ReadSeeker, options domain.CropOptions) (domain.PdfFile, error) {
+	ret := _m.Called(ctx, fileName, file, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CropPdf")
+	}
+
+	var r0 domain.PdfFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, domain.CropOptions) (domain.PdfFile, error)); ok {
+		return rf(ctx, fileName, file, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, domain.CropOptions) domain.PdfFile); ok {
+		r0 = rf(ctx, fileName, file, options)
+	} else {
+		r0 = ret.Get(0).(domain.PdfFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.ReadSeeker, domain.CropOptions) error); ok {
+		r1 = rf(ctx, fileName, file, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertPdf provides a mock function with given fields: ctx, fileName, target, source, sourcePages, position
+func (_m *PdfService) InsertPdf(ctx context.Context, fileName string, target io.ReadSeeker, source io.ReadSeeker, sourcePages []int, position domain.InsertPosition) (domain.PdfFile, error) {
+	ret := _m.Called(ctx, fileName, target, source, sourcePages, position)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertPdf")
+	}
+
+	var r0 domain.PdfFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, io.ReadSeeker, []int, domain.InsertPosition) (domain.PdfFile, error)); ok {
+		return rf(ctx, fileName, target, source, sourcePages, position)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, io.ReadSeeker, []int, domain.InsertPosition) domain.PdfFile); ok {
+		r0 = rf(ctx, fileName, target, source, sourcePages, position)
+	} else {
+		r0 = ret.Get(0).(domain.PdfFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.ReadSeeker, io.ReadSeeker, []int, domain.InsertPosition) error); ok {
+		r1 = rf(ctx, fileName, target, source, sourcePages, position)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InterleavePdf provides a mock function with given fields: ctx, outputName, odd, even
+func (_m *PdfService) InterleavePdf(ctx context.Context, outputName string, odd io.ReadSeeker, even io.ReadSeeker) (domain.PdfFile, error) {
+	ret := _m.Called(ctx, outputName, odd, even)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InterleavePdf")
+	}
+
+	var r0 domain.PdfFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, io.ReadSeeker) (domain.PdfFile, error)); ok {
+		return rf(ctx, outputName, odd, even)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, io.ReadSeeker) domain.PdfFile); ok {
+		r0 = rf(ctx, outputName, odd, even)
+	} else {
+		r0 = ret.Get(0).(domain.PdfFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.ReadSeeker, io.ReadSeeker) error); ok {
+		r1 = rf(ctx, outputName, odd, even)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PageCount provides a mock function with given fields: ctx, file
-func (_m *PdfService) PageCount(ctx context.Context, file multipart.File) (int, error) {
+func (_m *PdfService) PageCount(ctx context.Context, file io.ReadSeeker) (int, error) {
 	ret := _m.Called(ctx, file)
 
 	if len(ret) == 0 {
@@ -54,16 +252,16 @@ func (_m *PdfService) PageCount(ctx context.Context, file multipart.File) (int,
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, multipart.File) (int, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker) (int, error)); ok {
 		return rf(ctx, file)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, multipart.File) int); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker) int); ok {
 		r0 = rf(ctx, file)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, multipart.File) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, io.ReadSeeker) error); ok {
 		r1 = rf(ctx, file)
 	} else {
 		r1 = ret.Error(1)
@@ -73,7 +271,7 @@ func (_m *PdfService) PageCount(ctx context.Context, file multipart.File) (int,
 }
 
 // RemovePagesPdf provides a mock function with given fields: ctx, fileName, file, removePages, pageCount
-func (_m *PdfService) RemovePagesPdf(ctx context.Context, fileName string, file multipart.File, removePages []int, pageCount int) (domain.PdfFile, error) {
+func (_m *PdfService) RemovePagesPdf(ctx context.Context, fileName string, file io.ReadSeeker, removePages []int, pageCount int) (domain.PdfFile, error) {
 	ret := _m.Called(ctx, fileName, file, removePages, pageCount)
 
 	if len(ret) == 0 {
@@ -82,16 +280,16 @@ func (_m *PdfService) RemovePagesPdf(ctx context.Context, fileName string, file
 
 	var r0 domain.PdfFile
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, multipart.File, []int, int) (domain.PdfFile, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, []int, int) (domain.PdfFile, error)); ok {
 		return rf(ctx, fileName, file, removePages, pageCount)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, multipart.File, []int, int) domain.PdfFile); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, []int, int) domain.PdfFile); ok {
 		r0 = rf(ctx, fileName, file, removePages, pageCount)
 	} else {
 		r0 = ret.Get(0).(domain.PdfFile)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, multipart.File, []int, int) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.ReadSeeker, []int, int) error); ok {
 		r1 = rf(ctx, fileName, file, removePages, pageCount)
 	} else {
 		r1 = ret.Error(1)
@@ -100,9 +298,9 @@ func (_m *PdfService) RemovePagesPdf(ctx context.Context, fileName string, file
 	return r0, r1
 }
 
-// SplitAndZipPdfByFixedRange provides a mock function with given fields: ctx, fileName, file, fra
-func (_m *PdfService) SplitAndZipPdfByFixedRange(ctx context.Context, fileName string, file multipart.File, fra [][]int) (domain.PdfFile, error) {
-	ret := _m.Called(ctx, fileName, file, fra)
+// SplitAndZipPdfByFixedRange provides a mock function with given fields: ctx, fileName, file, fra, naming
+func (_m *PdfService) SplitAndZipPdfByFixedRange(ctx context.Context, fileName string, file io.ReadSeeker, fra [][]int, naming domain.SplitNaming) (domain.PdfFile, error) {
+	ret := _m.Called(ctx, fileName, file, fra, naming)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SplitAndZipPdfByFixedRange")
@@ -110,17 +308,17 @@ func (_m *PdfService) SplitAndZipPdfByFixedRange(ctx context.Context, fileName s
 
 	var r0 domain.PdfFile
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, multipart.File, [][]int) (domain.PdfFile, error)); ok {
-		return rf(ctx, fileName, file, fra)
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, [][]int, domain.SplitNaming) (domain.PdfFile, error)); ok {
+		return rf(ctx, fileName, file, fra, naming)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, multipart.File, [][]int) domain.PdfFile); ok {
-		r0 = rf(ctx, fileName, file, fra)
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, [][]int, domain.SplitNaming) domain.PdfFile); ok {
+		r0 = rf(ctx, fileName, file, fra, naming)
 	} else {
 		r0 = ret.Get(0).(domain.PdfFile)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, multipart.File, [][]int) error); ok {
-		r1 = rf(ctx, fileName, file, fra)
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.ReadSeeker, [][]int, domain.SplitNaming) error); ok {
+		r1 = rf(ctx, fileName, file, fra, naming)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -129,7 +327,7 @@ func (_m *PdfService) SplitAndZipPdfByFixedRange(ctx context.Context, fileName s
 }
 
 // SplitPdfByRanges provides a mock function with given fields: ctx, fileName, file, ranges
-func (_m *PdfService) SplitPdfByRanges(ctx context.Context, fileName string, file multipart.File, ranges []int) (domain.PdfFile, error) {
+func (_m *PdfService) SplitPdfByRanges(ctx context.Context, fileName string, file io.ReadSeeker, ranges []int) (domain.PdfFile, error) {
 	ret := _m.Called(ctx, fileName, file, ranges)
 
 	if len(ret) == 0 {
@@ -138,16 +336,16 @@ func (_m *PdfService) SplitPdfByRanges(ctx context.Context, fileName string, fil
 
 	var r0 domain.PdfFile
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, multipart.File, []int) (domain.PdfFile, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, []int) (domain.PdfFile, error)); ok {
 		return rf(ctx, fileName, file, ranges)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, multipart.File, []int) domain.PdfFile); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, []int) domain.PdfFile); ok {
 		r0 = rf(ctx, fileName, file, ranges)
 	} else {
 		r0 = ret.Get(0).(domain.PdfFile)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, multipart.File, []int) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.ReadSeeker, []int) error); ok {
 		r1 = rf(ctx, fileName, file, ranges)
 	} else {
 		r1 = ret.Error(1)
@@ -156,6 +354,43 @@ func (_m *PdfService) SplitPdfByRanges(ctx context.Context, fileName string, fil
 	return r0, r1
 }
 
+// Spool provides a mock function with given fields: ctx, content
+func (_m *PdfService) Spool(ctx context.Context, content io.Reader) (domain.SpooledContent, int64, error) {
+	ret := _m.Called(ctx, content)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Spool")
+	}
+
+	var r0 domain.SpooledContent
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader) (domain.SpooledContent, int64, error)); ok {
+		return rf(ctx, content)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader) domain.SpooledContent); ok {
+		r0 = rf(ctx, content)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(domain.SpooledContent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.Reader) int64); ok {
+		r1 = rf(ctx, content)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, io.Reader) error); ok {
+		r2 = rf(ctx, content)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // NewPdfService creates a new instance of PdfService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewPdfService(t interface {
@@ -0,0 +1,161 @@
+// Code generated by mockery v2.50.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+
+	domain "github.com/bxcodec/go-clean-arch/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ResumableUploadService is an autogenerated mock type for the ResumableUploadService type
+type ResumableUploadService struct {
+	mock.Mock
+}
+
+// Append provides a mock function with given fields: ctx, id, offset, content, checksum
+func (_m *ResumableUploadService) Append(ctx context.Context, id string, offset int64, content io.Reader, checksum *domain.Checksum) (domain.ResumableUpload, error) {
+	ret := _m.Called(ctx, id, offset, content, checksum)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Append")
+	}
+
+	var r0 domain.ResumableUpload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, io.Reader, *domain.Checksum) (domain.ResumableUpload, error)); ok {
+		return rf(ctx, id, offset, content, checksum)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, io.Reader, *domain.Checksum) domain.ResumableUpload); ok {
+		r0 = rf(ctx, id, offset, content, checksum)
+	} else {
+		r0 = ret.Get(0).(domain.ResumableUpload)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, io.Reader, *domain.Checksum) error); ok {
+		r1 = rf(ctx, id, offset, content, checksum)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, length, metadata
+func (_m *ResumableUploadService) Create(ctx context.Context, length int64, metadata map[string]string) (domain.ResumableUpload, error) {
+	ret := _m.Called(ctx, length, metadata)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 domain.ResumableUpload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, map[string]string) (domain.ResumableUpload, error)); ok {
+		return rf(ctx, length, metadata)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, map[string]string) domain.ResumableUpload); ok {
+		r0 = rf(ctx, length, metadata)
+	} else {
+		r0 = ret.Get(0).(domain.ResumableUpload)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, map[string]string) error); ok {
+		r1 = rf(ctx, length, metadata)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *ResumableUploadService) Delete(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, id
+func (_m *ResumableUploadService) Get(ctx context.Context, id string) (domain.ResumableUpload, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 domain.ResumableUpload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.ResumableUpload, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.ResumableUpload); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.ResumableUpload)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Open provides a mock function with given fields: ctx, id
+func (_m *ResumableUploadService) Open(ctx context.Context, id string) (domain.SourceFile, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Open")
+	}
+
+	var r0 domain.SourceFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.SourceFile, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.SourceFile); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.SourceFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewResumableUploadService creates a new instance of ResumableUploadService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewResumableUploadService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ResumableUploadService {
+	mock := &ResumableUploadService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,57 @@
+// Code generated by mockery v2.50.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/bxcodec/go-clean-arch/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SourceFetcher is an autogenerated mock type for the SourceFetcher type
+type SourceFetcher struct {
+	mock.Mock
+}
+
+// Fetch provides a mock function with given fields: ctx, rawURL, maxBytes
+func (_m *SourceFetcher) Fetch(ctx context.Context, rawURL string, maxBytes int64) (domain.SourceFile, error) {
+	ret := _m.Called(ctx, rawURL, maxBytes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Fetch")
+	}
+
+	var r0 domain.SourceFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) (domain.SourceFile, error)); ok {
+		return rf(ctx, rawURL, maxBytes)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) domain.SourceFile); ok {
+		r0 = rf(ctx, rawURL, maxBytes)
+	} else {
+		r0 = ret.Get(0).(domain.SourceFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, rawURL, maxBytes)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSourceFetcher creates a new instance of SourceFetcher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSourceFetcher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SourceFetcher {
+	mock := &SourceFetcher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -1,104 +1,218 @@
 package rest
 
 import (
-	"bytes"
+	"archive/zip"
 	"context"
+	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
+	"math"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/pdf"
 	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
 )
 
-const (
-	SPLIT_MODE_RANGE        = "ranges"
-	SPLIT_MODE_FIXED_RANGE  = "fixed_range"
-	SPLIT_MODE_REMOVE_PAGED = "remove_pages"
-)
+// RESPONSE_LINK asks for a download link instead of the file itself
+const RESPONSE_LINK = "link"
+
+// StatusClientClosedRequest is the nginx convention for a client that went away
+// before the response was ready
+const StatusClientClosedRequest = 499
+
+// uploadMemory is how much of a multipart upload is kept in memory. With zero every
+// file part is spooled to a temp file, so large documents never sit in RAM.
+const uploadMemory = 0
 
 type PdfService interface {
-	CompressPdf(ctx context.Context, fileName string, file multipart.File) (domain.PdfFile, error)
-	SplitPdfByRanges(ctx context.Context, fileName string, file multipart.File, ranges []int) (domain.PdfFile, error)
-	SplitAndZipPdfByFixedRange(ctx context.Context, fileName string, file multipart.File, fra [][]int) (domain.PdfFile, error)
-	RemovePagesPdf(ctx context.Context, fileName string, file multipart.File, removePages []int, pageCount int) (domain.PdfFile, error)
-	PageCount(ctx context.Context, file multipart.File) (int, error)
+	CompressPdf(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error)
+	SplitPdfByRanges(ctx context.Context, fileName string, file io.ReadSeeker, ranges []int) (domain.PdfFile, error)
+	SplitAndZipPdfByFixedRange(ctx context.Context, fileName string, file io.ReadSeeker, fra [][]int, naming domain.SplitNaming) (domain.PdfFile, error)
+	RemovePagesPdf(ctx context.Context, fileName string, file io.ReadSeeker, removePages []int, pageCount int) (domain.PdfFile, error)
+	PageCount(ctx context.Context, file io.ReadSeeker) (int, error)
+	BatchPdf(ctx context.Context, fileName string, archive *zip.Reader, process domain.PdfProcessor) (domain.PdfFile, error)
+	Spool(ctx context.Context, content io.Reader) (domain.SpooledContent, int64, error)
+	CropPdf(ctx context.Context, fileName string, file io.ReadSeeker, options domain.CropOptions) (domain.PdfFile, error)
+	AnalyzePdf(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error)
+	ComparePdf(ctx context.Context, originalName string, original io.ReadSeeker, revisedName string, revised io.ReadSeeker, annotate bool) (domain.PdfFile, error)
+	InsertPdf(ctx context.Context, fileName string, target io.ReadSeeker, source io.ReadSeeker, sourcePages []int, position domain.InsertPosition) (domain.PdfFile, error)
+	CollatePdf(ctx context.Context, outputName string, documents map[string]io.ReadSeeker, selections []domain.PageSelection) (domain.PdfFile, error)
+	InterleavePdf(ctx context.Context, outputName string, odd io.ReadSeeker, even io.ReadSeeker) (domain.PdfFile, error)
 }
 
 type PdfHandler struct {
 	Service PdfService
+	Links   DownloadService
+	Limits  UploadLimits
+	// Sources downloads source_url documents, nil disables source_url
+	Sources SourceFetcher
+	// Uploads opens complete resumable uploads, nil disables upload_id
+	Uploads ResumableUploadService
+	// Jobs follows the progress of requests, nil disables progress events
+	Jobs JobService
+	// Webhooks delivers results to callback_url, nil disables callback_url
+	Webhooks WebhookService
+	// Audit records every processed document, nil disables the audit log
+	Audit AuditService
+	// AsyncTimeout limits a job answered right away, zero means no limit
+	AsyncTimeout time.Duration
 }
 
-func NewPdfHandler(e *echo.Echo, svc PdfService) {
-	handler := &PdfHandler{
-		Service: svc,
+// PdfHandlerOption enables an optional feature of the PDF endpoints
+type PdfHandlerOption func(*PdfHandler)
+
+// WithDownloadLinks lets clients ask for response = link
+func WithDownloadLinks(links DownloadService) PdfHandlerOption {
+	return func(h *PdfHandler) {
+		h.Links = links
 	}
-	e.POST("/process/compress", handler.StartCompress)
-	e.POST("/process/split", handler.StartSplit)
 }
 
-func (a *PdfHandler) validateAndOpenFile(c echo.Context) (string, multipart.File, error) {
-	file, err := c.FormFile("file")
-	if err != nil {
-		return "", nil, c.JSON(http.StatusBadRequest, ResponseError{Message: "Failed to get the file"})
+// WithUploadLimits caps the size of uploads
+func WithUploadLimits(limits UploadLimits) PdfHandlerOption {
+	return func(h *PdfHandler) {
+		h.Limits = limits
+	}
+}
+
+// WithSourceFetcher lets clients send a source_url instead of the file
+func WithSourceFetcher(sources SourceFetcher) PdfHandlerOption {
+	return func(h *PdfHandler) {
+		h.Sources = sources
 	}
+}
 
-	src, err := file.Open()
-	if err != nil {
-		return "", nil, c.JSON(http.StatusInternalServerError, ResponseError{Message: "Failed to open the file"})
+// WithResumableUploads lets clients send the upload_id of a complete resumable upload
+// instead of the file
+func WithResumableUploads(uploads ResumableUploadService) PdfHandlerOption {
+	return func(h *PdfHandler) {
+		h.Uploads = uploads
+	}
+}
+
+// WithJobs publishes the progress of every request
+func WithJobs(jobs JobService) PdfHandlerOption {
+	return func(h *PdfHandler) {
+		h.Jobs = jobs
+	}
+}
+
+// WithWebhooks lets clients send a callback_url and get the result delivered there.
+// It needs download links, the webhook refers to the stored result.
+func WithWebhooks(webhooks WebhookService, timeout time.Duration) PdfHandlerOption {
+	return func(h *PdfHandler) {
+		h.Webhooks = webhooks
+		h.AsyncTimeout = timeout
 	}
-	defer src.Close()
+}
 
-	return file.Filename, src, nil
+func NewPdfHandler(e *echo.Echo, svc PdfService, opts ...PdfHandlerOption) {
+	handler := &PdfHandler{
+		Service: svc,
+	}
+	for _, opt := range opts {
+		opt(handler)
+	}
+	e.POST("/process/compress", handler.StartCompress)
+	e.POST("/process/split", handler.StartSplit)
+	e.POST("/process/compare", handler.StartCompare)
+	e.POST("/process/insert", handler.StartInsert)
+	e.POST("/process/collate", handler.StartCollate)
+	e.POST("/process/:operation", handler.StartProcess)
 }
 
 // @Summary Compress a PDF file
 // @Description This API compresses the provided PDF file and returns the compressed version.
 // @Tags PDF
 // @Accept multipart/form-data
-// @Param file formData file true "PDF file"
+// @Produce application/pdf, application/zip, application/gzip, multipart/mixed
+// @Param file formData file false "PDF file, or a zip of PDF files with batch=true"
+// @Param source_url formData string false "URL to download the document from instead of uploading file"
+// @Param upload_id formData string false "Id of a complete resumable upload from /uploads to process instead of uploading file"
+// @Param batch formData boolean false "Compress every PDF of the uploaded zip and return a zip of results with a report.json"
+// @Param response formData string false "Set to 'link' to receive a JSON download link instead of the file"
+// @Param format formData string false "Format of a result with several files: 'zip' (default), 'tar.gz' or 'multipart' for multipart/mixed. Without it the Accept header decides."
+// @Param job_id formData string false "Id of 16 to 64 letters, digits, '-' or '_' to follow the progress at /jobs/{id}/events"
+// @Param callback_url formData string false "Answer right away with 202 and post the result, signed with HMAC-SHA256, to this URL when done"
 // @Success 200 {file} string "Compressed PDF file"
-// @Failure 400 {object} ResponseError "File type is invalid"
+// @Header 200 {string} X-Cache "HIT or MISS when the result cache is enabled"
+// @Header 200 {string} X-Job-ID "Id of the job"
+// @Success 202 {object} JobAccepted "Accepted, the result goes to callback_url"
+// @Failure 400 {object} ResponseError "File is missing or source_url is not allowed"
+// @Failure 404 {object} ResponseError "upload_id is unknown or expired"
+// @Failure 409 {object} ResponseError "job_id is already taken or upload_id is not complete"
+// @Failure 413 {object} ResponseError "File exceeds the upload limit"
+// @Failure 415 {object} ResponseError "File is not a PDF document or zip archive"
+// @Failure 422 {object} ResponseError "The document crashed pdfcpu or ran out of its worker limits"
 // @Failure 500 {object} ResponseError "Failed to compress PDF"
+// @Failure 502 {object} ResponseError "source_url could not be fetched"
+// @Failure 503 {object} ResponseError "Too busy, retry after the Retry-After header"
+// @Header 503 {integer} Retry-After "Seconds to wait before retrying"
+// @Failure 504 {object} ResponseError "Processing took longer than the request timeout"
+// @Failure 507 {object} ResponseError "Not enough scratch space"
 // @Router /process/compress [post]
 func (a *PdfHandler) StartCompress(c echo.Context) error {
-	fileName, src, err := a.validateAndOpenFile(c)
-	if err != nil {
-		return err
-	}
-	defer src.Close()
-
-	src.Seek(0, io.SeekStart)
-	ctx := c.Request().Context()
-	compressPdfFile, err := a.Service.CompressPdf(ctx, fileName, src)
+	upload, err := a.openRequestUpload(c, a.Limits.Compress)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, ResponseError{Message: "Failed to compress pdf"})
+		return respondWithPdfError(c, err, "Failed to open the file")
 	}
+	defer upload.file.Close()
 
-	c.Response().Header().Set(echo.HeaderContentType, "application/pdf")
-	c.Response().Header().Set(echo.HeaderContentDisposition, "attachment; filename="+compressPdfFile.Name)
-	reader := bytes.NewReader(compressPdfFile.Content)
-	return c.Stream(http.StatusOK, "application/pdf", reader)
+	op := a.audited(c, pdf.OperationCompress, nil, a.Service.CompressPdf)
+	return a.process(c, upload, op, "Failed to compress pdf")
 }
 
 // @Summary Split a PDF file
 // @Description This API splits the provided PDF file based on the specified split mode and range
 // @Tags PDF
 // @Accept multipart/form-data
-// @Produce application/pdf, application/zip
-// @Param file formData file true "PDF file to be split"
+// @Produce application/pdf, application/zip, application/gzip, multipart/mixed
+// @Param file formData file false "PDF file to be split, or a zip of PDF files with batch=true"
+// @Param source_url formData string false "URL to download the document from instead of uploading file"
+// @Param upload_id formData string false "Id of a complete resumable upload from /uploads to process instead of uploading file"
 // @Param split_mode formData string true "Split mode (e.g., 'ranges', 'fixed_range', 'remove_pages')"
 // @Param ranges formData string false "Page ranges when split_mode = 'ranges' (e.g., '1','5','1-5')"
 // @Param remove_page formData string false "Remove pages when split_mode = 'remove_pages' (e.g., '1','5','1-5')"
 // @Param fixed_range formData int false "Fixed range when split_mode = fixed_range (e.g., '2', '1')"
-// @Success 200 {file} string "Split PDF file"
-// @Failure 400 {object} ResponseError "Invalid input or file type"
+// @Param part_name formData string false "Name of the parts in the zip of split_mode = fixed_range, may use {base}, {index}, {from}, {to} and {bookmark}, numbers may be padded as in {index:3} (default 'split_part_{index}.pdf')"
+// @Param zip_name formData string false "Name of the zip of split_mode = fixed_range, may use {base} (default 'split_<file name>.zip')"
+// @Param batch formData boolean false "Split every PDF of the uploaded zip and return a zip of results with a report.json"
+// @Param response formData string false "Set to 'link' to receive a JSON download link instead of the file"
+// @Param format formData string false "Format of a result with several files: 'zip' (default), 'tar.gz' or 'multipart' for multipart/mixed. Without it the Accept header decides."
+// @Param job_id formData string false "Id of 16 to 64 letters, digits, '-' or '_' to follow the progress at /jobs/{id}/events"
+// @Param callback_url formData string false "Answer right away with 202 and post the result, signed with HMAC-SHA256, to this URL when done"
+// @Success 200 {file} string "Split PDF file, or the parts with a manifest.json as a zip, tar.gz or multipart/mixed"
+// @Header 200 {string} X-Cache "HIT or MISS when the result cache is enabled"
+// @Header 200 {string} X-Job-ID "Id of the job"
+// @Success 202 {object} JobAccepted "Accepted, the result goes to callback_url"
+// @Failure 400 {object} ResponseError "Invalid input, missing file or source_url not allowed"
+// @Failure 404 {object} ResponseError "upload_id is unknown or expired"
+// @Failure 409 {object} ResponseError "job_id is already taken or upload_id is not complete"
+// @Failure 413 {object} ResponseError "File exceeds the upload limit"
+// @Failure 415 {object} ResponseError "File is not a PDF document or zip archive"
+// @Failure 422 {object} ResponseError "The document crashed pdfcpu or ran out of its worker limits"
 // @Failure 500 {object} ResponseError "Failed to split PDF"
+// @Failure 502 {object} ResponseError "source_url could not be fetched"
+// @Failure 503 {object} ResponseError "Too busy, retry after the Retry-After header"
+// @Header 503 {integer} Retry-After "Seconds to wait before retrying"
+// @Failure 504 {object} ResponseError "Processing took longer than the request timeout"
+// @Failure 507 {object} ResponseError "Not enough scratch space"
 // @Router /process/split [post]
 func (a *PdfHandler) StartSplit(c echo.Context) error {
+	// The file is opened first so the form is parsed with uploadMemory rather than
+	// the default limit used by Bind.
+	upload, err := a.openRequestUpload(c, a.Limits.Split)
+	if err != nil {
+		return respondWithPdfError(c, err, "Failed to open the file")
+	}
+	defer upload.file.Close()
+
 	req := new(domain.SplitPdfFile)
 	if err := c.Bind(req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
@@ -107,154 +221,501 @@ func (a *PdfHandler) StartSplit(c echo.Context) error {
 		return err
 	}
 
-	fileName, src, err := a.validateAndOpenFile(c)
+	// What depends on the page count is checked per document
+	split, err := pdf.DefaultRegistry.Prepare(a.Service, pdf.KindSplit, req.SplitMode, c.FormValue)
+	if errors.Is(err, pdf.ErrUnknownOperation) {
+		err = paramError("Invalid Split Mode")
+	}
 	if err != nil {
-		return err
+		return respondWithPdfError(c, err, "Invalid split parameters")
 	}
-	defer src.Close()
+	info, _ := pdf.DefaultRegistry.Lookup(pdf.KindSplit, req.SplitMode)
+	split = a.audited(c, pdf.KindSplit, append([]pdf.Param{{Name: "split_mode"}}, info.Params...), split)
+
+	return a.process(c, upload, split, "Failed to split PDF")
+}
 
-	src.Seek(0, io.SeekStart)
-	ctx := c.Request().Context()
-	pageCount, err := a.Service.PageCount(ctx, src)
+// @Summary Run a registered operation on a PDF file
+// @Description Runs a processor of the operation registry, such as compress, on the provided PDF file. Processors share the upload limit of compress, their parameters are listed in this document.
+// @Tags PDF
+// @Accept multipart/form-data
+// @Produce application/pdf, application/zip, application/gzip, multipart/mixed
+// @Param operation path string true "Name of the operation"
+// @Param file formData file false "PDF file, or a zip of PDF files with batch=true"
+// @Param source_url formData string false "URL to download the document from instead of uploading file"
+// @Param upload_id formData string false "Id of a complete resumable upload from /uploads to process instead of uploading file"
+// @Param batch formData boolean false "Process every PDF of the uploaded zip and return a zip of results with a report.json"
+// @Param response formData string false "Set to 'link' to receive a JSON download link instead of the file"
+// @Param format formData string false "Format of a result with several files: 'zip' (default), 'tar.gz' or 'multipart' for multipart/mixed. Without it the Accept header decides."
+// @Param job_id formData string false "Id of 16 to 64 letters, digits, '-' or '_' to follow the progress at /jobs/{id}/events"
+// @Param callback_url formData string false "Answer right away with 202 and post the result, signed with HMAC-SHA256, to this URL when done"
+// @Success 200 {file} string "Processed PDF file"
+// @Header 200 {string} X-Cache "HIT or MISS when the result cache is enabled"
+// @Header 200 {string} X-Job-ID "Id of the job"
+// @Success 202 {object} JobAccepted "Accepted, the result goes to callback_url"
+// @Failure 400 {object} ResponseError "Invalid parameters, missing file or source_url not allowed"
+// @Failure 404 {object} ResponseError "Unknown operation, or upload_id is unknown or expired"
+// @Failure 409 {object} ResponseError "job_id is already taken or upload_id is not complete"
+// @Failure 413 {object} ResponseError "File exceeds the upload limit"
+// @Failure 415 {object} ResponseError "File is not a PDF document or zip archive"
+// @Failure 422 {object} ResponseError "The document crashed pdfcpu or ran out of its worker limits"
+// @Failure 500 {object} ResponseError "Failed to process PDF"
+// @Failure 502 {object} ResponseError "source_url could not be fetched"
+// @Failure 503 {object} ResponseError "Too busy, retry after the Retry-After header"
+// @Header 503 {integer} Retry-After "Seconds to wait before retrying"
+// @Failure 504 {object} ResponseError "Processing took longer than the request timeout"
+// @Failure 507 {object} ResponseError "Not enough scratch space"
+// @Router /process/{operation} [post]
+func (a *PdfHandler) StartProcess(c echo.Context) error {
+	name := c.Param("operation")
+	info, ok := pdf.DefaultRegistry.Lookup(pdf.KindProcess, name)
+	if !ok {
+		return c.JSON(http.StatusNotFound, ResponseError{Message: "Unknown operation"})
+	}
+
+	upload, err := a.openRequestUpload(c, a.Limits.Compress)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, ResponseError{Message: "Failed to get page count"})
+		return respondWithPdfError(c, err, "Failed to open the file")
 	}
+	defer upload.file.Close()
 
-	switch req.SplitMode {
-	case SPLIT_MODE_RANGE:
-		if req.Ranges == "" {
-			return c.JSON(http.StatusBadRequest, ResponseError{Message: "Invalid Range"})
-		}
+	op, err := pdf.DefaultRegistry.Prepare(a.Service, pdf.KindProcess, name, c.FormValue)
+	if err != nil {
+		return respondWithPdfError(c, err, "Invalid parameters")
+	}
+	op = a.audited(c, name, info.Params, op)
 
-		ranges, err := validateAndParseRanges(req.Ranges)
-		if err != nil {
-			return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
-		}
+	return a.process(c, upload, op, "Failed to process PDF")
+}
 
-		if ranges[len(ranges)-1] > pageCount {
-			return c.JSON(http.StatusBadRequest, ResponseError{Message: "Ranges exceed page count"})
-		}
+// @Summary Compare two PDF files
+// @Description Reports what changed from the original file to the revised one per page: pages added or removed, changed text, size or rotation, and the document information that differs. Pages are matched by their content, so inserted and removed pages don't show as changes of the pages after them. Both files share the upload limit of compress.
+// @Tags PDF
+// @Accept multipart/form-data
+// @Produce application/json, application/zip, application/gzip, multipart/mixed
+// @Param file formData file false "Original PDF file"
+// @Param source_url formData string false "URL to download the original from instead of uploading file"
+// @Param upload_id formData string false "Id of a complete resumable upload from /uploads to use as the original instead of uploading file"
+// @Param revised formData file true "Revised PDF file"
+// @Param annotate formData boolean false "Also return the revised file with the added and changed pages stamped, in a zip with the report as comparison.json"
+// @Param response formData string false "Set to 'link' to receive a JSON download link instead of the file"
+// @Param format formData string false "Format of the result with annotate: 'zip' (default), 'tar.gz' or 'multipart' for multipart/mixed. Without it the Accept header decides."
+// @Param job_id formData string false "Id of 16 to 64 letters, digits, '-' or '_' to follow the progress at /jobs/{id}/events"
+// @Param callback_url formData string false "Answer right away with 202 and post the result, signed with HMAC-SHA256, to this URL when done"
+// @Success 200 {object} domain.PdfComparison "Comparison, or a zip of it and the annotated revision"
+// @Header 200 {string} X-Job-ID "Id of the job"
+// @Success 202 {object} JobAccepted "Accepted, the result goes to callback_url"
+// @Failure 400 {object} ResponseError "Invalid parameters, missing file or source_url not allowed"
+// @Failure 404 {object} ResponseError "upload_id is unknown or expired"
+// @Failure 409 {object} ResponseError "job_id is already taken or upload_id is not complete"
+// @Failure 413 {object} ResponseError "A file exceeds the upload limit"
+// @Failure 415 {object} ResponseError "A file is not a PDF document"
+// @Failure 422 {object} ResponseError "A document crashed pdfcpu or ran out of its worker limits"
+// @Failure 500 {object} ResponseError "Failed to compare PDF"
+// @Failure 502 {object} ResponseError "source_url could not be fetched"
+// @Failure 503 {object} ResponseError "Too busy, retry after the Retry-After header"
+// @Header 503 {integer} Retry-After "Seconds to wait before retrying"
+// @Failure 504 {object} ResponseError "Processing took longer than the request timeout"
+// @Failure 507 {object} ResponseError "Not enough scratch space"
+// @Router /process/compare [post]
+func (a *PdfHandler) StartCompare(c echo.Context) error {
+	// the form carries two documents
+	maxBytes := a.Limits.Compress
+	if err := parseUpload(c, 2*maxBytes); err != nil {
+		return respondWithPdfError(c, err, "Failed to open the file")
+	}
+	if c.FormValue("batch") == "true" {
+		return respondWithPdfError(c, paramError("batch is not supported by compare"), "Invalid parameters")
+	}
+
+	upload, err := a.openRequestUpload(c, maxBytes)
+	if err != nil {
+		return respondWithPdfError(c, err, "Failed to open the file")
+	}
+	defer upload.file.Close()
 
-		src.Seek(0, io.SeekStart)
-		compressedFile, err := a.Service.SplitPdfByRanges(ctx, fileName, src, ranges)
+	revised, err := a.openCompanion(c, "revised", maxBytes)
+	if err != nil {
+		return respondWithPdfError(c, err, "Failed to open the revised file")
+	}
+	defer revised.releaseWith(c)
+
+	annotate := c.FormValue("annotate") == "true"
+	op := func(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+		defer revised.release()
+		return a.Service.ComparePdf(ctx, fileName, file, revised.name, revised.file, annotate)
+	}
+	op = a.audited(c, "compare", []pdf.Param{{Name: "annotate"}}, op)
+
+	return a.process(c, upload, op, "Failed to compare PDF")
+}
+
+// @Summary Insert pages of a PDF file into another
+// @Description Inserts the selected pages of the source file into the target file before or after one of its pages, or at its end. Both files share the upload limit of compress.
+// @Tags PDF
+// @Accept multipart/form-data
+// @Produce application/pdf
+// @Param target formData file false "PDF file to insert into"
+// @Param source_url formData string false "URL to download the target from instead of uploading it"
+// @Param upload_id formData string false "Id of a complete resumable upload from /uploads to use as the target instead of uploading it"
+// @Param source formData file true "PDF file to take the pages from"
+// @Param source_pages formData string false "Pages of the source to insert in order, e.g. 1-3,5, all when empty"
+// @Param position formData string false "Where to insert: 'before' or 'after' page, or 'end' (default)"
+// @Param page formData integer false "Page of the target the position refers to, required with before and after"
+// @Param response formData string false "Set to 'link' to receive a JSON download link instead of the file"
+// @Param job_id formData string false "Id of 16 to 64 letters, digits, '-' or '_' to follow the progress at /jobs/{id}/events"
+// @Param callback_url formData string false "Answer right away with 202 and post the result, signed with HMAC-SHA256, to this URL when done"
+// @Success 200 {file} file "PDF file with the pages inserted"
+// @Header 200 {string} X-Job-ID "Id of the job"
+// @Success 202 {object} JobAccepted "Accepted, the result goes to callback_url"
+// @Failure 400 {object} ResponseError "Invalid parameters, batch, missing file or source_url not allowed"
+// @Failure 404 {object} ResponseError "upload_id is unknown or expired"
+// @Failure 409 {object} ResponseError "job_id is already taken or upload_id is not complete"
+// @Failure 413 {object} ResponseError "A file exceeds the upload limit"
+// @Failure 415 {object} ResponseError "A file is not a PDF document"
+// @Failure 422 {object} ResponseError "A document crashed pdfcpu or ran out of its worker limits"
+// @Failure 500 {object} ResponseError "Failed to insert pages"
+// @Failure 502 {object} ResponseError "source_url could not be fetched"
+// @Failure 503 {object} ResponseError "Too busy, retry after the Retry-After header"
+// @Header 503 {integer} Retry-After "Seconds to wait before retrying"
+// @Failure 504 {object} ResponseError "Processing took longer than the request timeout"
+// @Failure 507 {object} ResponseError "Not enough scratch space"
+// @Router /process/insert [post]
+func (a *PdfHandler) StartInsert(c echo.Context) error {
+	// the form carries two documents
+	maxBytes := a.Limits.Compress
+	if err := parseUpload(c, 2*maxBytes); err != nil {
+		return respondWithPdfError(c, err, "Failed to open the file")
+	}
+	if err := rejectBatch(c); err != nil {
+		return respondWithPdfError(c, err, "Invalid parameters")
+	}
+
+	var sourcePages []int
+	if ranges := c.FormValue("source_pages"); ranges != "" {
+		pages, err := pdf.ParseRanges(ranges)
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, ResponseError{Message: "Failed to split PDF"})
+			return respondWithPdfError(c, paramError(err.Error()), "Invalid parameters")
 		}
+		sourcePages = pages
+	}
+	position, err := pdf.ParseInsertPosition(c.FormValue("position"), c.FormValue("page"))
+	if err != nil {
+		return respondWithPdfError(c, err, "Invalid parameters")
+	}
+
+	target, err := a.openFieldUpload(c, "target", maxBytes)
+	if err != nil {
+		return respondWithPdfError(c, err, "Failed to open the target file")
+	}
+	defer target.file.Close()
+
+	source, err := a.openCompanion(c, "source", maxBytes)
+	if err != nil {
+		return respondWithPdfError(c, err, "Failed to open the source file")
+	}
+	defer source.releaseWith(c)
+
+	op := func(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+		defer source.release()
+		return a.Service.InsertPdf(ctx, fileName, file, source.file, sourcePages, position)
+	}
+	op = a.audited(c, "insert", []pdf.Param{{Name: "source_pages"}, {Name: "position"}, {Name: "page"}}, op)
 
-		return a.respondWithPdfOrZip(c, compressedFile)
-	case SPLIT_MODE_REMOVE_PAGED:
-		if req.RemovePages == "" {
-			return c.JSON(http.StatusBadRequest, ResponseError{Message: "Invalid Range"})
+	return a.process(c, target, op, "Failed to insert pages")
+}
+
+// maxCollateFiles caps the documents of a collation, which share its upload limit
+const maxCollateFiles = 16
+
+// @Summary Collate pages of several PDF files
+// @Description Assembles one document of pages of several uploads, each named by its form field, in the order of the selector. With preset=interleave the uploads odd and even hold the fronts and the backs of sheets scanned one side at a time, the backs last to first, and are joined into a duplex document. Up to 16 files share 16 times the upload limit of compress.
+// @Tags PDF
+// @Accept multipart/form-data
+// @Produce application/pdf
+// @Param selector formData string false "Pages to take, e.g. a:1-3,b:1,a:4-,c:even for uploads a, b and c. A name alone takes every page. Required without preset."
+// @Param preset formData string false "Set to 'interleave' to join the uploads odd and even instead of using a selector"
+// @Param odd formData file false "Fronts of the sheets with preset=interleave"
+// @Param even formData file false "Backs of the sheets, last to first, with preset=interleave"
+// @Param source_url formData string false "URL to download the document selected first from instead of uploading it"
+// @Param upload_id formData string false "Id of a complete resumable upload from /uploads to use as the document selected first instead of uploading it"
+// @Param response formData string false "Set to 'link' to receive a JSON download link instead of the file"
+// @Param job_id formData string false "Id of 16 to 64 letters, digits, '-' or '_' to follow the progress at /jobs/{id}/events"
+// @Param callback_url formData string false "Answer right away with 202 and post the result, signed with HMAC-SHA256, to this URL when done"
+// @Success 200 {file} file "Collated PDF file"
+// @Header 200 {string} X-Job-ID "Id of the job"
+// @Success 202 {object} JobAccepted "Accepted, the result goes to callback_url"
+// @Failure 400 {object} ResponseError "Invalid parameters, batch, a selected file is missing or source_url not allowed"
+// @Failure 404 {object} ResponseError "upload_id is unknown or expired"
+// @Failure 409 {object} ResponseError "job_id is already taken or upload_id is not complete"
+// @Failure 413 {object} ResponseError "A file exceeds the upload limit"
+// @Failure 415 {object} ResponseError "A file is not a PDF document"
+// @Failure 422 {object} ResponseError "A document crashed pdfcpu or ran out of its worker limits"
+// @Failure 500 {object} ResponseError "Failed to collate PDF"
+// @Failure 502 {object} ResponseError "source_url could not be fetched"
+// @Failure 503 {object} ResponseError "Too busy, retry after the Retry-After header"
+// @Header 503 {integer} Retry-After "Seconds to wait before retrying"
+// @Failure 504 {object} ResponseError "Processing took longer than the request timeout"
+// @Failure 507 {object} ResponseError "Not enough scratch space"
+// @Router /process/collate [post]
+func (a *PdfHandler) StartCollate(c echo.Context) error {
+	maxBytes := a.Limits.Compress
+	if err := parseUpload(c, maxCollateFiles*maxBytes); err != nil {
+		return respondWithPdfError(c, err, "Failed to open the file")
+	}
+	if err := rejectBatch(c); err != nil {
+		return respondWithPdfError(c, err, "Invalid parameters")
+	}
+
+	var names []string
+	var selections []domain.PageSelection
+	switch preset, selector := c.FormValue("preset"), c.FormValue("selector"); {
+	case preset == "interleave" && selector == "":
+		names = []string{"odd", "even"}
+	case preset != "":
+		return respondWithPdfError(c, paramError("Invalid preset, use interleave without selector"), "Invalid parameters")
+	case selector == "":
+		return respondWithPdfError(c, paramError("selector is required"), "Invalid parameters")
+	default:
+		var err error
+		if selections, err = pdf.ParseSelector(selector); err != nil {
+			return respondWithPdfError(c, paramError(err.Error()), "Invalid parameters")
+		}
+		for _, selection := range selections {
+			if !slices.Contains(names, selection.Document) {
+				names = append(names, selection.Document)
+			}
+		}
+		if len(names) > maxCollateFiles {
+			return respondWithPdfError(c, paramError(fmt.Sprintf("Select at most %d files", maxCollateFiles)), "Invalid parameters")
 		}
+	}
+
+	// the document selected first is the one of the request, source_url or upload_id
+	// may stand in for its upload. The others go with it.
+	first, err := a.openFieldUpload(c, names[0], maxBytes)
+	if err != nil {
+		return respondWithPdfError(c, err, fmt.Sprintf("Failed to open the file %s", names[0]))
+	}
+	defer first.file.Close()
 
-		ranges, err := validateAndParseRanges(req.RemovePages)
+	others := make([]*companion, 0, len(names)-1)
+	defer func() {
+		for _, other := range others {
+			other.releaseWith(c)
+		}
+	}()
+	for _, field := range names[1:] {
+		other, err := a.openCompanion(c, field, maxBytes)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, ResponseError{Message: err.Error()})
+			return respondWithPdfError(c, err, fmt.Sprintf("Failed to open the file %s", field))
 		}
+		others = append(others, other)
+	}
 
-		if ranges[len(ranges)-1] > pageCount {
-			return c.JSON(http.StatusBadRequest, ResponseError{Message: "Ranges exceed page count"})
+	outputName := "collated_" + first.name
+	op := func(ctx context.Context, _ string, file io.ReadSeeker) (domain.PdfFile, error) {
+		defer func() {
+			for _, other := range others {
+				other.release()
+			}
+		}()
+		if selections == nil {
+			return a.Service.InterleavePdf(ctx, outputName, file, others[0].file)
 		}
-
-		src.Seek(0, io.SeekStart)
-		compressedFile, err := a.Service.RemovePagesPdf(ctx, fileName, src, ranges, pageCount)
-		if err != nil {
-			return c.JSON(http.StatusInternalServerError, ResponseError{Message: "Failed to split PDF"})
+		documents := map[string]io.ReadSeeker{names[0]: file}
+		for i, other := range others {
+			documents[names[i+1]] = other.file
 		}
+		return a.Service.CollatePdf(ctx, outputName, documents, selections)
+	}
+	op = a.audited(c, "collate", []pdf.Param{{Name: "selector"}, {Name: "preset"}}, op)
 
-		return a.respondWithPdfOrZip(c, compressedFile)
+	return a.process(c, first, op, "Failed to collate PDF")
+}
 
-	case SPLIT_MODE_FIXED_RANGE:
-		if req.FixedRange <= 0 {
-			return c.JSON(http.StatusBadRequest, ResponseError{Message: "Fixed range must be greater than 0"})
-		}
+// process runs op on the uploaded document, or on every document of the zip in batch
+// mode, as a job others can follow until the result was sent. With a callback_url
+// the request is answered right away and the result is delivered there.
+func (a *PdfHandler) process(c echo.Context, upload *upload, op domain.PdfProcessor, message string) error {
+	// checked up front, the result would be thrown away after all the work
+	if c.FormValue("response") == RESPONSE_LINK && a.Links == nil {
+		return respondWithPdfError(c, paramError("Download links are not enabled"), message)
+	}
+	if callbackURL := c.FormValue("callback_url"); callbackURL != "" {
+		return a.processAsync(c, upload, op, message, callbackURL)
+	}
 
-		fixedRange := generateFixedRange(pageCount, req.FixedRange)
+	format, err := responseFormat(c)
+	if err != nil {
+		return respondWithPdfError(c, err, message)
+	}
 
-		src.Seek(0, io.SeekStart)
-		compressedFile, err := a.Service.SplitAndZipPdfByFixedRange(ctx, fileName, src, fixedRange)
-		if err != nil {
-			return c.JSON(http.StatusInternalServerError, ResponseError{Message: "Failed to split PDF by fixed range"})
-		}
+	ctx, _, finish, err := a.startJob(c)
+	if err != nil {
+		return respondWithPdfError(c, err, message)
+	}
 
-		return a.respondWithPdfOrZip(c, compressedFile)
+	result, err := a.runOperation(ctx, upload, op)
+	if err != nil {
+		finish(err, message)
+		return respondWithPdfError(c, err, message)
+	}
 
-	default:
-		return c.JSON(http.StatusBadRequest, ResponseError{Message: "Invalid Split Mode"})
+	err = a.respondWithPdfOrZip(c, result, format)
+	finish(err, message)
+	return err
+}
+
+func (a *PdfHandler) runOperation(ctx context.Context, upload *upload, op domain.PdfProcessor) (domain.PdfFile, error) {
+	if !upload.batch {
+		return op(ctx, upload.name, upload.file)
+	}
+
+	archive, err := zip.NewReader(upload.file, upload.size)
+	if err != nil {
+		return domain.PdfFile{}, domain.ErrUnsupportedMediaType
+	}
+	return a.Service.BatchPdf(ctx, upload.name, archive, op)
+}
+
+// respondWithPdfOrZip streams the result, a zip in the negotiated format, or stores
+// it and answers with a download link when the client asked for response = link,
+// which process only lets through with a.Links set. Stored results stay as they are.
+func (a *PdfHandler) respondWithPdfOrZip(c echo.Context, compressedFile domain.PdfFile, format string) error {
+	if compressedFile.CacheStatus != "" {
+		c.Response().Header().Set("X-Cache", compressedFile.CacheStatus)
+	}
+	if c.FormValue("response") != RESPONSE_LINK {
+		if isZipFile(compressedFile.Name) && format != FORMAT_ZIP {
+			return a.respondWithArchive(c, compressedFile, format)
+		}
+		return respondWithFile(c, compressedFile)
 	}
+	defer compressedFile.Content.Close()
+
+	link, err := a.Links.Publish(c.Request().Context(), compressedFile)
+	if err != nil {
+		return respondWithPdfError(c, err, "Failed to store the file")
+	}
+
+	link.URL = downloadURL(c, link.Token)
+	return c.JSON(http.StatusOK, link)
 }
 
-func (a *PdfHandler) respondWithPdfOrZip(c echo.Context, compressedFile domain.PdfFile) error {
+func respondWithFile(c echo.Context, file domain.PdfFile) error {
+	defer file.Content.Close()
+
 	contentType := "application/pdf"
-	if isZipFile(compressedFile.Name) {
+	switch {
+	case isZipFile(file.Name):
 		contentType = "application/zip"
+	case strings.HasSuffix(file.Name, ".json"):
+		contentType = echo.MIMEApplicationJSON
 	}
 
 	c.Response().Header().Set(echo.HeaderContentType, contentType)
-	c.Response().Header().Set(echo.HeaderContentDisposition, "attachment; filename="+compressedFile.Name)
-	reader := bytes.NewReader(compressedFile.Content)
-	return c.Stream(http.StatusOK, contentType, reader)
-}
+	c.Response().Header().Set(echo.HeaderContentDisposition, attachment(file.Name))
+	if file.Size >= 0 {
+		c.Response().Header().Set(echo.HeaderContentLength, strconv.FormatInt(file.Size, 10))
+	}
 
-func isZipFile(fileName string) bool {
-	return strings.HasSuffix(fileName, ".zip")
+	return c.Stream(http.StatusOK, contentType, file.Content)
 }
 
-func generateFixedRange(totalPages int, fixedRange int) [][]int {
-	var result [][]int
-	for i := 1; i <= totalPages; i += fixedRange {
-		end := i + fixedRange - 1
-		if end > totalPages {
-			end = totalPages
+// attachment builds a Content-Disposition for fileName. Clients that don't read the
+// RFC 5987 filename* get an ASCII approximation in filename.
+func attachment(fileName string) string {
+	fallback := strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e || r == '"' || r == '\\' {
+			return '_'
 		}
-		var chunk []int
-		for j := i; j <= end; j++ {
-			chunk = append(chunk, j)
-		}
-		result = append(result, chunk)
-	}
+		return r
+	}, fileName)
 
-	return result
+	disposition := `attachment; filename="` + fallback + `"`
+	if fallback != fileName {
+		disposition += "; filename*=UTF-8''" + rfc5987Escape(fileName)
+	}
+	return disposition
 }
 
-func validateAndParseRanges(inputRange string) ([]int, error) {
-	var result []int
-
-	parts := strings.Split(inputRange, ",")
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-
-		if strings.Contains(part, "-") {
-			rangeParts := strings.Split(part, "-")
-			if len(rangeParts) != 2 {
-				return nil, fmt.Errorf("invalid range format: %s", part)
-			}
+// rfc5987Escape percent-encodes everything but the attr-char of RFC 5987
+func rfc5987Escape(value string) string {
+	const attrChars = "!#$&+-.^_`|~"
+
+	var escaped strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9', strings.IndexByte(attrChars, b) >= 0:
+			escaped.WriteByte(b)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+	return escaped.String()
+}
 
-			start, err := strconv.Atoi(strings.TrimSpace(rangeParts[0]))
-			if err != nil {
-				return nil, fmt.Errorf("invalid number in range start: %s", rangeParts[0])
-			}
+// paramError is a request parameter that doesn't fit, reported to the client as is.
+// It is the error of the operations in the pdf registry too.
+type paramError = pdf.ParamError
+
+// pdfErrorStatus lists the service errors a client can act on, their messages are in
+// pdf.PublicErrors. Anything else is logged and reported as a 500 with a generic message.
+var pdfErrorStatus = []struct {
+	err    error
+	status int
+}{
+	{domain.ErrStorageFull, http.StatusInsufficientStorage},
+	{domain.ErrMissingFile, http.StatusBadRequest},
+	{domain.ErrUnsupportedMediaType, http.StatusUnsupportedMediaType},
+	{domain.ErrFileTooLarge, http.StatusRequestEntityTooLarge},
+	{domain.ErrTooManyFiles, http.StatusRequestEntityTooLarge},
+	{domain.ErrOverloaded, http.StatusServiceUnavailable},
+	{domain.ErrForbiddenSource, http.StatusBadRequest},
+	{domain.ErrSourceUnavailable, http.StatusBadGateway},
+	{domain.ErrConflict, http.StatusConflict},
+	{domain.ErrNotFound, http.StatusNotFound},
+	{domain.ErrUploadIncomplete, http.StatusConflict},
+	{domain.ErrUnprocessable, http.StatusUnprocessableEntity},
+	{context.DeadlineExceeded, http.StatusGatewayTimeout},
+	{context.Canceled, StatusClientClosedRequest},
+}
 
-			end, err := strconv.Atoi(strings.TrimSpace(rangeParts[1]))
-			if err != nil {
-				return nil, fmt.Errorf("invalid number in range end: %s", rangeParts[1])
-			}
+func respondWithPdfError(c echo.Context, err error, message string) error {
+	var overloaded *domain.OverloadedError
+	if errors.As(err, &overloaded) {
+		seconds := int64(math.Ceil(overloaded.RetryAfter.Seconds()))
+		c.Response().Header().Set("Retry-After", strconv.FormatInt(max(seconds, 1), 10))
+	}
 
-			if start > end {
-				return nil, fmt.Errorf("range start cannot be greater than range end")
-			}
+	status := pdfErrorStatusOf(err)
+	if status == http.StatusInternalServerError {
+		logrus.Error(err)
+	}
+	return c.JSON(status, ResponseError{Message: pdfErrorMessage(err, message)})
+}
 
-			for i := start; i <= end; i++ {
-				result = append(result, i)
-			}
-		} else {
-			num, err := strconv.Atoi(part)
-			if err != nil {
-				return nil, fmt.Errorf("invalid number: %s", part)
-			}
-			result = append(result, num)
+// pdfErrorStatusOf picks the status of err from pdfErrorStatus
+func pdfErrorStatusOf(err error) int {
+	var param paramError
+	if errors.As(err, &param) {
+		return http.StatusBadRequest
+	}
+	for _, known := range pdfErrorStatus {
+		if errors.Is(err, known.err) {
+			return known.status
 		}
 	}
+	return http.StatusInternalServerError
+}
 
-	return result, nil
+// pdfErrorMessage is what the client learns about err, message stands in for
+// anything unexpected
+func pdfErrorMessage(err error, message string) string {
+	return pdf.PublicMessage(err, message)
+}
+
+func isZipFile(fileName string) bool {
+	return strings.HasSuffix(fileName, ".zip")
 }
@@ -2,17 +2,21 @@ package rest_test
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/bxcodec/go-clean-arch/domain"
 	"github.com/bxcodec/go-clean-arch/internal/rest"
 	"github.com/bxcodec/go-clean-arch/internal/rest/mocks"
+	"github.com/bxcodec/go-clean-arch/pdf"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -22,7 +26,7 @@ import (
 func TestStartCompress(t *testing.T) {
 	mockPdfSvc := new(mocks.PdfService)
 
-	createMultipartForm := func(filePath string) (*bytes.Buffer, string, error) {
+	createMultipartForm := func(filePath string, fields map[string]string) (*bytes.Buffer, string, error) {
 		file, err := os.Open(filePath)
 		if err != nil {
 			return nil, "", fmt.Errorf("failed to open test file: %v", err)
@@ -41,20 +45,25 @@ func TestStartCompress(t *testing.T) {
 			return nil, "", fmt.Errorf("failed to copy file to multipart form: %v", err)
 		}
 
+		for name, value := range fields {
+			writer.WriteField(name, value)
+		}
+
 		writer.Close()
 		return &body, writer.FormDataContentType(), nil
 	}
 
 	t.Run("when start compress success should return status 200", func(t *testing.T) {
 		testFile := "../resource/test.pdf"
-		body, contentType, err := createMultipartForm(testFile)
+		body, contentType, err := createMultipartForm(testFile, nil)
 		if err != nil {
 			t.Fatalf("Error creating multipart form: %v", err)
 		}
 
 		mockPdfSvc.On("CompressPdf", mock.Anything, mock.Anything, mock.Anything).Return(domain.PdfFile{
 			Name:    "compress_test.pdf",
-			Content: []byte{1},
+			Content: io.NopCloser(bytes.NewReader([]byte{1})),
+			Size:    1,
 		}, nil).Once()
 
 		e := echo.New()
@@ -72,12 +81,73 @@ func TestStartCompress(t *testing.T) {
 		assert.Equal(t, http.StatusOK, rec.Code)
 		assert.Contains(t, rec.Header().Get(echo.HeaderContentType), "pdf")
 		assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "compress_test.pdf")
+		assert.Equal(t, "1", rec.Header().Get(echo.HeaderContentLength))
+		assert.Equal(t, []byte{1}, rec.Body.Bytes())
+
+	})
+
+	t.Run("when result comes from the cache should set X-Cache", func(t *testing.T) {
+		testFile := "../resource/test.pdf"
+		body, contentType, err := createMultipartForm(testFile, nil)
+		if err != nil {
+			t.Fatalf("Error creating multipart form: %v", err)
+		}
+
+		mockPdfSvc.On("CompressPdf", mock.Anything, mock.Anything, mock.Anything).Return(domain.PdfFile{
+			Name:        "compress_test.pdf",
+			Content:     io.NopCloser(bytes.NewReader([]byte{1})),
+			Size:        1,
+			CacheStatus: domain.CacheHit,
+		}, nil).Once()
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/process/compress", body)
+		req.Header.Set("Content-Type", contentType)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		handler := rest.PdfHandler{
+			Service: mockPdfSvc,
+		}
+
+		err = handler.StartCompress(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "HIT", rec.Header().Get("X-Cache"))
+	})
+
+	t.Run("when file name isn't plain ASCII should add an encoded filename*", func(t *testing.T) {
+		testFile := "../resource/test.pdf"
+		body, contentType, err := createMultipartForm(testFile, nil)
+		if err != nil {
+			t.Fatalf("Error creating multipart form: %v", err)
+		}
 
+		mockPdfSvc.On("CompressPdf", mock.Anything, mock.Anything, mock.Anything).Return(domain.PdfFile{
+			Name:    "compress_résumé.pdf",
+			Content: io.NopCloser(bytes.NewReader([]byte{1})),
+			Size:    1,
+		}, nil).Once()
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/process/compress", body)
+		req.Header.Set("Content-Type", contentType)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		handler := rest.PdfHandler{
+			Service: mockPdfSvc,
+		}
+
+		err = handler.StartCompress(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, `attachment; filename="compress_r_sum_.pdf"; filename*=UTF-8''compress_r%C3%A9sum%C3%A9.pdf`,
+			rec.Header().Get(echo.HeaderContentDisposition))
 	})
 
 	t.Run("when compress fails should return status 500", func(t *testing.T) {
 		testFile := "../resource/test.pdf"
-		body, contentType, err := createMultipartForm(testFile)
+		body, contentType, err := createMultipartForm(testFile, nil)
 		if err != nil {
 			t.Fatalf("Error creating multipart form: %v", err)
 		}
@@ -98,4 +168,456 @@ func TestStartCompress(t *testing.T) {
 
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 	})
+	t.Run("when service is overloaded should return status 503 with Retry-After", func(t *testing.T) {
+		testFile := "../resource/test.pdf"
+		body, contentType, err := createMultipartForm(testFile, nil)
+		if err != nil {
+			t.Fatalf("Error creating multipart form: %v", err)
+		}
+
+		mockPdfSvc.On("CompressPdf", mock.Anything, mock.Anything, mock.Anything).Return(domain.PdfFile{}, &domain.OverloadedError{RetryAfter: 1500 * time.Millisecond}).Once()
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/process/compress", body)
+		req.Header.Set("Content-Type", contentType)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		handler := rest.PdfHandler{
+			Service: mockPdfSvc,
+		}
+
+		err = handler.StartCompress(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+	})
+
+	t.Run("when processing outlives the request should return status 504 or 499", func(t *testing.T) {
+		for err, status := range map[error]int{
+			context.DeadlineExceeded: http.StatusGatewayTimeout,
+			context.Canceled:         rest.StatusClientClosedRequest,
+		} {
+			body, contentType, createErr := createMultipartForm("../resource/test.pdf", nil)
+			require.NoError(t, createErr)
+
+			mockPdfSvc.On("CompressPdf", mock.Anything, mock.Anything, mock.Anything).Return(domain.PdfFile{}, fmt.Errorf("failed to optimize PDF: %w", err)).Once()
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/process/compress", body)
+			req.Header.Set("Content-Type", contentType)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			handler := rest.PdfHandler{
+				Service: mockPdfSvc,
+			}
+
+			require.NoError(t, handler.StartCompress(c))
+			assert.Equal(t, status, rec.Code)
+		}
+	})
+
+	t.Run("when the document crashed its worker should return status 422", func(t *testing.T) {
+		body, contentType, err := createMultipartForm("../resource/test.pdf", nil)
+		require.NoError(t, err)
+
+		mockPdfSvc.On("CompressPdf", mock.Anything, mock.Anything, mock.Anything).Return(domain.PdfFile{}, fmt.Errorf("failed to optimize PDF: %w: the worker exited", domain.ErrUnprocessable)).Once()
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/process/compress", body)
+		req.Header.Set("Content-Type", contentType)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		handler := rest.PdfHandler{
+			Service: mockPdfSvc,
+		}
+
+		require.NoError(t, handler.StartCompress(c))
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Contains(t, rec.Body.String(), domain.ErrUnprocessable.Error())
+	})
+
+	t.Run("when response is link should store the file and return a download link", func(t *testing.T) {
+		testFile := "../resource/test.pdf"
+		body, contentType, err := createMultipartForm(testFile, map[string]string{"response": "link"})
+		if err != nil {
+			t.Fatalf("Error creating multipart form: %v", err)
+		}
+
+		mockPdfSvc.On("CompressPdf", mock.Anything, mock.Anything, mock.Anything).Return(domain.PdfFile{
+			Name:    "compress_test.pdf",
+			Content: io.NopCloser(bytes.NewReader([]byte{1})),
+			Size:    1,
+		}, nil).Once()
+		mockLinks := new(mocks.DownloadService)
+		mockLinks.On("Publish", mock.Anything, mock.Anything).Return(domain.DownloadLink{
+			Token: "token",
+			Name:  "compress_test.pdf",
+			Size:  1,
+		}, nil).Once()
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/process/compress", body)
+		req.Header.Set("Content-Type", contentType)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		handler := rest.PdfHandler{
+			Service: mockPdfSvc,
+			Links:   mockLinks,
+		}
+
+		err = handler.StartCompress(c)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentType), "json")
+		assert.Contains(t, rec.Body.String(), `"url":"http://example.com/downloads/token"`)
+		mockLinks.AssertExpectations(t)
+	})
+
+	t.Run("when response is link without download links should return status 400 before compressing", func(t *testing.T) {
+		body, contentType, err := createMultipartForm("../resource/test.pdf", map[string]string{"response": "link"})
+		require.NoError(t, err)
+		mockPdfSvc := new(mocks.PdfService)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/process/compress", body)
+		req.Header.Set("Content-Type", contentType)
+		rec := httptest.NewRecorder()
+		handler := rest.PdfHandler{Service: mockPdfSvc}
+
+		require.NoError(t, handler.StartCompress(e.NewContext(req, rec)))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Download links are not enabled")
+		mockPdfSvc.AssertNotCalled(t, "CompressPdf", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// stampParams are the parameters of the stamp processor the tests register
+type stampParams struct {
+	Text string `param:"text" doc:"Text of the stamp"`
+}
+
+func init() {
+	pdf.Register(pdf.DefaultRegistry, pdf.Operation[stampParams]{
+		Kind:        pdf.KindProcess,
+		Name:        "stamp",
+		Description: "stamp every page",
+		Validate: func(p *stampParams) error {
+			if p.Text == "" {
+				return pdf.ParamError("text is required")
+			}
+			return nil
+		},
+		Run: func(ctx context.Context, exec pdf.Executor, p stampParams, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+			return domain.PdfFile{
+				Name:    "stamped_" + fileName,
+				Content: io.NopCloser(strings.NewReader(p.Text)),
+				Size:    int64(len(p.Text)),
+			}, nil
+		},
+	})
+}
+
+func TestStartProcess(t *testing.T) {
+	serve := func(operation string, fields map[string]string) *httptest.ResponseRecorder {
+		pdfContent, _ := os.ReadFile("../resource/test.pdf")
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, _ := writer.CreateFormFile("file", "test.pdf")
+		part.Write(pdfContent)
+		for name, value := range fields {
+			writer.WriteField(name, value)
+		}
+		writer.Close()
+
+		e := echo.New()
+		rest.NewPdfHandler(e, new(mocks.PdfService))
+		req := httptest.NewRequest(http.MethodPost, "/process/"+operation, &body)
+		req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("when the operation is registered should run it with the form parameters", func(t *testing.T) {
+		rec := serve("stamp", map[string]string{"text": "draft"})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "draft", rec.Body.String())
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "stamped_test.pdf")
+	})
+
+	t.Run("when parameters don't fit should return status 400", func(t *testing.T) {
+		rec := serve("stamp", nil)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "text is required")
+	})
+
+	t.Run("when the operation is unknown should return status 404", func(t *testing.T) {
+		rec := serve("shred", nil)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestStartCompare(t *testing.T) {
+	serve := func(svc *mocks.PdfService, files []string, fields map[string]string) *httptest.ResponseRecorder {
+		pdfContent, _ := os.ReadFile("../resource/test.pdf")
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		for _, field := range files {
+			part, _ := writer.CreateFormFile(field, field+".pdf")
+			part.Write(pdfContent)
+		}
+		for name, value := range fields {
+			writer.WriteField(name, value)
+		}
+		writer.Close()
+
+		e := echo.New()
+		rest.NewPdfHandler(e, svc)
+		req := httptest.NewRequest(http.MethodPost, "/process/compare", &body)
+		req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("when both files are sent should return the comparison as JSON", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("ComparePdf", mock.Anything, "file.pdf", mock.Anything, "revised.pdf", mock.Anything, false).Return(domain.PdfFile{
+			Name:    "compare_file.json",
+			Content: io.NopCloser(strings.NewReader(`{"identical":true}`)),
+			Size:    18,
+		}, nil).Once()
+
+		rec := serve(mockPdfSvc, []string{"file", "revised"}, nil)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, echo.MIMEApplicationJSON, rec.Header().Get(echo.HeaderContentType))
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "compare_file.json")
+		assert.JSONEq(t, `{"identical":true}`, rec.Body.String())
+		mockPdfSvc.AssertExpectations(t)
+	})
+
+	t.Run("when annotate is set should return the zip", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("ComparePdf", mock.Anything, "file.pdf", mock.Anything, "revised.pdf", mock.Anything, true).Return(domain.PdfFile{
+			Name:    "compare_file.zip",
+			Content: io.NopCloser(strings.NewReader("PK")),
+			Size:    -1,
+		}, nil).Once()
+
+		rec := serve(mockPdfSvc, []string{"file", "revised"}, map[string]string{"annotate": "true"})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/zip", rec.Header().Get(echo.HeaderContentType))
+		mockPdfSvc.AssertExpectations(t)
+	})
+
+	t.Run("when the revised file is missing should return status 400", func(t *testing.T) {
+		rec := serve(new(mocks.PdfService), []string{"file"}, nil)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("when batch is set should return status 400", func(t *testing.T) {
+		rec := serve(new(mocks.PdfService), []string{"file", "revised"}, map[string]string{"batch": "true"})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "batch")
+	})
+}
+
+func TestStartInsert(t *testing.T) {
+	serve := func(svc *mocks.PdfService, files []string, fields map[string]string, opts ...rest.PdfHandlerOption) *httptest.ResponseRecorder {
+		pdfContent, _ := os.ReadFile("../resource/test.pdf")
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		for _, field := range files {
+			part, _ := writer.CreateFormFile(field, field+".pdf")
+			part.Write(pdfContent)
+		}
+		for name, value := range fields {
+			writer.WriteField(name, value)
+		}
+		writer.Close()
+
+		e := echo.New()
+		rest.NewPdfHandler(e, svc, opts...)
+		req := httptest.NewRequest(http.MethodPost, "/process/insert", &body)
+		req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("when both files and a position are sent should return the PDF", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("InsertPdf", mock.Anything, "target.pdf", mock.Anything, mock.Anything, []int{2, 3},
+			domain.InsertPosition{Where: domain.InsertAfter, Page: 1}).Return(domain.PdfFile{
+			Name:    "inserted_target.pdf",
+			Content: io.NopCloser(strings.NewReader("%PDF")),
+			Size:    4,
+		}, nil).Once()
+
+		rec := serve(mockPdfSvc, []string{"target", "source"}, map[string]string{"source_pages": "2-3", "position": "after", "page": "1"})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "inserted_target.pdf")
+		mockPdfSvc.AssertExpectations(t)
+	})
+
+	t.Run("when no position is sent should insert every source page at the end", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("InsertPdf", mock.Anything, "target.pdf", mock.Anything, mock.Anything, []int(nil),
+			domain.InsertPosition{Where: domain.InsertEnd}).Return(domain.PdfFile{
+			Name:    "inserted_target.pdf",
+			Content: io.NopCloser(strings.NewReader("%PDF")),
+			Size:    4,
+		}, nil).Once()
+
+		rec := serve(mockPdfSvc, []string{"target", "source"}, nil)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockPdfSvc.AssertExpectations(t)
+	})
+
+	t.Run("when before is sent without a page should return status 400", func(t *testing.T) {
+		rec := serve(new(mocks.PdfService), []string{"target", "source"}, map[string]string{"position": "before"})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("when the source file is missing should return status 400", func(t *testing.T) {
+		rec := serve(new(mocks.PdfService), []string{"target"}, nil)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("when upload_id is sent should insert into the stored document", func(t *testing.T) {
+		stored, err := os.Open("../resource/test.pdf")
+		require.NoError(t, err)
+		mockUploads := new(mocks.ResumableUploadService)
+		mockUploads.On("Open", mock.Anything, "abc").Return(domain.SourceFile{
+			Name:        "contract.pdf",
+			ContentType: "application/pdf",
+			Content:     stored,
+		}, nil).Once()
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("InsertPdf", mock.Anything, "contract.pdf", mock.Anything, mock.Anything, []int(nil),
+			domain.InsertPosition{Where: domain.InsertEnd}).Return(domain.PdfFile{
+			Name:    "inserted_contract.pdf",
+			Content: io.NopCloser(strings.NewReader("%PDF")),
+			Size:    4,
+		}, nil).Once()
+
+		rec := serve(mockPdfSvc, []string{"source"}, map[string]string{"upload_id": "abc"}, rest.WithResumableUploads(mockUploads))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockUploads.AssertExpectations(t)
+		mockPdfSvc.AssertExpectations(t)
+	})
+
+	t.Run("when batch is sent should return status 400", func(t *testing.T) {
+		rec := serve(new(mocks.PdfService), []string{"target", "source"}, map[string]string{"batch": "true"})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "batch")
+	})
+}
+
+func TestStartCollate(t *testing.T) {
+	serve := func(svc *mocks.PdfService, files []string, fields map[string]string, opts ...rest.PdfHandlerOption) *httptest.ResponseRecorder {
+		pdfContent, _ := os.ReadFile("../resource/test.pdf")
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		for _, field := range files {
+			part, _ := writer.CreateFormFile(field, field+".pdf")
+			part.Write(pdfContent)
+		}
+		for name, value := range fields {
+			writer.WriteField(name, value)
+		}
+		writer.Close()
+
+		e := echo.New()
+		rest.NewPdfHandler(e, svc, opts...)
+		req := httptest.NewRequest(http.MethodPost, "/process/collate", &body)
+		req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	collated := domain.PdfFile{Name: "collated_b.pdf", Content: io.NopCloser(strings.NewReader("%PDF")), Size: 4}
+
+	t.Run("when a selector is sent should collate the selected uploads", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("CollatePdf", mock.Anything, "collated_b.pdf", mock.MatchedBy(func(documents map[string]io.ReadSeeker) bool {
+			return len(documents) == 2 && documents["a"] != nil && documents["b"] != nil
+		}), []domain.PageSelection{{Document: "b", First: 1, Last: 1}, {Document: "a", First: 2}}).Return(collated, nil).Once()
+
+		rec := serve(mockPdfSvc, []string{"a", "b", "c"}, map[string]string{"selector": "b:1,a:2-"})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentDisposition), "collated_b.pdf")
+		mockPdfSvc.AssertExpectations(t)
+	})
+
+	t.Run("when preset is interleave should join odd and even", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("InterleavePdf", mock.Anything, "collated_odd.pdf", mock.Anything, mock.Anything).Return(collated, nil).Once()
+
+		rec := serve(mockPdfSvc, []string{"odd", "even"}, map[string]string{"preset": "interleave"})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockPdfSvc.AssertExpectations(t)
+	})
+
+	t.Run("when a selected upload is missing should return status 400", func(t *testing.T) {
+		rec := serve(new(mocks.PdfService), []string{"a"}, map[string]string{"selector": "a,b"})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("when neither selector nor preset is sent should return status 400", func(t *testing.T) {
+		rec := serve(new(mocks.PdfService), []string{"a"}, nil)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "selector")
+	})
+
+	t.Run("when the selector is invalid should return status 400", func(t *testing.T) {
+		rec := serve(new(mocks.PdfService), []string{"a"}, map[string]string{"selector": "a:3-1"})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("when upload_id is sent should stand in for the upload selected first", func(t *testing.T) {
+		stored, err := os.Open("../resource/test.pdf")
+		require.NoError(t, err)
+		mockUploads := new(mocks.ResumableUploadService)
+		mockUploads.On("Open", mock.Anything, "abc").Return(domain.SourceFile{
+			Name:        "fronts.pdf",
+			ContentType: "application/pdf",
+			Content:     stored,
+		}, nil).Once()
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("InterleavePdf", mock.Anything, "collated_fronts.pdf", mock.Anything, mock.Anything).Return(collated, nil).Once()
+
+		rec := serve(mockPdfSvc, []string{"even"}, map[string]string{"preset": "interleave", "upload_id": "abc"}, rest.WithResumableUploads(mockUploads))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockUploads.AssertExpectations(t)
+		mockPdfSvc.AssertExpectations(t)
+	})
+
+	t.Run("when batch is sent should return status 400", func(t *testing.T) {
+		rec := serve(new(mocks.PdfService), []string{"odd", "even"}, map[string]string{"preset": "interleave", "batch": "true"})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "batch")
+	})
 }
@@ -0,0 +1,333 @@
+package rest
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// tusVersion is the version of the tus protocol the upload endpoints speak
+const tusVersion = "1.0.0"
+
+// StatusChecksumMismatch is the tus status of a chunk that doesn't match its checksum
+const StatusChecksumMismatch = 460
+
+// tus headers
+const (
+	headerTusResumable      = "Tus-Resumable"
+	headerTusVersion        = "Tus-Version"
+	headerTusExtension      = "Tus-Extension"
+	headerTusMaxSize        = "Tus-Max-Size"
+	headerTusChecksumAlgos  = "Tus-Checksum-Algorithm"
+	headerUploadLength      = "Upload-Length"
+	headerUploadOffset      = "Upload-Offset"
+	headerUploadMetadata    = "Upload-Metadata"
+	headerUploadExpires     = "Upload-Expires"
+	headerUploadChecksum    = "Upload-Checksum"
+	contentTypeOffsetStream = "application/offset+octet-stream"
+)
+
+// ResumableUploadService represent the usecases of resumable uploads
+//
+//go:generate mockery --name ResumableUploadService
+type ResumableUploadService interface {
+	Create(ctx context.Context, length int64, metadata map[string]string) (domain.ResumableUpload, error)
+	Get(ctx context.Context, id string) (domain.ResumableUpload, error)
+	Append(ctx context.Context, id string, offset int64, content io.Reader, checksum *domain.Checksum) (domain.ResumableUpload, error)
+	// Open returns a complete upload to process
+	Open(ctx context.Context, id string) (domain.SourceFile, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// TusHandler serves resumable uploads following the tus protocol, with the creation,
+// expiration, checksum and termination extensions
+type TusHandler struct {
+	Service ResumableUploadService
+	// MaxSize is announced to clients, zero means no limit
+	MaxSize int64
+	// ChecksumAlgorithms are announced to clients
+	ChecksumAlgorithms []string
+}
+
+// NewTusHandler will initialize the uploads/ resources endpoint
+func NewTusHandler(e *echo.Echo, svc ResumableUploadService, maxSize int64, checksumAlgorithms []string) {
+	handler := &TusHandler{
+		Service:            svc,
+		MaxSize:            maxSize,
+		ChecksumAlgorithms: checksumAlgorithms,
+	}
+	g := e.Group("/uploads", tusResumable)
+	g.OPTIONS("", handler.Options)
+	g.POST("", handler.Create)
+	g.HEAD("/:id", handler.Head)
+	g.PATCH("/:id", handler.Patch)
+	g.DELETE("/:id", handler.Delete)
+}
+
+// tusResumable rejects requests of another protocol version and tags every response
+// with the version spoken. OPTIONS is how clients find the version out.
+func tusResumable(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		header := c.Response().Header()
+		header.Set(headerTusResumable, tusVersion)
+		header.Set(echo.HeaderAccessControlExposeHeaders, strings.Join([]string{
+			headerTusResumable, headerTusVersion, headerTusExtension, headerTusMaxSize, headerTusChecksumAlgos,
+			headerUploadLength, headerUploadOffset, headerUploadMetadata, headerUploadExpires, echo.HeaderLocation,
+		}, ", "))
+
+		if c.Request().Method != http.MethodOptions && c.Request().Header.Get(headerTusResumable) != tusVersion {
+			header.Set(headerTusVersion, tusVersion)
+			return c.JSON(http.StatusPreconditionFailed, ResponseError{Message: "Unsupported Tus-Resumable version"})
+		}
+		return next(c)
+	}
+}
+
+// @Summary Discover the tus protocol support
+// @Description Lists the tus version, extensions, size limit and checksum algorithms of the upload endpoints
+// @Tags Upload
+// @Success 204 "Supported"
+// @Header 204 {string} Tus-Version "Supported versions"
+// @Header 204 {string} Tus-Extension "Supported extensions"
+// @Header 204 {integer} Tus-Max-Size "Largest upload accepted"
+// @Header 204 {string} Tus-Checksum-Algorithm "Supported checksum algorithms"
+// @Router /uploads [options]
+func (a *TusHandler) Options(c echo.Context) error {
+	header := c.Response().Header()
+	header.Set(headerTusVersion, tusVersion)
+	header.Set(headerTusExtension, "creation,expiration,checksum,termination")
+	if a.MaxSize > 0 {
+		header.Set(headerTusMaxSize, strconv.FormatInt(a.MaxSize, 10))
+	}
+	header.Set(headerTusChecksumAlgos, strings.Join(a.ChecksumAlgorithms, ","))
+	return c.NoContent(http.StatusNoContent)
+}
+
+// @Summary Create a resumable upload
+// @Description Registers an upload of Upload-Length bytes. The chunks go to the returned Location with PATCH, and once complete its id may be sent as upload_id to the /process endpoints.
+// @Tags Upload
+// @Param Tus-Resumable header string true "1.0.0"
+// @Param Upload-Length header integer true "Size of the document in bytes"
+// @Param Upload-Metadata header string false "Comma separated keys with base64 values, e.g. filename and filetype"
+// @Success 201 "Created"
+// @Header 201 {string} Location "URL of the upload"
+// @Header 201 {string} Upload-Expires "When the upload is removed unless written to"
+// @Failure 400 {object} ResponseError "Upload-Length or Upload-Metadata is invalid"
+// @Failure 412 {object} ResponseError "Unsupported Tus-Resumable version"
+// @Failure 413 {object} ResponseError "Upload-Length exceeds Tus-Max-Size"
+// @Router /uploads [post]
+func (a *TusHandler) Create(c echo.Context) error {
+	length, err := strconv.ParseInt(c.Request().Header.Get(headerUploadLength), 10, 64)
+	if err != nil || length < 0 {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: "Invalid Upload-Length"})
+	}
+	metadata, err := parseUploadMetadata(c.Request().Header.Get(headerUploadMetadata))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: "Invalid Upload-Metadata"})
+	}
+
+	upload, err := a.Service.Create(c.Request().Context(), length, metadata)
+	if err != nil {
+		return respondWithTusError(c, err, "Failed to create the upload")
+	}
+
+	setUploadExpires(c, upload)
+	c.Response().Header().Set(echo.HeaderLocation, c.Scheme()+"://"+c.Request().Host+"/uploads/"+upload.ID)
+	return c.NoContent(http.StatusCreated)
+}
+
+// @Summary Get the offset of a resumable upload
+// @Description Tells where to resume an upload
+// @Tags Upload
+// @Param Tus-Resumable header string true "1.0.0"
+// @Param id path string true "Upload id"
+// @Success 200 "Upload found"
+// @Header 200 {integer} Upload-Offset "Bytes received so far"
+// @Header 200 {integer} Upload-Length "Size of the document in bytes"
+// @Failure 404 "Unknown or expired upload"
+// @Router /uploads/{id} [head]
+func (a *TusHandler) Head(c echo.Context) error {
+	upload, err := a.Service.Get(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		// HEAD answers carry no body
+		status := tusErrorStatusOf(err)
+		if status == http.StatusInternalServerError {
+			logrus.Error(err)
+		}
+		return c.NoContent(status)
+	}
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderCacheControl, "no-store")
+	header.Set(headerUploadOffset, strconv.FormatInt(upload.Offset, 10))
+	header.Set(headerUploadLength, strconv.FormatInt(upload.Length, 10))
+	if len(upload.Metadata) > 0 {
+		header.Set(headerUploadMetadata, formatUploadMetadata(upload.Metadata))
+	}
+	setUploadExpires(c, upload)
+	return c.NoContent(http.StatusOK)
+}
+
+// @Summary Send a chunk of a resumable upload
+// @Description Appends the body at Upload-Offset, which has to be the current offset. With Upload-Checksum the chunk is dropped unless it matches.
+// @Tags Upload
+// @Accept application/offset+octet-stream
+// @Param Tus-Resumable header string true "1.0.0"
+// @Param Upload-Offset header integer true "Offset the chunk starts at"
+// @Param Upload-Checksum header string false "Algorithm and base64 digest of the chunk, e.g. 'sha1 Kq5sNclPz7QV2+lfQIuc6R7oRu0='"
+// @Param id path string true "Upload id"
+// @Success 204 "Chunk stored"
+// @Header 204 {integer} Upload-Offset "Bytes received so far"
+// @Failure 400 {object} ResponseError "Invalid Upload-Offset or Upload-Checksum"
+// @Failure 404 {object} ResponseError "Unknown or expired upload"
+// @Failure 409 {object} ResponseError "Upload-Offset is not the offset of the upload"
+// @Failure 413 {object} ResponseError "Chunk runs past Upload-Length"
+// @Failure 415 {object} ResponseError "Content-Type is not application/offset+octet-stream"
+// @Failure 423 {object} ResponseError "Another request is writing to the upload"
+// @Failure 460 {object} ResponseError "Chunk does not match Upload-Checksum"
+// @Router /uploads/{id} [patch]
+func (a *TusHandler) Patch(c echo.Context) error {
+	req := c.Request()
+	if mediaType, _, _ := mime.ParseMediaType(req.Header.Get(echo.HeaderContentType)); mediaType != contentTypeOffsetStream {
+		return c.JSON(http.StatusUnsupportedMediaType, ResponseError{Message: "Content-Type must be " + contentTypeOffsetStream})
+	}
+	offset, err := strconv.ParseInt(req.Header.Get(headerUploadOffset), 10, 64)
+	if err != nil || offset < 0 {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: "Invalid Upload-Offset"})
+	}
+	checksum, err := parseUploadChecksum(req.Header.Get(headerUploadChecksum))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ResponseError{Message: "Invalid Upload-Checksum"})
+	}
+
+	upload, err := a.Service.Append(req.Context(), c.Param("id"), offset, req.Body, checksum)
+	if err != nil {
+		return respondWithTusError(c, err, "Failed to store the chunk")
+	}
+
+	c.Response().Header().Set(headerUploadOffset, strconv.FormatInt(upload.Offset, 10))
+	setUploadExpires(c, upload)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// @Summary Delete a resumable upload
+// @Description Removes an upload that is no longer needed, complete or not
+// @Tags Upload
+// @Param Tus-Resumable header string true "1.0.0"
+// @Param id path string true "Upload id"
+// @Success 204 "Deleted"
+// @Failure 404 {object} ResponseError "Unknown or expired upload"
+// @Failure 423 {object} ResponseError "Another request is writing to the upload"
+// @Router /uploads/{id} [delete]
+func (a *TusHandler) Delete(c echo.Context) error {
+	if err := a.Service.Delete(c.Request().Context(), c.Param("id")); err != nil {
+		return respondWithTusError(c, err, "Failed to delete the upload")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func setUploadExpires(c echo.Context, upload domain.ResumableUpload) {
+	if !upload.ExpiresAt.IsZero() {
+		c.Response().Header().Set(headerUploadExpires, upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	}
+}
+
+// parseUploadMetadata reads comma separated pairs of a key and a base64 value, the
+// value may be left out
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+	if strings.TrimSpace(header) == "" {
+		return metadata, nil
+	}
+
+	for _, pair := range strings.Split(header, ",") {
+		key, encoded, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			return nil, errors.New("empty metadata key")
+		}
+		value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, err
+		}
+		metadata[key] = string(value)
+	}
+	return metadata, nil
+}
+
+func formatUploadMetadata(metadata map[string]string) string {
+	pairs := make([]string, 0, len(metadata))
+	for key, value := range metadata {
+		pairs = append(pairs, key+" "+base64.StdEncoding.EncodeToString([]byte(value)))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// parseUploadChecksum reads an algorithm and a base64 digest, nil without a header
+func parseUploadChecksum(header string) (*domain.Checksum, error) {
+	if header == "" {
+		return nil, nil
+	}
+
+	algorithm, encoded, ok := strings.Cut(header, " ")
+	if !ok {
+		return nil, errors.New("missing checksum")
+	}
+	sum, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Checksum{Algorithm: algorithm, Sum: sum}, nil
+}
+
+// tusErrorStatus lists the upload errors a client can act on. Anything else is
+// logged and reported as a 500 with a generic message.
+var tusErrorStatus = []struct {
+	err    error
+	status int
+}{
+	{domain.ErrNotFound, http.StatusNotFound},
+	{domain.ErrBadParamInput, http.StatusBadRequest},
+	{domain.ErrOffsetMismatch, http.StatusConflict},
+	{domain.ErrChecksumMismatch, StatusChecksumMismatch},
+	{domain.ErrUploadLocked, http.StatusLocked},
+	{domain.ErrFileTooLarge, http.StatusRequestEntityTooLarge},
+	{domain.ErrStorageFull, http.StatusInsufficientStorage},
+	{context.DeadlineExceeded, http.StatusGatewayTimeout},
+	{context.Canceled, StatusClientClosedRequest},
+}
+
+func tusErrorStatusOf(err error) int {
+	for _, known := range tusErrorStatus {
+		if errors.Is(err, known.err) {
+			return known.status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+func respondWithTusError(c echo.Context, err error, message string) error {
+	status := tusErrorStatusOf(err)
+	if status == http.StatusInternalServerError {
+		logrus.Error(err)
+		return c.JSON(status, ResponseError{Message: message})
+	}
+	for _, known := range tusErrorStatus {
+		if errors.Is(err, known.err) {
+			message = known.err.Error()
+			break
+		}
+	}
+	return c.JSON(status, ResponseError{Message: message})
+}
@@ -0,0 +1,162 @@
+package rest_test
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rest"
+	"github.com/bxcodec/go-clean-arch/internal/rest/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTusHandler(t *testing.T) {
+	serve := func(svc *mocks.ResumableUploadService, method, path string, body string, headers map[string]string) *httptest.ResponseRecorder {
+		e := echo.New()
+		rest.NewTusHandler(e, svc, 100, []string{"md5", "sha1"})
+
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Tus-Resumable", "1.0.0")
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	expiresAt := time.Date(2026, 10, 20, 8, 0, 0, 0, time.UTC)
+
+	t.Run("when asked for options should list the supported extensions", func(t *testing.T) {
+		rec := serve(new(mocks.ResumableUploadService), http.MethodOptions, "/uploads", "", map[string]string{"Tus-Resumable": ""})
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "1.0.0", rec.Header().Get("Tus-Version"))
+		assert.Equal(t, "creation,expiration,checksum,termination", rec.Header().Get("Tus-Extension"))
+		assert.Equal(t, "100", rec.Header().Get("Tus-Max-Size"))
+		assert.Equal(t, "md5,sha1", rec.Header().Get("Tus-Checksum-Algorithm"))
+	})
+
+	t.Run("when Tus-Resumable is missing should return status 412", func(t *testing.T) {
+		rec := serve(new(mocks.ResumableUploadService), http.MethodPost, "/uploads", "", map[string]string{"Tus-Resumable": ""})
+
+		assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+		assert.Equal(t, "1.0.0", rec.Header().Get("Tus-Version"))
+	})
+
+	t.Run("when created should return the location of the upload", func(t *testing.T) {
+		svc := new(mocks.ResumableUploadService)
+		svc.On("Create", mock.Anything, int64(42), map[string]string{"filename": "scan.pdf", "is_confidential": ""}).
+			Return(domain.ResumableUpload{ID: "abc", Length: 42, ExpiresAt: expiresAt}, nil).Once()
+
+		rec := serve(svc, http.MethodPost, "/uploads", "", map[string]string{
+			"Upload-Length":   "42",
+			"Upload-Metadata": "filename " + base64.StdEncoding.EncodeToString([]byte("scan.pdf")) + ",is_confidential",
+		})
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "http://example.com/uploads/abc", rec.Header().Get(echo.HeaderLocation))
+		assert.Equal(t, "Tue, 20 Oct 2026 08:00:00 GMT", rec.Header().Get("Upload-Expires"))
+		assert.Equal(t, "1.0.0", rec.Header().Get("Tus-Resumable"))
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("when Upload-Length is missing should return status 400", func(t *testing.T) {
+		rec := serve(new(mocks.ResumableUploadService), http.MethodPost, "/uploads", "", nil)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("when the length exceeds the limit should return status 413", func(t *testing.T) {
+		svc := new(mocks.ResumableUploadService)
+		svc.On("Create", mock.Anything, int64(101), mock.Anything).Return(domain.ResumableUpload{}, domain.ErrFileTooLarge).Once()
+
+		rec := serve(svc, http.MethodPost, "/uploads", "", map[string]string{"Upload-Length": "101"})
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	})
+
+	t.Run("when head should return the offset to resume from", func(t *testing.T) {
+		svc := new(mocks.ResumableUploadService)
+		svc.On("Get", mock.Anything, "abc").Return(domain.ResumableUpload{
+			ID: "abc", Length: 42, Offset: 7, Metadata: map[string]string{"filename": "scan.pdf"}, ExpiresAt: expiresAt,
+		}, nil).Once()
+
+		rec := serve(svc, http.MethodHead, "/uploads/abc", "", nil)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "7", rec.Header().Get("Upload-Offset"))
+		assert.Equal(t, "42", rec.Header().Get("Upload-Length"))
+		assert.Equal(t, "filename c2Nhbi5wZGY=", rec.Header().Get("Upload-Metadata"))
+		assert.Equal(t, "no-store", rec.Header().Get(echo.HeaderCacheControl))
+	})
+
+	t.Run("when head of an unknown upload should return status 404", func(t *testing.T) {
+		svc := new(mocks.ResumableUploadService)
+		svc.On("Get", mock.Anything, "abc").Return(domain.ResumableUpload{}, domain.ErrNotFound).Once()
+
+		rec := serve(svc, http.MethodHead, "/uploads/abc", "", nil)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("when patch with a checksum should pass it to the service", func(t *testing.T) {
+		svc := new(mocks.ResumableUploadService)
+		svc.On("Append", mock.Anything, "abc", int64(7), mock.Anything, &domain.Checksum{Algorithm: "sha1", Sum: []byte{1, 2, 3}}).
+			Return(domain.ResumableUpload{ID: "abc", Length: 42, Offset: 10, ExpiresAt: expiresAt}, nil).Once()
+
+		rec := serve(svc, http.MethodPatch, "/uploads/abc", "123", map[string]string{
+			echo.HeaderContentType: "application/offset+octet-stream",
+			"Upload-Offset":        "7",
+			"Upload-Checksum":      "sha1 AQID",
+		})
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "10", rec.Header().Get("Upload-Offset"))
+		assert.Equal(t, "Tue, 20 Oct 2026 08:00:00 GMT", rec.Header().Get("Upload-Expires"))
+	})
+
+	t.Run("when patch is not an offset stream should return status 415", func(t *testing.T) {
+		rec := serve(new(mocks.ResumableUploadService), http.MethodPatch, "/uploads/abc", "123", map[string]string{
+			echo.HeaderContentType: "application/octet-stream",
+			"Upload-Offset":        "0",
+		})
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	})
+
+	t.Run("when patch fails should map the error to the tus status", func(t *testing.T) {
+		for err, status := range map[error]int{
+			domain.ErrOffsetMismatch:   http.StatusConflict,
+			domain.ErrChecksumMismatch: rest.StatusChecksumMismatch,
+			domain.ErrUploadLocked:     http.StatusLocked,
+			domain.ErrNotFound:         http.StatusNotFound,
+		} {
+			svc := new(mocks.ResumableUploadService)
+			svc.On("Append", mock.Anything, "abc", int64(0), mock.Anything, (*domain.Checksum)(nil)).
+				Return(domain.ResumableUpload{}, err).Once()
+
+			rec := serve(svc, http.MethodPatch, "/uploads/abc", "123", map[string]string{
+				echo.HeaderContentType: "application/offset+octet-stream",
+				"Upload-Offset":        "0",
+			})
+
+			assert.Equal(t, status, rec.Code, err)
+		}
+	})
+
+	t.Run("when deleted should return status 204", func(t *testing.T) {
+		svc := new(mocks.ResumableUploadService)
+		svc.On("Delete", mock.Anything, "abc").Return(nil).Once()
+
+		rec := serve(svc, http.MethodDelete, "/uploads/abc", "", nil)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		svc.AssertExpectations(t)
+	})
+}
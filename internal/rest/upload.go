@@ -0,0 +1,280 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/labstack/echo/v4"
+)
+
+// UploadLimits caps the size of the uploaded document per endpoint, zero means no limit
+type UploadLimits struct {
+	Compress int64
+	Split    int64
+}
+
+// SourceFetcher downloads the document a request names by source_url instead of
+// uploading it
+//
+//go:generate mockery --name SourceFetcher
+type SourceFetcher interface {
+	Fetch(ctx context.Context, rawURL string, maxBytes int64) (domain.SourceFile, error)
+}
+
+// uploadKind describes what an accepted upload looks like
+type uploadKind struct {
+	extension    string
+	contentTypes []string
+	// magic must appear within the first magicWindow bytes
+	magic       []byte
+	magicWindow int
+}
+
+var (
+	// PDF readers tolerate junk before the header within the first KiB, so the check
+	// does too. Many clients send everything as octet-stream, the magic bytes decide
+	// in that case.
+	pdfUpload = uploadKind{
+		extension:    ".pdf",
+		contentTypes: []string{"application/pdf", "application/x-pdf", "application/octet-stream"},
+		magic:        []byte("%PDF-"),
+		magicWindow:  1024,
+	}
+	zipUpload = uploadKind{
+		extension:    ".zip",
+		contentTypes: []string{"application/zip", "application/x-zip-compressed", "application/octet-stream"},
+		magic:        []byte("PK\x03\x04"),
+		magicWindow:  4,
+	}
+)
+
+// multipartOverhead is allowed on top of the file limit for the other form fields and
+// the part headers, so an oversized body is cut off while it is still being received.
+const multipartOverhead = 1 << 20
+
+// parseUpload parses the multipart form without keeping files in memory. It has to run
+// before any form value is read, otherwise echo parses the form with its defaults.
+func parseUpload(c echo.Context, maxBytes int64) error {
+	req := c.Request()
+	if req.MultipartForm != nil {
+		return nil
+	}
+	if maxBytes > 0 {
+		req.Body = http.MaxBytesReader(c.Response(), req.Body, maxBytes+multipartOverhead)
+	}
+
+	if err := req.ParseMultipartForm(uploadMemory); err != nil {
+		// a url encoded form can still carry a source_url or an upload_id
+		if errors.Is(err, http.ErrNotMultipart) && req.PostForm != nil {
+			return nil
+		}
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return domain.ErrFileTooLarge
+		}
+		return fmt.Errorf("%w: %s", domain.ErrMissingFile, err)
+	}
+	return nil
+}
+
+// upload is the document of a request, or a zip of documents in batch mode
+type upload struct {
+	name  string
+	file  domain.SpooledContent
+	size  int64
+	batch bool
+}
+
+// openRequestUpload opens the "file" part, or downloads source_url, once it looks like
+// a PDF, or like a zip with batch=true: extension, declared content type, size and
+// magic bytes. The file is positioned at the start.
+func (a *PdfHandler) openRequestUpload(c echo.Context, maxBytes int64) (*upload, error) {
+	return a.openFieldUpload(c, "file", maxBytes)
+}
+
+// openFieldUpload is openRequestUpload for operations that name their documents, the
+// part field or else source_url or upload_id is the document of the request
+func (a *PdfHandler) openFieldUpload(c echo.Context, field string, maxBytes int64) (*upload, error) {
+	if err := parseUpload(c, maxBytes); err != nil {
+		return nil, err
+	}
+
+	kind, batch := pdfUpload, c.FormValue("batch") == "true"
+	if batch {
+		kind = zipUpload
+	}
+	sourceURL, uploadID := c.FormValue("source_url"), c.FormValue("upload_id")
+	if sourceURL != "" || uploadID != "" {
+		if _, err := c.FormFile(field); err == nil || (sourceURL != "" && uploadID != "") {
+			return nil, paramError(fmt.Sprintf("Send only one of %s, source_url and upload_id", field))
+		}
+	}
+	if sourceURL != "" {
+		if a.Sources == nil {
+			return nil, paramError("source_url is not enabled")
+		}
+		source, err := a.Sources.Fetch(c.Request().Context(), sourceURL, maxBytes)
+		if err != nil {
+			return nil, err
+		}
+		return openSource(source, kind, batch)
+	}
+	if uploadID != "" {
+		if a.Uploads == nil {
+			return nil, paramError("upload_id is not enabled")
+		}
+		source, err := a.Uploads.Open(c.Request().Context(), uploadID)
+		if err != nil {
+			return nil, err
+		}
+		if maxBytes > 0 && source.Size > maxBytes {
+			source.Content.Close()
+			return nil, domain.ErrFileTooLarge
+		}
+		return openSource(source, kind, batch)
+	}
+
+	name, file, size, err := openUpload(c, field, maxBytes, kind)
+	if err != nil {
+		return nil, err
+	}
+	return &upload{name: name, file: file, size: size, batch: batch}, nil
+}
+
+// rejectBatch turns batch=true away for operations of several documents, a zip holds
+// the documents of a single one
+func rejectBatch(c echo.Context) error {
+	if c.FormValue("batch") == "true" {
+		return paramError("batch is not supported by this operation")
+	}
+	return nil
+}
+
+// companion is a document of a request besides the one it processes. It is released
+// by the operation once done with it, or with the request unless a job answered
+// right away took it over.
+type companion struct {
+	name string
+	file domain.SpooledContent
+	once sync.Once
+}
+
+// openCompanion opens the PDF of the form file field as a companion. With a
+// callback_url the upload is removed with the request, the job gets a copy of its own.
+func (a *PdfHandler) openCompanion(c echo.Context, field string, maxBytes int64) (*companion, error) {
+	name, file, _, err := openUpload(c, field, maxBytes, pdfUpload)
+	if err != nil {
+		return nil, err
+	}
+	if c.FormValue("callback_url") == "" {
+		return &companion{name: name, file: file}, nil
+	}
+
+	spooled, _, err := a.Service.Spool(c.Request().Context(), file)
+	file.Close()
+	if err != nil {
+		return nil, err
+	}
+	return &companion{name: name, file: spooled}, nil
+}
+
+func (d *companion) release() {
+	d.once.Do(func() { d.file.Close() })
+}
+
+// releaseWith releases d with the request c unless a job took it over
+func (d *companion) releaseWith(c echo.Context) {
+	if c.Response().Status != http.StatusAccepted {
+		d.release()
+	}
+}
+
+// openSource checks a downloaded or resumable upload like a form upload. URLs rarely
+// end in an extension, so only the content type and the magic bytes count, and the
+// name gets the extension the result naming relies on.
+func openSource(source domain.SourceFile, kind uploadKind, batch bool) (*upload, error) {
+	if !kind.acceptsContentType(source.ContentType) {
+		source.Content.Close()
+		return nil, domain.ErrUnsupportedMediaType
+	}
+	if err := kind.sniff(source.Content); err != nil {
+		source.Content.Close()
+		return nil, err
+	}
+
+	name := source.Name
+	if !strings.EqualFold(filepath.Ext(name), kind.extension) {
+		name += kind.extension
+	}
+	return &upload{name: name, file: source.Content, size: source.Size, batch: batch}, nil
+}
+
+func openUpload(c echo.Context, field string, maxBytes int64, kind uploadKind) (string, multipart.File, int64, error) {
+	if err := parseUpload(c, maxBytes); err != nil {
+		return "", nil, 0, err
+	}
+
+	header, err := c.FormFile(field)
+	if err != nil {
+		return "", nil, 0, domain.ErrMissingFile
+	}
+	if maxBytes > 0 && header.Size > maxBytes {
+		return "", nil, 0, domain.ErrFileTooLarge
+	}
+	if !strings.EqualFold(filepath.Ext(header.Filename), kind.extension) || !kind.acceptsContentType(header.Header.Get(echo.HeaderContentType)) {
+		return "", nil, 0, domain.ErrUnsupportedMediaType
+	}
+
+	src, err := header.Open()
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("failed to open upload: %w", err)
+	}
+	if err := kind.sniff(src); err != nil {
+		src.Close()
+		return "", nil, 0, err
+	}
+
+	return header.Filename, src, header.Size, nil
+}
+
+func (k uploadKind) acceptsContentType(declared string) bool {
+	if declared == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(declared)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range k.contentTypes {
+		if mediaType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func (k uploadKind) sniff(src io.ReadSeeker) error {
+	head := make([]byte, k.magicWindow)
+	n, err := io.ReadFull(src, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return fmt.Errorf("failed to read upload: %w", err)
+	}
+	if !bytes.Contains(head[:n], k.magic) {
+		return domain.ErrUnsupportedMediaType
+	}
+
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind upload: %w", err)
+	}
+	return nil
+}
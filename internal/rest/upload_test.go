@@ -0,0 +1,291 @@
+package rest_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rest"
+	"github.com/bxcodec/go-clean-arch/internal/rest/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPdfUploadValidation(t *testing.T) {
+	pdfContent, err := os.ReadFile("../resource/test.pdf")
+	require.NoError(t, err)
+	pngContent, err := os.ReadFile("../resource/test.fake")
+	require.NoError(t, err)
+
+	createUpload := func(fileName, contentType string, content []byte) (*bytes.Buffer, string) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		if content != nil {
+			header := textproto.MIMEHeader{}
+			header.Set("Content-Disposition", `form-data; name="file"; filename="`+fileName+`"`)
+			header.Set("Content-Type", contentType)
+			part, _ := writer.CreatePart(header)
+			part.Write(content)
+		}
+		writer.WriteField("split_mode", "ranges")
+		writer.Close()
+		return &body, writer.FormDataContentType()
+	}
+
+	tests := []struct {
+		name        string
+		fileName    string
+		contentType string
+		content     []byte
+		limit       int64
+		status      int
+	}{
+		{"when file is missing should return status 400", "", "", nil, 0, http.StatusBadRequest},
+		{"when content is not a pdf should return status 415", "test.pdf", "application/pdf", pngContent, 0, http.StatusUnsupportedMediaType},
+		{"when extension is not pdf should return status 415", "test.fake", "application/octet-stream", pdfContent, 0, http.StatusUnsupportedMediaType},
+		{"when declared type is not pdf should return status 415", "test.pdf", "image/png", pdfContent, 0, http.StatusUnsupportedMediaType},
+		{"when file exceeds the limit should return status 413", "test.pdf", "application/pdf", pdfContent, 16, http.StatusRequestEntityTooLarge},
+		{"when body exceeds the limit should return status 413", "test.pdf", "application/pdf", make([]byte, 2<<20), 16, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPdfSvc := new(mocks.PdfService)
+			handler := rest.PdfHandler{
+				Service: mockPdfSvc,
+				Limits:  rest.UploadLimits{Compress: tt.limit, Split: tt.limit},
+			}
+
+			for _, endpoint := range []struct {
+				path   string
+				handle echo.HandlerFunc
+			}{
+				{"/process/compress", handler.StartCompress},
+				{"/process/split", handler.StartSplit},
+			} {
+				body, contentType := createUpload(tt.fileName, tt.contentType, tt.content)
+				e := echo.New()
+				req := httptest.NewRequest(http.MethodPost, endpoint.path, body)
+				req.Header.Set("Content-Type", contentType)
+				rec := httptest.NewRecorder()
+				c := e.NewContext(req, rec)
+
+				err := endpoint.handle(c)
+				require.NoError(t, err)
+
+				assert.Equal(t, tt.status, rec.Code, endpoint.path)
+				assert.Contains(t, rec.Body.String(), `"message"`)
+			}
+
+			mockPdfSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSourceUpload(t *testing.T) {
+	pdfContent, err := os.ReadFile("../resource/test.pdf")
+	require.NoError(t, err)
+
+	spooled := func(t *testing.T, content []byte) *os.File {
+		name := filepath.Join(t.TempDir(), "source")
+		require.NoError(t, os.WriteFile(name, content, 0o600))
+		file, err := os.Open(name)
+		require.NoError(t, err)
+		return file
+	}
+
+	compress := func(handler rest.PdfHandler, form url.Values) *httptest.ResponseRecorder {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/process/compress", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		require.NoError(t, handler.StartCompress(e.NewContext(req, rec)))
+		return rec
+	}
+
+	t.Run("when source_url is given should process the downloaded document", func(t *testing.T) {
+		mockSources := new(mocks.SourceFetcher)
+		mockSources.On("Fetch", mock.Anything, "http://docs.internal/files/report", int64(1024)).Return(domain.SourceFile{
+			Name:        "report",
+			ContentType: "application/pdf",
+			Content:     spooled(t, pdfContent),
+			Size:        int64(len(pdfContent)),
+		}, nil).Once()
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("CompressPdf", mock.Anything, "report.pdf", mock.Anything).Return(domain.PdfFile{
+			Name:    "compressed_report.pdf",
+			Content: io.NopCloser(bytes.NewReader([]byte{1})),
+			Size:    1,
+		}, nil).Once()
+
+		rec := compress(rest.PdfHandler{
+			Service: mockPdfSvc,
+			Sources: mockSources,
+			Limits:  rest.UploadLimits{Compress: 1024},
+		}, url.Values{"source_url": {"http://docs.internal/files/report"}})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockSources.AssertExpectations(t)
+		mockPdfSvc.AssertExpectations(t)
+	})
+
+	t.Run("when download is not a pdf should return status 415", func(t *testing.T) {
+		mockSources := new(mocks.SourceFetcher)
+		mockSources.On("Fetch", mock.Anything, mock.Anything, mock.Anything).Return(domain.SourceFile{
+			Name:    "index.html",
+			Content: spooled(t, []byte("<html></html>")),
+			Size:    13,
+		}, nil).Once()
+
+		rec := compress(rest.PdfHandler{Service: new(mocks.PdfService), Sources: mockSources},
+			url.Values{"source_url": {"http://docs.internal/"}})
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	})
+
+	t.Run("when fetch fails should map the error to a status", func(t *testing.T) {
+		for err, status := range map[error]int{
+			domain.ErrForbiddenSource:                                 http.StatusBadRequest,
+			fmt.Errorf("%w: status 404", domain.ErrSourceUnavailable): http.StatusBadGateway,
+			domain.ErrFileTooLarge:                                    http.StatusRequestEntityTooLarge,
+		} {
+			mockSources := new(mocks.SourceFetcher)
+			mockSources.On("Fetch", mock.Anything, mock.Anything, mock.Anything).Return(domain.SourceFile{}, err).Once()
+
+			rec := compress(rest.PdfHandler{Service: new(mocks.PdfService), Sources: mockSources},
+				url.Values{"source_url": {"http://169.254.169.254/latest"}})
+
+			assert.Equal(t, status, rec.Code, err.Error())
+		}
+	})
+
+	t.Run("when source_url is not enabled should return status 400", func(t *testing.T) {
+		rec := compress(rest.PdfHandler{Service: new(mocks.PdfService)},
+			url.Values{"source_url": {"http://docs.internal/report.pdf"}})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "source_url is not enabled")
+	})
+
+	t.Run("when both file and source_url are given should return status 400", func(t *testing.T) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, _ := writer.CreateFormFile("file", "test.pdf")
+		part.Write(pdfContent)
+		writer.WriteField("source_url", "http://docs.internal/report.pdf")
+		writer.Close()
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/process/compress", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		rec := httptest.NewRecorder()
+		handler := rest.PdfHandler{Service: new(mocks.PdfService), Sources: new(mocks.SourceFetcher)}
+
+		require.NoError(t, handler.StartCompress(e.NewContext(req, rec)))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestResumableUploadID(t *testing.T) {
+	pdfContent, err := os.ReadFile("../resource/test.pdf")
+	require.NoError(t, err)
+
+	stored := func(t *testing.T) *os.File {
+		name := filepath.Join(t.TempDir(), "upload")
+		require.NoError(t, os.WriteFile(name, pdfContent, 0o600))
+		file, err := os.Open(name)
+		require.NoError(t, err)
+		return file
+	}
+
+	compress := func(handler rest.PdfHandler, form url.Values) *httptest.ResponseRecorder {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/process/compress", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		require.NoError(t, handler.StartCompress(e.NewContext(req, rec)))
+		return rec
+	}
+
+	t.Run("when upload_id is complete should process the stored document", func(t *testing.T) {
+		mockUploads := new(mocks.ResumableUploadService)
+		mockUploads.On("Open", mock.Anything, "abc").Return(domain.SourceFile{
+			Name:        "scan.pdf",
+			ContentType: "application/pdf",
+			Content:     stored(t),
+			Size:        int64(len(pdfContent)),
+		}, nil).Once()
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("CompressPdf", mock.Anything, "scan.pdf", mock.Anything).Return(domain.PdfFile{
+			Name:    "compressed_scan.pdf",
+			Content: io.NopCloser(bytes.NewReader([]byte{1})),
+			Size:    1,
+		}, nil).Once()
+
+		rec := compress(rest.PdfHandler{Service: mockPdfSvc, Uploads: mockUploads}, url.Values{"upload_id": {"abc"}})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		mockUploads.AssertExpectations(t)
+		mockPdfSvc.AssertExpectations(t)
+	})
+
+	t.Run("when upload_id exceeds the limit of the endpoint should return status 413", func(t *testing.T) {
+		mockUploads := new(mocks.ResumableUploadService)
+		mockUploads.On("Open", mock.Anything, "abc").Return(domain.SourceFile{
+			Name:    "scan.pdf",
+			Content: stored(t),
+			Size:    int64(len(pdfContent)),
+		}, nil).Once()
+
+		rec := compress(rest.PdfHandler{
+			Service: new(mocks.PdfService),
+			Uploads: mockUploads,
+			Limits:  rest.UploadLimits{Compress: 10},
+		}, url.Values{"upload_id": {"abc"}})
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	})
+
+	t.Run("when upload_id can't be opened should map the error to a status", func(t *testing.T) {
+		for err, status := range map[error]int{
+			domain.ErrNotFound:         http.StatusNotFound,
+			domain.ErrUploadIncomplete: http.StatusConflict,
+		} {
+			mockUploads := new(mocks.ResumableUploadService)
+			mockUploads.On("Open", mock.Anything, "abc").Return(domain.SourceFile{}, err).Once()
+
+			rec := compress(rest.PdfHandler{Service: new(mocks.PdfService), Uploads: mockUploads}, url.Values{"upload_id": {"abc"}})
+
+			assert.Equal(t, status, rec.Code, err.Error())
+		}
+	})
+
+	t.Run("when both upload_id and source_url are given should return status 400", func(t *testing.T) {
+		rec := compress(rest.PdfHandler{
+			Service: new(mocks.PdfService),
+			Sources: new(mocks.SourceFetcher),
+			Uploads: new(mocks.ResumableUploadService),
+		}, url.Values{"upload_id": {"abc"}, "source_url": {"http://docs.internal/report.pdf"}})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("when upload_id is not enabled should return status 400", func(t *testing.T) {
+		rec := compress(rest.PdfHandler{Service: new(mocks.PdfService)}, url.Values{"upload_id": {"abc"}})
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "upload_id is not enabled")
+	})
+}
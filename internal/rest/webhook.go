@@ -0,0 +1,132 @@
+package rest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// WebhookService represent the delivery of job results to callback urls
+//
+//go:generate mockery --name WebhookService
+type WebhookService interface {
+	Deliver(ctx context.Context, callbackURL string, result domain.JobResult) error
+}
+
+// JobAccepted answers a request whose result is delivered to its callback_url
+type JobAccepted struct {
+	JobID     string `json:"job_id"`
+	EventsURL string `json:"events_url,omitempty"`
+}
+
+// processAsync answers with 202 right away and runs op in the background. The result
+// is stored like with response = link and posted to callbackURL.
+func (a *PdfHandler) processAsync(c echo.Context, upload *upload, op domain.PdfProcessor, message, callbackURL string) error {
+	if a.Webhooks == nil || a.Links == nil {
+		return respondWithPdfError(c, paramError("callback_url is not enabled"), message)
+	}
+	if u, err := url.Parse(callbackURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return respondWithPdfError(c, paramError("callback_url must be an http or https url"), message)
+	}
+
+	// the upload is removed with the request, the job needs a copy of its own
+	file, size, err := a.Service.Spool(c.Request().Context(), upload.file)
+	if err != nil {
+		return respondWithPdfError(c, err, message)
+	}
+	owned := *upload
+	owned.file, owned.size = file, size
+
+	ctx, id, finish, err := a.startJob(c)
+	if err != nil {
+		file.Close()
+		return respondWithPdfError(c, err, message)
+	}
+
+	linkBase := downloadURL(c, "")
+	go func() {
+		result := a.runAsync(context.WithoutCancel(ctx), &owned, op, message, linkBase)
+		finish(result.err, message)
+
+		result.JobID = id
+		if err := a.Webhooks.Deliver(context.Background(), callbackURL, result.JobResult); err != nil {
+			logrus.Error(err)
+		}
+	}()
+
+	accepted := JobAccepted{JobID: id}
+	if a.Jobs != nil {
+		accepted.EventsURL = "/jobs/" + id + "/events"
+	}
+	return c.JSON(http.StatusAccepted, accepted)
+}
+
+type asyncResult struct {
+	domain.JobResult
+	err error
+}
+
+// runAsync runs op on upload and publishes the result, taking ownership of upload
+func (a *PdfHandler) runAsync(ctx context.Context, upload *upload, op domain.PdfProcessor, message, linkBase string) asyncResult {
+	defer upload.file.Close()
+	if a.AsyncTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.AsyncTimeout)
+		defer cancel()
+	}
+
+	result, err := a.runOperation(ctx, upload, op)
+	if err == nil {
+		var published domain.JobResult
+		published, err = a.publish(ctx, result, linkBase)
+		if err == nil {
+			published.Status = domain.JobSucceeded
+			published.FinishedAt = time.Now()
+			return asyncResult{JobResult: published}
+		}
+	}
+
+	if pdfErrorStatusOf(err) == http.StatusInternalServerError {
+		logrus.Error(err)
+	}
+	return asyncResult{
+		JobResult: domain.JobResult{
+			Status:     domain.JobFailed,
+			Error:      pdfErrorMessage(err, message),
+			FinishedAt: time.Now(),
+		},
+		err: err,
+	}
+}
+
+// publish stores result for download and checksums it on the way
+func (a *PdfHandler) publish(ctx context.Context, result domain.PdfFile, linkBase string) (domain.JobResult, error) {
+	defer result.Content.Close()
+
+	hash := sha256.New()
+	link, err := a.Links.Publish(ctx, domain.PdfFile{
+		Name:    result.Name,
+		Content: io.NopCloser(io.TeeReader(result.Content, hash)),
+		Size:    result.Size,
+	})
+	if err != nil {
+		return domain.JobResult{}, err
+	}
+	link.URL = linkBase + link.Token
+
+	return domain.JobResult{
+		Name:     link.Name,
+		Size:     link.Size,
+		Checksum: hex.EncodeToString(hash.Sum(nil)),
+		Download: &link,
+	}, nil
+}
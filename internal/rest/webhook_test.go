@@ -0,0 +1,128 @@
+package rest_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rest"
+	"github.com/bxcodec/go-clean-arch/internal/rest/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallbackURL(t *testing.T) {
+	pdfContent, err := os.ReadFile("../resource/test.pdf")
+	require.NoError(t, err)
+
+	compress := func(handler rest.PdfHandler, callbackURL string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+		part, _ := writer.CreateFormFile("file", "test.pdf")
+		part.Write(pdfContent)
+		writer.WriteField("callback_url", callbackURL)
+		writer.Close()
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/process/compress", &body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		rec := httptest.NewRecorder()
+		require.NoError(t, handler.StartCompress(e.NewContext(req, rec)))
+		return rec
+	}
+
+	spool := func(t *testing.T) *os.File {
+		name := filepath.Join(t.TempDir(), "upload")
+		require.NoError(t, os.WriteFile(name, pdfContent, 0o600))
+		file, err := os.Open(name)
+		require.NoError(t, err)
+		return file
+	}
+
+	awaitDelivery := func(t *testing.T, delivered chan domain.JobResult) domain.JobResult {
+		select {
+		case result := <-delivered:
+			return result
+		case <-time.After(5 * time.Second):
+			t.Fatal("webhook was not delivered")
+			return domain.JobResult{}
+		}
+	}
+
+	t.Run("when callback_url is given should answer 202 and deliver the stored result", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("Spool", mock.Anything, mock.Anything).Return(spool(t), int64(len(pdfContent)), nil).Once()
+		mockPdfSvc.On("CompressPdf", mock.Anything, "test.pdf", mock.Anything).Return(domain.PdfFile{
+			Name:    "compressed_test.pdf",
+			Content: io.NopCloser(bytes.NewReader([]byte("result"))),
+			Size:    6,
+		}, nil).Once()
+		mockLinks := new(mocks.DownloadService)
+		mockLinks.On("Publish", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			io.ReadAll(args.Get(1).(domain.PdfFile).Content)
+		}).Return(domain.DownloadLink{Token: "token", Name: "compressed_test.pdf", Size: 6}, nil).Once()
+		delivered := make(chan domain.JobResult, 1)
+		mockWebhooks := new(mocks.WebhookService)
+		mockWebhooks.On("Deliver", mock.Anything, "https://partner.example/hook", mock.Anything).Run(func(args mock.Arguments) {
+			delivered <- args.Get(2).(domain.JobResult)
+		}).Return(nil).Once()
+
+		rec := compress(rest.PdfHandler{Service: mockPdfSvc, Links: mockLinks, Webhooks: mockWebhooks}, "https://partner.example/hook")
+
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"job_id":"`+rec.Header().Get("X-Job-ID")+`"`)
+
+		result := awaitDelivery(t, delivered)
+		checksum := sha256.Sum256([]byte("result"))
+		assert.Equal(t, rec.Header().Get("X-Job-ID"), result.JobID)
+		assert.Equal(t, domain.JobSucceeded, result.Status)
+		assert.Equal(t, int64(6), result.Size)
+		assert.Equal(t, hex.EncodeToString(checksum[:]), result.Checksum)
+		assert.Equal(t, "http://example.com/downloads/token", result.Download.URL)
+	})
+
+	t.Run("when operation fails should deliver the failure", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("Spool", mock.Anything, mock.Anything).Return(spool(t), int64(len(pdfContent)), nil).Once()
+		mockPdfSvc.On("CompressPdf", mock.Anything, mock.Anything, mock.Anything).Return(domain.PdfFile{}, io.ErrUnexpectedEOF).Once()
+		delivered := make(chan domain.JobResult, 1)
+		mockWebhooks := new(mocks.WebhookService)
+		mockWebhooks.On("Deliver", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			delivered <- args.Get(2).(domain.JobResult)
+		}).Return(nil).Once()
+
+		rec := compress(rest.PdfHandler{Service: mockPdfSvc, Links: new(mocks.DownloadService), Webhooks: mockWebhooks}, "https://partner.example/hook")
+
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		result := awaitDelivery(t, delivered)
+		assert.Equal(t, domain.JobFailed, result.Status)
+		assert.Equal(t, "Failed to compress pdf", result.Error)
+		assert.Nil(t, result.Download)
+	})
+
+	t.Run("when callback_url is not enabled should return status 400", func(t *testing.T) {
+		rec := compress(rest.PdfHandler{Service: new(mocks.PdfService)}, "https://partner.example/hook")
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "callback_url is not enabled")
+	})
+
+	t.Run("when callback_url is not http should return status 400", func(t *testing.T) {
+		handler := rest.PdfHandler{Service: new(mocks.PdfService), Links: new(mocks.DownloadService), Webhooks: new(mocks.WebhookService)}
+
+		rec := compress(handler, "file:///etc/passwd")
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
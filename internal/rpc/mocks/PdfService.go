@@ -0,0 +1,264 @@
+// Code generated by mockery v2.50.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+
+	domain "github.com/bxcodec/go-clean-arch/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PdfService is an autogenerated mock type for the PdfService type
+type PdfService struct {
+	mock.Mock
+}
+
+// AnalyzePdf provides a mock function with given fields: ctx, fileName, file
+func (_m *PdfService) AnalyzePdf(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+	ret := _m.Called(ctx, fileName, file)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AnalyzePdf")
+	}
+
+	var r0 domain.PdfFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker) (domain.PdfFile, error)); ok {
+		return rf(ctx, fileName, file)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker) domain.PdfFile); ok {
+		r0 = rf(ctx, fileName, file)
+	} else {
+		r0 = ret.Get(0).(domain.PdfFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.ReadSeeker) error); ok {
+		r1 = rf(ctx, fileName, file)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompressPdf provides a mock function with given fields: ctx, fileName, file
+func (_m *PdfService) CompressPdf(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+	ret := _m.Called(ctx, fileName, file)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompressPdf")
+	}
+
+	var r0 domain.PdfFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker) (domain.PdfFile, error)); ok {
+		return rf(ctx, fileName, file)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker) domain.PdfFile); ok {
+		r0 = rf(ctx, fileName, file)
+	} else {
+		r0 = ret.Get(0).(domain.PdfFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.ReadSeeker) error); ok {
+		r1 = rf(ctx, fileName, file)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CropPdf provides a mock function with given fields: ctx, fileName, file, options
+func (_m *PdfService) CropPdf(ctx context.Context, fileName string, file io.ReadSeeker, options domain.CropOptions) (domain.PdfFile, error) {
+	ret := _m.Called(ctx, fileName, file, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CropPdf")
+	}
+
+	var r0 domain.PdfFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, domain.CropOptions) (domain.PdfFile, error)); ok {
+		return rf(ctx, fileName, file, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, domain.CropOptions) domain.PdfFile); ok {
+		r0 = rf(ctx, fileName, file, options)
+	} else {
+		r0 = ret.Get(0).(domain.PdfFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.ReadSeeker, domain.CropOptions) error); ok {
+		r1 = rf(ctx, fileName, file, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PageCount provides a mock function with given fields: ctx, file
+func (_m *PdfService) PageCount(ctx context.Context, file io.ReadSeeker) (int, error) {
+	ret := _m.Called(ctx, file)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PageCount")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker) (int, error)); ok {
+		return rf(ctx, file)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker) int); ok {
+		r0 = rf(ctx, file)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.ReadSeeker) error); ok {
+		r1 = rf(ctx, file)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemovePagesPdf provides a mock function with given fields: ctx, fileName, file, removePages, pageCount
+func (_m *PdfService) RemovePagesPdf(ctx context.Context, fileName string, file io.ReadSeeker, removePages []int, pageCount int) (domain.PdfFile, error) {
+	ret := _m.Called(ctx, fileName, file, removePages, pageCount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemovePagesPdf")
+	}
+
+	var r0 domain.PdfFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, []int, int) (domain.PdfFile, error)); ok {
+		return rf(ctx, fileName, file, removePages, pageCount)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, []int, int) domain.PdfFile); ok {
+		r0 = rf(ctx, fileName, file, removePages, pageCount)
+	} else {
+		r0 = ret.Get(0).(domain.PdfFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.ReadSeeker, []int, int) error); ok {
+		r1 = rf(ctx, fileName, file, removePages, pageCount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SplitAndZipPdfByFixedRange provides a mock function with given fields: ctx, fileName, file, fra, naming
+func (_m *PdfService) SplitAndZipPdfByFixedRange(ctx context.Context, fileName string, file io.ReadSeeker, fra [][]int, naming domain.SplitNaming) (domain.PdfFile, error) {
+	ret := _m.Called(ctx, fileName, file, fra, naming)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SplitAndZipPdfByFixedRange")
+	}
+
+	var r0 domain.PdfFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, [][]int, domain.SplitNaming) (domain.PdfFile, error)); ok {
+		return rf(ctx, fileName, file, fra, naming)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, [][]int, domain.SplitNaming) domain.PdfFile); ok {
+		r0 = rf(ctx, fileName, file, fra, naming)
+	} else {
+		r0 = ret.Get(0).(domain.PdfFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.ReadSeeker, [][]int, domain.SplitNaming) error); ok {
+		r1 = rf(ctx, fileName, file, fra, naming)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SplitPdfByRanges provides a mock function with given fields: ctx, fileName, file, ranges
+func (_m *PdfService) SplitPdfByRanges(ctx context.Context, fileName string, file io.ReadSeeker, ranges []int) (domain.PdfFile, error) {
+	ret := _m.Called(ctx, fileName, file, ranges)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SplitPdfByRanges")
+	}
+
+	var r0 domain.PdfFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, []int) (domain.PdfFile, error)); ok {
+		return rf(ctx, fileName, file, ranges)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, []int) domain.PdfFile); ok {
+		r0 = rf(ctx, fileName, file, ranges)
+	} else {
+		r0 = ret.Get(0).(domain.PdfFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.ReadSeeker, []int) error); ok {
+		r1 = rf(ctx, fileName, file, ranges)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Spool provides a mock function with given fields: ctx, content
+func (_m *PdfService) Spool(ctx context.Context, content io.Reader) (domain.SpooledContent, int64, error) {
+	ret := _m.Called(ctx, content)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Spool")
+	}
+
+	var r0 domain.SpooledContent
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader) (domain.SpooledContent, int64, error)); ok {
+		return rf(ctx, content)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader) domain.SpooledContent); ok {
+		r0 = rf(ctx, content)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(domain.SpooledContent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.Reader) int64); ok {
+		r1 = rf(ctx, content)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, io.Reader) error); ok {
+		r2 = rf(ctx, content)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewPdfService creates a new instance of PdfService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPdfService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PdfService {
+	mock := &PdfService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,257 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"maps"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	pdfv1 "github.com/bxcodec/go-clean-arch/api/pdf/v1"
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/pdf"
+)
+
+// PdfService is what the gRPC API needs from the PDF usecase
+//
+//go:generate mockery --name PdfService
+type PdfService interface {
+	CompressPdf(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error)
+	SplitPdfByRanges(ctx context.Context, fileName string, file io.ReadSeeker, ranges []int) (domain.PdfFile, error)
+	SplitAndZipPdfByFixedRange(ctx context.Context, fileName string, file io.ReadSeeker, fra [][]int, naming domain.SplitNaming) (domain.PdfFile, error)
+	RemovePagesPdf(ctx context.Context, fileName string, file io.ReadSeeker, removePages []int, pageCount int) (domain.PdfFile, error)
+	PageCount(ctx context.Context, file io.ReadSeeker) (int, error)
+	CropPdf(ctx context.Context, fileName string, file io.ReadSeeker, options domain.CropOptions) (domain.PdfFile, error)
+	AnalyzePdf(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error)
+	Spool(ctx context.Context, content io.Reader) (domain.SpooledContent, int64, error)
+}
+
+// UploadLimits caps the size of the streamed document per operation, zero means no limit
+type UploadLimits struct {
+	Compress int64
+	Split    int64
+}
+
+type PdfServer struct {
+	pdfv1.UnimplementedPdfServiceServer
+
+	Service PdfService
+	Limits  UploadLimits
+}
+
+// PdfServerOption enables an optional feature of the PDF service
+type PdfServerOption func(*PdfServer)
+
+// WithUploadLimits caps the size of uploads
+func WithUploadLimits(limits UploadLimits) PdfServerOption {
+	return func(s *PdfServer) {
+		s.Limits = limits
+	}
+}
+
+func NewPdfServer(registrar grpc.ServiceRegistrar, svc PdfService, opts ...PdfServerOption) {
+	server := &PdfServer{
+		Service: svc,
+	}
+	for _, opt := range opts {
+		opt(server)
+	}
+	pdfv1.RegisterPdfServiceServer(registrar, server)
+}
+
+func (s *PdfServer) Compress(stream pdfv1.PdfService_CompressServer) error {
+	const message = "Failed to compress PDF"
+
+	options, err := receiveOptions(stream.Recv)
+	if err != nil {
+		return pdfError(err, message)
+	}
+
+	return s.process(stream, chunks(stream.Recv), s.Limits.Compress, options.GetFileName(), s.Service.CompressPdf, message)
+}
+
+func (s *PdfServer) Split(stream pdfv1.PdfService_SplitServer) error {
+	const message = "Failed to split PDF"
+
+	options, err := receiveOptions(stream.Recv)
+	if err != nil {
+		return pdfError(err, message)
+	}
+
+	// the built-in modes have fields of their own, the parameters of any other mode
+	// come by their HTTP name
+	values := maps.Clone(options.GetParams())
+	if values == nil {
+		values = map[string]string{}
+	}
+	var mode string
+	switch m := options.GetMode().(type) {
+	case *pdfv1.SplitOptions_Ranges:
+		mode, values["ranges"] = pdf.SplitModeRanges, m.Ranges
+	case *pdfv1.SplitOptions_FixedRange:
+		mode, values["fixed_range"] = pdf.SplitModeFixedRange, strconv.Itoa(int(m.FixedRange))
+		values["part_name"], values["zip_name"] = options.GetPartName(), options.GetZipName()
+	case *pdfv1.SplitOptions_RemovePages:
+		mode, values["remove_page"] = pdf.SplitModeRemovePages, m.RemovePages
+	case *pdfv1.SplitOptions_NamedMode:
+		mode = m.NamedMode
+	}
+
+	split, err := s.prepare(pdf.KindSplit, mode, values)
+	if errors.Is(err, pdf.ErrUnknownOperation) {
+		err = paramError("Invalid Split Mode")
+	}
+	if err != nil {
+		return pdfError(err, message)
+	}
+
+	return s.process(stream, chunks(stream.Recv), s.Limits.Split, options.GetFileName(), split, message)
+}
+
+func (s *PdfServer) RemovePages(stream pdfv1.PdfService_RemovePagesServer) error {
+	const message = "Failed to split PDF"
+
+	options, err := receiveOptions(stream.Recv)
+	if err != nil {
+		return pdfError(err, message)
+	}
+
+	removePages, err := s.prepare(pdf.KindSplit, pdf.SplitModeRemovePages, map[string]string{"remove_page": options.GetPages()})
+	if err != nil {
+		return pdfError(err, message)
+	}
+
+	return s.process(stream, chunks(stream.Recv), s.Limits.Split, options.GetFileName(), removePages, message)
+}
+
+// Process runs a processor of the registry, processors share the upload limit of compress
+func (s *PdfServer) Process(stream pdfv1.PdfService_ProcessServer) error {
+	const message = "Failed to process PDF"
+
+	options, err := receiveOptions(stream.Recv)
+	if err != nil {
+		return pdfError(err, message)
+	}
+
+	op, err := s.prepare(pdf.KindProcess, options.GetOperation(), options.GetParams())
+	if errors.Is(err, pdf.ErrUnknownOperation) {
+		return status.Errorf(codes.NotFound, "Unknown operation %q", options.GetOperation())
+	}
+	if err != nil {
+		return pdfError(err, message)
+	}
+
+	return s.process(stream, chunks(stream.Recv), s.Limits.Compress, options.GetFileName(), op, message)
+}
+
+func (s *PdfServer) PageCount(stream pdfv1.PdfService_PageCountServer) error {
+	const message = "Failed to count pages"
+	ctx := stream.Context()
+
+	if _, err := receiveOptions(stream.Recv); err != nil {
+		return pdfError(err, message)
+	}
+
+	file, err := openUpload(ctx, s.Service, chunks(stream.Recv), s.Limits.Split)
+	if err != nil {
+		return pdfError(err, message)
+	}
+	defer file.Close()
+
+	pageCount, err := s.Service.PageCount(ctx, file)
+	if err != nil {
+		return pdfError(err, message)
+	}
+	return stream.SendAndClose(&pdfv1.PageCountResponse{Pages: int32(pageCount)})
+}
+
+// process receives the document that follows the options from recv, runs op on it
+// and streams the result back
+func (s *PdfServer) process(stream grpc.ServerStreamingServer[pdfv1.FileChunk], recv func() ([]byte, error), maxBytes int64, fileName string, op domain.PdfProcessor, message string) error {
+	ctx := stream.Context()
+
+	file, err := openUpload(ctx, s.Service, recv, maxBytes)
+	if err != nil {
+		return pdfError(err, message)
+	}
+	defer file.Close()
+
+	result, err := op(ctx, uploadName(fileName), file)
+	if err != nil {
+		return pdfError(err, message)
+	}
+
+	if err := sendFile(stream, result); err != nil {
+		return pdfError(err, message)
+	}
+	return nil
+}
+
+// prepare returns the operation of the registry named name with the parameters in values
+func (s *PdfServer) prepare(kind, name string, values map[string]string) (domain.PdfProcessor, error) {
+	return pdf.DefaultRegistry.Prepare(s.Service, kind, name, func(name string) string {
+		return values[name]
+	})
+}
+
+// paramError is a request parameter that doesn't fit, reported to the client as is.
+// It is the error of the operations in the pdf registry too.
+type paramError = pdf.ParamError
+
+// pdfErrorCode lists the service errors a client can act on. Anything else is logged
+// and reported as Internal with a generic message.
+var pdfErrorCode = []struct {
+	err  error
+	code codes.Code
+}{
+	{domain.ErrStorageFull, codes.ResourceExhausted},
+	{domain.ErrMissingFile, codes.InvalidArgument},
+	{domain.ErrUnsupportedMediaType, codes.InvalidArgument},
+	{domain.ErrFileTooLarge, codes.ResourceExhausted},
+	{domain.ErrTooManyFiles, codes.ResourceExhausted},
+	{domain.ErrOverloaded, codes.Unavailable},
+	{domain.ErrConflict, codes.AlreadyExists},
+	{domain.ErrUnprocessable, codes.InvalidArgument},
+	{context.DeadlineExceeded, codes.DeadlineExceeded},
+	{context.Canceled, codes.Canceled},
+}
+
+// pdfError turns err into a status. An overloaded service adds a RetryInfo so clients
+// know when to come back.
+func pdfError(err error, message string) error {
+	var param paramError
+	if errors.As(err, &param) {
+		return status.Error(codes.InvalidArgument, param.Error())
+	}
+
+	for _, known := range pdfErrorCode {
+		if errors.Is(err, known.err) {
+			st := status.New(known.code, known.err.Error())
+
+			var overloaded *domain.OverloadedError
+			if errors.As(err, &overloaded) {
+				if detailed, detailErr := st.WithDetails(&errdetails.RetryInfo{
+					RetryDelay: durationpb.New(overloaded.RetryAfter),
+				}); detailErr == nil {
+					st = detailed
+				}
+			}
+			return st.Err()
+		}
+	}
+
+	// the stream itself failed, e.g. the client went away
+	if st, ok := status.FromError(err); ok {
+		return st.Err()
+	}
+
+	logrus.Error(err)
+	return status.Error(codes.Internal, message)
+}
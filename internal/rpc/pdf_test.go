@@ -0,0 +1,367 @@
+package rpc_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	pdfv1 "github.com/bxcodec/go-clean-arch/api/pdf/v1"
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/rpc"
+	"github.com/bxcodec/go-clean-arch/internal/rpc/mocks"
+	"github.com/bxcodec/go-clean-arch/pdf"
+)
+
+func newPdfClient(t *testing.T, svc rpc.PdfService, opts ...rpc.PdfServerOption) pdfv1.PdfServiceClient {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	rpc.NewPdfServer(server, svc, opts...)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return pdfv1.NewPdfServiceClient(conn)
+}
+
+// spoolTo makes a mocked Spool write the upload to a file below dir
+func spoolTo(dir string) func(context.Context, io.Reader) (domain.SpooledContent, int64, error) {
+	return func(_ context.Context, content io.Reader) (domain.SpooledContent, int64, error) {
+		file, err := os.CreateTemp(dir, "upload")
+		if err != nil {
+			return nil, 0, err
+		}
+		size, err := io.Copy(file, content)
+		if err != nil {
+			file.Close()
+			return nil, 0, err
+		}
+		_, err = file.Seek(0, io.SeekStart)
+		return file, size, err
+	}
+}
+
+// upload sends options followed by content in chunks of 1000 bytes and closes the
+// sending side
+func upload[Req any](t *testing.T, send func(*Req) error, closeSend func() error, options *Req, content []byte, chunk func([]byte) *Req) {
+	require.NoError(t, send(options))
+	for len(content) > 0 {
+		n := min(len(content), 1000)
+		if err := send(chunk(content[:n])); err != nil {
+			// the server already answered, the answer tells why
+			break
+		}
+		content = content[n:]
+	}
+	require.NoError(t, closeSend())
+}
+
+// download collects the file streamed back by recv
+func download(recv func() (*pdfv1.FileChunk, error)) (*pdfv1.FileInfo, []byte, error) {
+	var info *pdfv1.FileInfo
+	var content bytes.Buffer
+	for {
+		msg, err := recv()
+		if errors.Is(err, io.EOF) {
+			return info, content.Bytes(), nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if msg.GetInfo() != nil {
+			info = msg.GetInfo()
+		}
+		content.Write(msg.GetChunk())
+	}
+}
+
+func compress(t *testing.T, client pdfv1.PdfServiceClient, fileName string, content []byte) (*pdfv1.FileInfo, []byte, error) {
+	stream, err := client.Compress(context.Background())
+	require.NoError(t, err)
+
+	upload(t, stream.Send, stream.CloseSend,
+		&pdfv1.CompressRequest{Data: &pdfv1.CompressRequest_Options{Options: &pdfv1.CompressOptions{FileName: fileName}}},
+		content,
+		func(chunk []byte) *pdfv1.CompressRequest {
+			return &pdfv1.CompressRequest{Data: &pdfv1.CompressRequest_Chunk{Chunk: chunk}}
+		})
+	return download(stream.Recv)
+}
+
+func split(t *testing.T, client pdfv1.PdfServiceClient, options *pdfv1.SplitOptions, content []byte) (*pdfv1.FileInfo, []byte, error) {
+	stream, err := client.Split(context.Background())
+	require.NoError(t, err)
+
+	upload(t, stream.Send, stream.CloseSend,
+		&pdfv1.SplitRequest{Data: &pdfv1.SplitRequest_Options{Options: options}},
+		content,
+		func(chunk []byte) *pdfv1.SplitRequest {
+			return &pdfv1.SplitRequest{Data: &pdfv1.SplitRequest_Chunk{Chunk: chunk}}
+		})
+	return download(stream.Recv)
+}
+
+func process(t *testing.T, client pdfv1.PdfServiceClient, options *pdfv1.ProcessOptions, content []byte) (*pdfv1.FileInfo, []byte, error) {
+	stream, err := client.Process(context.Background())
+	require.NoError(t, err)
+
+	upload(t, stream.Send, stream.CloseSend,
+		&pdfv1.ProcessRequest{Data: &pdfv1.ProcessRequest_Options{Options: options}},
+		content,
+		func(chunk []byte) *pdfv1.ProcessRequest {
+			return &pdfv1.ProcessRequest{Data: &pdfv1.ProcessRequest_Chunk{Chunk: chunk}}
+		})
+	return download(stream.Recv)
+}
+
+func TestPdfServer(t *testing.T) {
+	pdfContent, err := os.ReadFile("../resource/test.pdf")
+	require.NoError(t, err)
+	result := bytes.Repeat([]byte("result"), 50000)
+
+	t.Run("when compress success should stream the result back in chunks", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("Spool", mock.Anything, mock.Anything).Return(spoolTo(t.TempDir()), nil, nil).Once()
+		mockPdfSvc.On("CompressPdf", mock.Anything, "report.pdf", mock.Anything).Run(func(args mock.Arguments) {
+			received, _ := io.ReadAll(args.Get(2).(io.Reader))
+			assert.Equal(t, pdfContent, received)
+		}).Return(domain.PdfFile{
+			Name:        "compressed_report.pdf",
+			Content:     io.NopCloser(bytes.NewReader(result)),
+			Size:        int64(len(result)),
+			CacheStatus: "MISS",
+		}, nil).Once()
+
+		info, content, err := compress(t, newPdfClient(t, mockPdfSvc), "uploads/report.pdf", pdfContent)
+
+		require.NoError(t, err)
+		assert.Equal(t, "compressed_report.pdf", info.FileName)
+		assert.Equal(t, "application/pdf", info.ContentType)
+		assert.Equal(t, int64(len(result)), info.Size)
+		assert.Equal(t, "MISS", info.CacheStatus)
+		assert.Equal(t, result, content)
+		mockPdfSvc.AssertExpectations(t)
+	})
+
+	t.Run("when split by ranges success should return the split document", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("Spool", mock.Anything, mock.Anything).Return(spoolTo(t.TempDir()), nil, nil).Once()
+		mockPdfSvc.On("PageCount", mock.Anything, mock.Anything).Return(12, nil).Once()
+		mockPdfSvc.On("SplitPdfByRanges", mock.Anything, "document.pdf", mock.Anything, []int{1, 2, 3, 5}).Return(domain.PdfFile{
+			Name:    "split_document.pdf",
+			Content: io.NopCloser(bytes.NewReader([]byte{1})),
+			Size:    1,
+		}, nil).Once()
+
+		info, content, err := split(t, newPdfClient(t, mockPdfSvc),
+			&pdfv1.SplitOptions{Mode: &pdfv1.SplitOptions_Ranges{Ranges: "1-3,5"}}, pdfContent)
+
+		require.NoError(t, err)
+		assert.Equal(t, "split_document.pdf", info.FileName)
+		assert.Equal(t, []byte{1}, content)
+		mockPdfSvc.AssertExpectations(t)
+	})
+
+	t.Run("when split by fixed range success should return a zip", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("Spool", mock.Anything, mock.Anything).Return(spoolTo(t.TempDir()), nil, nil).Once()
+		mockPdfSvc.On("PageCount", mock.Anything, mock.Anything).Return(5, nil).Once()
+		mockPdfSvc.On("SplitAndZipPdfByFixedRange", mock.Anything, "a.pdf", mock.Anything, [][]int{{1, 2}, {3, 4}, {5}}, domain.SplitNaming{}).Return(domain.PdfFile{
+			Name:    "split_a.pdf.zip",
+			Content: io.NopCloser(bytes.NewReader([]byte{1})),
+			Size:    1,
+		}, nil).Once()
+
+		info, _, err := split(t, newPdfClient(t, mockPdfSvc),
+			&pdfv1.SplitOptions{FileName: "a.pdf", Mode: &pdfv1.SplitOptions_FixedRange{FixedRange: 2}}, pdfContent)
+
+		require.NoError(t, err)
+		assert.Equal(t, "application/zip", info.ContentType)
+		mockPdfSvc.AssertExpectations(t)
+	})
+
+	t.Run("when remove pages success should return the remaining pages", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("Spool", mock.Anything, mock.Anything).Return(spoolTo(t.TempDir()), nil, nil).Once()
+		mockPdfSvc.On("PageCount", mock.Anything, mock.Anything).Return(12, nil).Once()
+		mockPdfSvc.On("RemovePagesPdf", mock.Anything, "a.pdf", mock.Anything, []int{2}, 12).Return(domain.PdfFile{
+			Name:    "split_a.pdf",
+			Content: io.NopCloser(bytes.NewReader([]byte{1})),
+			Size:    1,
+		}, nil).Once()
+
+		stream, err := newPdfClient(t, mockPdfSvc).RemovePages(context.Background())
+		require.NoError(t, err)
+		upload(t, stream.Send, stream.CloseSend,
+			&pdfv1.RemovePagesRequest{Data: &pdfv1.RemovePagesRequest_Options{Options: &pdfv1.RemovePagesOptions{FileName: "a.pdf", Pages: "2"}}},
+			pdfContent,
+			func(chunk []byte) *pdfv1.RemovePagesRequest {
+				return &pdfv1.RemovePagesRequest{Data: &pdfv1.RemovePagesRequest_Chunk{Chunk: chunk}}
+			})
+		info, _, err := download(stream.Recv)
+
+		require.NoError(t, err)
+		assert.Equal(t, "split_a.pdf", info.FileName)
+		mockPdfSvc.AssertExpectations(t)
+	})
+
+	t.Run("when the split mode is named should read its parameters from params", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("Spool", mock.Anything, mock.Anything).Return(spoolTo(t.TempDir()), nil, nil).Once()
+		mockPdfSvc.On("PageCount", mock.Anything, mock.Anything).Return(12, nil).Once()
+		mockPdfSvc.On("RemovePagesPdf", mock.Anything, "a.pdf", mock.Anything, []int{2, 3}, 12).Return(domain.PdfFile{
+			Name:    "split_a.pdf",
+			Content: io.NopCloser(bytes.NewReader([]byte{1})),
+			Size:    1,
+		}, nil).Once()
+
+		info, _, err := split(t, newPdfClient(t, mockPdfSvc), &pdfv1.SplitOptions{
+			FileName: "a.pdf",
+			Mode:     &pdfv1.SplitOptions_NamedMode{NamedMode: pdf.SplitModeRemovePages},
+			Params:   map[string]string{"remove_page": "2-3"},
+		}, pdfContent)
+
+		require.NoError(t, err)
+		assert.Equal(t, "split_a.pdf", info.FileName)
+		mockPdfSvc.AssertExpectations(t)
+	})
+
+	t.Run("when a processor is run by name should return its result", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("Spool", mock.Anything, mock.Anything).Return(spoolTo(t.TempDir()), nil, nil).Once()
+		mockPdfSvc.On("CompressPdf", mock.Anything, "a.pdf", mock.Anything).Return(domain.PdfFile{
+			Name:    "compressed_a.pdf",
+			Content: io.NopCloser(bytes.NewReader([]byte{1})),
+			Size:    1,
+		}, nil).Once()
+
+		info, _, err := process(t, newPdfClient(t, mockPdfSvc), &pdfv1.ProcessOptions{FileName: "a.pdf", Operation: pdf.OperationCompress}, pdfContent)
+
+		require.NoError(t, err)
+		assert.Equal(t, "compressed_a.pdf", info.FileName)
+	})
+
+	t.Run("when the processor is unknown should return NotFound", func(t *testing.T) {
+		_, _, err := process(t, newPdfClient(t, new(mocks.PdfService)), &pdfv1.ProcessOptions{Operation: "shred"}, pdfContent)
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("when counting pages should return the page count", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("Spool", mock.Anything, mock.Anything).Return(spoolTo(t.TempDir()), nil, nil).Once()
+		mockPdfSvc.On("PageCount", mock.Anything, mock.Anything).Return(12, nil).Once()
+
+		stream, err := newPdfClient(t, mockPdfSvc).PageCount(context.Background())
+		require.NoError(t, err)
+		upload(t, stream.Send, func() error { return nil },
+			&pdfv1.PageCountRequest{Data: &pdfv1.PageCountRequest_Options{Options: &pdfv1.PageCountOptions{}}},
+			pdfContent,
+			func(chunk []byte) *pdfv1.PageCountRequest {
+				return &pdfv1.PageCountRequest{Data: &pdfv1.PageCountRequest_Chunk{Chunk: chunk}}
+			})
+		resp, err := stream.CloseAndRecv()
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(12), resp.Pages)
+	})
+
+	t.Run("when parameters are invalid should return InvalidArgument before reading the upload", func(t *testing.T) {
+		for _, options := range []*pdfv1.SplitOptions{
+			{},
+			{Mode: &pdfv1.SplitOptions_Ranges{Ranges: "3-1"}},
+			{Mode: &pdfv1.SplitOptions_FixedRange{FixedRange: 0}},
+			{Mode: &pdfv1.SplitOptions_RemovePages{RemovePages: ""}},
+			{Mode: &pdfv1.SplitOptions_NamedMode{NamedMode: "shred"}},
+		} {
+			_, _, err := split(t, newPdfClient(t, new(mocks.PdfService)), options, pdfContent)
+
+			assert.Equal(t, codes.InvalidArgument, status.Code(err), options.String())
+		}
+	})
+
+	t.Run("when ranges exceed the page count should return InvalidArgument", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("Spool", mock.Anything, mock.Anything).Return(spoolTo(t.TempDir()), nil, nil).Once()
+		mockPdfSvc.On("PageCount", mock.Anything, mock.Anything).Return(2, nil).Once()
+
+		_, _, err := split(t, newPdfClient(t, mockPdfSvc),
+			&pdfv1.SplitOptions{Mode: &pdfv1.SplitOptions_Ranges{Ranges: "1-3"}}, pdfContent)
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, "Ranges exceed page count", status.Convert(err).Message())
+	})
+
+	t.Run("when upload is not a pdf should return InvalidArgument", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("Spool", mock.Anything, mock.Anything).Return(spoolTo(t.TempDir()), nil, nil).Once()
+
+		_, _, err := compress(t, newPdfClient(t, mockPdfSvc), "a.pdf", []byte("<html></html>"))
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, domain.ErrUnsupportedMediaType.Error(), status.Convert(err).Message())
+	})
+
+	t.Run("when upload is missing should return InvalidArgument", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("Spool", mock.Anything, mock.Anything).Return(spoolTo(t.TempDir()), nil, nil).Once()
+
+		_, _, err := compress(t, newPdfClient(t, mockPdfSvc), "a.pdf", nil)
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, domain.ErrMissingFile.Error(), status.Convert(err).Message())
+	})
+
+	t.Run("when upload exceeds the limit should return ResourceExhausted", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("Spool", mock.Anything, mock.Anything).Return(spoolTo(t.TempDir()), nil, nil).Once()
+
+		_, _, err := compress(t, newPdfClient(t, mockPdfSvc, rpc.WithUploadLimits(rpc.UploadLimits{Compress: 1024})), "a.pdf", pdfContent)
+
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+
+	t.Run("when service is overloaded should return Unavailable with a retry delay", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("Spool", mock.Anything, mock.Anything).Return(spoolTo(t.TempDir()), nil, nil).Once()
+		mockPdfSvc.On("CompressPdf", mock.Anything, mock.Anything, mock.Anything).
+			Return(domain.PdfFile{}, &domain.OverloadedError{RetryAfter: 3 * time.Second}).Once()
+
+		_, _, err := compress(t, newPdfClient(t, mockPdfSvc), "a.pdf", pdfContent)
+
+		st := status.Convert(err)
+		assert.Equal(t, codes.Unavailable, st.Code())
+		require.Len(t, st.Details(), 1)
+		assert.Equal(t, 3*time.Second, st.Details()[0].(*errdetails.RetryInfo).RetryDelay.AsDuration())
+	})
+
+	t.Run("when service fails should return Internal without the details", func(t *testing.T) {
+		mockPdfSvc := new(mocks.PdfService)
+		mockPdfSvc.On("Spool", mock.Anything, mock.Anything).Return(spoolTo(t.TempDir()), nil, nil).Once()
+		mockPdfSvc.On("CompressPdf", mock.Anything, mock.Anything, mock.Anything).
+			Return(domain.PdfFile{}, errors.New("pdfcpu: /tmp/ws/input.pdf is broken")).Once()
+
+		_, _, err := compress(t, newPdfClient(t, mockPdfSvc), "a.pdf", pdfContent)
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.Equal(t, "Failed to compress PDF", status.Convert(err).Message())
+	})
+}
@@ -0,0 +1,192 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/grpc"
+
+	pdfv1 "github.com/bxcodec/go-clean-arch/api/pdf/v1"
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// chunkSize is how much of a result goes into one message, well below the default
+// message limit of 4 MiB
+const chunkSize = 64 << 10
+
+// pdfMagic must appear within the first pdfMagicWindow bytes of a document. PDF readers
+// tolerate junk before the header, so the check does too.
+var pdfMagic = []byte("%PDF-")
+
+const pdfMagicWindow = 1024
+
+// optionsMessage is the first message of an upload stream
+type optionsMessage[O any] interface {
+	GetOptions() O
+}
+
+// chunkMessage is a message carrying part of an uploaded document
+type chunkMessage interface {
+	GetChunk() []byte
+}
+
+// receiveOptions reads the first message of an upload stream, which has to carry the
+// options of the call
+func receiveOptions[Req optionsMessage[O], O comparable](recv func() (Req, error)) (O, error) {
+	var none O
+
+	req, err := recv()
+	if errors.Is(err, io.EOF) {
+		return none, domain.ErrMissingFile
+	}
+	if err != nil {
+		return none, err
+	}
+
+	options := req.GetOptions()
+	if options == none {
+		return none, paramError("The first message must carry the options")
+	}
+	return options, nil
+}
+
+// chunks returns the document chunks of the messages recv reads
+func chunks[Req chunkMessage](recv func() (Req, error)) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		req, err := recv()
+		if err != nil {
+			return nil, err
+		}
+		return req.GetChunk(), nil
+	}
+}
+
+// openUpload spools the chunks received until the client closes its side of the
+// stream and makes sure they make up a PDF document
+func openUpload(ctx context.Context, svc PdfService, recv func() ([]byte, error), maxBytes int64) (domain.SpooledContent, error) {
+	var content io.Reader = &chunkReader{recv: recv}
+	if maxBytes > 0 {
+		content = &limitReader{r: content, remaining: maxBytes}
+	}
+
+	file, size, err := svc.Spool(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		file.Close()
+		return nil, domain.ErrMissingFile
+	}
+
+	if err := sniffPdf(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+// chunkReader reads the chunks of an upload stream as one document
+type chunkReader struct {
+	recv    func() ([]byte, error)
+	pending []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		chunk, err := r.recv()
+		if err != nil {
+			return 0, err
+		}
+		r.pending = chunk
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// limitReader fails with domain.ErrFileTooLarge once more than remaining bytes arrive
+type limitReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return 0, domain.ErrFileTooLarge
+	}
+	return n, err
+}
+
+func sniffPdf(src io.ReadSeeker) error {
+	head := make([]byte, pdfMagicWindow)
+	n, err := io.ReadFull(src, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return fmt.Errorf("failed to read upload: %w", err)
+	}
+	if !bytes.Contains(head[:n], pdfMagic) {
+		return domain.ErrUnsupportedMediaType
+	}
+
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind upload: %w", err)
+	}
+	return nil
+}
+
+// uploadName is the file name the service names its result after. Clients may leave
+// it out, directories are dropped.
+func uploadName(fileName string) string {
+	name := filepath.Base(filepath.Clean("/" + fileName))
+	if name == "/" || name == "." {
+		name = "document"
+	}
+	if !strings.EqualFold(filepath.Ext(name), ".pdf") {
+		name += ".pdf"
+	}
+	return name
+}
+
+// sendFile streams file back, described by the first message and followed by its
+// content in chunks
+func sendFile(stream grpc.ServerStreamingServer[pdfv1.FileChunk], file domain.PdfFile) error {
+	defer file.Content.Close()
+
+	contentType := "application/pdf"
+	if strings.HasSuffix(file.Name, ".zip") {
+		contentType = "application/zip"
+	}
+
+	if err := stream.Send(&pdfv1.FileChunk{Data: &pdfv1.FileChunk_Info{Info: &pdfv1.FileInfo{
+		FileName:    file.Name,
+		ContentType: contentType,
+		Size:        file.Size,
+		CacheStatus: file.CacheStatus,
+	}}}); err != nil {
+		return err
+	}
+
+	for {
+		// a message may still be in use after Send returned, so every chunk gets its own buffer
+		chunk := make([]byte, chunkSize)
+		n, err := io.ReadFull(file.Content, chunk)
+		if n > 0 {
+			if err := stream.Send(&pdfv1.FileChunk{Data: &pdfv1.FileChunk_Chunk{Chunk: chunk[:n]}}); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read result: %w", err)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package workers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// Purger represent a scratch area that can drop stale entries and report its size
+//
+//go:generate mockery --name Purger
+type Purger interface {
+	Purge(olderThan time.Duration) (int, error)
+	Usage() (domain.DiskUsage, error)
+}
+
+// JanitorStats is the outcome of the latest sweep
+type JanitorStats struct {
+	domain.DiskUsage
+	Purged    int       `json:"purged"`
+	LastSweep time.Time `json:"last_sweep"`
+}
+
+// Janitor periodically purges entries older than a TTL from a Purger
+type Janitor struct {
+	purger   Purger
+	ttl      time.Duration
+	interval time.Duration
+
+	mu    sync.Mutex
+	stats JanitorStats
+}
+
+// NewJanitor will create a janitor that removes entries older than ttl every interval
+func NewJanitor(purger Purger, ttl, interval time.Duration) *Janitor {
+	return &Janitor{
+		purger:   purger,
+		ttl:      ttl,
+		interval: interval,
+	}
+}
+
+// Run sweeps once straight away, so orphans of a previous process are removed at
+// startup, and then on every interval until ctx is done.
+func (j *Janitor) Run(ctx context.Context) {
+	j.Sweep()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.Sweep()
+		}
+	}
+}
+
+// Sweep purges stale entries and refreshes the stats
+func (j *Janitor) Sweep() {
+	purged, err := j.purger.Purge(j.ttl)
+	if err != nil {
+		logrus.Error(err)
+	}
+
+	usage, err := j.purger.Usage()
+	if err != nil {
+		logrus.Error(err)
+	}
+
+	j.mu.Lock()
+	j.stats = JanitorStats{
+		DiskUsage: usage,
+		Purged:    j.stats.Purged + purged,
+		LastSweep: time.Now(),
+	}
+	j.mu.Unlock()
+
+	if purged > 0 {
+		logrus.Infof("janitor purged %d stale entries, %d remaining using %d bytes", purged, usage.Entries, usage.Bytes)
+	}
+}
+
+// Stats returns the disk usage seen by the latest sweep and the total purged so far
+func (j *Janitor) Stats() JanitorStats {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.stats
+}
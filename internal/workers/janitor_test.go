@@ -0,0 +1,43 @@
+package workers_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/internal/workers"
+	"github.com/bxcodec/go-clean-arch/internal/workers/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJanitorSweep(t *testing.T) {
+	t.Run("when sweep success should purge with ttl and record usage", func(t *testing.T) {
+		mockPurger := new(mocks.Purger)
+		mockPurger.On("Purge", time.Hour).Return(2, nil).Once()
+		mockPurger.On("Usage").Return(domain.DiskUsage{Entries: 1, Bytes: 10}, nil).Once()
+
+		janitor := workers.NewJanitor(mockPurger, time.Hour, time.Minute)
+		janitor.Sweep()
+
+		stats := janitor.Stats()
+		assert.Equal(t, 2, stats.Purged)
+		assert.Equal(t, domain.DiskUsage{Entries: 1, Bytes: 10}, stats.DiskUsage)
+		assert.False(t, stats.LastSweep.IsZero())
+		mockPurger.AssertExpectations(t)
+	})
+
+	t.Run("when purge failed should keep sweeping and accumulate purged count", func(t *testing.T) {
+		mockPurger := new(mocks.Purger)
+		mockPurger.On("Purge", time.Hour).Return(1, nil).Once()
+		mockPurger.On("Purge", time.Hour).Return(0, fmt.Errorf("Purge Error")).Once()
+		mockPurger.On("Usage").Return(domain.DiskUsage{}, nil).Twice()
+
+		janitor := workers.NewJanitor(mockPurger, time.Hour, time.Minute)
+		janitor.Sweep()
+		janitor.Sweep()
+
+		assert.Equal(t, 1, janitor.Stats().Purged)
+		mockPurger.AssertExpectations(t)
+	})
+}
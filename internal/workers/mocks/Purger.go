@@ -0,0 +1,85 @@
+// Code generated by mockery v2.50.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/bxcodec/go-clean-arch/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// Purger is an autogenerated mock type for the Purger type
+type Purger struct {
+	mock.Mock
+}
+
+// Purge provides a mock function with given fields: olderThan
+func (_m *Purger) Purge(olderThan time.Duration) (int, error) {
+	ret := _m.Called(olderThan)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Purge")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Duration) (int, error)); ok {
+		return rf(olderThan)
+	}
+	if rf, ok := ret.Get(0).(func(time.Duration) int); ok {
+		r0 = rf(olderThan)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Duration) error); ok {
+		r1 = rf(olderThan)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Usage provides a mock function with no fields
+func (_m *Purger) Usage() (domain.DiskUsage, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Usage")
+	}
+
+	var r0 domain.DiskUsage
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (domain.DiskUsage, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() domain.DiskUsage); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(domain.DiskUsage)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPurger creates a new instance of Purger. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPurger(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Purger {
+	mock := &Purger{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,171 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// Service follows the progress of running PDF jobs in memory. Clients may subscribe to
+// a job before it starts, and the last state of a finished job is kept for retention
+// so a late subscriber still learns how it ended.
+type Service struct {
+	retention time.Duration
+	now       func() time.Time
+
+	mu   sync.Mutex
+	jobs map[string]*entry
+}
+
+type entry struct {
+	progress    domain.JobProgress
+	started     bool
+	updatedAt   time.Time
+	subscribers map[chan domain.JobProgress]struct{}
+}
+
+// NewService will create a job service that forgets finished and never started jobs
+// after retention
+func NewService(retention time.Duration) *Service {
+	return &Service{
+		retention: retention,
+		now:       time.Now,
+		jobs:      make(map[string]*entry),
+	}
+}
+
+// Start registers job id and returns a context that reports the progress of whatever
+// runs with it. A job that is already running or finished is a domain.ErrConflict.
+func (s *Service) Start(ctx context.Context, id string) (context.Context, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeLocked()
+
+	job, ok := s.jobs[id]
+	if ok && job.started {
+		return nil, fmt.Errorf("%w: job %s already exists", domain.ErrConflict, id)
+	}
+	if !ok {
+		job = s.newEntryLocked(id)
+	}
+	job.started = true
+	s.publishLocked(job, domain.JobProgress{JobID: id, Step: domain.StepQueued})
+
+	return domain.WithProgress(ctx, func(step string, done, total int) {
+		s.report(id, step, done, total)
+	}), nil
+}
+
+// Finish publishes the outcome of job id and ends its subscriptions
+func (s *Service) Finish(id string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return
+	}
+
+	progress := job.progress
+	progress.Done = true
+	progress.Step = domain.StepDone
+	progress.Percent = 100
+	if err != nil {
+		progress.Step = domain.StepFailed
+		progress.Percent = job.progress.Percent
+		progress.Error = err.Error()
+	}
+	s.publishLocked(job, progress)
+
+	for subscriber := range job.subscribers {
+		close(subscriber)
+	}
+	job.subscribers = nil
+}
+
+// Subscribe returns the progress of job id, starting with its current state. Slow
+// readers only miss intermediate states. The channel is closed once the job finished
+// or ctx ended.
+func (s *Service) Subscribe(ctx context.Context, id string) (<-chan domain.JobProgress, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeLocked()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		job = s.newEntryLocked(id)
+	}
+
+	updates := make(chan domain.JobProgress, 1)
+	updates <- job.progress
+	if job.progress.Done {
+		close(updates)
+		return updates, nil
+	}
+
+	job.subscribers[updates] = struct{}{}
+	context.AfterFunc(ctx, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := job.subscribers[updates]; ok {
+			delete(job.subscribers, updates)
+			close(updates)
+		}
+	})
+	return updates, nil
+}
+
+func (s *Service) report(id, step string, done, total int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok || job.progress.Done {
+		return
+	}
+
+	progress := domain.JobProgress{JobID: id, Step: step, Pages: done, TotalPages: total}
+	if total > 0 {
+		progress.Percent = min(done*100/total, 100)
+	}
+	s.publishLocked(job, progress)
+}
+
+func (s *Service) newEntryLocked(id string) *entry {
+	job := &entry{
+		progress:    domain.JobProgress{JobID: id, Step: domain.StepPending},
+		updatedAt:   s.now(),
+		subscribers: make(map[chan domain.JobProgress]struct{}),
+	}
+	s.jobs[id] = job
+	return job
+}
+
+// publishLocked hands progress to every subscriber, replacing what a subscriber has
+// not read yet rather than waiting for it
+func (s *Service) publishLocked(job *entry, progress domain.JobProgress) {
+	job.progress = progress
+	job.updatedAt = s.now()
+
+	for subscriber := range job.subscribers {
+		select {
+		case <-subscriber:
+		default:
+		}
+		subscriber <- progress
+	}
+}
+
+// purgeLocked forgets finished jobs and jobs nobody started after retention
+func (s *Service) purgeLocked() {
+	cutoff := s.now().Add(-s.retention)
+	for id, job := range s.jobs {
+		finished := job.progress.Done || !job.started
+		if finished && len(job.subscribers) == 0 && job.updatedAt.Before(cutoff) {
+			delete(s.jobs, id)
+		}
+	}
+}
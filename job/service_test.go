@@ -0,0 +1,111 @@
+package job_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/job"
+)
+
+func collect(updates <-chan domain.JobProgress) []domain.JobProgress {
+	var all []domain.JobProgress
+	for progress := range updates {
+		all = append(all, progress)
+	}
+	return all
+}
+
+func TestService(t *testing.T) {
+	t.Run("when subscribed before start should receive every step until done", func(t *testing.T) {
+		svc := job.NewService(time.Minute)
+		updates, err := svc.Subscribe(context.TODO(), "job-1")
+		require.NoError(t, err)
+		assert.Equal(t, domain.StepPending, (<-updates).Step)
+
+		ctx, err := svc.Start(context.TODO(), "job-1")
+		require.NoError(t, err)
+		assert.Equal(t, domain.StepQueued, (<-updates).Step)
+
+		domain.ReportProgress(ctx, domain.StepSplitting, 5, 20)
+		assert.Equal(t, domain.JobProgress{JobID: "job-1", Step: domain.StepSplitting, Pages: 5, TotalPages: 20, Percent: 25}, <-updates)
+
+		svc.Finish("job-1", nil)
+		assert.Equal(t, []domain.JobProgress{
+			{JobID: "job-1", Step: domain.StepDone, Pages: 5, TotalPages: 20, Percent: 100, Done: true},
+		}, collect(updates))
+	})
+
+	t.Run("when subscriber falls behind should only keep the latest progress", func(t *testing.T) {
+		svc := job.NewService(time.Minute)
+		ctx, _ := svc.Start(context.TODO(), "job-1")
+		updates, _ := svc.Subscribe(context.TODO(), "job-1")
+
+		for page := 1; page <= 10; page++ {
+			domain.ReportProgress(ctx, domain.StepMerging, page, 10)
+		}
+
+		assert.Equal(t, 10, (<-updates).Pages)
+	})
+
+	t.Run("when subscribed after the job failed should receive the outcome", func(t *testing.T) {
+		svc := job.NewService(time.Minute)
+		svc.Start(context.TODO(), "job-1")
+		svc.Finish("job-1", errors.New("the server is busy, try again later"))
+
+		updates, err := svc.Subscribe(context.TODO(), "job-1")
+		require.NoError(t, err)
+
+		all := collect(updates)
+		require.Len(t, all, 1)
+		assert.True(t, all[0].Done)
+		assert.Equal(t, domain.StepFailed, all[0].Step)
+		assert.Equal(t, "the server is busy, try again later", all[0].Error)
+	})
+
+	t.Run("when job id is taken should return ErrConflict", func(t *testing.T) {
+		svc := job.NewService(time.Minute)
+		_, err := svc.Start(context.TODO(), "job-1")
+		require.NoError(t, err)
+
+		_, err = svc.Start(context.TODO(), "job-1")
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+	})
+
+	t.Run("when subscriber context ends should close the channel", func(t *testing.T) {
+		svc := job.NewService(time.Minute)
+		ctx, cancel := context.WithCancel(context.TODO())
+		updates, _ := svc.Subscribe(ctx, "job-1")
+		<-updates
+
+		cancel()
+
+		select {
+		case _, ok := <-updates:
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("subscription was not closed")
+		}
+	})
+
+	t.Run("when progress is muted should not report", func(t *testing.T) {
+		svc := job.NewService(time.Minute)
+		ctx, _ := svc.Start(context.TODO(), "job-1")
+		updates, _ := svc.Subscribe(context.TODO(), "job-1")
+		<-updates
+
+		domain.ReportProgress(domain.WithProgress(ctx, nil), domain.StepMerging, 1, 2)
+
+		select {
+		case progress := <-updates:
+			t.Fatalf("unexpected progress %+v", progress)
+		default:
+		}
+	})
+}
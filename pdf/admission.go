@@ -0,0 +1,207 @@
+package pdf
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// DefaultCosts weigh operations by the CPU and memory they take, relative to counting
+// pages. Operations not listed cost 1. Analyze compresses the document as well, so it
+// costs what compress does on top of its own.
+var DefaultCosts = map[string]int64{
+	"compress":   4,
+	"split_zip":  4,
+	"split":      2,
+	"merge":      2,
+	"compare":    2,
+	"crop":       2,
+	"insert":     2,
+	"collate":    2,
+	"analyze":    6,
+	"page_count": 1,
+}
+
+// AdmissionConfig bounds the work a Service runs at once
+type AdmissionConfig struct {
+	// Capacity is the total cost of the operations allowed to run at the same time
+	Capacity int64
+	// MaxQueue is how many requests may wait for capacity before new ones are rejected
+	MaxQueue int
+	// MaxWait is how long a request waits for capacity, zero waits as long as its context
+	MaxWait time.Duration
+	// Costs per operation name, DefaultCosts when nil
+	Costs map[string]int64
+}
+
+// AdmissionStats tells how busy the Service is
+type AdmissionStats struct {
+	Capacity  int64   `json:"capacity"`
+	InFlight  int64   `json:"in_flight"`
+	Queued    int64   `json:"queued"`
+	Admitted  int64   `json:"admitted"`
+	Rejected  int64   `json:"rejected"`
+	AvgWaitMs float64 `json:"avg_wait_ms"`
+	LastWait  string  `json:"last_wait"`
+}
+
+// Admission is a weighted semaphore with a bounded wait queue. Requests are admitted
+// in arrival order; once the queue is full, or a request waited MaxWait, it is turned
+// away with a domain.OverloadedError instead of piling up.
+type Admission struct {
+	config AdmissionConfig
+	sem    *semaphore.Weighted
+
+	mu        sync.Mutex
+	inFlight  int64
+	queued    int64
+	admitted  int64
+	rejected  int64
+	totalWait time.Duration
+	lastWait  time.Duration
+	avgHold   time.Duration
+}
+
+func NewAdmission(config AdmissionConfig) *Admission {
+	if config.Costs == nil {
+		config.Costs = DefaultCosts
+	}
+	return &Admission{
+		config: config,
+		sem:    semaphore.NewWeighted(config.Capacity),
+	}
+}
+
+// WithAdmission makes every operation of the Service wait for admission first
+func WithAdmission(admission *Admission) Option {
+	return func(s *Service) {
+		s.admission = admission
+	}
+}
+
+func (a *Admission) Stats() AdmissionStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	stats := AdmissionStats{
+		Capacity: a.config.Capacity,
+		InFlight: a.inFlight,
+		Queued:   a.queued,
+		Admitted: a.admitted,
+		Rejected: a.rejected,
+		LastWait: a.lastWait.String(),
+	}
+	if a.admitted > 0 {
+		stats.AvgWaitMs = float64(a.totalWait.Milliseconds()) / float64(a.admitted)
+	}
+	return stats
+}
+
+// acquire waits until op fits and returns the func that gives its capacity back
+func (a *Admission) acquire(ctx context.Context, op string) (func(), error) {
+	cost := a.cost(op)
+	start := time.Now()
+
+	if !a.sem.TryAcquire(cost) {
+		a.mu.Lock()
+		if a.queued >= int64(a.config.MaxQueue) {
+			defer a.mu.Unlock()
+			return nil, a.rejectLocked()
+		}
+		a.queued++
+		a.mu.Unlock()
+
+		waitCtx, cancel := ctx, context.CancelFunc(func() {})
+		if a.config.MaxWait > 0 {
+			waitCtx, cancel = context.WithTimeout(ctx, a.config.MaxWait)
+		}
+		err := a.sem.Acquire(waitCtx, cost)
+		cancel()
+
+		a.mu.Lock()
+		a.queued--
+		if err != nil {
+			defer a.mu.Unlock()
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, a.rejectLocked()
+		}
+		a.mu.Unlock()
+	}
+
+	wait := time.Since(start)
+	a.mu.Lock()
+	a.inFlight += cost
+	a.admitted++
+	a.totalWait += wait
+	a.lastWait = wait
+	a.mu.Unlock()
+
+	admitted := time.Now()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			a.sem.Release(cost)
+
+			a.mu.Lock()
+			defer a.mu.Unlock()
+			a.inFlight -= cost
+			// moving average of how long an operation holds its capacity
+			a.avgHold += (time.Since(admitted) - a.avgHold) / 8
+		})
+	}, nil
+}
+
+// execute runs op once it is admitted. A result of unknown size is still being produced
+// while it is read, so it keeps its capacity until it is closed.
+func (a *Service) execute(ctx context.Context, op operation, exec func() (io.ReadCloser, int64, error)) (io.ReadCloser, int64, error) {
+	if a.admission == nil {
+		return exec()
+	}
+
+	domain.ReportProgress(ctx, domain.StepQueued, 0, 1)
+	release, err := a.admission.acquire(ctx, op.name)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	content, size, err := exec()
+	if err != nil || size >= 0 {
+		release()
+		return content, size, err
+	}
+	return &releaseOnClose{ReadCloser: content, release: release}, size, nil
+}
+
+// rejectLocked counts a rejection and suggests coming back after about one operation
+func (a *Admission) rejectLocked() error {
+	a.rejected++
+	return &domain.OverloadedError{RetryAfter: max(a.avgHold, time.Second)}
+}
+
+func (a *Admission) cost(op string) int64 {
+	cost, ok := a.config.Costs[op]
+	if !ok {
+		cost = 1
+	}
+	// an operation costing more than the capacity would never be admitted
+	return min(cost, a.config.Capacity)
+}
+
+// releaseOnClose holds the capacity of an operation whose result is still produced
+// while it is read
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	defer r.release()
+	return r.ReadCloser.Close()
+}
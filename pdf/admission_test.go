@@ -0,0 +1,135 @@
+package pdf_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/pdf"
+	"github.com/bxcodec/go-clean-arch/pdf/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdmission(t *testing.T) {
+	// blockingCompress makes the next Compress hold its capacity until unblock is closed
+	blockingCompress := func(mockPdfRepo *mocks.PdfRepository) (started, unblock chan struct{}) {
+		started, unblock = make(chan struct{}), make(chan struct{})
+		mockPdfRepo.On("Compress", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			close(started)
+			<-unblock
+		}).Return(io.NopCloser(bytes.NewReader([]byte{1})), int64(1), nil).Once()
+		return started, unblock
+	}
+
+	compress := func(service *pdf.Service) chan error {
+		done := make(chan error, 1)
+		go func() {
+			_, err := service.CompressPdf(context.TODO(), "input.pdf", bytes.NewReader([]byte{1}))
+			done <- err
+		}()
+		return done
+	}
+
+	t.Run("when queue is full should reject with retry hint", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		admission := pdf.NewAdmission(pdf.AdmissionConfig{Capacity: 4, MaxQueue: 0})
+		service := pdf.NewService(mockPdfRepo, pdf.WithAdmission(admission))
+
+		started, unblock := blockingCompress(mockPdfRepo)
+		first := compress(service)
+		<-started
+
+		_, err := service.PageCount(context.TODO(), bytes.NewReader([]byte{1}))
+
+		assert.ErrorIs(t, err, domain.ErrOverloaded)
+		var overloaded *domain.OverloadedError
+		require.ErrorAs(t, err, &overloaded)
+		assert.GreaterOrEqual(t, overloaded.RetryAfter, time.Second)
+		assert.Equal(t, int64(1), admission.Stats().Rejected)
+
+		close(unblock)
+		assert.NoError(t, <-first)
+		assert.Equal(t, int64(0), admission.Stats().InFlight)
+	})
+
+	t.Run("when waiting longer than max wait should reject", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		admission := pdf.NewAdmission(pdf.AdmissionConfig{Capacity: 4, MaxQueue: 1, MaxWait: 20 * time.Millisecond})
+		service := pdf.NewService(mockPdfRepo, pdf.WithAdmission(admission))
+
+		started, unblock := blockingCompress(mockPdfRepo)
+		first := compress(service)
+		<-started
+
+		err := <-compress(service)
+
+		assert.ErrorIs(t, err, domain.ErrOverloaded)
+		close(unblock)
+		assert.NoError(t, <-first)
+	})
+
+	t.Run("when capacity frees up should admit the queued request", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		admission := pdf.NewAdmission(pdf.AdmissionConfig{Capacity: 4, MaxQueue: 1})
+		service := pdf.NewService(mockPdfRepo, pdf.WithAdmission(admission))
+
+		started, unblock := blockingCompress(mockPdfRepo)
+		first := compress(service)
+		<-started
+		mockPdfRepo.On("PageCount", mock.Anything, mock.Anything).Return(3, nil).Once()
+
+		second := make(chan int, 1)
+		go func() {
+			count, _ := service.PageCount(context.TODO(), bytes.NewReader([]byte{1}))
+			second <- count
+		}()
+		assert.Eventually(t, func() bool { return admission.Stats().Queued == 1 }, time.Second, time.Millisecond)
+
+		close(unblock)
+		assert.NoError(t, <-first)
+		assert.Equal(t, 3, <-second)
+		assert.Equal(t, int64(2), admission.Stats().Admitted)
+	})
+
+	t.Run("when context is canceled while queued should return the context error", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		admission := pdf.NewAdmission(pdf.AdmissionConfig{Capacity: 4, MaxQueue: 1})
+		service := pdf.NewService(mockPdfRepo, pdf.WithAdmission(admission))
+
+		started, unblock := blockingCompress(mockPdfRepo)
+		first := compress(service)
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+		defer cancel()
+		_, err := service.PageCount(ctx, bytes.NewReader([]byte{1}))
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, int64(0), admission.Stats().Rejected)
+		close(unblock)
+		assert.NoError(t, <-first)
+	})
+
+	t.Run("when result is streamed should hold capacity until closed", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		admission := pdf.NewAdmission(pdf.AdmissionConfig{Capacity: 4, MaxQueue: 0})
+		service := pdf.NewService(mockPdfRepo, pdf.WithAdmission(admission))
+
+		mockPdfRepo.On("SplitRanges", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(func(_ context.Context, _ io.ReadSeeker, _ [][]int, emit func(int, io.Reader, int64) error) error {
+			return emit(0, bytes.NewReader([]byte{1}), 1)
+		})
+
+		actual, err := service.SplitAndZipPdfByFixedRange(context.TODO(), "test.pdf", bytes.NewReader([]byte{1}), [][]int{{1}, {2}}, domain.SplitNaming{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), admission.Stats().InFlight)
+
+		io.Copy(io.Discard, actual.Content)
+		actual.Content.Close()
+		assert.Equal(t, int64(0), admission.Stats().InFlight)
+	})
+}
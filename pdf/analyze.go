@@ -0,0 +1,66 @@
+package pdf
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// minSavingShare is the share of the file size a setting has to save to be recommended
+const minSavingShare = 0.02
+
+// AnalyzePdf reports how file is built and which compression setting saves the most
+// as JSON. What compress saves is measured by compressing file.
+func (a *Service) AnalyzePdf(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+	outputName := "analysis_" + strings.TrimSuffix(fileName, filepath.Ext(fileName)) + ".json"
+
+	return a.run(ctx, operation{name: "analyze"}, outputName, file, func() (io.ReadCloser, int64, error) {
+		domain.ReportProgress(ctx, domain.StepAnalyzing, 0, 1)
+		analysis, err := a.pdfRepo.Analyze(ctx, file)
+		if err != nil {
+			return nil, 0, err
+		}
+		domain.ReportProgress(ctx, domain.StepAnalyzing, 1, 1)
+
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return nil, 0, fmt.Errorf("failed to rewind pdf: %w", err)
+		}
+		compressed, size, err := a.pdfRepo.Compress(ctx, file)
+		if err != nil {
+			return nil, 0, err
+		}
+		compressed.Close()
+
+		analysis.Name = fileName
+		analysis.Savings = append(analysis.Savings, domain.PdfSaving{Setting: domain.SettingCompress, Bytes: max(analysis.FileSize-size, 0)})
+		recommend(&analysis)
+
+		report, err := json.MarshalIndent(analysis, "", "  ")
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to encode analysis: %w", err)
+		}
+		return io.NopCloser(bytes.NewReader(report)), int64(len(report)), nil
+	})
+}
+
+// recommend orders the savings of analysis, the most first, and recommends the first
+// unless it saves too little to be worth it
+func recommend(analysis *domain.PdfAnalysis) {
+	slices.SortStableFunc(analysis.Savings, func(x, y domain.PdfSaving) int { return cmp.Compare(y.Bytes, x.Bytes) })
+
+	analysis.Recommendation = domain.SettingNone
+	if len(analysis.Savings) == 0 {
+		return
+	}
+	if best := analysis.Savings[0]; best.Bytes > 0 && float64(best.Bytes) >= minSavingShare*float64(analysis.FileSize) {
+		analysis.Recommendation = best.Setting
+	}
+}
@@ -0,0 +1,83 @@
+package pdf_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/pdf"
+	"github.com/bxcodec/go-clean-arch/pdf/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzePdf(t *testing.T) {
+	analyzed := func(savings ...domain.PdfSaving) domain.PdfAnalysis {
+		return domain.PdfAnalysis{FileSize: 1000, Pages: 2, Objects: 12, Savings: savings}
+	}
+	compressed := func(size int64) (io.ReadCloser, int64, error) {
+		return io.NopCloser(strings.NewReader("%PDF")), size, nil
+	}
+	report := func(t *testing.T, file domain.PdfFile) domain.PdfAnalysis {
+		var analysis domain.PdfAnalysis
+		require.NoError(t, json.NewDecoder(file.Content).Decode(&analysis))
+		return analysis
+	}
+
+	t.Run("when compress saves the most should recommend it", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		service := pdf.NewService(mockPdfRepo)
+		mockPdfRepo.On("Analyze", mock.Anything, mock.Anything).Return(analyzed(
+			domain.PdfSaving{Setting: domain.SettingFlate, Bytes: 50},
+			domain.PdfSaving{Setting: domain.SettingJpeg, Bytes: 0},
+		), nil).Once()
+		mockPdfRepo.On("Compress", mock.Anything, mock.Anything).Return(compressed(700)).Once()
+
+		actual, err := service.AnalyzePdf(context.TODO(), "export.pdf", strings.NewReader("%PDF"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "analysis_export.json", actual.Name)
+		analysis := report(t, actual)
+		assert.Equal(t, "export.pdf", analysis.Name)
+		assert.Equal(t, domain.SettingCompress, analysis.Recommendation)
+		assert.Equal(t, []domain.PdfSaving{
+			{Setting: domain.SettingCompress, Bytes: 300},
+			{Setting: domain.SettingFlate, Bytes: 50},
+			{Setting: domain.SettingJpeg, Bytes: 0},
+		}, analysis.Savings)
+		mockPdfRepo.AssertExpectations(t)
+	})
+
+	t.Run("when nothing saves enough should recommend none", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		service := pdf.NewService(mockPdfRepo)
+		mockPdfRepo.On("Analyze", mock.Anything, mock.Anything).Return(analyzed(
+			domain.PdfSaving{Setting: domain.SettingFlate, Bytes: 5},
+		), nil).Once()
+		// an optimized copy may well be larger
+		mockPdfRepo.On("Compress", mock.Anything, mock.Anything).Return(compressed(1010)).Once()
+
+		actual, err := service.AnalyzePdf(context.TODO(), "export.pdf", strings.NewReader("%PDF"))
+
+		require.NoError(t, err)
+		analysis := report(t, actual)
+		assert.Equal(t, domain.SettingNone, analysis.Recommendation)
+		assert.Equal(t, domain.PdfSaving{Setting: domain.SettingCompress, Bytes: 0}, analysis.Savings[1])
+	})
+
+	t.Run("when analysis failed should return error", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		service := pdf.NewService(mockPdfRepo)
+		mockPdfRepo.On("Analyze", mock.Anything, mock.Anything).Return(domain.PdfAnalysis{}, errors.New("Analyze Error")).Once()
+
+		_, err := service.AnalyzePdf(context.TODO(), "export.pdf", strings.NewReader("%PDF"))
+
+		assert.Error(t, err)
+		mockPdfRepo.AssertNotCalled(t, "Compress", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,156 @@
+package pdf
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// AuditRepository represent the storage of the audit log
+//
+//go:generate mockery --name AuditRepository
+type AuditRepository interface {
+	Store(ctx context.Context, record *domain.PdfAuditRecord) error
+	// Fetch lists the records matching filter from the oldest on, starting after cursor
+	Fetch(ctx context.Context, filter domain.PdfAuditFilter, cursor string, num int64) ([]domain.PdfAuditRecord, string, error)
+}
+
+// PageCounter counts the pages of a document
+type PageCounter interface {
+	PageCount(ctx context.Context, file io.ReadSeeker) (int, error)
+}
+
+// AuditLog records every document that goes through an operation, so it can be
+// proven which documents were processed, by whom and when
+type AuditLog struct {
+	repo  AuditRepository
+	pages PageCounter
+	now   func() time.Time
+}
+
+// NewAuditLog will create an audit log stored in repo. pages counts the pages of the
+// inputs, usually the Service.
+func NewAuditLog(repo AuditRepository, pages PageCounter) *AuditLog {
+	return &AuditLog{
+		repo:  repo,
+		pages: pages,
+		now:   time.Now,
+	}
+}
+
+// Audited wraps op so that every document it processes is recorded along with call.
+// A result is recorded once its content is closed, so the record tells how much of
+// it was delivered. Failing to store a record is logged, the document was processed
+// already.
+func (l *AuditLog) Audited(op domain.PdfProcessor, call domain.PdfAuditCall) domain.PdfProcessor {
+	return func(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+		start := l.now()
+		record := &domain.PdfAuditRecord{
+			Caller:    call.Caller,
+			Operation: call.Operation,
+			Params:    call.Params,
+			FileName:  fileName,
+		}
+
+		if err := l.describeInput(ctx, record, file); err != nil {
+			return domain.PdfFile{}, err
+		}
+
+		result, err := op(ctx, fileName, file)
+		if err != nil {
+			record.Outcome = domain.AuditFailed
+			record.Error = err.Error()
+			l.store(ctx, record, start)
+			return domain.PdfFile{}, err
+		}
+
+		result.Content = &auditedContent{
+			ReadCloser: result.Content,
+			size:       result.Size,
+			done: func(delivered int64, complete bool) {
+				record.OutputSize = delivered
+				record.Outcome = domain.AuditSucceeded
+				if !complete {
+					record.Outcome = domain.AuditAborted
+				}
+				l.store(ctx, record, start)
+			},
+		}
+		return result, nil
+	}
+}
+
+// Fetch lists the records matching filter from the oldest on, num at a time
+func (l *AuditLog) Fetch(ctx context.Context, filter domain.PdfAuditFilter, cursor string, num int64) ([]domain.PdfAuditRecord, string, error) {
+	return l.repo.Fetch(ctx, filter, cursor, num)
+}
+
+// describeInput hashes and measures file and counts its pages, then rewinds it for
+// the operation. A document whose pages can't be counted is left to the operation to
+// reject.
+func (l *AuditLog) describeInput(ctx context.Context, record *domain.PdfAuditRecord, file io.ReadSeeker) error {
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return fmt.Errorf("failed to hash pdf: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind pdf: %w", err)
+	}
+	record.InputSHA256 = hex.EncodeToString(hash.Sum(nil))
+	record.InputSize = size
+
+	pages, err := l.pages.PageCount(ctx, file)
+	if err == nil {
+		record.PageCount = pages
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to rewind pdf: %w", err)
+	}
+	return nil
+}
+
+func (l *AuditLog) store(ctx context.Context, record *domain.PdfAuditRecord, start time.Time) {
+	record.CreatedAt = l.now()
+	record.DurationMs = record.CreatedAt.Sub(start).Milliseconds()
+	// the record is kept even if the request was given up on
+	if err := l.repo.Store(context.WithoutCancel(ctx), record); err != nil {
+		logrus.WithError(err).Errorf("failed to store the audit record of %s on %s", record.Operation, record.InputSHA256)
+	}
+}
+
+// auditedContent counts what is read of a result and reports it once on Close. The
+// result is complete once it was read to the end, or to its size when it is known.
+type auditedContent struct {
+	io.ReadCloser
+	size      int64
+	delivered int64
+	eof       bool
+	once      sync.Once
+	done      func(delivered int64, complete bool)
+}
+
+func (c *auditedContent) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.delivered += int64(n)
+	if err == io.EOF {
+		c.eof = true
+	}
+	return n, err
+}
+
+func (c *auditedContent) Close() error {
+	err := c.ReadCloser.Close()
+	c.once.Do(func() {
+		c.done(c.delivered, c.eof || (c.size >= 0 && c.delivered == c.size))
+	})
+	return err
+}
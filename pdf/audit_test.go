@@ -0,0 +1,115 @@
+package pdf_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/pdf"
+	"github.com/bxcodec/go-clean-arch/pdf/mocks"
+)
+
+func TestAuditLog(t *testing.T) {
+	call := domain.PdfAuditCall{Caller: "203.0.113.7", Operation: "compress", Params: map[string]string{"level": "high"}}
+	// the hex SHA-256 of "%PDF-1.7"
+	const inputHash = "86edbaa24831badfa0a8b04bb410141e2ee4182b6d0014493fe262a7a331c20b"
+
+	newAuditLog := func(pages int, pagesErr error) (*pdf.AuditLog, *[]domain.PdfAuditRecord) {
+		repo := new(mocks.AuditRepository)
+		counter := new(mocks.Executor)
+		counter.On("PageCount", mock.Anything, mock.Anything).Return(pages, pagesErr).Once()
+
+		var stored []domain.PdfAuditRecord
+		repo.On("Store", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			stored = append(stored, *args.Get(1).(*domain.PdfAuditRecord))
+		}).Return(nil)
+		return pdf.NewAuditLog(repo, counter), &stored
+	}
+
+	t.Run("when the result is delivered should record the input, output and caller", func(t *testing.T) {
+		audit, stored := newAuditLog(4, nil)
+		var seen string
+		op := audit.Audited(func(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+			content, _ := io.ReadAll(file)
+			seen = string(content)
+			return domain.PdfFile{Name: fileName, Content: io.NopCloser(strings.NewReader("result")), Size: -1}, nil
+		}, call)
+
+		result, err := op(context.TODO(), "a.pdf", strings.NewReader("%PDF-1.7"))
+		require.NoError(t, err)
+		assert.Empty(t, *stored, "recorded before the result was delivered")
+		io.Copy(io.Discard, result.Content)
+		result.Content.Close()
+		result.Content.Close()
+
+		assert.Equal(t, "%PDF-1.7", seen, "the operation should read the input from the start")
+		require.Len(t, *stored, 1)
+		record := (*stored)[0]
+		assert.Equal(t, "203.0.113.7", record.Caller)
+		assert.Equal(t, "compress", record.Operation)
+		assert.Equal(t, map[string]string{"level": "high"}, record.Params)
+		assert.Equal(t, "a.pdf", record.FileName)
+		assert.Equal(t, inputHash, record.InputSHA256)
+		assert.Equal(t, int64(8), record.InputSize)
+		assert.Equal(t, 4, record.PageCount)
+		assert.Equal(t, int64(len("result")), record.OutputSize)
+		assert.Equal(t, domain.AuditSucceeded, record.Outcome)
+		assert.False(t, record.CreatedAt.IsZero())
+	})
+
+	t.Run("when the operation fails should record the error", func(t *testing.T) {
+		audit, stored := newAuditLog(0, domain.ErrUnsupportedMediaType)
+		op := audit.Audited(func(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+			return domain.PdfFile{}, domain.ErrUnsupportedMediaType
+		}, call)
+
+		_, err := op(context.TODO(), "a.pdf", strings.NewReader("not a pdf"))
+
+		assert.ErrorIs(t, err, domain.ErrUnsupportedMediaType)
+		require.Len(t, *stored, 1)
+		assert.Equal(t, domain.AuditFailed, (*stored)[0].Outcome)
+		assert.Equal(t, domain.ErrUnsupportedMediaType.Error(), (*stored)[0].Error)
+		assert.Zero(t, (*stored)[0].PageCount)
+	})
+
+	t.Run("when the result is closed before the end should record it as aborted", func(t *testing.T) {
+		audit, stored := newAuditLog(1, nil)
+		op := audit.Audited(func(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+			return domain.PdfFile{Name: fileName, Content: io.NopCloser(strings.NewReader("result")), Size: 6}, nil
+		}, call)
+
+		result, err := op(context.TODO(), "a.pdf", strings.NewReader("%PDF-1.7"))
+		require.NoError(t, err)
+		io.CopyN(io.Discard, result.Content, 2)
+		result.Content.Close()
+
+		require.Len(t, *stored, 1)
+		assert.Equal(t, domain.AuditAborted, (*stored)[0].Outcome)
+		assert.Equal(t, int64(2), (*stored)[0].OutputSize)
+	})
+
+	t.Run("when the record can't be stored should still return the result", func(t *testing.T) {
+		repo := new(mocks.AuditRepository)
+		repo.On("Store", mock.Anything, mock.Anything).Return(errors.New("database is down")).Once()
+		counter := new(mocks.Executor)
+		counter.On("PageCount", mock.Anything, mock.Anything).Return(1, nil).Once()
+		op := pdf.NewAuditLog(repo, counter).Audited(func(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+			return domain.PdfFile{Name: fileName, Content: io.NopCloser(strings.NewReader("result")), Size: 6}, nil
+		}, call)
+
+		result, err := op(context.TODO(), "a.pdf", strings.NewReader("%PDF-1.7"))
+		require.NoError(t, err)
+		content, _ := io.ReadAll(result.Content)
+
+		assert.Equal(t, "result", string(content))
+		assert.NoError(t, result.Content.Close())
+		repo.AssertExpectations(t)
+	})
+}
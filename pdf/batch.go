@@ -0,0 +1,244 @@
+package pdf
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"runtime"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// BatchLimits bounds a single batch. Zero values mean no limit, except Parallelism
+// which defaults to the number of CPUs.
+type BatchLimits struct {
+	Parallelism   int
+	MaxEntries    int
+	MaxEntryBytes int64
+}
+
+// WithBatchLimits bounds the batches run by the Service
+func WithBatchLimits(limits BatchLimits) Option {
+	return func(s *Service) {
+		s.batch = limits
+	}
+}
+
+// batchFileFailed is reported for a file that failed unexpectedly
+const batchFileFailed = "failed to process the file"
+
+// batchJob is one file of a batch. done is closed once result or err is set.
+type batchJob struct {
+	entry     *zip.File
+	inputSize int64
+	result    domain.PdfFile
+	err       error
+	done      chan struct{}
+}
+
+// BatchPdf runs process on every file of archive, several at a time, and streams back
+// a zip holding the results under the folders of their inputs plus a report.json. A
+// failing file is recorded in the report and does not fail the batch.
+func (a *Service) BatchPdf(ctx context.Context, fileName string, archive *zip.Reader, process domain.PdfProcessor) (domain.PdfFile, error) {
+	entries := make([]*zip.File, 0, len(archive.File))
+	for _, entry := range archive.File {
+		if !entry.FileInfo().IsDir() {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		return domain.PdfFile{}, fmt.Errorf("%w: the archive is empty", domain.ErrMissingFile)
+	}
+	if a.batch.MaxEntries > 0 && len(entries) > a.batch.MaxEntries {
+		return domain.PdfFile{}, domain.ErrTooManyFiles
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(a.writeBatch(ctx, pw, entries, process))
+	}()
+
+	return domain.PdfFile{Name: "batch_" + fileName, Content: pr, Size: -1}, nil
+}
+
+// writeBatch processes up to Parallelism files ahead of the one being written, so the
+// results land in the zip in the order of the archive. Progress counts written files,
+// the files themselves report none since they run side by side.
+func (a *Service) writeBatch(ctx context.Context, w io.Writer, entries []*zip.File, process domain.PdfProcessor) error {
+	parallelism := a.batch.Parallelism
+	if parallelism <= 0 {
+		parallelism = runtime.NumCPU()
+	}
+
+	jobs := make([]*batchJob, len(entries))
+	for i, entry := range entries {
+		jobs[i] = &batchJob{entry: entry, done: make(chan struct{})}
+	}
+
+	zipWriter := zip.NewWriter(w)
+	report := domain.BatchReport{Files: make([]domain.BatchFileReport, 0, len(jobs))}
+
+	g, gctx := errgroup.WithContext(ctx)
+	slots := make(chan struct{}, parallelism)
+
+	g.Go(func() error {
+		for _, job := range jobs {
+			select {
+			case slots <- struct{}{}:
+			case <-gctx.Done():
+				return gctx.Err()
+			}
+
+			g.Go(func() error {
+				defer close(job.done)
+				job.result, job.err = a.processEntry(domain.WithProgress(gctx, nil), job, process)
+				return nil
+			})
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		for _, job := range jobs {
+			select {
+			case <-job.done:
+			case <-gctx.Done():
+				return gctx.Err()
+			}
+
+			fileReport, err := a.addBatchResult(zipWriter, job)
+			if err != nil {
+				return err
+			}
+			report.Files = append(report.Files, fileReport)
+			if fileReport.Success {
+				report.Succeeded++
+			} else {
+				report.Failed++
+			}
+			<-slots
+			domain.ReportProgress(ctx, domain.StepBatch, len(report.Files), len(jobs))
+		}
+		return nil
+	})
+
+	err := g.Wait()
+	// results that were ready but never written still hold their workspaces
+	for _, job := range jobs {
+		if job.result.Content != nil {
+			job.result.Content.Close()
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	reportWriter, err := zipWriter.Create("report.json")
+	if err != nil {
+		return fmt.Errorf("failed to create zip entry: %w", err)
+	}
+	if err := json.NewEncoder(reportWriter).Encode(report); err != nil {
+		return fmt.Errorf("failed to write batch report: %w", err)
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		return fmt.Errorf("failed to close zip writer: %w", err)
+	}
+	return nil
+}
+
+// processEntry spools one file of the archive to disk and runs process on it. The
+// spooled input lives until the result is closed.
+func (a *Service) processEntry(ctx context.Context, job *batchJob, process domain.PdfProcessor) (domain.PdfFile, error) {
+	entry := job.entry
+	job.inputSize = int64(entry.UncompressedSize64)
+	if !strings.EqualFold(path.Ext(entry.Name), ".pdf") {
+		return domain.PdfFile{}, domain.ErrUnsupportedMediaType
+	}
+
+	limit := a.batch.MaxEntryBytes
+	if limit > 0 && job.inputSize > limit {
+		return domain.PdfFile{}, domain.ErrFileTooLarge
+	}
+
+	content, err := entry.Open()
+	if err != nil {
+		return domain.PdfFile{}, fmt.Errorf("failed to open archive entry: %w", err)
+	}
+	defer content.Close()
+
+	// the declared size can't be trusted, so the limit is enforced while reading
+	var input io.Reader = content
+	if limit > 0 {
+		input = io.LimitReader(content, limit+1)
+	}
+	file, size, err := a.pdfRepo.Spool(ctx, input)
+	if err != nil {
+		return domain.PdfFile{}, err
+	}
+	job.inputSize = size
+	if limit > 0 && size > limit {
+		file.Close()
+		return domain.PdfFile{}, domain.ErrFileTooLarge
+	}
+
+	result, err := process(ctx, path.Base(entry.Name), file)
+	if err != nil {
+		file.Close()
+		return domain.PdfFile{}, err
+	}
+	result.Content = &closeAlso{ReadCloser: result.Content, also: file}
+	return result, nil
+}
+
+// addBatchResult writes the result of job next to where its input was in the archive
+func (a *Service) addBatchResult(zipWriter *zip.Writer, job *batchJob) (domain.BatchFileReport, error) {
+	fileReport := domain.BatchFileReport{Input: job.entry.Name, InputSize: job.inputSize}
+	if job.err != nil {
+		// the report goes to the client, unexpected errors are only logged
+		fileReport.Error = PublicMessage(job.err, batchFileFailed)
+		if fileReport.Error == batchFileFailed {
+			logrus.WithError(job.err).Errorf("failed to process %s of a batch", job.entry.Name)
+		}
+		return fileReport, nil
+	}
+
+	content := job.result.Content
+	defer content.Close()
+	job.result.Content = nil
+
+	// rooting the name before cleaning it drops any "../" that would escape the folder
+	dir := strings.TrimPrefix(path.Dir(path.Clean("/"+job.entry.Name)), "/")
+	fileReport.Output = path.Join(dir, job.result.Name)
+
+	fileWriter, err := zipWriter.Create(fileReport.Output)
+	if err != nil {
+		return fileReport, fmt.Errorf("failed to create zip entry: %w", err)
+	}
+	written, err := io.Copy(fileWriter, content)
+	if err != nil {
+		return fileReport, fmt.Errorf("failed to write %s to zip: %w", fileReport.Output, err)
+	}
+
+	fileReport.Success = true
+	fileReport.OutputSize = written
+	return fileReport, nil
+}
+
+// closeAlso closes another resource together with the content
+type closeAlso struct {
+	io.ReadCloser
+	also io.Closer
+}
+
+func (c *closeAlso) Close() error {
+	defer c.also.Close()
+	return c.ReadCloser.Close()
+}
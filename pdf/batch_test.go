@@ -0,0 +1,139 @@
+package pdf_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/pdf"
+	"github.com/bxcodec/go-clean-arch/pdf/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// spooledFile stands in for the temp file returned by PdfRepository.Spool
+type spooledFile struct {
+	*bytes.Reader
+}
+
+func (spooledFile) Close() error { return nil }
+
+func TestBatchPdf(t *testing.T) {
+	createArchive := func(files map[string]string) *zip.Reader {
+		var buf bytes.Buffer
+		writer := zip.NewWriter(&buf)
+		for _, name := range []string{"a.pdf", "statements/2024/b.pdf", "statements/notes.txt", "broken.pdf", "../escape.pdf", "empty/"} {
+			content, ok := files[name]
+			if !ok {
+				continue
+			}
+			w, _ := writer.Create(name)
+			w.Write([]byte(content))
+		}
+		writer.Close()
+		archive, _ := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		return archive
+	}
+
+	spool := func(_ context.Context, content io.Reader) (domain.SpooledContent, int64, error) {
+		data, err := io.ReadAll(content)
+		return spooledFile{bytes.NewReader(data)}, int64(len(data)), err
+	}
+
+	// upper "processes" a document by upper-casing it and fails on broken input
+	upper := func(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+		data, _ := io.ReadAll(file)
+		if strings.Contains(string(data), "broken") {
+			return domain.PdfFile{}, fmt.Errorf("pdfcpu: corrupt document")
+		}
+		out := strings.ToUpper(string(data))
+		return domain.PdfFile{Name: "processed_" + fileName, Content: io.NopCloser(strings.NewReader(out)), Size: int64(len(out))}, nil
+	}
+
+	t.Run("when batch success should mirror the folders and write a report", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		service := pdf.NewService(mockPdfRepo, pdf.WithBatchLimits(pdf.BatchLimits{Parallelism: 2}))
+		mockPdfRepo.On("Spool", mock.Anything, mock.Anything).Return(spool)
+
+		archive := createArchive(map[string]string{
+			"a.pdf":                 "%pdf a",
+			"statements/2024/b.pdf": "%pdf b",
+			"statements/notes.txt":  "notes",
+			"broken.pdf":            "broken",
+			"../escape.pdf":         "%pdf escape",
+			"empty/":                "",
+		})
+
+		actual, err := service.BatchPdf(context.TODO(), "dump.zip", archive, upper)
+		require.NoError(t, err)
+		assert.Equal(t, "batch_dump.zip", actual.Name)
+
+		content, err := io.ReadAll(actual.Content)
+		require.NoError(t, err)
+		result, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+		require.NoError(t, err)
+
+		names := make([]string, 0)
+		files := map[string]string{}
+		for _, f := range result.File {
+			names = append(names, f.Name)
+			r, _ := f.Open()
+			data, _ := io.ReadAll(r)
+			files[f.Name] = string(data)
+		}
+		assert.Equal(t, []string{"processed_a.pdf", "statements/2024/processed_b.pdf", "processed_escape.pdf", "report.json"}, names)
+		assert.Equal(t, "%PDF B", files["statements/2024/processed_b.pdf"])
+
+		var report domain.BatchReport
+		require.NoError(t, json.Unmarshal([]byte(files["report.json"]), &report))
+		assert.Equal(t, 3, report.Succeeded)
+		assert.Equal(t, 2, report.Failed)
+		require.Len(t, report.Files, 5)
+		assert.Equal(t, domain.BatchFileReport{
+			Input: "statements/2024/b.pdf", Output: "statements/2024/processed_b.pdf", Success: true, InputSize: 6, OutputSize: 6,
+		}, report.Files[1])
+		assert.Equal(t, domain.ErrUnsupportedMediaType.Error(), report.Files[2].Error)
+		assert.False(t, report.Files[3].Success)
+		assert.Equal(t, "failed to process the file", report.Files[3].Error)
+	})
+
+	t.Run("when an entry exceeds the size limit should report it", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		service := pdf.NewService(mockPdfRepo, pdf.WithBatchLimits(pdf.BatchLimits{MaxEntryBytes: 3}))
+
+		actual, err := service.BatchPdf(context.TODO(), "dump.zip", createArchive(map[string]string{"a.pdf": "%pdf a"}), upper)
+		require.NoError(t, err)
+
+		content, _ := io.ReadAll(actual.Content)
+		result, _ := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+		require.Len(t, result.File, 1)
+		r, _ := result.File[0].Open()
+		var report domain.BatchReport
+		json.NewDecoder(r).Decode(&report)
+		assert.Equal(t, domain.ErrFileTooLarge.Error(), report.Files[0].Error)
+		mockPdfRepo.AssertNotCalled(t, "Spool", mock.Anything, mock.Anything)
+	})
+
+	t.Run("when archive has too many files should return error", func(t *testing.T) {
+		service := pdf.NewService(new(mocks.PdfRepository), pdf.WithBatchLimits(pdf.BatchLimits{MaxEntries: 1}))
+
+		_, err := service.BatchPdf(context.TODO(), "dump.zip", createArchive(map[string]string{"a.pdf": "a", "broken.pdf": "b"}), upper)
+
+		assert.ErrorIs(t, err, domain.ErrTooManyFiles)
+	})
+
+	t.Run("when archive is empty should return error", func(t *testing.T) {
+		service := pdf.NewService(new(mocks.PdfRepository))
+
+		_, err := service.BatchPdf(context.TODO(), "dump.zip", createArchive(map[string]string{"empty/": ""}), upper)
+
+		assert.ErrorIs(t, err, domain.ErrMissingFile)
+	})
+}
@@ -0,0 +1,152 @@
+package pdf
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// ResultCache represent the storage of results that were already computed once
+//
+//go:generate mockery --name ResultCache
+type ResultCache interface {
+	Get(ctx context.Context, key string) (io.ReadCloser, int64, bool)
+	// Put reads content to the end and stores it under key, unless reading fails.
+	Put(ctx context.Context, key string, content io.Reader) error
+}
+
+// WithCache puts cache in front of the repository
+func WithCache(cache ResultCache) Option {
+	return func(s *Service) {
+		s.cache = cache
+	}
+}
+
+var errPartialRead = errors.New("result was not read to the end")
+
+// operation identifies what is done to an input. The same operation on the same
+// bytes always produces the same output, so together they make up the cache key.
+type operation struct {
+	name   string
+	params string
+}
+
+func splitOperation(pages []int) operation {
+	params := make([]string, len(pages))
+	for i, page := range pages {
+		params[i] = strconv.Itoa(page)
+	}
+	return operation{name: "split", params: strings.Join(params, ",")}
+}
+
+// run executes op through the cache. A hit never reaches the repository, a miss is
+// stored while the caller streams it.
+func (a *Service) run(ctx context.Context, op operation, outputName string, file io.ReadSeeker, exec func() (io.ReadCloser, int64, error)) (domain.PdfFile, error) {
+	if a.cache == nil {
+		content, size, err := a.execute(ctx, op, exec)
+		if err != nil {
+			return domain.PdfFile{}, err
+		}
+		return domain.PdfFile{Name: outputName, Content: content, Size: size}, nil
+	}
+
+	key, err := cacheKey(op, file)
+	if err != nil {
+		return domain.PdfFile{}, err
+	}
+
+	if content, size, ok := a.cache.Get(ctx, key); ok {
+		domain.ReportProgress(ctx, domain.StepCached, 1, 1)
+		return domain.PdfFile{Name: outputName, Content: content, Size: size, CacheStatus: domain.CacheHit}, nil
+	}
+
+	content, size, err := a.execute(ctx, op, exec)
+	if err != nil {
+		return domain.PdfFile{}, err
+	}
+
+	return domain.PdfFile{
+		Name:        outputName,
+		Content:     a.cacheOnRead(key, content),
+		Size:        size,
+		CacheStatus: domain.CacheMiss,
+	}, nil
+}
+
+// cacheKey hashes the input and rewinds it for the repository
+func cacheKey(op operation, file io.ReadSeeker) (string, error) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to rewind pdf: %w", err)
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", fmt.Errorf("failed to hash pdf: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to rewind pdf: %w", err)
+	}
+
+	fmt.Fprintf(h, "\x00%s\x00%s", op.name, op.params)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// cacheOnRead hands everything the caller reads from content to the cache as well
+func (a *Service) cacheOnRead(key string, content io.ReadCloser) io.ReadCloser {
+	pr, pw := io.Pipe()
+	reader := &cachingReader{content: content, pw: pw, done: make(chan struct{})}
+
+	go func() {
+		defer close(reader.done)
+		if err := a.cache.Put(context.Background(), key, pr); err != nil && !errors.Is(err, errPartialRead) {
+			logrus.Error(err)
+		}
+		// unblock the reader if the cache gave up before the end
+		pr.Close()
+	}()
+
+	return reader
+}
+
+// cachingReader tees content into the cache. A failing cache never fails the read and
+// only a result read to the end is committed, so an aborted download is not cached.
+type cachingReader struct {
+	content io.ReadCloser
+	pw      *io.PipeWriter
+	eof     bool
+	done    chan struct{}
+}
+
+func (r *cachingReader) Read(p []byte) (int, error) {
+	n, err := r.content.Read(p)
+	if n > 0 && r.pw != nil {
+		if _, werr := r.pw.Write(p[:n]); werr != nil {
+			r.pw = nil
+		}
+	}
+	if err == io.EOF {
+		r.eof = true
+	}
+	return n, err
+}
+
+func (r *cachingReader) Close() error {
+	if r.pw != nil {
+		if r.eof {
+			r.pw.Close()
+		} else {
+			r.pw.CloseWithError(errPartialRead)
+		}
+	}
+	<-r.done
+	return r.content.Close()
+}
@@ -0,0 +1,107 @@
+package pdf_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/pdf"
+	"github.com/bxcodec/go-clean-arch/pdf/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCachedService(t *testing.T) {
+	t.Run("when result is cached should not call the repository", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		mockCache := new(mocks.ResultCache)
+		service := pdf.NewService(mockPdfRepo, pdf.WithCache(mockCache))
+
+		input := bytes.NewReader([]byte("%PDF-1.7"))
+
+		mockCache.On("Get", mock.Anything, mock.Anything).Return(io.NopCloser(bytes.NewReader([]byte{9})), int64(1), true).Once()
+
+		actual, err := service.CompressPdf(context.TODO(), "input.pdf", input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.CacheHit, actual.CacheStatus)
+		content, _ := io.ReadAll(actual.Content)
+		assert.Equal(t, []byte{9}, content)
+		mockPdfRepo.AssertNotCalled(t, "Compress", mock.Anything)
+	})
+
+	t.Run("when result is read to the end should store it in the cache", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		mockCache := &recordingCache{}
+		service := pdf.NewService(mockPdfRepo, pdf.WithCache(mockCache))
+
+		input := bytes.NewReader([]byte("%PDF-1.7"))
+
+		mockCache.On("Get", mock.Anything, mock.Anything).Return(nil, int64(0), false).Once()
+		mockPdfRepo.On("Compress", mock.Anything, mock.Anything).Return(io.NopCloser(bytes.NewReader([]byte{1, 2})), int64(2), nil).Once()
+
+		actual, err := service.CompressPdf(context.TODO(), "input.pdf", input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.CacheMiss, actual.CacheStatus)
+		content, _ := io.ReadAll(actual.Content)
+		actual.Content.Close()
+		assert.Equal(t, []byte{1, 2}, content)
+		assert.Equal(t, []byte{1, 2}, mockCache.stored)
+		assert.NoError(t, mockCache.readErr)
+	})
+
+	t.Run("when result is not read to the end should not commit it", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		mockCache := &recordingCache{}
+		service := pdf.NewService(mockPdfRepo, pdf.WithCache(mockCache))
+
+		input := bytes.NewReader([]byte("%PDF-1.7"))
+
+		mockCache.On("Get", mock.Anything, mock.Anything).Return(nil, int64(0), false).Once()
+		mockPdfRepo.On("Split", mock.Anything, mock.Anything, mock.Anything).Return(io.NopCloser(bytes.NewReader([]byte{1, 2, 3})), int64(3), nil).Once()
+
+		actual, err := service.SplitPdfByRanges(context.TODO(), "test.pdf", input, []int{1})
+
+		assert.NoError(t, err)
+		actual.Content.Read(make([]byte, 1))
+		actual.Content.Close()
+		assert.Error(t, mockCache.readErr)
+	})
+
+	t.Run("when parameters differ should use different keys", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		mockCache := new(mocks.ResultCache)
+		service := pdf.NewService(mockPdfRepo, pdf.WithCache(mockCache))
+
+		input := bytes.NewReader([]byte("%PDF-1.7"))
+
+		var keys []string
+		mockCache.On("Get", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			keys = append(keys, args.String(1))
+		}).Return(io.NopCloser(bytes.NewReader(nil)), int64(0), true)
+
+		service.SplitPdfByRanges(context.TODO(), "test.pdf", input, []int{1})
+		service.SplitPdfByRanges(context.TODO(), "test.pdf", input, []int{1, 2})
+		service.SplitPdfByRanges(context.TODO(), "other.pdf", input, []int{1})
+
+		assert.Len(t, keys, 3)
+		assert.NotEqual(t, keys[0], keys[1])
+		assert.Equal(t, keys[0], keys[2])
+	})
+}
+
+// recordingCache misses on Get and keeps what Put read. testify can't be used for Put
+// since it formats its arguments while the pipe is being written.
+type recordingCache struct {
+	mocks.ResultCache
+	stored  []byte
+	readErr error
+}
+
+func (c *recordingCache) Put(ctx context.Context, key string, content io.Reader) error {
+	c.stored, c.readErr = io.ReadAll(content)
+	return c.readErr
+}
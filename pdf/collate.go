@@ -0,0 +1,164 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// CollatePdf assembles one document of the pages selections take from the named
+// documents, in the order of selections
+func (a *Service) CollatePdf(ctx context.Context, outputName string, documents map[string]io.ReadSeeker, selections []domain.PageSelection) (domain.PdfFile, error) {
+	if len(selections) == 0 {
+		return domain.PdfFile{}, ParamError("Selector selects no pages")
+	}
+
+	content, size, err := a.execute(ctx, operation{name: "collate"}, func() (io.ReadCloser, int64, error) {
+		// documents take part in the order they are first selected
+		index := make(map[string]int)
+		var files []io.ReadSeeker
+		var pageCounts []int
+		for _, selection := range selections {
+			if _, ok := index[selection.Document]; ok {
+				continue
+			}
+			file, ok := documents[selection.Document]
+			if !ok {
+				return nil, 0, ParamError(fmt.Sprintf("Unknown document %q", selection.Document))
+			}
+			pageCount, err := a.pdfRepo.PageCount(ctx, file)
+			if err != nil {
+				return nil, 0, err
+			}
+			index[selection.Document] = len(files)
+			files = append(files, file)
+			pageCounts = append(pageCounts, pageCount)
+		}
+
+		var pages []domain.PageRef
+		for _, selection := range selections {
+			document := index[selection.Document]
+			selected, err := selectedPages(selection, pageCounts[document])
+			if err != nil {
+				return nil, 0, err
+			}
+			for _, page := range selected {
+				pages = append(pages, domain.PageRef{Document: document, Page: page})
+			}
+		}
+		if len(pages) == 0 {
+			return nil, 0, ParamError("Selector selects no pages")
+		}
+
+		return a.assemble(ctx, files, pages)
+	})
+	if err != nil {
+		return domain.PdfFile{}, err
+	}
+	return domain.PdfFile{Name: outputName, Content: content, Size: size}, nil
+}
+
+// InterleavePdf joins the fronts and the backs of sheets scanned one side at a time
+// into a duplex document. The stack is turned over to scan the backs, so even holds
+// them last to first. A blank back of the last sheet may have been left out.
+func (a *Service) InterleavePdf(ctx context.Context, outputName string, odd io.ReadSeeker, even io.ReadSeeker) (domain.PdfFile, error) {
+	content, size, err := a.execute(ctx, operation{name: "collate"}, func() (io.ReadCloser, int64, error) {
+		oddCount, err := a.pdfRepo.PageCount(ctx, odd)
+		if err != nil {
+			return nil, 0, err
+		}
+		evenCount, err := a.pdfRepo.PageCount(ctx, even)
+		if err != nil {
+			return nil, 0, err
+		}
+		if evenCount != oddCount && evenCount != oddCount-1 {
+			return nil, 0, ParamError(fmt.Sprintf("%d odd pages don't pair up with %d even pages", oddCount, evenCount))
+		}
+
+		pages := make([]domain.PageRef, 0, oddCount+evenCount)
+		for page := 1; page <= oddCount; page++ {
+			pages = append(pages, domain.PageRef{Document: 0, Page: page})
+			if page <= evenCount {
+				pages = append(pages, domain.PageRef{Document: 1, Page: evenCount - page + 1})
+			}
+		}
+		return a.assemble(ctx, []io.ReadSeeker{odd, even}, pages)
+	})
+	if err != nil {
+		return domain.PdfFile{}, err
+	}
+	return domain.PdfFile{Name: outputName, Content: content, Size: size}, nil
+}
+
+// assemble rewinds files, which were read to count their pages, and assembles pages
+func (a *Service) assemble(ctx context.Context, files []io.ReadSeeker, pages []domain.PageRef) (io.ReadCloser, int64, error) {
+	for _, file := range files {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return nil, 0, fmt.Errorf("failed to rewind pdf: %w", err)
+		}
+	}
+	return a.pdfRepo.Assemble(ctx, files, pages)
+}
+
+// selectedPages lists the pages selection takes of a document of pageCount pages
+func selectedPages(selection domain.PageSelection, pageCount int) ([]int, error) {
+	last := selection.Last
+	if last == 0 {
+		last = pageCount
+	}
+	if selection.First > pageCount || last > pageCount {
+		return nil, ParamError(fmt.Sprintf("Selection of %q exceeds its %d pages", selection.Document, pageCount))
+	}
+
+	var pages []int
+	for page := selection.First; page <= last; page++ {
+		switch {
+		case selection.Parity == domain.PagesOdd && page%2 == 0,
+			selection.Parity == domain.PagesEven && page%2 == 1:
+			continue
+		}
+		pages = append(pages, page)
+	}
+	return pages, nil
+}
+
+// ParseSelector reads a selector such as "a:1-3,b:1,a:4-,c:even": a comma separated
+// list of a document name with the pages to take of it, a page, a range of pages, a
+// range open to the end, odd or even. A name alone takes every page.
+func ParseSelector(selector string) ([]domain.PageSelection, error) {
+	var selections []domain.PageSelection
+	for _, item := range strings.Split(selector, ",") {
+		name, pages, _ := strings.Cut(strings.TrimSpace(item), ":")
+		name, pages = strings.TrimSpace(name), strings.TrimSpace(pages)
+		if name == "" {
+			return nil, fmt.Errorf("missing document name in %q", item)
+		}
+
+		selection := domain.PageSelection{Document: name, First: 1}
+		switch pages {
+		case "":
+		case domain.PagesOdd, domain.PagesEven:
+			selection.Parity = pages
+		default:
+			first, last, isRange := strings.Cut(pages, "-")
+			var err error
+			if selection.First, err = strconv.Atoi(strings.TrimSpace(first)); err != nil || selection.First < 1 {
+				return nil, fmt.Errorf("invalid pages %q", item)
+			}
+			switch {
+			case !isRange:
+				selection.Last = selection.First
+			case strings.TrimSpace(last) != "":
+				if selection.Last, err = strconv.Atoi(strings.TrimSpace(last)); err != nil || selection.Last < selection.First {
+					return nil, fmt.Errorf("invalid pages %q", item)
+				}
+			}
+		}
+		selections = append(selections, selection)
+	}
+	return selections, nil
+}
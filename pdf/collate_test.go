@@ -0,0 +1,126 @@
+package pdf_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/pdf"
+	"github.com/bxcodec/go-clean-arch/pdf/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollatePdf(t *testing.T) {
+	stored := func() (io.ReadCloser, int64, error) {
+		return io.NopCloser(strings.NewReader("%PDF")), 4, nil
+	}
+	a, b, c := strings.NewReader("a"), strings.NewReader("b"), strings.NewReader("c")
+	documents := map[string]io.ReadSeeker{"a": a, "b": b, "c": c}
+
+	t.Run("when selections span documents should assemble their pages in order", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		service := pdf.NewService(mockPdfRepo)
+		mockPdfRepo.On("PageCount", mock.Anything, a).Return(5, nil).Once()
+		mockPdfRepo.On("PageCount", mock.Anything, b).Return(1, nil).Once()
+		mockPdfRepo.On("PageCount", mock.Anything, c).Return(5, nil).Once()
+		mockPdfRepo.On("Assemble", mock.Anything, []io.ReadSeeker{a, b, c}, []domain.PageRef{
+			{Document: 0, Page: 1}, {Document: 0, Page: 2}, {Document: 0, Page: 3},
+			{Document: 1, Page: 1},
+			{Document: 0, Page: 4}, {Document: 0, Page: 5},
+			{Document: 2, Page: 2}, {Document: 2, Page: 4},
+		}).Return(stored()).Once()
+
+		selections, err := pdf.ParseSelector("a:1-3,b:1,a:4-,c:even")
+		require.NoError(t, err)
+		actual, err := service.CollatePdf(context.TODO(), "collated_a.pdf", documents, selections)
+
+		require.NoError(t, err)
+		assert.Equal(t, "collated_a.pdf", actual.Name)
+		mockPdfRepo.AssertExpectations(t)
+	})
+
+	t.Run("when a document is not uploaded should return a param error", func(t *testing.T) {
+		service := pdf.NewService(new(mocks.PdfRepository))
+
+		_, err := service.CollatePdf(context.TODO(), "collated_a.pdf", documents, []domain.PageSelection{{Document: "d", First: 1}})
+
+		var paramErr pdf.ParamError
+		assert.True(t, errors.As(err, &paramErr))
+	})
+
+	t.Run("when a selection exceeds its document should return a param error", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		service := pdf.NewService(mockPdfRepo)
+		mockPdfRepo.On("PageCount", mock.Anything, b).Return(1, nil).Once()
+
+		_, err := service.CollatePdf(context.TODO(), "collated_b.pdf", documents, []domain.PageSelection{{Document: "b", First: 2}})
+
+		var paramErr pdf.ParamError
+		assert.True(t, errors.As(err, &paramErr))
+		mockPdfRepo.AssertNotCalled(t, "Assemble", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestInterleavePdf(t *testing.T) {
+	stored := func() (io.ReadCloser, int64, error) {
+		return io.NopCloser(strings.NewReader("%PDF")), 4, nil
+	}
+	odd, even := strings.NewReader("odd"), strings.NewReader("even")
+
+	t.Run("when the backs are one short should pair the fronts with the reversed backs", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		service := pdf.NewService(mockPdfRepo)
+		mockPdfRepo.On("PageCount", mock.Anything, odd).Return(3, nil).Once()
+		mockPdfRepo.On("PageCount", mock.Anything, even).Return(2, nil).Once()
+		mockPdfRepo.On("Assemble", mock.Anything, []io.ReadSeeker{odd, even}, []domain.PageRef{
+			{Document: 0, Page: 1}, {Document: 1, Page: 2},
+			{Document: 0, Page: 2}, {Document: 1, Page: 1},
+			{Document: 0, Page: 3},
+		}).Return(stored()).Once()
+
+		_, err := service.InterleavePdf(context.TODO(), "collated_odd.pdf", odd, even)
+
+		require.NoError(t, err)
+		mockPdfRepo.AssertExpectations(t)
+	})
+
+	t.Run("when the pages don't pair up should return a param error", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		service := pdf.NewService(mockPdfRepo)
+		mockPdfRepo.On("PageCount", mock.Anything, odd).Return(2, nil).Once()
+		mockPdfRepo.On("PageCount", mock.Anything, even).Return(3, nil).Once()
+
+		_, err := service.InterleavePdf(context.TODO(), "collated_odd.pdf", odd, even)
+
+		var paramErr pdf.ParamError
+		assert.True(t, errors.As(err, &paramErr))
+	})
+}
+
+func TestParseSelector(t *testing.T) {
+	t.Run("when valid should read every kind of selection", func(t *testing.T) {
+		actual, err := pdf.ParseSelector("a:1-3, b:2, a:4-, c:odd, d")
+
+		require.NoError(t, err)
+		assert.Equal(t, []domain.PageSelection{
+			{Document: "a", First: 1, Last: 3},
+			{Document: "b", First: 2, Last: 2},
+			{Document: "a", First: 4},
+			{Document: "c", First: 1, Parity: domain.PagesOdd},
+			{Document: "d", First: 1},
+		}, actual)
+	})
+
+	t.Run("when invalid should return an error", func(t *testing.T) {
+		for _, input := range []string{"", ":1", "a:0", "a:3-1", "a:x", "a:1-2-3", "a,"} {
+			_, err := pdf.ParseSelector(input)
+
+			assert.Error(t, err, input)
+		}
+	})
+}
@@ -0,0 +1,391 @@
+package pdf
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// comparisonName is the report in the zip of an annotated comparison
+const comparisonName = "comparison.json"
+
+// maxAlignment bounds the pages of both documents, multiplied, that are aligned by
+// their content once the pages both start and end with are taken off. Beyond it the
+// rest is paired by index.
+const maxAlignment = 1 << 22
+
+// maxGapScores bounds the pages between two matches, multiplied, that are paired by
+// the text they share. Scoring a pair compares the words of both pages, so larger
+// gaps are paired by index.
+const maxGapScores = 1 << 16
+
+// minSimilarity is how much text two pages between aligned ones have to share to be
+// reported as a changed page rather than one removed and one added
+const minSimilarity = 0.5
+
+// sizeTolerance is how far page sizes may differ, in points, and still count as equal
+const sizeTolerance = 0.5
+
+// ComparePdf reports what changed from original to revised as JSON. With annotate the
+// report comes in a zip along with a copy of the revision whose added and changed
+// pages are stamped with what changed.
+func (a *Service) ComparePdf(ctx context.Context, originalName string, original io.ReadSeeker, revisedName string, revised io.ReadSeeker, annotate bool) (domain.PdfFile, error) {
+	outputName := "compare_" + strings.TrimSuffix(originalName, filepath.Ext(originalName))
+
+	content, size, err := a.execute(ctx, operation{name: "compare"}, func() (io.ReadCloser, int64, error) {
+		domain.ReportProgress(ctx, domain.StepComparing, 0, 2)
+		originalInfo, err := a.pdfRepo.Describe(ctx, original)
+		if err != nil {
+			return nil, 0, err
+		}
+		domain.ReportProgress(ctx, domain.StepComparing, 1, 2)
+		revisedInfo, err := a.pdfRepo.Describe(ctx, revised)
+		if err != nil {
+			return nil, 0, err
+		}
+		domain.ReportProgress(ctx, domain.StepComparing, 2, 2)
+
+		comparison := compare(originalInfo, revisedInfo)
+		comparison.Original, comparison.Revised = originalName, revisedName
+		report, err := json.MarshalIndent(comparison, "", "  ")
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to encode comparison: %w", err)
+		}
+		if !annotate {
+			return io.NopCloser(bytes.NewReader(report)), int64(len(report)), nil
+		}
+
+		if _, err := revised.Seek(0, io.SeekStart); err != nil {
+			return nil, 0, fmt.Errorf("failed to rewind pdf: %w", err)
+		}
+		annotated, _, err := a.pdfRepo.Stamp(ctx, revised, changeStamps(comparison))
+		if err != nil {
+			return nil, 0, err
+		}
+		return zipComparison(report, annotated, "annotated_"+revisedName), -1, nil
+	})
+	if err != nil {
+		return domain.PdfFile{}, err
+	}
+
+	if annotate {
+		outputName += ".zip"
+	} else {
+		outputName += ".json"
+	}
+	return domain.PdfFile{Name: outputName, Content: content, Size: size}, nil
+}
+
+// zipComparison streams a zip of the report and the annotated revision
+func zipComparison(report []byte, annotated io.ReadCloser, annotatedName string) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		defer annotated.Close()
+
+		zipWriter := zip.NewWriter(pw)
+		err := addZipEntry(zipWriter, comparisonName, bytes.NewReader(report))
+		if err == nil {
+			err = addZipEntry(zipWriter, annotatedName, annotated)
+		}
+		if err == nil {
+			err = zipWriter.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+func addZipEntry(zipWriter *zip.Writer, name string, content io.Reader) error {
+	fileWriter, err := zipWriter.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to create zip entry: %w", err)
+	}
+	if _, err := io.Copy(fileWriter, content); err != nil {
+		return fmt.Errorf("failed to write %s to zip: %w", name, err)
+	}
+	return nil
+}
+
+// changeStamps labels the added and changed pages of the revision
+func changeStamps(comparison domain.PdfComparison) map[int]string {
+	stamps := map[int]string{}
+	for _, page := range comparison.Pages {
+		switch page.Change {
+		case domain.PageAdded:
+			stamps[page.Revised.Page] = "Added page"
+		case domain.PageChanged:
+			stamps[page.Revised.Page] = "Changed: " + strings.Join(page.Differences, ", ")
+		}
+	}
+	return stamps
+}
+
+// compare aligns the pages of original and revised by their content and reports the
+// pages that were added, removed or changed along with the document information that
+// differs
+func compare(original, revised domain.PdfDocumentInfo) domain.PdfComparison {
+	comparison := domain.PdfComparison{
+		OriginalPages: len(original.Pages),
+		RevisedPages:  len(revised.Pages),
+		Pages:         []domain.PdfPageDiff{},
+		Metadata:      compareMetadata(original.Metadata, revised.Metadata),
+	}
+
+	for _, pair := range alignPages(original.Pages, revised.Pages) {
+		switch {
+		case pair.revised < 0:
+			comparison.Pages = append(comparison.Pages, domain.PdfPageDiff{
+				Change:   domain.PageRemoved,
+				Original: pageSide(original.Pages, pair.original),
+			})
+		case pair.original < 0:
+			comparison.Pages = append(comparison.Pages, domain.PdfPageDiff{
+				Change:  domain.PageAdded,
+				Revised: pageSide(revised.Pages, pair.revised),
+			})
+		default:
+			diff := comparePages(original.Pages[pair.original], revised.Pages[pair.revised])
+			if len(diff.Differences) > 0 {
+				diff.Original = pageSide(original.Pages, pair.original)
+				diff.Revised = pageSide(revised.Pages, pair.revised)
+				comparison.Pages = append(comparison.Pages, diff)
+			}
+		}
+	}
+
+	comparison.Identical = len(comparison.Pages) == 0 && len(comparison.Metadata) == 0
+	return comparison
+}
+
+func pageSide(pages []domain.PdfPageInfo, index int) *domain.PdfPageSide {
+	page := pages[index]
+	return &domain.PdfPageSide{Page: index + 1, Width: page.Width, Height: page.Height, Rotation: page.Rotation}
+}
+
+// comparePages lists what differs between two pages of the same place
+func comparePages(original, revised domain.PdfPageInfo) domain.PdfPageDiff {
+	diff := domain.PdfPageDiff{Change: domain.PageChanged}
+	if !slices.Equal(original.Text, revised.Text) {
+		diff.Differences = append(diff.Differences, domain.DiffText)
+		diff.TextRemoved = missingLines(original.Text, revised.Text)
+		diff.TextAdded = missingLines(revised.Text, original.Text)
+	} else if original.ContentHash != revised.ContentHash {
+		diff.Differences = append(diff.Differences, domain.DiffContent)
+	}
+	if math.Abs(original.Width-revised.Width) > sizeTolerance || math.Abs(original.Height-revised.Height) > sizeTolerance {
+		diff.Differences = append(diff.Differences, domain.DiffSize)
+	}
+	if original.Rotation != revised.Rotation {
+		diff.Differences = append(diff.Differences, domain.DiffRotation)
+	}
+	return diff
+}
+
+// missingLines lists the lines of text, in order, that other does not have as often
+func missingLines(text, other []string) []string {
+	left := map[string]int{}
+	for _, line := range other {
+		left[line]++
+	}
+
+	var missing []string
+	for _, line := range text {
+		if left[line] > 0 {
+			left[line]--
+			continue
+		}
+		missing = append(missing, line)
+	}
+	return missing
+}
+
+// similarity is the share of lines two pages have in common, pages without text are
+// alike
+func similarity(original, revised domain.PdfPageInfo) float64 {
+	total := len(original.Text) + len(revised.Text)
+	if total == 0 {
+		return 1
+	}
+	common := len(original.Text) - len(missingLines(original.Text, revised.Text))
+	return float64(2*common) / float64(total)
+}
+
+func compareMetadata(original, revised map[string]string) []domain.PdfMetadataDiff {
+	keys := make([]string, 0, len(original)+len(revised))
+	for key := range original {
+		keys = append(keys, key)
+	}
+	for key := range revised {
+		if _, ok := original[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	diffs := []domain.PdfMetadataDiff{}
+	for _, key := range keys {
+		if original[key] != revised[key] {
+			diffs = append(diffs, domain.PdfMetadataDiff{Key: key, Original: original[key], Revised: revised[key]})
+		}
+	}
+	return diffs
+}
+
+// pagePair is a page of the original and the page of the revision it corresponds
+// to, by index. The side a page is missing from is -1.
+type pagePair struct {
+	original int
+	revised  int
+}
+
+// alignPages pairs the pages of both documents in reading order. Pages with the same
+// content are matched first, as the longest common subsequence, and the pages between
+// matches are paired up by the text they share.
+func alignPages(original, revised []domain.PdfPageInfo) []pagePair {
+	same := func(i, j int) bool { return original[i].ContentHash == revised[j].ContentHash }
+
+	start := 0
+	for start < len(original) && start < len(revised) && same(start, start) {
+		start++
+	}
+	end := 0
+	for end < len(original)-start && end < len(revised)-start && same(len(original)-1-end, len(revised)-1-end) {
+		end++
+	}
+
+	pairs := make([]pagePair, 0, max(len(original), len(revised)))
+	for i := range start {
+		pairs = append(pairs, pagePair{i, i})
+	}
+
+	if (len(original)-start-end)*(len(revised)-start-end) > maxAlignment {
+		pairs = append(pairs, pairByIndex(len(original)-start-end, len(revised)-start-end, start, start)...)
+	} else {
+		matches := commonPages(start, len(original)-end, start, len(revised)-end, same)
+		i, j := start, start
+		for _, match := range append(matches, pagePair{len(original) - end, len(revised) - end}) {
+			pairs = append(pairs, pairGap(original[i:match.original], revised[j:match.revised], i, j)...)
+			i, j = match.original, match.revised
+			if i < len(original)-end {
+				pairs = append(pairs, match)
+				i, j = i+1, j+1
+			}
+		}
+	}
+
+	for k := range end {
+		pairs = append(pairs, pagePair{len(original) - end + k, len(revised) - end + k})
+	}
+	return pairs
+}
+
+// commonPages returns the longest common subsequence of the pages in [fromOriginal,
+// toOriginal) and [fromRevised, toRevised)
+func commonPages(fromOriginal, toOriginal, fromRevised, toRevised int, same func(i, j int) bool) []pagePair {
+	n, m := toOriginal-fromOriginal, toRevised-fromRevised
+	// lengths[i][j] is the length of the subsequence of the pages from i and j on
+	lengths := make([][]int32, n+1)
+	for i := range lengths {
+		lengths[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if same(fromOriginal+i, fromRevised+j) {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	var matches []pagePair
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case same(fromOriginal+i, fromRevised+j):
+			matches = append(matches, pagePair{fromOriginal + i, fromRevised + j})
+			i, j = i+1, j+1
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return matches
+}
+
+// pairGap pairs the pages between two matches that share the most text, in order.
+// The pages left over were removed from the original or added to the revision.
+func pairGap(original, revised []domain.PdfPageInfo, offsetOriginal, offsetRevised int) []pagePair {
+	n, m := len(original), len(revised)
+	if n*m > maxGapScores {
+		return pairByIndex(n, m, offsetOriginal, offsetRevised)
+	}
+	// scores[i][j] is the best total similarity of the pages from i and j on
+	scores := make([][]float64, n+1)
+	for i := range scores {
+		scores[i] = make([]float64, m+1)
+	}
+	similar := func(i, j int) (float64, bool) {
+		score := similarity(original[i], revised[j])
+		return score, score >= minSimilarity
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			scores[i][j] = max(scores[i+1][j], scores[i][j+1])
+			if score, ok := similar(i, j); ok {
+				scores[i][j] = max(scores[i][j], scores[i+1][j+1]+score)
+			}
+		}
+	}
+
+	var pairs []pagePair
+	i, j := 0, 0
+	for i < n && j < m {
+		score, ok := similar(i, j)
+		switch {
+		case ok && scores[i][j] == scores[i+1][j+1]+score:
+			pairs = append(pairs, pagePair{offsetOriginal + i, offsetRevised + j})
+			i, j = i+1, j+1
+		case scores[i][j] == scores[i+1][j]:
+			pairs = append(pairs, pagePair{offsetOriginal + i, -1})
+			i++
+		default:
+			pairs = append(pairs, pagePair{-1, offsetRevised + j})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		pairs = append(pairs, pagePair{offsetOriginal + i, -1})
+	}
+	for ; j < m; j++ {
+		pairs = append(pairs, pagePair{-1, offsetRevised + j})
+	}
+	return pairs
+}
+
+// pairByIndex pairs n pages of the original with m of the revision side by side, the
+// pages one has more of were removed or added
+func pairByIndex(n, m, offsetOriginal, offsetRevised int) []pagePair {
+	pairs := make([]pagePair, 0, max(n, m))
+	for k := range max(n, m) {
+		pair := pagePair{-1, -1}
+		if k < n {
+			pair.original = offsetOriginal + k
+		}
+		if k < m {
+			pair.revised = offsetRevised + k
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs
+}
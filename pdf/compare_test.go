@@ -0,0 +1,191 @@
+package pdf_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/pdf"
+	"github.com/bxcodec/go-clean-arch/pdf/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func comparePage(hash string, text ...string) domain.PdfPageInfo {
+	return domain.PdfPageInfo{Width: 595, Height: 842, Text: text, ContentHash: hash}
+}
+
+func compareDocuments(t *testing.T, original, revised domain.PdfDocumentInfo) domain.PdfComparison {
+	mockPdfRepo := new(mocks.PdfRepository)
+	service := pdf.NewService(mockPdfRepo)
+	mockPdfRepo.On("Describe", mock.Anything, mock.Anything).Return(original, nil).Once()
+	mockPdfRepo.On("Describe", mock.Anything, mock.Anything).Return(revised, nil).Once()
+
+	result, err := service.ComparePdf(context.TODO(), "contract.pdf", strings.NewReader("a"), "contract_v2.pdf", strings.NewReader("b"), false)
+	require.NoError(t, err)
+	defer result.Content.Close()
+	assert.Equal(t, "compare_contract.json", result.Name)
+
+	var comparison domain.PdfComparison
+	require.NoError(t, json.NewDecoder(result.Content).Decode(&comparison))
+	return comparison
+}
+
+func TestComparePdf(t *testing.T) {
+	pages := []domain.PdfPageInfo{
+		comparePage("1", "Parties", "Alice and Bob"),
+		comparePage("2", "Term", "The term is one year"),
+		comparePage("3", "Fees", "The fee is 100"),
+		comparePage("4", "Signatures"),
+	}
+
+	t.Run("when documents are the same should report them identical", func(t *testing.T) {
+		document := domain.PdfDocumentInfo{Pages: pages, Metadata: map[string]string{"Title": "Contract"}}
+
+		comparison := compareDocuments(t, document, document)
+
+		assert.True(t, comparison.Identical)
+		assert.Empty(t, comparison.Pages)
+		assert.Empty(t, comparison.Metadata)
+		assert.Equal(t, "contract.pdf", comparison.Original)
+		assert.Equal(t, "contract_v2.pdf", comparison.Revised)
+		assert.Equal(t, 4, comparison.RevisedPages)
+	})
+
+	t.Run("when a page is inserted should report only that page added", func(t *testing.T) {
+		revised := []domain.PdfPageInfo{pages[0], comparePage("5", "Definitions"), pages[1], pages[2], pages[3]}
+
+		comparison := compareDocuments(t, domain.PdfDocumentInfo{Pages: pages}, domain.PdfDocumentInfo{Pages: revised})
+
+		require.Len(t, comparison.Pages, 1)
+		assert.Equal(t, domain.PageAdded, comparison.Pages[0].Change)
+		assert.Nil(t, comparison.Pages[0].Original)
+		assert.Equal(t, 2, comparison.Pages[0].Revised.Page)
+		assert.False(t, comparison.Identical)
+	})
+
+	t.Run("when a page is removed should report it where it was", func(t *testing.T) {
+		revised := []domain.PdfPageInfo{pages[0], pages[2], pages[3]}
+
+		comparison := compareDocuments(t, domain.PdfDocumentInfo{Pages: pages}, domain.PdfDocumentInfo{Pages: revised})
+
+		require.Len(t, comparison.Pages, 1)
+		assert.Equal(t, domain.PageRemoved, comparison.Pages[0].Change)
+		assert.Equal(t, 2, comparison.Pages[0].Original.Page)
+		assert.Nil(t, comparison.Pages[0].Revised)
+	})
+
+	t.Run("when text changes next to an inserted page should pair the changed page by its text", func(t *testing.T) {
+		revised := []domain.PdfPageInfo{
+			pages[0],
+			comparePage("5", "Definitions"),
+			pages[1],
+			comparePage("6", "Fees", "The fee is 120"),
+			pages[3],
+		}
+		original := append([]domain.PdfPageInfo{}, pages...)
+		original[2] = comparePage("3", "Fees", "The fee is 100")
+
+		comparison := compareDocuments(t, domain.PdfDocumentInfo{Pages: original}, domain.PdfDocumentInfo{Pages: revised})
+
+		require.Len(t, comparison.Pages, 2)
+		assert.Equal(t, domain.PageAdded, comparison.Pages[0].Change)
+		changed := comparison.Pages[1]
+		assert.Equal(t, domain.PageChanged, changed.Change)
+		assert.Equal(t, 3, changed.Original.Page)
+		assert.Equal(t, 4, changed.Revised.Page)
+		assert.Equal(t, []string{domain.DiffText}, changed.Differences)
+		assert.Equal(t, []string{"The fee is 100"}, changed.TextRemoved)
+		assert.Equal(t, []string{"The fee is 120"}, changed.TextAdded)
+	})
+
+	t.Run("when size, rotation or graphics change should report them", func(t *testing.T) {
+		revised := append([]domain.PdfPageInfo{}, pages...)
+		revised[1] = pages[1]
+		revised[1].Width, revised[1].Height, revised[1].Rotation = 842, 595, 90
+		revised[3] = comparePage("7", "Signatures")
+
+		comparison := compareDocuments(t, domain.PdfDocumentInfo{Pages: pages}, domain.PdfDocumentInfo{Pages: revised})
+
+		require.Len(t, comparison.Pages, 2)
+		assert.Equal(t, []string{domain.DiffSize, domain.DiffRotation}, comparison.Pages[0].Differences)
+		assert.Equal(t, []string{domain.DiffContent}, comparison.Pages[1].Differences)
+		assert.Empty(t, comparison.Pages[1].TextAdded)
+	})
+
+	t.Run("when metadata differs should list the entries that differ", func(t *testing.T) {
+		original := domain.PdfDocumentInfo{Pages: pages, Metadata: map[string]string{"Title": "Contract", "Author": "Alice"}}
+		revised := domain.PdfDocumentInfo{Pages: pages, Metadata: map[string]string{"Title": "Contract v2", "Author": "Alice", "Subject": "Lease"}}
+
+		comparison := compareDocuments(t, original, revised)
+
+		assert.Equal(t, []domain.PdfMetadataDiff{
+			{Key: "Subject", Original: "", Revised: "Lease"},
+			{Key: "Title", Original: "Contract", Revised: "Contract v2"},
+		}, comparison.Metadata)
+		assert.False(t, comparison.Identical)
+	})
+
+	t.Run("when documents are too large to align should pair the pages by index", func(t *testing.T) {
+		// 2100 x 2101 pages is beyond what is aligned by content or scored by text
+		original := domain.PdfDocumentInfo{Pages: make([]domain.PdfPageInfo, 2100)}
+		revised := domain.PdfDocumentInfo{Pages: make([]domain.PdfPageInfo, 2101)}
+		for i := range revised.Pages {
+			if i < len(original.Pages) {
+				original.Pages[i] = comparePage(fmt.Sprintf("o%d", i), "Clause", fmt.Sprint(i))
+			}
+			revised.Pages[i] = comparePage(fmt.Sprintf("r%d", i), "Clause", fmt.Sprint(i))
+		}
+
+		comparison := compareDocuments(t, original, revised)
+
+		require.Len(t, comparison.Pages, 2101)
+		assert.Equal(t, domain.PageChanged, comparison.Pages[0].Change)
+		assert.Equal(t, 1, comparison.Pages[0].Original.Page)
+		assert.Equal(t, 1, comparison.Pages[0].Revised.Page)
+		assert.Equal(t, domain.PageChanged, comparison.Pages[2099].Change)
+		assert.Equal(t, domain.PageAdded, comparison.Pages[2100].Change)
+		assert.Equal(t, 2101, comparison.Pages[2100].Revised.Page)
+	})
+
+	t.Run("when annotate is set should zip the report with the stamped revision", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		service := pdf.NewService(mockPdfRepo)
+		revised := []domain.PdfPageInfo{pages[0], comparePage("5", "Definitions"), pages[1], pages[2], comparePage("8", "Signatures", "Carol")}
+		mockPdfRepo.On("Describe", mock.Anything, mock.Anything).Return(domain.PdfDocumentInfo{Pages: pages}, nil).Once()
+		mockPdfRepo.On("Describe", mock.Anything, mock.Anything).Return(domain.PdfDocumentInfo{Pages: revised}, nil).Once()
+		mockPdfRepo.On("Stamp", mock.Anything, mock.Anything, map[int]string{2: "Added page", 5: "Changed: text"}).
+			Return(io.NopCloser(strings.NewReader("%PDF-stamped")), int64(12), nil).Once()
+
+		result, err := service.ComparePdf(context.TODO(), "contract.pdf", strings.NewReader("a"), "contract_v2.pdf", strings.NewReader("b"), true)
+		require.NoError(t, err)
+		defer result.Content.Close()
+
+		assert.Equal(t, "compare_contract.zip", result.Name)
+		content, err := io.ReadAll(result.Content)
+		require.NoError(t, err)
+		archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+		require.NoError(t, err)
+		require.Len(t, archive.File, 2)
+		assert.Equal(t, "comparison.json", archive.File[0].Name)
+		assert.Equal(t, "annotated_contract_v2.pdf", archive.File[1].Name)
+		mockPdfRepo.AssertExpectations(t)
+	})
+
+	t.Run("when a document can't be read should return the error", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		service := pdf.NewService(mockPdfRepo)
+		mockPdfRepo.On("Describe", mock.Anything, mock.Anything).Return(domain.PdfDocumentInfo{}, fmt.Errorf("Describe Failed")).Once()
+
+		_, err := service.ComparePdf(context.TODO(), "contract.pdf", strings.NewReader("a"), "contract_v2.pdf", strings.NewReader("b"), false)
+
+		assert.Error(t, err)
+	})
+}
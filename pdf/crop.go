@@ -0,0 +1,132 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// CropPdf sets the box of options on the selected pages, see domain.CropOptions. In
+// auto mode pages that draw nothing within their crop box are left as they are.
+func (a *Service) CropPdf(ctx context.Context, fileName string, file io.ReadSeeker, options domain.CropOptions) (domain.PdfFile, error) {
+	outputName := "cropped_" + fileName
+
+	return a.run(ctx, cropOperation(options), outputName, file, func() (io.ReadCloser, int64, error) {
+		rects, err := a.cropRects(ctx, file, options)
+		if err != nil {
+			return nil, 0, err
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return nil, 0, fmt.Errorf("failed to rewind pdf: %w", err)
+		}
+		return a.pdfRepo.SetBoxes(ctx, file, options.Box, rects)
+	})
+}
+
+func cropOperation(options domain.CropOptions) operation {
+	pages := make([]string, len(options.Pages))
+	for i, page := range options.Pages {
+		pages[i] = strconv.Itoa(page)
+	}
+	m := options.Margins
+	return operation{
+		name:   "crop",
+		params: fmt.Sprintf("%s;%s;%t;%g,%g,%g,%g", options.Box, strings.Join(pages, ","), options.Auto, m.Top, m.Right, m.Bottom, m.Left),
+	}
+}
+
+// cropRects works out the box of every selected page and rewinds file
+func (a *Service) cropRects(ctx context.Context, file io.ReadSeeker, options domain.CropOptions) (map[int]domain.PdfRect, error) {
+	boxes, err := a.pdfRepo.PageBoxes(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := options.Pages
+	if len(pages) == 0 {
+		pages = allPages(len(boxes))
+	}
+	if len(pages) == 0 {
+		return nil, ParamError("The document has no pages")
+	}
+	if slices.Min(pages) < 1 || slices.Max(pages) > len(boxes) {
+		return nil, ParamError("Ranges exceed page count")
+	}
+
+	var contentBounds map[int]domain.PdfRect
+	if options.Auto {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("failed to rewind pdf: %w", err)
+		}
+		if contentBounds, err = a.pdfRepo.ContentBounds(ctx, file, pages); err != nil {
+			return nil, err
+		}
+	}
+
+	rects := make(map[int]domain.PdfRect, len(pages))
+	for _, page := range pages {
+		box := boxes[page-1]
+		top, right, bottom, left := userMargins(options.Margins, box.Rotation)
+
+		if !options.Auto {
+			rect := domain.PdfRect{LLX: box.Crop.LLX + left, LLY: box.Crop.LLY + bottom, URX: box.Crop.URX - right, URY: box.Crop.URY - top}
+			if rect.Empty() {
+				return nil, ParamError(fmt.Sprintf("Margins leave nothing of page %d", page))
+			}
+			rects[page] = rect
+			continue
+		}
+
+		content, ok := contentBounds[page]
+		if !ok {
+			continue
+		}
+		rect := domain.PdfRect{
+			LLX: max(content.LLX-left, box.Crop.LLX),
+			LLY: max(content.LLY-bottom, box.Crop.LLY),
+			URX: min(content.URX+right, box.Crop.URX),
+			URY: min(content.URY+top, box.Crop.URY),
+		}
+		if !rect.Empty() {
+			rects[page] = rect
+		}
+	}
+	return rects, nil
+}
+
+// userMargins turns margins of the page as it is displayed into margins of its user
+// space, which a rotation of the page turns clockwise
+func userMargins(margins domain.PdfMargins, rotation int) (top, right, bottom, left float64) {
+	displayed := [4]float64{margins.Top, margins.Right, margins.Bottom, margins.Left}
+	turns := ((rotation/90)%4 + 4) % 4
+	return displayed[turns], displayed[(turns+1)%4], displayed[(turns+2)%4], displayed[(turns+3)%4]
+}
+
+// ParseMargins reads margins in points the way CSS writes them: one value for every
+// side, two for top and bottom then left and right, or four from the top clockwise
+func ParseMargins(value string) (domain.PdfMargins, error) {
+	fields := strings.Split(value, ",")
+	values := make([]float64, len(fields))
+	for i, field := range fields {
+		v, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil || v < 0 {
+			return domain.PdfMargins{}, fmt.Errorf("invalid margin %q", field)
+		}
+		values[i] = v
+	}
+
+	switch len(values) {
+	case 1:
+		return domain.PdfMargins{Top: values[0], Right: values[0], Bottom: values[0], Left: values[0]}, nil
+	case 2:
+		return domain.PdfMargins{Top: values[0], Right: values[1], Bottom: values[0], Left: values[1]}, nil
+	case 4:
+		return domain.PdfMargins{Top: values[0], Right: values[1], Bottom: values[2], Left: values[3]}, nil
+	}
+	return domain.PdfMargins{}, fmt.Errorf("margins take 1, 2 or 4 values, got %d", len(values))
+}
@@ -0,0 +1,127 @@
+package pdf_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/pdf"
+	"github.com/bxcodec/go-clean-arch/pdf/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCropPdf(t *testing.T) {
+	letter := domain.PdfRect{LLX: 0, LLY: 0, URX: 612, URY: 792}
+	boxes := []domain.PdfPageBoxes{
+		{Media: letter, Crop: letter, Trim: letter, Bleed: letter},
+		{Media: letter, Crop: letter, Trim: letter, Bleed: letter, Rotation: 90},
+		{Media: letter, Crop: letter, Trim: letter, Bleed: letter},
+	}
+	stored := func() (io.ReadCloser, int64, error) {
+		return io.NopCloser(strings.NewReader("%PDF")), 4, nil
+	}
+
+	t.Run("when margins are given should take them off the crop box as the page is displayed", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		service := pdf.NewService(mockPdfRepo)
+		mockPdfRepo.On("PageBoxes", mock.Anything, mock.Anything).Return(boxes, nil).Once()
+		mockPdfRepo.On("SetBoxes", mock.Anything, mock.Anything, domain.BoxCrop, map[int]domain.PdfRect{
+			1: {LLX: 40, LLY: 30, URX: 592, URY: 782},
+			// turned clockwise the displayed top is the left of user space
+			2: {LLX: 10, LLY: 40, URX: 582, URY: 772},
+		}).Return(stored()).Once()
+
+		actual, err := service.CropPdf(context.TODO(), "scan.pdf", strings.NewReader("%PDF"), domain.CropOptions{
+			Box:     domain.BoxCrop,
+			Pages:   []int{1, 2},
+			Margins: domain.PdfMargins{Top: 10, Right: 20, Bottom: 30, Left: 40},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "cropped_scan.pdf", actual.Name)
+		mockPdfRepo.AssertExpectations(t)
+	})
+
+	t.Run("when auto should fit the content plus margins within the crop box and skip blank pages", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		service := pdf.NewService(mockPdfRepo)
+		mockPdfRepo.On("PageBoxes", mock.Anything, mock.Anything).Return(boxes, nil).Once()
+		mockPdfRepo.On("ContentBounds", mock.Anything, mock.Anything, []int{1, 2, 3}).Return(map[int]domain.PdfRect{
+			1: {LLX: 100, LLY: 200, URX: 500, URY: 700},
+			2: {LLX: 2, LLY: 100, URX: 300, URY: 790},
+		}, nil).Once()
+		mockPdfRepo.On("SetBoxes", mock.Anything, mock.Anything, domain.BoxTrim, map[int]domain.PdfRect{
+			1: {LLX: 95, LLY: 195, URX: 505, URY: 705},
+			2: {LLX: 0, LLY: 95, URX: 305, URY: 792},
+		}).Return(stored()).Once()
+
+		_, err := service.CropPdf(context.TODO(), "scan.pdf", strings.NewReader("%PDF"), domain.CropOptions{
+			Box:     domain.BoxTrim,
+			Auto:    true,
+			Margins: domain.PdfMargins{Top: 5, Right: 5, Bottom: 5, Left: 5},
+		})
+
+		require.NoError(t, err)
+		mockPdfRepo.AssertExpectations(t)
+	})
+
+	t.Run("when a page is out of the document should return a ParamError", func(t *testing.T) {
+		for _, pages := range [][]int{{0}, {4}} {
+			mockPdfRepo := new(mocks.PdfRepository)
+			service := pdf.NewService(mockPdfRepo)
+			mockPdfRepo.On("PageBoxes", mock.Anything, mock.Anything).Return(boxes, nil).Once()
+
+			_, err := service.CropPdf(context.TODO(), "scan.pdf", strings.NewReader("%PDF"), domain.CropOptions{
+				Box:     domain.BoxCrop,
+				Pages:   pages,
+				Margins: domain.PdfMargins{Top: 10},
+			})
+
+			var param pdf.ParamError
+			assert.True(t, errors.As(err, &param), pages)
+		}
+	})
+
+	t.Run("when margins leave nothing of a page should return a ParamError", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		service := pdf.NewService(mockPdfRepo)
+		mockPdfRepo.On("PageBoxes", mock.Anything, mock.Anything).Return(boxes, nil).Once()
+
+		_, err := service.CropPdf(context.TODO(), "scan.pdf", strings.NewReader("%PDF"), domain.CropOptions{
+			Box:     domain.BoxCrop,
+			Margins: domain.PdfMargins{Left: 400, Right: 300},
+		})
+
+		var param pdf.ParamError
+		assert.True(t, errors.As(err, &param))
+		mockPdfRepo.AssertNotCalled(t, "SetBoxes", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestParseMargins(t *testing.T) {
+	t.Run("when given 1, 2 or 4 values should spread them the way CSS does", func(t *testing.T) {
+		for value, expected := range map[string]domain.PdfMargins{
+			"12":        {Top: 12, Right: 12, Bottom: 12, Left: 12},
+			"10, 20":    {Top: 10, Right: 20, Bottom: 10, Left: 20},
+			"1,2,3,4.5": {Top: 1, Right: 2, Bottom: 3, Left: 4.5},
+		} {
+			actual, err := pdf.ParseMargins(value)
+
+			require.NoError(t, err, value)
+			assert.Equal(t, expected, actual, value)
+		}
+	})
+
+	t.Run("when values are missing, negative or too many should return an error", func(t *testing.T) {
+		for _, value := range []string{"", "a", "-1", "1,2,3", "1,2,3,4,5"} {
+			_, err := pdf.ParseMargins(value)
+
+			assert.Error(t, err, value)
+		}
+	})
+}
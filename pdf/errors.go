@@ -0,0 +1,42 @@
+package pdf
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// PublicErrors are the errors whose message is told to clients as is, as is that of a
+// ParamError. Anything else may carry paths and library internals.
+var PublicErrors = []error{
+	domain.ErrStorageFull,
+	domain.ErrMissingFile,
+	domain.ErrUnsupportedMediaType,
+	domain.ErrFileTooLarge,
+	domain.ErrTooManyFiles,
+	domain.ErrOverloaded,
+	domain.ErrForbiddenSource,
+	domain.ErrSourceUnavailable,
+	domain.ErrConflict,
+	domain.ErrNotFound,
+	domain.ErrUploadIncomplete,
+	domain.ErrUnprocessable,
+	context.DeadlineExceeded,
+	context.Canceled,
+}
+
+// PublicMessage is what a client learns about err, message stands in for anything
+// not in PublicErrors
+func PublicMessage(err error, message string) string {
+	var param ParamError
+	if errors.As(err, &param) {
+		return param.Error()
+	}
+	for _, public := range PublicErrors {
+		if errors.Is(err, public) {
+			return public.Error()
+		}
+	}
+	return message
+}
@@ -0,0 +1,100 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// InsertPdf inserts sourcePages of source, or all of them when empty, into target at
+// position. Results are not cached, the cache is keyed by a single input.
+func (a *Service) InsertPdf(ctx context.Context, fileName string, target io.ReadSeeker, source io.ReadSeeker, sourcePages []int, position domain.InsertPosition) (domain.PdfFile, error) {
+	outputName := "inserted_" + fileName
+
+	content, size, err := a.execute(ctx, operation{name: "insert"}, func() (io.ReadCloser, int64, error) {
+		targetCount, err := a.pdfRepo.PageCount(ctx, target)
+		if err != nil {
+			return nil, 0, err
+		}
+		sourceCount, err := a.pdfRepo.PageCount(ctx, source)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		at, err := insertionPoint(position, targetCount)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(sourcePages) == 0 {
+			sourcePages = allPages(sourceCount)
+		} else if slices.Min(sourcePages) < 1 || slices.Max(sourcePages) > sourceCount {
+			return nil, 0, ParamError("Source pages exceed page count")
+		}
+
+		pages := make([]domain.PageRef, 0, targetCount+len(sourcePages))
+		for page := 1; page <= at; page++ {
+			pages = append(pages, domain.PageRef{Document: 0, Page: page})
+		}
+		for _, page := range sourcePages {
+			pages = append(pages, domain.PageRef{Document: 1, Page: page})
+		}
+		for page := at + 1; page <= targetCount; page++ {
+			pages = append(pages, domain.PageRef{Document: 0, Page: page})
+		}
+
+		return a.assemble(ctx, []io.ReadSeeker{target, source}, pages)
+	})
+	if err != nil {
+		return domain.PdfFile{}, err
+	}
+	return domain.PdfFile{Name: outputName, Content: content, Size: size}, nil
+}
+
+// insertionPoint is the number of pages of the target that come before the inserted
+// ones
+func insertionPoint(position domain.InsertPosition, pageCount int) (int, error) {
+	switch position.Where {
+	case domain.InsertEnd:
+		return pageCount, nil
+	case domain.InsertBefore, domain.InsertAfter:
+		if position.Page < 1 || position.Page > pageCount {
+			return 0, ParamError("Position exceeds page count")
+		}
+		if position.Where == domain.InsertBefore {
+			return position.Page - 1, nil
+		}
+		return position.Page, nil
+	}
+	return 0, ParamError(fmt.Sprintf("Unknown position %q", position.Where))
+}
+
+// ParseInsertPosition reads a position such as "after" and the page it refers to,
+// which the end goes without
+func ParseInsertPosition(where string, page string) (domain.InsertPosition, error) {
+	switch where {
+	case "", domain.InsertEnd:
+		if page != "" {
+			return domain.InsertPosition{}, ParamError("page goes with position before or after")
+		}
+		return domain.InsertPosition{Where: domain.InsertEnd}, nil
+	case domain.InsertBefore, domain.InsertAfter:
+		n, err := strconv.Atoi(page)
+		if err != nil || n < 1 {
+			return domain.InsertPosition{}, ParamError(fmt.Sprintf("Invalid page %q", page))
+		}
+		return domain.InsertPosition{Where: where, Page: n}, nil
+	}
+	return domain.InsertPosition{}, ParamError(fmt.Sprintf("Unknown position %q", where))
+}
+
+func allPages(pageCount int) []int {
+	pages := make([]int, pageCount)
+	for i := range pages {
+		pages[i] = i + 1
+	}
+	return pages
+}
@@ -0,0 +1,113 @@
+package pdf_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/pdf"
+	"github.com/bxcodec/go-clean-arch/pdf/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsertPdf(t *testing.T) {
+	stored := func() (io.ReadCloser, int64, error) {
+		return io.NopCloser(strings.NewReader("%PDF")), 4, nil
+	}
+	target, source := strings.NewReader("target"), strings.NewReader("source")
+	counts := func(repo *mocks.PdfRepository) {
+		repo.On("PageCount", mock.Anything, target).Return(3, nil).Once()
+		repo.On("PageCount", mock.Anything, source).Return(2, nil).Once()
+	}
+
+	cases := []struct {
+		name     string
+		pages    []int
+		position domain.InsertPosition
+		expected []domain.PageRef
+	}{
+		{
+			name:     "when before a page should put the source pages ahead of it",
+			pages:    []int{2},
+			position: domain.InsertPosition{Where: domain.InsertBefore, Page: 1},
+			expected: []domain.PageRef{{Document: 1, Page: 2}, {Document: 0, Page: 1}, {Document: 0, Page: 2}, {Document: 0, Page: 3}},
+		},
+		{
+			name:     "when after a page should put the source pages behind it",
+			pages:    []int{2, 1},
+			position: domain.InsertPosition{Where: domain.InsertAfter, Page: 2},
+			expected: []domain.PageRef{{Document: 0, Page: 1}, {Document: 0, Page: 2}, {Document: 1, Page: 2}, {Document: 1, Page: 1}, {Document: 0, Page: 3}},
+		},
+		{
+			name:     "when at the end without pages should append every source page",
+			position: domain.InsertPosition{Where: domain.InsertEnd},
+			expected: []domain.PageRef{{Document: 0, Page: 1}, {Document: 0, Page: 2}, {Document: 0, Page: 3}, {Document: 1, Page: 1}, {Document: 1, Page: 2}},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockPdfRepo := new(mocks.PdfRepository)
+			service := pdf.NewService(mockPdfRepo)
+			counts(mockPdfRepo)
+			mockPdfRepo.On("Assemble", mock.Anything, []io.ReadSeeker{target, source}, tc.expected).Return(stored()).Once()
+
+			actual, err := service.InsertPdf(context.TODO(), "contract.pdf", target, source, tc.pages, tc.position)
+
+			require.NoError(t, err)
+			assert.Equal(t, "inserted_contract.pdf", actual.Name)
+			mockPdfRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("when the position is past the target should return a param error", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		service := pdf.NewService(mockPdfRepo)
+		counts(mockPdfRepo)
+
+		_, err := service.InsertPdf(context.TODO(), "contract.pdf", target, source, nil, domain.InsertPosition{Where: domain.InsertAfter, Page: 4})
+
+		var paramErr pdf.ParamError
+		assert.True(t, errors.As(err, &paramErr))
+		mockPdfRepo.AssertNotCalled(t, "Assemble", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("when source pages exceed the source should return a param error", func(t *testing.T) {
+		mockPdfRepo := new(mocks.PdfRepository)
+		service := pdf.NewService(mockPdfRepo)
+		counts(mockPdfRepo)
+
+		_, err := service.InsertPdf(context.TODO(), "contract.pdf", target, source, []int{3}, domain.InsertPosition{Where: domain.InsertEnd})
+
+		var paramErr pdf.ParamError
+		assert.True(t, errors.As(err, &paramErr))
+	})
+}
+
+func TestParseInsertPosition(t *testing.T) {
+	t.Run("when empty should insert at the end", func(t *testing.T) {
+		actual, err := pdf.ParseInsertPosition("", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.InsertPosition{Where: domain.InsertEnd}, actual)
+	})
+
+	t.Run("when after a page should keep the page", func(t *testing.T) {
+		actual, err := pdf.ParseInsertPosition("after", "3")
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.InsertPosition{Where: domain.InsertAfter, Page: 3}, actual)
+	})
+
+	t.Run("when invalid should return an error", func(t *testing.T) {
+		for _, input := range [][2]string{{"before", ""}, {"after", "0"}, {"end", "2"}, {"middle", "1"}} {
+			_, err := pdf.ParseInsertPosition(input[0], input[1])
+
+			assert.Error(t, err, input)
+		}
+	})
+}
@@ -0,0 +1,84 @@
+// Code generated by mockery v2.50.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/bxcodec/go-clean-arch/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditRepository is an autogenerated mock type for the AuditRepository type
+type AuditRepository struct {
+	mock.Mock
+}
+
+// Fetch provides a mock function with given fields: ctx, filter, cursor, num
+func (_m *AuditRepository) Fetch(ctx context.Context, filter domain.PdfAuditFilter, cursor string, num int64) ([]domain.PdfAuditRecord, string, error) {
+	ret := _m.Called(ctx, filter, cursor, num)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Fetch")
+	}
+
+	var r0 []domain.PdfAuditRecord
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PdfAuditFilter, string, int64) ([]domain.PdfAuditRecord, string, error)); ok {
+		return rf(ctx, filter, cursor, num)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PdfAuditFilter, string, int64) []domain.PdfAuditRecord); ok {
+		r0 = rf(ctx, filter, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.PdfAuditRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.PdfAuditFilter, string, int64) string); ok {
+		r1 = rf(ctx, filter, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, domain.PdfAuditFilter, string, int64) error); ok {
+		r2 = rf(ctx, filter, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Store provides a mock function with given fields: ctx, record
+func (_m *AuditRepository) Store(ctx context.Context, record *domain.PdfAuditRecord) error {
+	ret := _m.Called(ctx, record)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Store")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.PdfAuditRecord) error); ok {
+		r0 = rf(ctx, record)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAuditRepository creates a new instance of AuditRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditRepository {
+	mock := &AuditRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,227 @@
+// Code generated by mockery v2.50.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+
+	domain "github.com/bxcodec/go-clean-arch/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Executor is an autogenerated mock type for the Executor type
+type Executor struct {
+	mock.Mock
+}
+
+// AnalyzePdf provides a mock function with given fields: ctx, fileName, file
+func (_m *Executor) AnalyzePdf(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+	ret := _m.Called(ctx, fileName, file)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AnalyzePdf")
+	}
+
+	var r0 domain.PdfFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker) (domain.PdfFile, error)); ok {
+		return rf(ctx, fileName, file)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker) domain.PdfFile); ok {
+		r0 = rf(ctx, fileName, file)
+	} else {
+		r0 = ret.Get(0).(domain.PdfFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.ReadSeeker) error); ok {
+		r1 = rf(ctx, fileName, file)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompressPdf provides a mock function with given fields: ctx, fileName, file
+func (_m *Executor) CompressPdf(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+	ret := _m.Called(ctx, fileName, file)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompressPdf")
+	}
+
+	var r0 domain.PdfFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker) (domain.PdfFile, error)); ok {
+		return rf(ctx, fileName, file)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker) domain.PdfFile); ok {
+		r0 = rf(ctx, fileName, file)
+	} else {
+		r0 = ret.Get(0).(domain.PdfFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.ReadSeeker) error); ok {
+		r1 = rf(ctx, fileName, file)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CropPdf provides a mock function with given fields: ctx, fileName, file, options
+func (_m *Executor) CropPdf(ctx context.Context, fileName string, file io.ReadSeeker, options domain.CropOptions) (domain.PdfFile, error) {
+	ret := _m.Called(ctx, fileName, file, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CropPdf")
+	}
+
+	var r0 domain.PdfFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, domain.CropOptions) (domain.PdfFile, error)); ok {
+		return rf(ctx, fileName, file, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, domain.CropOptions) domain.PdfFile); ok {
+		r0 = rf(ctx, fileName, file, options)
+	} else {
+		r0 = ret.Get(0).(domain.PdfFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.ReadSeeker, domain.CropOptions) error); ok {
+		r1 = rf(ctx, fileName, file, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PageCount provides a mock function with given fields: ctx, file
+func (_m *Executor) PageCount(ctx context.Context, file io.ReadSeeker) (int, error) {
+	ret := _m.Called(ctx, file)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PageCount")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker) (int, error)); ok {
+		return rf(ctx, file)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker) int); ok {
+		r0 = rf(ctx, file)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.ReadSeeker) error); ok {
+		r1 = rf(ctx, file)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemovePagesPdf provides a mock function with given fields: ctx, fileName, file, removePages, pageCount
+func (_m *Executor) RemovePagesPdf(ctx context.Context, fileName string, file io.ReadSeeker, removePages []int, pageCount int) (domain.PdfFile, error) {
+	ret := _m.Called(ctx, fileName, file, removePages, pageCount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemovePagesPdf")
+	}
+
+	var r0 domain.PdfFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, []int, int) (domain.PdfFile, error)); ok {
+		return rf(ctx, fileName, file, removePages, pageCount)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, []int, int) domain.PdfFile); ok {
+		r0 = rf(ctx, fileName, file, removePages, pageCount)
+	} else {
+		r0 = ret.Get(0).(domain.PdfFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.ReadSeeker, []int, int) error); ok {
+		r1 = rf(ctx, fileName, file, removePages, pageCount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SplitAndZipPdfByFixedRange provides a mock function with given fields: ctx, fileName, file, fra, naming
+func (_m *Executor) SplitAndZipPdfByFixedRange(ctx context.Context, fileName string, file io.ReadSeeker, fra [][]int, naming domain.SplitNaming) (domain.PdfFile, error) {
+	ret := _m.Called(ctx, fileName, file, fra, naming)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SplitAndZipPdfByFixedRange")
+	}
+
+	var r0 domain.PdfFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, [][]int, domain.SplitNaming) (domain.PdfFile, error)); ok {
+		return rf(ctx, fileName, file, fra, naming)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, [][]int, domain.SplitNaming) domain.PdfFile); ok {
+		r0 = rf(ctx, fileName, file, fra, naming)
+	} else {
+		r0 = ret.Get(0).(domain.PdfFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.ReadSeeker, [][]int, domain.SplitNaming) error); ok {
+		r1 = rf(ctx, fileName, file, fra, naming)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SplitPdfByRanges provides a mock function with given fields: ctx, fileName, file, ranges
+func (_m *Executor) SplitPdfByRanges(ctx context.Context, fileName string, file io.ReadSeeker, ranges []int) (domain.PdfFile, error) {
+	ret := _m.Called(ctx, fileName, file, ranges)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SplitPdfByRanges")
+	}
+
+	var r0 domain.PdfFile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, []int) (domain.PdfFile, error)); ok {
+		return rf(ctx, fileName, file, ranges)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.ReadSeeker, []int) domain.PdfFile); ok {
+		r0 = rf(ctx, fileName, file, ranges)
+	} else {
+		r0 = ret.Get(0).(domain.PdfFile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, io.ReadSeeker, []int) error); ok {
+		r1 = rf(ctx, fileName, file, ranges)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewExecutor creates a new instance of Executor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewExecutor(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Executor {
+	mock := &Executor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
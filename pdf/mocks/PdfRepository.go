@@ -3,7 +3,10 @@
 package mocks
 
 import (
-	multipart "mime/multipart"
+	context "context"
+	io "io"
+
+	domain "github.com/bxcodec/go-clean-arch/domain"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -13,29 +16,256 @@ type PdfRepository struct {
 	mock.Mock
 }
 
-// Compress provides a mock function with given fields: file
-func (_m *PdfRepository) Compress(file multipart.File) ([]byte, error) {
-	ret := _m.Called(file)
+// Analyze provides a mock function with given fields: ctx, file
+func (_m *PdfRepository) Analyze(ctx context.Context, file io.ReadSeeker) (domain.PdfAnalysis, error) {
+	ret := _m.Called(ctx, file)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Analyze")
+	}
+
+	var r0 domain.PdfAnalysis
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker) (domain.PdfAnalysis, error)); ok {
+		return rf(ctx, file)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker) domain.PdfAnalysis); ok {
+		r0 = rf(ctx, file)
+	} else {
+		r0 = ret.Get(0).(domain.PdfAnalysis)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.ReadSeeker) error); ok {
+		r1 = rf(ctx, file)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Assemble provides a mock function with given fields: ctx, files, pages
+func (_m *PdfRepository) Assemble(ctx context.Context, files []io.ReadSeeker, pages []domain.PageRef) (io.ReadCloser, int64, error) {
+	ret := _m.Called(ctx, files, pages)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Assemble")
+	}
+
+	var r0 io.ReadCloser
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, []io.ReadSeeker, []domain.PageRef) (io.ReadCloser, int64, error)); ok {
+		return rf(ctx, files, pages)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []io.ReadSeeker, []domain.PageRef) io.ReadCloser); ok {
+		r0 = rf(ctx, files, pages)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []io.ReadSeeker, []domain.PageRef) int64); ok {
+		r1 = rf(ctx, files, pages)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, []io.ReadSeeker, []domain.PageRef) error); ok {
+		r2 = rf(ctx, files, pages)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Bookmarks provides a mock function with given fields: ctx, file
+func (_m *PdfRepository) Bookmarks(ctx context.Context, file io.ReadSeeker) ([]domain.Bookmark, error) {
+	ret := _m.Called(ctx, file)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Bookmarks")
+	}
+
+	var r0 []domain.Bookmark
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker) ([]domain.Bookmark, error)); ok {
+		return rf(ctx, file)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker) []domain.Bookmark); ok {
+		r0 = rf(ctx, file)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Bookmark)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.ReadSeeker) error); ok {
+		r1 = rf(ctx, file)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Compress provides a mock function with given fields: ctx, file
+func (_m *PdfRepository) Compress(ctx context.Context, file io.ReadSeeker) (io.ReadCloser, int64, error) {
+	ret := _m.Called(ctx, file)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Compress")
 	}
 
-	var r0 []byte
+	var r0 io.ReadCloser
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker) (io.ReadCloser, int64, error)); ok {
+		return rf(ctx, file)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker) io.ReadCloser); ok {
+		r0 = rf(ctx, file)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.ReadSeeker) int64); ok {
+		r1 = rf(ctx, file)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, io.ReadSeeker) error); ok {
+		r2 = rf(ctx, file)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ContentBounds provides a mock function with given fields: ctx, file, pages
+func (_m *PdfRepository) ContentBounds(ctx context.Context, file io.ReadSeeker, pages []int) (map[int]domain.PdfRect, error) {
+	ret := _m.Called(ctx, file, pages)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContentBounds")
+	}
+
+	var r0 map[int]domain.PdfRect
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, []int) (map[int]domain.PdfRect, error)); ok {
+		return rf(ctx, file, pages)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, []int) map[int]domain.PdfRect); ok {
+		r0 = rf(ctx, file, pages)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int]domain.PdfRect)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.ReadSeeker, []int) error); ok {
+		r1 = rf(ctx, file, pages)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Describe provides a mock function with given fields: ctx, file
+func (_m *PdfRepository) Describe(ctx context.Context, file io.ReadSeeker) (domain.PdfDocumentInfo, error) {
+	ret := _m.Called(ctx, file)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Describe")
+	}
+
+	var r0 domain.PdfDocumentInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker) (domain.PdfDocumentInfo, error)); ok {
+		return rf(ctx, file)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker) domain.PdfDocumentInfo); ok {
+		r0 = rf(ctx, file)
+	} else {
+		r0 = ret.Get(0).(domain.PdfDocumentInfo)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.ReadSeeker) error); ok {
+		r1 = rf(ctx, file)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Merge provides a mock function with given fields: ctx, files
+func (_m *PdfRepository) Merge(ctx context.Context, files []io.ReadSeeker) (io.ReadCloser, int64, error) {
+	ret := _m.Called(ctx, files)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Merge")
+	}
+
+	var r0 io.ReadCloser
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, []io.ReadSeeker) (io.ReadCloser, int64, error)); ok {
+		return rf(ctx, files)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []io.ReadSeeker) io.ReadCloser); ok {
+		r0 = rf(ctx, files)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []io.ReadSeeker) int64); ok {
+		r1 = rf(ctx, files)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, []io.ReadSeeker) error); ok {
+		r2 = rf(ctx, files)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// PageBoxes provides a mock function with given fields: ctx, file
+func (_m *PdfRepository) PageBoxes(ctx context.Context, file io.ReadSeeker) ([]domain.PdfPageBoxes, error) {
+	ret := _m.Called(ctx, file)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PageBoxes")
+	}
+
+	var r0 []domain.PdfPageBoxes
 	var r1 error
-	if rf, ok := ret.Get(0).(func(multipart.File) ([]byte, error)); ok {
-		return rf(file)
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker) ([]domain.PdfPageBoxes, error)); ok {
+		return rf(ctx, file)
 	}
-	if rf, ok := ret.Get(0).(func(multipart.File) []byte); ok {
-		r0 = rf(file)
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker) []domain.PdfPageBoxes); ok {
+		r0 = rf(ctx, file)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]byte)
+			r0 = ret.Get(0).([]domain.PdfPageBoxes)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(multipart.File) error); ok {
-		r1 = rf(file)
+	if rf, ok := ret.Get(1).(func(context.Context, io.ReadSeeker) error); ok {
+		r1 = rf(ctx, file)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -43,9 +273,9 @@ func (_m *PdfRepository) Compress(file multipart.File) ([]byte, error) {
 	return r0, r1
 }
 
-// PageCount provides a mock function with given fields: file
-func (_m *PdfRepository) PageCount(file multipart.File) (int, error) {
-	ret := _m.Called(file)
+// PageCount provides a mock function with given fields: ctx, file
+func (_m *PdfRepository) PageCount(ctx context.Context, file io.ReadSeeker) (int, error) {
+	ret := _m.Called(ctx, file)
 
 	if len(ret) == 0 {
 		panic("no return value specified for PageCount")
@@ -53,17 +283,17 @@ func (_m *PdfRepository) PageCount(file multipart.File) (int, error) {
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(multipart.File) (int, error)); ok {
-		return rf(file)
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker) (int, error)); ok {
+		return rf(ctx, file)
 	}
-	if rf, ok := ret.Get(0).(func(multipart.File) int); ok {
-		r0 = rf(file)
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker) int); ok {
+		r0 = rf(ctx, file)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(multipart.File) error); ok {
-		r1 = rf(file)
+	if rf, ok := ret.Get(1).(func(context.Context, io.ReadSeeker) error); ok {
+		r1 = rf(ctx, file)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -71,34 +301,170 @@ func (_m *PdfRepository) PageCount(file multipart.File) (int, error) {
 	return r0, r1
 }
 
-// Split provides a mock function with given fields: file, pages
-func (_m *PdfRepository) Split(file multipart.File, pages []int) ([]byte, error) {
-	ret := _m.Called(file, pages)
+// SetBoxes provides a mock function with given fields: ctx, file, box, rects
+func (_m *PdfRepository) SetBoxes(ctx context.Context, file io.ReadSeeker, box string, rects map[int]domain.PdfRect) (io.ReadCloser, int64, error) {
+	ret := _m.Called(ctx, file, box, rects)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetBoxes")
+	}
+
+	var r0 io.ReadCloser
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, string, map[int]domain.PdfRect) (io.ReadCloser, int64, error)); ok {
+		return rf(ctx, file, box, rects)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, string, map[int]domain.PdfRect) io.ReadCloser); ok {
+		r0 = rf(ctx, file, box, rects)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.ReadSeeker, string, map[int]domain.PdfRect) int64); ok {
+		r1 = rf(ctx, file, box, rects)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, io.ReadSeeker, string, map[int]domain.PdfRect) error); ok {
+		r2 = rf(ctx, file, box, rects)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Split provides a mock function with given fields: ctx, file, pages
+func (_m *PdfRepository) Split(ctx context.Context, file io.ReadSeeker, pages []int) (io.ReadCloser, int64, error) {
+	ret := _m.Called(ctx, file, pages)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Split")
 	}
 
-	var r0 []byte
-	var r1 error
-	if rf, ok := ret.Get(0).(func(multipart.File, []int) ([]byte, error)); ok {
-		return rf(file, pages)
+	var r0 io.ReadCloser
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, []int) (io.ReadCloser, int64, error)); ok {
+		return rf(ctx, file, pages)
 	}
-	if rf, ok := ret.Get(0).(func(multipart.File, []int) []byte); ok {
-		r0 = rf(file, pages)
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, []int) io.ReadCloser); ok {
+		r0 = rf(ctx, file, pages)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]byte)
+			r0 = ret.Get(0).(io.ReadCloser)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(multipart.File, []int) error); ok {
-		r1 = rf(file, pages)
+	if rf, ok := ret.Get(1).(func(context.Context, io.ReadSeeker, []int) int64); ok {
+		r1 = rf(ctx, file, pages)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(int64)
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, io.ReadSeeker, []int) error); ok {
+		r2 = rf(ctx, file, pages)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SplitRanges provides a mock function with given fields: ctx, file, ranges, emit
+func (_m *PdfRepository) SplitRanges(ctx context.Context, file io.ReadSeeker, ranges [][]int, emit func(int, io.Reader, int64) error) error {
+	ret := _m.Called(ctx, file, ranges, emit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SplitRanges")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, [][]int, func(int, io.Reader, int64) error) error); ok {
+		r0 = rf(ctx, file, ranges, emit)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Spool provides a mock function with given fields: ctx, content
+func (_m *PdfRepository) Spool(ctx context.Context, content io.Reader) (domain.SpooledContent, int64, error) {
+	ret := _m.Called(ctx, content)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Spool")
+	}
+
+	var r0 domain.SpooledContent
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader) (domain.SpooledContent, int64, error)); ok {
+		return rf(ctx, content)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader) domain.SpooledContent); ok {
+		r0 = rf(ctx, content)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(domain.SpooledContent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.Reader) int64); ok {
+		r1 = rf(ctx, content)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, io.Reader) error); ok {
+		r2 = rf(ctx, content)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Stamp provides a mock function with given fields: ctx, file, stamps
+func (_m *PdfRepository) Stamp(ctx context.Context, file io.ReadSeeker, stamps map[int]string) (io.ReadCloser, int64, error) {
+	ret := _m.Called(ctx, file, stamps)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stamp")
+	}
+
+	var r0 io.ReadCloser
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, map[int]string) (io.ReadCloser, int64, error)); ok {
+		return rf(ctx, file, stamps)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.ReadSeeker, map[int]string) io.ReadCloser); ok {
+		r0 = rf(ctx, file, stamps)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.ReadSeeker, map[int]string) int64); ok {
+		r1 = rf(ctx, file, stamps)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, io.ReadSeeker, map[int]string) error); ok {
+		r2 = rf(ctx, file, stamps)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // NewPdfRepository creates a new instance of PdfRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
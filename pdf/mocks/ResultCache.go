@@ -0,0 +1,84 @@
+// Code generated by mockery v2.50.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ResultCache is an autogenerated mock type for the ResultCache type
+type ResultCache struct {
+	mock.Mock
+}
+
+// Get provides a mock function with given fields: ctx, key
+func (_m *ResultCache) Get(ctx context.Context, key string) (io.ReadCloser, int64, bool) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 io.ReadCloser
+	var r1 int64
+	var r2 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string) (io.ReadCloser, int64, bool)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) io.ReadCloser); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) int64); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) bool); ok {
+		r2 = rf(ctx, key)
+	} else {
+		r2 = ret.Get(2).(bool)
+	}
+
+	return r0, r1, r2
+}
+
+// Put provides a mock function with given fields: ctx, key, content
+func (_m *ResultCache) Put(ctx context.Context, key string, content io.Reader) error {
+	ret := _m.Called(ctx, key, content)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Put")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, io.Reader) error); ok {
+		r0 = rf(ctx, key, content)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewResultCache creates a new instance of ResultCache. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewResultCache(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ResultCache {
+	mock := &ResultCache{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,237 @@
+package pdf
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// Default names of the parts of a split and of the zip holding them
+const (
+	DefaultPartName = "split_part_{index}.pdf"
+	manifestName    = "manifest.json"
+)
+
+// ErrInvalidNaming is a naming template that can't be expanded
+var ErrInvalidNaming = errors.New("invalid naming template")
+
+// namingVariables lists what a template may refer to, and whether it is a number that
+// may be zero padded as in {index:3}
+var namingVariables = map[string]bool{
+	"base":     false,
+	"index":    true,
+	"from":     true,
+	"to":       true,
+	"bookmark": false,
+}
+
+var archiveVariables = map[string]bool{
+	"base": false,
+}
+
+// ValidateNaming checks that the templates of naming only use known variables
+func ValidateNaming(naming domain.SplitNaming) error {
+	if _, err := parseTemplate(naming.Part, namingVariables); err != nil {
+		return fmt.Errorf("part name: %w", err)
+	}
+	if _, err := parseTemplate(naming.Archive, archiveVariables); err != nil {
+		return fmt.Errorf("zip name: %w", err)
+	}
+	return nil
+}
+
+// templateSegment is literal text or, when variable is set, a variable padded to width
+type templateSegment struct {
+	text     string
+	variable string
+	width    int
+}
+
+type template []templateSegment
+
+func parseTemplate(text string, variables map[string]bool) (template, error) {
+	var segments template
+	for text != "" {
+		open := strings.IndexAny(text, "{}")
+		if open < 0 {
+			segments = append(segments, templateSegment{text: text})
+			break
+		}
+		if text[open] == '}' {
+			return nil, fmt.Errorf("%w: unexpected '}'", ErrInvalidNaming)
+		}
+		if open > 0 {
+			segments = append(segments, templateSegment{text: text[:open]})
+		}
+
+		end := strings.IndexByte(text[open:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("%w: missing '}'", ErrInvalidNaming)
+		}
+		segment, err := parseVariable(text[open+1:open+end], variables)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, segment)
+		text = text[open+end+1:]
+	}
+	return segments, nil
+}
+
+func parseVariable(text string, variables map[string]bool) (templateSegment, error) {
+	name, width, padded := strings.Cut(text, ":")
+	numeric, ok := variables[name]
+	if !ok {
+		return templateSegment{}, fmt.Errorf("%w: unknown variable {%s}", ErrInvalidNaming, name)
+	}
+
+	segment := templateSegment{variable: name}
+	if padded {
+		n, err := strconv.Atoi(width)
+		if !numeric || err != nil || n < 1 || n > 9 {
+			return templateSegment{}, fmt.Errorf("%w: invalid padding in {%s}", ErrInvalidNaming, text)
+		}
+		segment.width = n
+	}
+	return segment, nil
+}
+
+func (t template) uses(variable string) bool {
+	for _, segment := range t {
+		if segment.variable == variable {
+			return true
+		}
+	}
+	return false
+}
+
+func (t template) expand(values map[string]string) string {
+	var name strings.Builder
+	for _, segment := range t {
+		if segment.variable == "" {
+			name.WriteString(segment.text)
+			continue
+		}
+		value := values[segment.variable]
+		if pad := segment.width - len(value); pad > 0 {
+			value = strings.Repeat("0", pad) + value
+		}
+		name.WriteString(value)
+	}
+	return name.String()
+}
+
+// partNamer names the parts of one split. Names are made safe for zip entries and
+// unique within the zip.
+type partNamer struct {
+	part      template
+	archive   template
+	base      string
+	bookmarks []domain.Bookmark
+	used      map[string]bool
+}
+
+func newPartNamer(naming domain.SplitNaming, fileName string) (*partNamer, error) {
+	if naming.Part == "" {
+		naming.Part = DefaultPartName
+	}
+	part, err := parseTemplate(naming.Part, namingVariables)
+	if err != nil {
+		return nil, err
+	}
+	archive, err := parseTemplate(naming.Archive, archiveVariables)
+	if err != nil {
+		return nil, err
+	}
+
+	return &partNamer{
+		part:    part,
+		archive: archive,
+		base:    strings.TrimSuffix(fileName, filepath.Ext(fileName)),
+		used:    make(map[string]bool),
+	}, nil
+}
+
+// needsBookmarks tells whether the outline of the document has to be read first
+func (n *partNamer) needsBookmarks() bool {
+	return n.part.uses("bookmark")
+}
+
+// key identifies the names the parts get, it is part of the cache key since the names
+// end up in the zip
+func (n *partNamer) key() string {
+	var key strings.Builder
+	for _, segment := range n.part {
+		fmt.Fprintf(&key, "%q%s:%d", segment.text, segment.variable, segment.width)
+	}
+	if n.part.uses("base") {
+		fmt.Fprintf(&key, "|%q", n.base)
+	}
+	return key.String()
+}
+
+// archiveName names the zip, split_<fileName>.zip unless a template was given
+func (n *partNamer) archiveName(fileName string) string {
+	if len(n.archive) == 0 {
+		return "split_" + fileName + ".zip"
+	}
+	return withExtension(sanitizeName(n.archive.expand(map[string]string{"base": n.base}), n.base), ".zip")
+}
+
+// name returns the entry name of the part at index holding pages
+func (n *partNamer) name(index int, pages []int) string {
+	fallback := fmt.Sprintf("part_%d", index+1)
+	name := n.part.expand(map[string]string{
+		"base":     n.base,
+		"index":    strconv.Itoa(index + 1),
+		"from":     strconv.Itoa(pages[0]),
+		"to":       strconv.Itoa(pages[len(pages)-1]),
+		"bookmark": n.bookmark(pages[0]),
+	})
+	name = withExtension(sanitizeName(name, fallback), ".pdf")
+
+	if n.used[name] || name == manifestName {
+		ext := filepath.Ext(name)
+		name = fmt.Sprintf("%s_%d%s", strings.TrimSuffix(name, ext), index+1, ext)
+	}
+	n.used[name] = true
+	return name
+}
+
+// bookmark is the title of the deepest outline entry on or before page, the chapter
+// the part starts in
+func (n *partNamer) bookmark(page int) string {
+	title, titlePage := "", 0
+	for _, bookmark := range n.bookmarks {
+		if bookmark.Page > 0 && bookmark.Page <= page && bookmark.Page >= titlePage {
+			title, titlePage = bookmark.Title, bookmark.Page
+		}
+	}
+	return title
+}
+
+// sanitizeName drops what could be read as a path or breaks file systems
+func sanitizeName(name, fallback string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.Trim(name, " .")
+	if name == "" {
+		return fallback
+	}
+	return name
+}
+
+func withExtension(name, ext string) string {
+	if strings.EqualFold(filepath.Ext(name), ext) {
+		return name
+	}
+	return name + ext
+}
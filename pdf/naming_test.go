@@ -0,0 +1,35 @@
+package pdf_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/pdf"
+)
+
+func TestValidateNaming(t *testing.T) {
+	t.Run("when templates use known variables should accept them", func(t *testing.T) {
+		for _, naming := range []domain.SplitNaming{
+			{},
+			{Part: pdf.DefaultPartName},
+			{Part: "{base}_{index:3}_{from}-{to} {bookmark}.pdf", Archive: "{base}-parts.zip"},
+		} {
+			assert.NoError(t, pdf.ValidateNaming(naming), naming)
+		}
+	})
+
+	t.Run("when templates can't be expanded should return ErrInvalidNaming", func(t *testing.T) {
+		for _, naming := range []domain.SplitNaming{
+			{Part: "{page}"},
+			{Part: "{index"},
+			{Part: "index}"},
+			{Part: "{bookmark:3}"},
+			{Part: "{index:x}"},
+			{Archive: "{index}.zip"},
+		} {
+			assert.ErrorIs(t, pdf.ValidateNaming(naming), pdf.ErrInvalidNaming, naming)
+		}
+	})
+}
@@ -0,0 +1,208 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// Built-in split modes
+const (
+	SplitModeRanges      = "ranges"
+	SplitModeFixedRange  = "fixed_range"
+	SplitModeRemovePages = "remove_pages"
+)
+
+// Built-in processors
+const (
+	OperationCompress = "compress"
+	OperationCrop     = "crop"
+	OperationAnalyze  = "analyze"
+)
+
+// marginsAuto asks crop to trim every page to what it draws
+const marginsAuto = "auto"
+
+type rangesParams struct {
+	Ranges string `param:"ranges" doc:"Pages to keep when split_mode = ranges (e.g. '1-3,5')"`
+
+	pages []int
+}
+
+type removePagesParams struct {
+	RemovePages string `param:"remove_page" flag:"remove-pages" doc:"Pages to drop when split_mode = remove_pages (e.g. '2,4-6')"`
+
+	pages []int
+}
+
+type fixedRangeParams struct {
+	FixedRange int    `param:"fixed_range" doc:"Pages per part when split_mode = fixed_range"`
+	PartName   string `param:"part_name" doc:"Name of the parts in the zip of split_mode = fixed_range, may use {base}, {index}, {from}, {to} and {bookmark}, numbers may be padded as in {index:3} (default 'split_part_{index}.pdf')"`
+	ZipName    string `param:"zip_name" doc:"Name of the zip of split_mode = fixed_range, may use {base} (default 'split_<file name>.zip')"`
+}
+
+type cropParams struct {
+	Margins string `param:"margins" doc:"'auto' to trim every page to what it draws, or the margins in points to take off the crop box: one value for every side, 'vertical,horizontal' or 'top,right,bottom,left'"`
+	Padding int    `param:"padding" doc:"Points to leave around what a page draws when margins = auto"`
+	Box     string `param:"box" doc:"Box to set: 'crop' (default), 'trim' or 'bleed'"`
+	Pages   string `param:"pages" doc:"Pages to set the box on (e.g. '1-3,5'), every page when empty"`
+
+	options domain.CropOptions
+}
+
+func (p fixedRangeParams) naming() domain.SplitNaming {
+	return domain.SplitNaming{Part: p.PartName, Archive: p.ZipName}
+}
+
+func init() {
+	Register(DefaultRegistry, Operation[rangesParams]{
+		Kind:        KindSplit,
+		Name:        SplitModeRanges,
+		Description: "keep the pages of the given ranges",
+		Validate: func(p *rangesParams) (err error) {
+			p.pages, err = parsePageList(p.Ranges)
+			return err
+		},
+		Run: func(ctx context.Context, exec Executor, p rangesParams, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+			if _, err := checkPages(ctx, exec, file, p.pages); err != nil {
+				return domain.PdfFile{}, err
+			}
+			return exec.SplitPdfByRanges(ctx, fileName, file, p.pages)
+		},
+	})
+
+	Register(DefaultRegistry, Operation[fixedRangeParams]{
+		Kind:        KindSplit,
+		Name:        SplitModeFixedRange,
+		Description: "cut the document into parts of a fixed number of pages, returned as a zip",
+		Validate: func(p *fixedRangeParams) error {
+			if p.FixedRange <= 0 {
+				return ParamError("Fixed range must be greater than 0")
+			}
+			if err := ValidateNaming(p.naming()); err != nil {
+				return ParamError(err.Error())
+			}
+			return nil
+		},
+		Run: func(ctx context.Context, exec Executor, p fixedRangeParams, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+			pageCount, err := checkPages(ctx, exec, file, nil)
+			if err != nil {
+				return domain.PdfFile{}, err
+			}
+			return exec.SplitAndZipPdfByFixedRange(ctx, fileName, file, FixedRanges(pageCount, p.FixedRange), p.naming())
+		},
+	})
+
+	Register(DefaultRegistry, Operation[removePagesParams]{
+		Kind:        KindSplit,
+		Name:        SplitModeRemovePages,
+		Description: "drop the pages of the given ranges",
+		Validate: func(p *removePagesParams) (err error) {
+			p.pages, err = parsePageList(p.RemovePages)
+			return err
+		},
+		Run: func(ctx context.Context, exec Executor, p removePagesParams, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+			pageCount, err := checkPages(ctx, exec, file, p.pages)
+			if err != nil {
+				return domain.PdfFile{}, err
+			}
+			return exec.RemovePagesPdf(ctx, fileName, file, p.pages, pageCount)
+		},
+	})
+
+	Register(DefaultRegistry, Operation[struct{}]{
+		Kind:        KindProcess,
+		Name:        OperationCompress,
+		Description: "optimize the document",
+		Run: func(ctx context.Context, exec Executor, _ struct{}, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+			return exec.CompressPdf(ctx, fileName, file)
+		},
+	})
+
+	Register(DefaultRegistry, Operation[cropParams]{
+		Kind:        KindProcess,
+		Name:        OperationCrop,
+		Description: "set the crop, trim or bleed box of pages, by margins or around what they draw",
+		Validate: func(p *cropParams) (err error) {
+			p.options.Box = p.Box
+			switch p.Box {
+			case "":
+				p.options.Box = domain.BoxCrop
+			case domain.BoxCrop, domain.BoxTrim, domain.BoxBleed:
+			default:
+				return ParamError("box must be crop, trim or bleed")
+			}
+
+			switch {
+			case p.Margins == "":
+				return ParamError("margins is required")
+			case p.Margins == marginsAuto:
+				if p.Padding < 0 {
+					return ParamError("padding must not be negative")
+				}
+				padding := float64(p.Padding)
+				p.options.Auto = true
+				p.options.Margins = domain.PdfMargins{Top: padding, Right: padding, Bottom: padding, Left: padding}
+			case p.Padding != 0:
+				return ParamError("padding goes with margins = auto")
+			default:
+				if p.options.Margins, err = ParseMargins(p.Margins); err != nil {
+					return ParamError(err.Error())
+				}
+			}
+
+			if p.Pages != "" {
+				if p.options.Pages, err = parsePageList(p.Pages); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Run: func(ctx context.Context, exec Executor, p cropParams, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+			if _, err := checkPages(ctx, exec, file, p.options.Pages); err != nil {
+				return domain.PdfFile{}, err
+			}
+			return exec.CropPdf(ctx, fileName, file, p.options)
+		},
+	})
+
+	Register(DefaultRegistry, Operation[struct{}]{
+		Kind:        KindProcess,
+		Name:        OperationAnalyze,
+		Description: "report objects, streams, duplicate and unused resources and the largest images as JSON, with the compression setting that saves the most",
+		Run: func(ctx context.Context, exec Executor, _ struct{}, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+			return exec.AnalyzePdf(ctx, fileName, file)
+		},
+	})
+}
+
+// parsePageList parses a required list of page ranges such as "1-3,5"
+func parsePageList(ranges string) ([]int, error) {
+	if ranges == "" {
+		return nil, ParamError("Invalid Range")
+	}
+	pages, err := ParseRanges(ranges)
+	if err != nil {
+		return nil, ParamError(err.Error())
+	}
+	return pages, nil
+}
+
+// checkPages counts the pages of file, makes sure pages exist and rewinds file for
+// the operation that follows
+func checkPages(ctx context.Context, exec Executor, file io.ReadSeeker, pages []int) (int, error) {
+	pageCount, err := exec.PageCount(ctx, file)
+	if err != nil {
+		return 0, err
+	}
+	if len(pages) > 0 && slices.Max(pages) > pageCount {
+		return 0, ParamError("Ranges exceed page count")
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("failed to rewind pdf: %w", err)
+	}
+	return pageCount, nil
+}
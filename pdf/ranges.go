@@ -0,0 +1,94 @@
+package pdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FixedRanges cuts totalPages into chunks of fixedRange pages, the last one may be shorter
+func FixedRanges(totalPages int, fixedRange int) [][]int {
+	var result [][]int
+	for i := 1; i <= totalPages; i += fixedRange {
+		end := i + fixedRange - 1
+		if end > totalPages {
+			end = totalPages
+		}
+		var chunk []int
+		for j := i; j <= end; j++ {
+			chunk = append(chunk, j)
+		}
+		result = append(result, chunk)
+	}
+
+	return result
+}
+
+// ParseRanges expands a page selection such as "1,3-5" into page numbers, in order.
+// Pages are numbered from 1.
+func ParseRanges(inputRange string) ([]int, error) {
+	var result []int
+
+	parts := strings.Split(inputRange, ",")
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+
+		if strings.Contains(part, "-") {
+			rangeParts := strings.Split(part, "-")
+			if len(rangeParts) != 2 {
+				return nil, fmt.Errorf("invalid range format: %s", part)
+			}
+
+			start, err := strconv.Atoi(strings.TrimSpace(rangeParts[0]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid number in range start: %s", rangeParts[0])
+			}
+
+			end, err := strconv.Atoi(strings.TrimSpace(rangeParts[1]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid number in range end: %s", rangeParts[1])
+			}
+
+			if start > end {
+				return nil, fmt.Errorf("range start cannot be greater than range end")
+			}
+			if start < 1 {
+				return nil, fmt.Errorf("pages are numbered from 1: %s", part)
+			}
+
+			for i := start; i <= end; i++ {
+				result = append(result, i)
+			}
+		} else {
+			num, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number: %s", part)
+			}
+			if num < 1 {
+				return nil, fmt.Errorf("pages are numbered from 1: %s", part)
+			}
+			result = append(result, num)
+		}
+	}
+
+	return result, nil
+}
+
+// FormatRanges is the reverse of ParseRanges, runs of consecutive pages are joined,
+// e.g. 1, 2, 3, 5 gives "1-3,5"
+func FormatRanges(pages []int) string {
+	var parts []string
+	for i := 0; i < len(pages); {
+		j := i
+		for j+1 < len(pages) && pages[j+1] == pages[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.Itoa(pages[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", pages[i], pages[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
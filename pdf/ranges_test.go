@@ -0,0 +1,37 @@
+package pdf_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bxcodec/go-clean-arch/pdf"
+)
+
+func TestParseRanges(t *testing.T) {
+	t.Run("when ranges are valid should expand them in order", func(t *testing.T) {
+		pages, err := pdf.ParseRanges("1, 3-5,2")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 3, 4, 5, 2}, pages)
+	})
+
+	t.Run("when ranges are invalid should return error", func(t *testing.T) {
+		for _, input := range []string{"a", "1-", "5-3", "1-2-3", "0", "0-2"} {
+			_, err := pdf.ParseRanges(input)
+
+			assert.Error(t, err, input)
+		}
+	})
+}
+
+func TestFixedRanges(t *testing.T) {
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, pdf.FixedRanges(5, 2))
+	assert.Empty(t, pdf.FixedRanges(0, 2))
+}
+
+func TestFormatRanges(t *testing.T) {
+	assert.Equal(t, "1-3,5,7-8", pdf.FormatRanges([]int{1, 2, 3, 5, 7, 8}))
+	assert.Equal(t, "4", pdf.FormatRanges([]int{4}))
+	assert.Equal(t, "", pdf.FormatRanges(nil))
+}
@@ -0,0 +1,243 @@
+package pdf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// Kinds of operations
+const (
+	// KindSplit operations are the split modes of /process/split, picked by split_mode
+	KindSplit = "split"
+	// KindProcess operations turn a document into another one, each at /process/{name}
+	KindProcess = "process"
+)
+
+// ErrUnknownOperation is returned for a name no operation of the kind was registered under
+var ErrUnknownOperation = errors.New("unknown operation")
+
+// Executor runs documents through the PDF usecase, operations are built on top of it.
+// Service implements it, so do the services the handlers are given.
+//
+//go:generate mockery --name Executor
+type Executor interface {
+	CompressPdf(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error)
+	SplitPdfByRanges(ctx context.Context, fileName string, file io.ReadSeeker, ranges []int) (domain.PdfFile, error)
+	SplitAndZipPdfByFixedRange(ctx context.Context, fileName string, file io.ReadSeeker, fra [][]int, naming domain.SplitNaming) (domain.PdfFile, error)
+	RemovePagesPdf(ctx context.Context, fileName string, file io.ReadSeeker, removePages []int, pageCount int) (domain.PdfFile, error)
+	PageCount(ctx context.Context, file io.ReadSeeker) (int, error)
+	CropPdf(ctx context.Context, fileName string, file io.ReadSeeker, options domain.CropOptions) (domain.PdfFile, error)
+	AnalyzePdf(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error)
+}
+
+// ParamError is a parameter of an operation that doesn't fit, clients see it as is
+type ParamError string
+
+func (e ParamError) Error() string {
+	return string(e)
+}
+
+// Operation is a split mode or processor of a single document. P is its parameter
+// struct: exported fields tagged `param:"name"` are filled from the request by that
+// name, `flag:"name"` renames the flag on the command line and `doc:"..."` documents
+// the parameter. Fields may be string, int or bool.
+type Operation[P any] struct {
+	Kind        string
+	Name        string
+	Description string
+	// Validate checks params before any document is read and may keep what it parsed
+	// in unexported fields. Nil accepts any params.
+	Validate func(params *P) error
+	// Run processes a single document
+	Run func(ctx context.Context, exec Executor, params P, fileName string, file io.ReadSeeker) (domain.PdfFile, error)
+}
+
+// Param describes a parameter of an operation for docs and command lines
+type Param struct {
+	Name string
+	Flag string
+	// Type is string, integer or boolean, as in Swagger
+	Type string
+	Doc  string
+}
+
+// OperationInfo describes a registered operation
+type OperationInfo struct {
+	Kind        string
+	Name        string
+	Description string
+	Params      []Param
+}
+
+// Registry holds the operations handlers, docs and the command line dispatch to
+type Registry struct {
+	mu sync.RWMutex
+	// operations lists the operations of each kind in the order they were registered
+	operations map[string][]*registeredOperation
+}
+
+type registeredOperation struct {
+	info    OperationInfo
+	prepare func(exec Executor, values func(name string) string) (domain.PdfProcessor, error)
+}
+
+// DefaultRegistry holds the built-in operations. Other packages add theirs with Register
+// from an init function.
+var DefaultRegistry = NewRegistry()
+
+func NewRegistry() *Registry {
+	return &Registry{operations: map[string][]*registeredOperation{}}
+}
+
+// Register adds op to r. Like http.Handle it panics when the name is taken or P can't
+// be filled from a request, both are mistakes of the program.
+func Register[P any](r *Registry, op Operation[P]) {
+	params, fields, err := paramsOf(reflect.TypeFor[P]())
+	if err != nil {
+		panic(fmt.Sprintf("pdf: operation %s: %v", op.Name, err))
+	}
+
+	registered := &registeredOperation{
+		info: OperationInfo{Kind: op.Kind, Name: op.Name, Description: op.Description, Params: params},
+		prepare: func(exec Executor, values func(name string) string) (domain.PdfProcessor, error) {
+			var p P
+			target := reflect.ValueOf(&p).Elem()
+			for i, param := range params {
+				if err := setParam(target.Field(fields[i]), param, values(param.Name)); err != nil {
+					return nil, err
+				}
+			}
+			if op.Validate != nil {
+				if err := op.Validate(&p); err != nil {
+					return nil, err
+				}
+			}
+
+			return func(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+				return op.Run(ctx, exec, p, fileName, file)
+			}, nil
+		},
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.lookup(op.Kind, op.Name) != nil {
+		panic(fmt.Sprintf("pdf: operation %s %s registered twice", op.Kind, op.Name))
+	}
+	r.operations[op.Kind] = append(r.operations[op.Kind], registered)
+}
+
+// List returns the operations of kind in the order they were registered
+func (r *Registry) List(kind string) []OperationInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	infos := make([]OperationInfo, 0, len(r.operations[kind]))
+	for _, op := range r.operations[kind] {
+		infos = append(infos, op.info)
+	}
+	return infos
+}
+
+// Lookup returns the operation of kind registered under name
+func (r *Registry) Lookup(kind, name string) (OperationInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if op := r.lookup(kind, name); op != nil {
+		return op.info, true
+	}
+	return OperationInfo{}, false
+}
+
+// Prepare fills the parameters of the operation from values, which returns "" for a
+// parameter that was not given, validates them and returns the operation for a single
+// document. Parameters that don't fit are reported as a ParamError.
+func (r *Registry) Prepare(exec Executor, kind, name string, values func(name string) string) (domain.PdfProcessor, error) {
+	r.mu.RLock()
+	op := r.lookup(kind, name)
+	r.mu.RUnlock()
+
+	if op == nil {
+		return nil, fmt.Errorf("%w: %s %q", ErrUnknownOperation, kind, name)
+	}
+	return op.prepare(exec, values)
+}
+
+func (r *Registry) lookup(kind, name string) *registeredOperation {
+	for _, op := range r.operations[kind] {
+		if op.info.Name == name {
+			return op
+		}
+	}
+	return nil
+}
+
+// paramTypes maps the supported field kinds to their Swagger type
+var paramTypes = map[reflect.Kind]string{
+	reflect.String: "string",
+	reflect.Int:    "integer",
+	reflect.Bool:   "boolean",
+}
+
+// paramsOf describes the tagged fields of the parameter struct t and returns their
+// indexes along
+func paramsOf(t reflect.Type) ([]Param, []int, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("parameters must be a struct, not %s", t)
+	}
+
+	var params []Param
+	var fields []int
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, ok := field.Tag.Lookup("param")
+		if !ok {
+			continue
+		}
+		paramType, ok := paramTypes[field.Type.Kind()]
+		if !ok || !field.IsExported() {
+			return nil, nil, fmt.Errorf("parameter %s must be an exported string, int or bool", name)
+		}
+
+		flag := field.Tag.Get("flag")
+		if flag == "" {
+			flag = strings.ReplaceAll(name, "_", "-")
+		}
+		params = append(params, Param{Name: name, Flag: flag, Type: paramType, Doc: field.Tag.Get("doc")})
+		fields = append(fields, i)
+	}
+	return params, fields, nil
+}
+
+func setParam(field reflect.Value, param Param, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return ParamError(fmt.Sprintf("%s must be a number", param.Name))
+		}
+		field.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return ParamError(fmt.Sprintf("%s must be true or false", param.Name))
+		}
+		field.SetBool(b)
+	default:
+		field.SetString(value)
+	}
+	return nil
+}
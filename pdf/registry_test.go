@@ -0,0 +1,230 @@
+package pdf_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/pdf"
+	"github.com/bxcodec/go-clean-arch/pdf/mocks"
+)
+
+type stampParams struct {
+	Text   string `param:"text" doc:"Text of the stamp"`
+	Page   int    `param:"page" flag:"on-page"`
+	Bold   bool   `param:"bold"`
+	Ignore string
+}
+
+func TestRegistry(t *testing.T) {
+	// values looks the parameters up in a map, as the gRPC server does
+	values := func(params map[string]string) func(string) string {
+		return func(name string) string { return params[name] }
+	}
+	newRegistry := func(run func(params stampParams) error) *pdf.Registry {
+		registry := pdf.NewRegistry()
+		pdf.Register(registry, pdf.Operation[stampParams]{
+			Kind:        pdf.KindProcess,
+			Name:        "stamp",
+			Description: "stamp the pages",
+			Validate: func(p *stampParams) error {
+				if p.Text == "" {
+					return pdf.ParamError("text is required")
+				}
+				return nil
+			},
+			Run: func(ctx context.Context, exec pdf.Executor, p stampParams, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
+				return domain.PdfFile{Name: fileName}, run(p)
+			},
+		})
+		return registry
+	}
+
+	t.Run("when registered should describe the parameters of the operation", func(t *testing.T) {
+		registry := newRegistry(nil)
+
+		actual := registry.List(pdf.KindProcess)
+
+		require.Len(t, actual, 1)
+		assert.Equal(t, "stamp", actual[0].Name)
+		assert.Equal(t, []pdf.Param{
+			{Name: "text", Flag: "text", Type: "string", Doc: "Text of the stamp"},
+			{Name: "page", Flag: "on-page", Type: "integer"},
+			{Name: "bold", Flag: "bold", Type: "boolean"},
+		}, actual[0].Params)
+		assert.Empty(t, registry.List(pdf.KindSplit))
+	})
+
+	t.Run("when prepared should run with the parsed parameters", func(t *testing.T) {
+		var actual stampParams
+		registry := newRegistry(func(p stampParams) error {
+			actual = p
+			return nil
+		})
+
+		op, err := registry.Prepare(nil, pdf.KindProcess, "stamp", values(map[string]string{"text": "draft", "page": "2", "bold": "true", "Ignore": "x"}))
+		require.NoError(t, err)
+		result, err := op(context.TODO(), "a.pdf", nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, "a.pdf", result.Name)
+		assert.Equal(t, stampParams{Text: "draft", Page: 2, Bold: true}, actual)
+	})
+
+	t.Run("when parameters don't fit should return a ParamError", func(t *testing.T) {
+		registry := newRegistry(nil)
+
+		for message, params := range map[string]map[string]string{
+			"text is required":           {},
+			"page must be a number":      {"text": "draft", "page": "two"},
+			"bold must be true or false": {"text": "draft", "bold": "very"},
+		} {
+			_, err := registry.Prepare(nil, pdf.KindProcess, "stamp", values(params))
+
+			var param pdf.ParamError
+			require.ErrorAs(t, err, &param)
+			assert.Equal(t, message, param.Error())
+		}
+	})
+
+	t.Run("when the name is unknown should return ErrUnknownOperation", func(t *testing.T) {
+		registry := newRegistry(nil)
+
+		_, err := registry.Prepare(nil, pdf.KindSplit, "stamp", values(nil))
+
+		assert.ErrorIs(t, err, pdf.ErrUnknownOperation)
+	})
+
+	t.Run("when registered twice or with unsupported parameters should panic", func(t *testing.T) {
+		registry := newRegistry(nil)
+
+		assert.Panics(t, func() {
+			pdf.Register(registry, pdf.Operation[stampParams]{Kind: pdf.KindProcess, Name: "stamp"})
+		})
+		assert.Panics(t, func() {
+			pdf.Register(registry, pdf.Operation[struct {
+				Pages []int `param:"pages"`
+			}]{Kind: pdf.KindProcess, Name: "pages"})
+		})
+		assert.Panics(t, func() {
+			pdf.Register(registry, pdf.Operation[string]{Kind: pdf.KindProcess, Name: "text"})
+		})
+	})
+}
+
+func TestBuiltInOperations(t *testing.T) {
+	values := func(params map[string]string) func(string) string {
+		return func(name string) string { return params[name] }
+	}
+	openPdf := func(t *testing.T) *os.File {
+		file, err := os.Open("../internal/resource/test.pdf")
+		require.NoError(t, err)
+		t.Cleanup(func() { file.Close() })
+		return file
+	}
+
+	t.Run("when listing split modes should keep the order they were registered in", func(t *testing.T) {
+		var names []string
+		for _, op := range pdf.DefaultRegistry.List(pdf.KindSplit) {
+			names = append(names, op.Name)
+		}
+		assert.Equal(t, []string{pdf.SplitModeRanges, pdf.SplitModeFixedRange, pdf.SplitModeRemovePages}, names)
+	})
+
+	t.Run("when remove pages fit the document should pass the page count on", func(t *testing.T) {
+		exec := new(mocks.Executor)
+		file := openPdf(t)
+		exec.On("PageCount", mock.Anything, file).Return(5, nil).Once()
+		exec.On("RemovePagesPdf", mock.Anything, "a.pdf", file, []int{2, 3}, 5).Return(domain.PdfFile{Name: "removed.pdf"}, nil).Once()
+
+		op, err := pdf.DefaultRegistry.Prepare(exec, pdf.KindSplit, pdf.SplitModeRemovePages, values(map[string]string{"remove_page": "2-3"}))
+		require.NoError(t, err)
+		actual, err := op(context.TODO(), "a.pdf", file)
+
+		require.NoError(t, err)
+		assert.Equal(t, "removed.pdf", actual.Name)
+		exec.AssertExpectations(t)
+	})
+
+	t.Run("when ranges exceed the page count should return a ParamError", func(t *testing.T) {
+		exec := new(mocks.Executor)
+		exec.On("PageCount", mock.Anything, mock.Anything).Return(2, nil).Once()
+
+		op, err := pdf.DefaultRegistry.Prepare(exec, pdf.KindSplit, pdf.SplitModeRanges, values(map[string]string{"ranges": "1-3"}))
+		require.NoError(t, err)
+		_, err = op(context.TODO(), "a.pdf", strings.NewReader(""))
+
+		assert.Equal(t, pdf.ParamError("Ranges exceed page count"), err)
+		exec.AssertNotCalled(t, "SplitPdfByRanges", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("when fixed range is split should cut the page count into parts", func(t *testing.T) {
+		exec := new(mocks.Executor)
+		exec.On("PageCount", mock.Anything, mock.Anything).Return(5, nil).Once()
+		exec.On("SplitAndZipPdfByFixedRange", mock.Anything, "a.pdf", mock.Anything, [][]int{{1, 2}, {3, 4}, {5}}, domain.SplitNaming{Part: "{index}.pdf"}).
+			Return(domain.PdfFile{Name: "split.zip"}, nil).Once()
+
+		op, err := pdf.DefaultRegistry.Prepare(exec, pdf.KindSplit, pdf.SplitModeFixedRange, values(map[string]string{"fixed_range": "2", "part_name": "{index}.pdf"}))
+		require.NoError(t, err)
+		_, err = op(context.TODO(), "a.pdf", strings.NewReader(""))
+
+		require.NoError(t, err)
+		exec.AssertExpectations(t)
+	})
+
+	t.Run("when split parameters are invalid should fail before reading the document", func(t *testing.T) {
+		for mode, params := range map[string]map[string]string{
+			pdf.SplitModeRanges:      {},
+			pdf.SplitModeRemovePages: {"remove_page": "x"},
+			pdf.SplitModeFixedRange:  {"fixed_range": "0"},
+		} {
+			_, err := pdf.DefaultRegistry.Prepare(new(mocks.Executor), pdf.KindSplit, mode, values(params))
+
+			var param pdf.ParamError
+			assert.True(t, errors.As(err, &param), mode)
+		}
+	})
+
+	t.Run("when crop is auto should pass the padding on as margins", func(t *testing.T) {
+		exec := new(mocks.Executor)
+		exec.On("PageCount", mock.Anything, mock.Anything).Return(5, nil).Once()
+		exec.On("CropPdf", mock.Anything, "a.pdf", mock.Anything, domain.CropOptions{
+			Box:     domain.BoxTrim,
+			Pages:   []int{2, 3},
+			Auto:    true,
+			Margins: domain.PdfMargins{Top: 6, Right: 6, Bottom: 6, Left: 6},
+		}).Return(domain.PdfFile{Name: "cropped.pdf"}, nil).Once()
+
+		op, err := pdf.DefaultRegistry.Prepare(exec, pdf.KindProcess, pdf.OperationCrop, values(map[string]string{"margins": "auto", "padding": "6", "box": "trim", "pages": "2-3"}))
+		require.NoError(t, err)
+		_, err = op(context.TODO(), "a.pdf", strings.NewReader(""))
+
+		require.NoError(t, err)
+		exec.AssertExpectations(t)
+	})
+
+	t.Run("when crop parameters are invalid should fail before reading the document", func(t *testing.T) {
+		for _, params := range []map[string]string{
+			{},
+			{"margins": "1,2,3"},
+			{"margins": "-1"},
+			{"margins": "10", "padding": "2"},
+			{"margins": "auto", "box": "art"},
+			{"margins": "auto", "pages": "x"},
+			{"margins": "auto", "pages": "0"},
+		} {
+			_, err := pdf.DefaultRegistry.Prepare(new(mocks.Executor), pdf.KindProcess, pdf.OperationCrop, values(params))
+
+			var param pdf.ParamError
+			assert.True(t, errors.As(err, &param), params)
+		}
+	})
+}
@@ -2,11 +2,12 @@ package pdf
 
 import (
 	"archive/zip"
-	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
-	"mime/multipart"
 	"slices"
 
 	"github.com/bxcodec/go-clean-arch/domain"
@@ -14,50 +15,70 @@ import (
 
 //go:generate mockery --name PdfRepository
 type PdfRepository interface {
-	Compress(file multipart.File) ([]byte, error)
-	Split(file multipart.File, pages []int) ([]byte, error)
-	PageCount(file multipart.File) (int, error)
+	Compress(ctx context.Context, file io.ReadSeeker) (io.ReadCloser, int64, error)
+	Split(ctx context.Context, file io.ReadSeeker, pages []int) (io.ReadCloser, int64, error)
+	// Spool copies content to a temporary file that is removed once it is closed
+	Spool(ctx context.Context, content io.Reader) (domain.SpooledContent, int64, error)
+	// SplitRanges hands each range as its own document to emit, in order
+	SplitRanges(ctx context.Context, file io.ReadSeeker, ranges [][]int, emit func(index int, part io.Reader, size int64) error) error
+	PageCount(ctx context.Context, file io.ReadSeeker) (int, error)
+	// Merge joins files into one document, in order
+	Merge(ctx context.Context, files []io.ReadSeeker) (io.ReadCloser, int64, error)
+	// Assemble builds a document of pages of files in the given order
+	Assemble(ctx context.Context, files []io.ReadSeeker, pages []domain.PageRef) (io.ReadCloser, int64, error)
+	// Bookmarks lists the outline of file, empty when it has none
+	Bookmarks(ctx context.Context, file io.ReadSeeker) ([]domain.Bookmark, error)
+	// Describe reads the pages and document information of file
+	Describe(ctx context.Context, file io.ReadSeeker) (domain.PdfDocumentInfo, error)
+	// Analyze sums up how file is built and what the flate and jpeg settings would save
+	Analyze(ctx context.Context, file io.ReadSeeker) (domain.PdfAnalysis, error)
+	// Stamp copies file with the text of stamps on top of the pages they are keyed by
+	Stamp(ctx context.Context, file io.ReadSeeker, stamps map[int]string) (io.ReadCloser, int64, error)
+	// PageBoxes reads the boxes in effect on every page of file
+	PageBoxes(ctx context.Context, file io.ReadSeeker) ([]domain.PdfPageBoxes, error)
+	// ContentBounds finds where the given pages draw, pages that draw nothing are left out
+	ContentBounds(ctx context.Context, file io.ReadSeeker, pages []int) (map[int]domain.PdfRect, error)
+	// SetBoxes copies file with box set to the rectangle of the page it is keyed by
+	SetBoxes(ctx context.Context, file io.ReadSeeker, box string, rects map[int]domain.PdfRect) (io.ReadCloser, int64, error)
 }
 
 type Service struct {
-	pdfRepo PdfRepository
+	pdfRepo   PdfRepository
+	cache     ResultCache
+	admission *Admission
+	batch     BatchLimits
 }
 
-func NewService(pdfRepo PdfRepository) *Service {
-	return &Service{
+// Option configures the optional collaborators of a Service
+type Option func(*Service)
+
+func NewService(pdfRepo PdfRepository, opts ...Option) *Service {
+	svc := &Service{
 		pdfRepo: pdfRepo,
 	}
-}
-
-func (a *Service) CompressPdf(ctx context.Context, fileName string, file multipart.File) (domain.PdfFile, error) {
-	compressContent, err := a.pdfRepo.Compress(file)
-	if err != nil {
-		return domain.PdfFile{}, err
+	for _, opt := range opts {
+		opt(svc)
 	}
+	return svc
+}
 
+func (a *Service) CompressPdf(ctx context.Context, fileName string, file io.ReadSeeker) (domain.PdfFile, error) {
 	outputName := "compressed_" + fileName
 
-	return domain.PdfFile{
-		Name:    outputName,
-		Content: compressContent,
-	}, nil
+	return a.run(ctx, operation{name: "compress"}, outputName, file, func() (io.ReadCloser, int64, error) {
+		return a.pdfRepo.Compress(ctx, file)
+	})
 }
 
-func (a *Service) SplitPdfByRanges(ctx context.Context, fileName string, file multipart.File, ranges []int) (domain.PdfFile, error) {
-	splitContent, err := a.pdfRepo.Split(file, ranges)
-	if err != nil {
-		return domain.PdfFile{}, err
-	}
-
+func (a *Service) SplitPdfByRanges(ctx context.Context, fileName string, file io.ReadSeeker, ranges []int) (domain.PdfFile, error) {
 	outputName := "split_" + fileName
 
-	return domain.PdfFile{
-		Name:    outputName,
-		Content: splitContent,
-	}, nil
+	return a.run(ctx, splitOperation(ranges), outputName, file, func() (io.ReadCloser, int64, error) {
+		return a.pdfRepo.Split(ctx, file, ranges)
+	})
 }
 
-func (a *Service) RemovePagesPdf(ctx context.Context, fileName string, file multipart.File, removePages []int, pageCount int) (domain.PdfFile, error) {
+func (a *Service) RemovePagesPdf(ctx context.Context, fileName string, file io.ReadSeeker, removePages []int, pageCount int) (domain.PdfFile, error) {
 	ranges := make([]int, 0)
 	for r := range pageCount {
 		if !slices.Contains(removePages, r+1) {
@@ -65,79 +86,157 @@ func (a *Service) RemovePagesPdf(ctx context.Context, fileName string, file mult
 		}
 	}
 
-	splitContent, err := a.pdfRepo.Split(file, ranges)
-	if err != nil {
-		return domain.PdfFile{}, err
-	}
-
 	outputName := "split_" + fileName
 
-	return domain.PdfFile{
-		Name:    outputName,
-		Content: splitContent,
-	}, nil
+	return a.run(ctx, splitOperation(ranges), outputName, file, func() (io.ReadCloser, int64, error) {
+		return a.pdfRepo.Split(ctx, file, ranges)
+	})
 }
 
-func (a *Service) SplitAndZipPdfByFixedRange(ctx context.Context, fileName string, file multipart.File, fra [][]int) (domain.PdfFile, error) {
+// SplitAndZipPdfByFixedRange splits file into the ranges of fra. More than one range
+// gives a zip with a part per range, named after naming, and a manifest.json.
+func (a *Service) SplitAndZipPdfByFixedRange(ctx context.Context, fileName string, file io.ReadSeeker, fra [][]int, naming domain.SplitNaming) (domain.PdfFile, error) {
 	if len(fra) == 1 {
-		return a.splitPdfWithoutZip(file, fileName, fra[0])
+		outputName := "split_" + fileName
+		return a.run(ctx, splitOperation(fra[0]), outputName, file, func() (io.ReadCloser, int64, error) {
+			return a.splitPdfWithoutZip(ctx, file, fra[0])
+		})
 	}
-	return a.splitPdfWithZip(file, fileName, fra)
-}
 
-func (a *Service) splitPdfWithoutZip(file multipart.File, fileName string, rangeSet []int) (domain.PdfFile, error) {
-	splitContent, err := a.pdfRepo.Split(file, rangeSet)
+	namer, err := newPartNamer(naming, fileName)
 	if err != nil {
-		return domain.PdfFile{}, fmt.Errorf("failed to split pdf for range %v: %w", rangeSet, err)
+		return domain.PdfFile{}, err
 	}
 
-	outputName := "split_" + fileName
-	return domain.PdfFile{
-		Name:    outputName,
-		Content: splitContent,
-	}, nil
+	outputName := namer.archiveName(fileName)
+	op := operation{name: "split_zip", params: fmt.Sprint(fra) + "|" + namer.key()}
+	return a.run(ctx, op, outputName, file, func() (io.ReadCloser, int64, error) {
+		return a.splitPdfWithZip(ctx, file, fra, namer)
+	})
 }
 
-func (a *Service) splitPdfWithZip(file multipart.File, fileName string, fra [][]int) (domain.PdfFile, error) {
-	var zipBuffer bytes.Buffer
-	zipWriter := zip.NewWriter(&zipBuffer)
+// MergePdf joins files into one document named outputName. Results are not cached,
+// the cache is keyed by a single input.
+func (a *Service) MergePdf(ctx context.Context, outputName string, files []io.ReadSeeker) (domain.PdfFile, error) {
+	if len(files) == 0 {
+		return domain.PdfFile{}, domain.ErrMissingFile
+	}
 
-	for i, ra := range fra {
-		file.Seek(0, io.SeekStart)
-		splitContent, err := a.pdfRepo.Split(file, ra)
+	content, size, err := a.execute(ctx, operation{name: "merge"}, func() (io.ReadCloser, int64, error) {
+		return a.pdfRepo.Merge(ctx, files)
+	})
+	if err != nil {
+		return domain.PdfFile{}, err
+	}
+	return domain.PdfFile{Name: outputName, Content: content, Size: size}, nil
+}
+
+func (a *Service) splitPdfWithoutZip(ctx context.Context, file io.ReadSeeker, rangeSet []int) (io.ReadCloser, int64, error) {
+	splitContent, size, err := a.pdfRepo.Split(ctx, file, rangeSet)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to split pdf for range %v: %w", rangeSet, err)
+	}
+	return splitContent, size, nil
+}
+
+// splitPdfWithZip streams the zip through a pipe while the repository builds the parts,
+// so nothing is buffered in memory. It returns once the first part is ready so that an
+// unreadable document still fails with a regular error. Once ctx ends, e.g. because the
+// client went away, the remaining parts are abandoned.
+func (a *Service) splitPdfWithZip(ctx context.Context, file io.ReadSeeker, fra [][]int, namer *partNamer) (io.ReadCloser, int64, error) {
+	if namer.needsBookmarks() {
+		bookmarks, err := a.pdfRepo.Bookmarks(ctx, file)
 		if err != nil {
-			return domain.PdfFile{}, fmt.Errorf("failed to split pdf for range %v: %w", ra, err)
+			return nil, 0, fmt.Errorf("failed to read bookmarks: %w", err)
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return nil, 0, fmt.Errorf("failed to rewind pdf: %w", err)
 		}
+		namer.bookmarks = bookmarks
+	}
 
-		if err := a.addToZip(zipWriter, splitContent, i); err != nil {
-			return domain.PdfFile{}, err
+	pr, pw := io.Pipe()
+	started := make(chan error, 1)
+
+	go func() {
+		zipWriter := zip.NewWriter(pw)
+		manifest := domain.SplitManifest{Parts: make([]domain.SplitManifestPart, 0, len(fra))}
+		emitted := false
+		err := a.pdfRepo.SplitRanges(ctx, file, fra, func(index int, part io.Reader, size int64) error {
+			if !emitted {
+				emitted = true
+				started <- nil
+			}
+			entry, err := a.addToZip(zipWriter, part, namer.name(index, fra[index]))
+			if err != nil {
+				return err
+			}
+			entry.Index = index + 1
+			entry.From, entry.To = fra[index][0], fra[index][len(fra[index])-1]
+			entry.Pages = FormatRanges(fra[index])
+			entry.PageCount = len(fra[index])
+			manifest.Parts = append(manifest.Parts, entry)
+			return nil
+		})
+		if err != nil {
+			err = fmt.Errorf("failed to split pdf: %w", err)
+		} else if err = addManifest(zipWriter, manifest); err != nil {
+			err = fmt.Errorf("failed to write manifest: %w", err)
+		} else if err = zipWriter.Close(); err != nil {
+			err = fmt.Errorf("failed to close zip writer: %w", err)
+		}
+		if !emitted {
+			started <- err
 		}
+		pw.CloseWithError(err)
+	}()
+
+	if err := <-started; err != nil {
+		return nil, 0, err
 	}
+	return pr, -1, nil
+}
 
-	if err := zipWriter.Close(); err != nil {
-		return domain.PdfFile{}, fmt.Errorf("failed to close zip writer: %w", err)
+// addToZip writes content as fileName and describes what was written for the manifest
+func (a *Service) addToZip(zipWriter *zip.Writer, content io.Reader, fileName string) (domain.SplitManifestPart, error) {
+	fileWriter, err := zipWriter.Create(fileName)
+	if err != nil {
+		return domain.SplitManifestPart{}, fmt.Errorf("failed to create zip entry: %w", err)
 	}
 
-	outputName := "split_" + fileName + ".zip"
-	return domain.PdfFile{
-		Name:    outputName,
-		Content: zipBuffer.Bytes(),
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(fileWriter, hash), content)
+	if err != nil {
+		return domain.SplitManifestPart{}, fmt.Errorf("failed to write split content to zip: %w", err)
+	}
+	return domain.SplitManifestPart{
+		FileName: fileName,
+		Size:     size,
+		SHA256:   hex.EncodeToString(hash.Sum(nil)),
 	}, nil
 }
 
-func (a *Service) addToZip(zipWriter *zip.Writer, content []byte, index int) error {
-	fileName := fmt.Sprintf("split_part_%d.pdf", index+1)
-	fileWriter, err := zipWriter.Create(fileName)
+func addManifest(zipWriter *zip.Writer, manifest domain.SplitManifest) error {
+	fileWriter, err := zipWriter.Create(manifestName)
 	if err != nil {
-		return fmt.Errorf("failed to create zip entry: %w", err)
+		return err
 	}
+	return json.NewEncoder(fileWriter).Encode(manifest)
+}
 
-	if _, err := fileWriter.Write(content); err != nil {
-		return fmt.Errorf("failed to write split content to zip: %w", err)
-	}
-	return nil
+// Spool copies content to a temporary file for work that outlives the request that
+// uploaded it. The file is removed once it is closed.
+func (a *Service) Spool(ctx context.Context, content io.Reader) (domain.SpooledContent, int64, error) {
+	return a.pdfRepo.Spool(ctx, content)
 }
 
-func (a *Service) PageCount(ctx context.Context, file multipart.File) (int, error) {
-	return a.pdfRepo.PageCount(file)
+func (a *Service) PageCount(ctx context.Context, file io.ReadSeeker) (int, error) {
+	if a.admission != nil {
+		release, err := a.admission.acquire(ctx, "page_count")
+		if err != nil {
+			return 0, err
+		}
+		defer release()
+	}
+	return a.pdfRepo.PageCount(ctx, file)
 }
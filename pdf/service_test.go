@@ -1,15 +1,21 @@
 package pdf_test
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"testing"
 
+	"github.com/bxcodec/go-clean-arch/domain"
 	"github.com/bxcodec/go-clean-arch/pdf"
 	"github.com/bxcodec/go-clean-arch/pdf/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCompressPdf(t *testing.T) {
@@ -20,20 +26,22 @@ func TestCompressPdf(t *testing.T) {
 		input, _ := os.Open("./resource/test.pdf")
 		defer input.Close()
 
-		mockPdfRepo.On("Compress", mock.Anything).Return([]byte{1}, nil).Once()
+		mockPdfRepo.On("Compress", mock.Anything, mock.Anything).Return(io.NopCloser(bytes.NewReader([]byte{1})), int64(1), nil).Once()
 
 		actual, err := service.CompressPdf(context.TODO(), "input.pdf", input)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "compressed_input.pdf", actual.Name)
-		assert.Equal(t, []byte{1}, actual.Content)
+		assert.Equal(t, int64(1), actual.Size)
+		content, _ := io.ReadAll(actual.Content)
+		assert.Equal(t, []byte{1}, content)
 	})
 
 	t.Run("when compress failed should be return error", func(t *testing.T) {
 		input, _ := os.Open("./resource/test.pdf")
 		defer input.Close()
 
-		mockPdfRepo.On("Compress", mock.Anything).Return(nil, fmt.Errorf("Compress Error")).Once()
+		mockPdfRepo.On("Compress", mock.Anything, mock.Anything).Return(nil, int64(0), fmt.Errorf("Compress Error")).Once()
 
 		_, err := service.CompressPdf(context.TODO(), "input.pdf", input)
 
@@ -49,20 +57,22 @@ func TestSplitPdfByRanges(t *testing.T) {
 		input, _ := os.Open("./resource/test.pdf")
 		defer input.Close()
 
-		mockPdfRepo.On("Split", mock.Anything, mock.Anything).Return([]byte{1, 2}, nil).Once()
+		mockPdfRepo.On("Split", mock.Anything, mock.Anything, mock.Anything).Return(io.NopCloser(bytes.NewReader([]byte{1, 2})), int64(2), nil).Once()
 
 		actual, err := service.SplitPdfByRanges(context.TODO(), "test.pdf", input, []int{1, 2})
 
 		assert.NoError(t, err)
 		assert.Equal(t, "split_test.pdf", actual.Name)
-		assert.Equal(t, []byte{1, 2}, actual.Content)
+		assert.Equal(t, int64(2), actual.Size)
+		content, _ := io.ReadAll(actual.Content)
+		assert.Equal(t, []byte{1, 2}, content)
 	})
 
 	t.Run("when split failed should be return error", func(t *testing.T) {
 		input, _ := os.Open("./resource/test.pdf")
 		defer input.Close()
 
-		mockPdfRepo.On("Split", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("Split Failed")).Once()
+		mockPdfRepo.On("Split", mock.Anything, mock.Anything, mock.Anything).Return(nil, int64(0), fmt.Errorf("Split Failed")).Once()
 
 		_, err := service.SplitPdfByRanges(context.TODO(), "test.pdf", input, []int{1, 2})
 
@@ -78,20 +88,22 @@ func TestRemovePagesPdf(t *testing.T) {
 		input, _ := os.Open("./resource/test.pdf")
 		defer input.Close()
 
-		mockPdfRepo.On("Split", mock.Anything, mock.Anything).Return([]byte{1, 2}, nil).Once()
+		mockPdfRepo.On("Split", mock.Anything, mock.Anything, mock.Anything).Return(io.NopCloser(bytes.NewReader([]byte{1, 2})), int64(2), nil).Once()
 
 		actual, err := service.RemovePagesPdf(context.TODO(), "test.pdf", input, []int{1, 2}, 10)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "split_test.pdf", actual.Name)
-		assert.Equal(t, []byte{1, 2}, actual.Content)
+		assert.Equal(t, int64(2), actual.Size)
+		content, _ := io.ReadAll(actual.Content)
+		assert.Equal(t, []byte{1, 2}, content)
 	})
 
 	t.Run("when split failed should be return error", func(t *testing.T) {
 		input, _ := os.Open("./resource/test.pdf")
 		defer input.Close()
 
-		mockPdfRepo.On("Split", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("Split Failed")).Once()
+		mockPdfRepo.On("Split", mock.Anything, mock.Anything, mock.Anything).Return(nil, int64(0), fmt.Errorf("Split Failed")).Once()
 
 		_, err := service.RemovePagesPdf(context.TODO(), "test.pdf", input, []int{1, 2}, 12)
 
@@ -103,26 +115,137 @@ func TestSplitAndZipPdfByFixedRange(t *testing.T) {
 	mockPdfRepo := new(mocks.PdfRepository)
 	service := pdf.NewService(mockPdfRepo)
 
+	// emitParts hands parts to emit like the repository does and then fails with err
+	emitParts := func(err error, parts ...[]byte) func(context.Context, io.ReadSeeker, [][]int, func(int, io.Reader, int64) error) error {
+		return func(_ context.Context, _ io.ReadSeeker, _ [][]int, emit func(int, io.Reader, int64) error) error {
+			for i, part := range parts {
+				if emitErr := emit(i, bytes.NewReader(part), int64(len(part))); emitErr != nil {
+					return emitErr
+				}
+			}
+			return err
+		}
+	}
+
 	t.Run("when split success with result multiple pdf file should return zip file", func(t *testing.T) {
-		input, _ := os.Open("./resource/test.pdf")
-		defer input.Close()
+		input := bytes.NewReader([]byte("%PDF-1.7"))
 
-		mockPdfRepo.On("Split", mock.Anything, mock.Anything).Return([]byte{1, 2}, nil).Times(2)
+		mockPdfRepo.On("SplitRanges", mock.Anything, mock.Anything, [][]int{{1, 2}, {3, 4}}, mock.Anything).Return(emitParts(nil, []byte{1, 2}, []byte{3, 4})).Once()
 
-		actual, err := service.SplitAndZipPdfByFixedRange(context.TODO(), "zip.pdf", input, [][]int{{1, 2}, {3, 4}})
+		actual, err := service.SplitAndZipPdfByFixedRange(context.TODO(), "zip.pdf", input, [][]int{{1, 2}, {3, 4}}, domain.SplitNaming{})
 
 		assert.NoError(t, err)
 		assert.Equal(t, "split_zip.pdf.zip", actual.Name)
-		assert.NotNil(t, actual)
+		assert.Equal(t, int64(-1), actual.Size)
+
+		content, err := io.ReadAll(actual.Content)
+		assert.NoError(t, err)
+		archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+		assert.NoError(t, err)
+		assert.Len(t, archive.File, 3)
+		assert.Equal(t, "split_part_1.pdf", archive.File[0].Name)
+		assert.Equal(t, "split_part_2.pdf", archive.File[1].Name)
+		second, _ := archive.File[1].Open()
+		data, _ := io.ReadAll(second)
+		assert.Equal(t, []byte{3, 4}, data)
+
+		assert.Equal(t, "manifest.json", archive.File[2].Name)
+		manifestFile, _ := archive.File[2].Open()
+		var manifest domain.SplitManifest
+		assert.NoError(t, json.NewDecoder(manifestFile).Decode(&manifest))
+		assert.Equal(t, domain.SplitManifestPart{
+			FileName:  "split_part_2.pdf",
+			Index:     2,
+			From:      3,
+			To:        4,
+			Pages:     "3-4",
+			PageCount: 2,
+			Size:      2,
+			SHA256:    "0ce3940bebf2b22a5d2108ecf0c368a0541c7e3c45703f8540921b4eafc82947",
+		}, manifest.Parts[1])
+	})
+
+	t.Run("when a naming template is given should name the parts and the zip after it", func(t *testing.T) {
+		input := bytes.NewReader([]byte("%PDF-1.7"))
+
+		mockPdfRepo.On("Bookmarks", mock.Anything, mock.Anything).Return([]domain.Bookmark{
+			{Title: "Intro", Page: 1},
+			{Title: "Part: One/Two", Page: 3},
+			{Title: "Details", Page: 3, Level: 1},
+		}, nil).Once()
+		mockPdfRepo.On("SplitRanges", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(emitParts(nil, []byte{1}, []byte{2}, []byte{3})).Once()
+
+		actual, err := service.SplitAndZipPdfByFixedRange(context.TODO(), "report.pdf", input, [][]int{{1, 2}, {3, 4}, {5}},
+			domain.SplitNaming{Part: "{base}_{index:2}_{from}-{to}_{bookmark}", Archive: "{base}-parts"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "report-parts.zip", actual.Name)
+		content, _ := io.ReadAll(actual.Content)
+		archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+		require.NoError(t, err)
+		names := make([]string, 0, len(archive.File))
+		for _, file := range archive.File {
+			names = append(names, file.Name)
+		}
+		assert.Equal(t, []string{"report_01_1-2_Intro.pdf", "report_02_3-4_Details.pdf", "report_03_5-5_Details.pdf", "manifest.json"}, names)
+	})
+
+	t.Run("when names collide should keep them unique", func(t *testing.T) {
+		input := bytes.NewReader([]byte("%PDF-1.7"))
+
+		mockPdfRepo.On("Bookmarks", mock.Anything, mock.Anything).Return(nil, nil).Once()
+		mockPdfRepo.On("SplitRanges", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(emitParts(nil, []byte{1}, []byte{2})).Once()
+
+		actual, err := service.SplitAndZipPdfByFixedRange(context.TODO(), "report.pdf", input, [][]int{{1}, {2}},
+			domain.SplitNaming{Part: "chapter"})
+
+		require.NoError(t, err)
+		content, _ := io.ReadAll(actual.Content)
+		archive, _ := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+		assert.Equal(t, "chapter.pdf", archive.File[0].Name)
+		assert.Equal(t, "chapter_2.pdf", archive.File[1].Name)
+	})
+
+	t.Run("when the naming template is invalid should return ErrInvalidNaming", func(t *testing.T) {
+		_, err := service.SplitAndZipPdfByFixedRange(context.TODO(), "report.pdf", bytes.NewReader(nil), [][]int{{1}, {2}},
+			domain.SplitNaming{Part: "{page}"})
+
+		assert.ErrorIs(t, err, pdf.ErrInvalidNaming)
+	})
+
+	t.Run("when split of a later part fails should surface the error while streaming", func(t *testing.T) {
+		input := bytes.NewReader([]byte("%PDF-1.7"))
+
+		mockPdfRepo.On("SplitRanges", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(emitParts(fmt.Errorf("Error Split"), []byte{1, 2})).Once()
+
+		actual, err := service.SplitAndZipPdfByFixedRange(context.TODO(), "zip.pdf", input, [][]int{{1, 2}, {3, 4}}, domain.SplitNaming{})
+		assert.NoError(t, err)
+
+		_, err = io.ReadAll(actual.Content)
+		assert.Error(t, err)
+	})
+
+	t.Run("when context ends while streaming should surface the context error", func(t *testing.T) {
+		input := bytes.NewReader([]byte("%PDF-1.7"))
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+
+		mockPdfRepo.On("SplitRanges", ctx, mock.Anything, mock.Anything, mock.Anything).Return(emitParts(context.Canceled, []byte{1, 2})).Once()
+
+		actual, err := service.SplitAndZipPdfByFixedRange(ctx, "zip.pdf", input, [][]int{{1, 2}, {3, 4}}, domain.SplitNaming{})
+		assert.NoError(t, err)
+
+		_, err = io.ReadAll(actual.Content)
+		assert.ErrorIs(t, err, context.Canceled)
 	})
 
 	t.Run("when split success with result single pdf file should return pdf file", func(t *testing.T) {
 		input, _ := os.Open("./resource/test.pdf")
 		defer input.Close()
 
-		mockPdfRepo.On("Split", mock.Anything, mock.Anything).Return([]byte{1, 2}, nil).Once()
+		mockPdfRepo.On("Split", mock.Anything, mock.Anything, mock.Anything).Return(io.NopCloser(bytes.NewReader([]byte{1, 2})), int64(2), nil).Once()
 
-		actual, err := service.SplitAndZipPdfByFixedRange(context.TODO(), "zip.pdf", input, [][]int{{1, 2}})
+		actual, err := service.SplitAndZipPdfByFixedRange(context.TODO(), "zip.pdf", input, [][]int{{1, 2}}, domain.SplitNaming{})
 
 		assert.NoError(t, err)
 		assert.Equal(t, "split_zip.pdf", actual.Name)
@@ -133,9 +256,9 @@ func TestSplitAndZipPdfByFixedRange(t *testing.T) {
 		input, _ := os.Open("./resource/test.pdf")
 		defer input.Close()
 
-		mockPdfRepo.On("Split", mock.Anything, mock.Anything).Return([]byte{1, 2}, fmt.Errorf("Error Split")).Once()
+		mockPdfRepo.On("SplitRanges", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("Error Split")).Once()
 
-		_, err := service.SplitAndZipPdfByFixedRange(context.TODO(), "zip.pdf", input, [][]int{{1, 2}, {3, 4}})
+		_, err := service.SplitAndZipPdfByFixedRange(context.TODO(), "zip.pdf", input, [][]int{{1, 2}, {3, 4}}, domain.SplitNaming{})
 
 		assert.Error(t, err)
 	})
@@ -144,9 +267,9 @@ func TestSplitAndZipPdfByFixedRange(t *testing.T) {
 		input, _ := os.Open("./resource/test.pdf")
 		defer input.Close()
 
-		mockPdfRepo.On("Split", mock.Anything, mock.Anything).Return([]byte{1, 2}, fmt.Errorf("Error split")).Once()
+		mockPdfRepo.On("Split", mock.Anything, mock.Anything, mock.Anything).Return(nil, int64(0), fmt.Errorf("Error split")).Once()
 
-		_, err := service.SplitAndZipPdfByFixedRange(context.TODO(), "zip.pdf", input, [][]int{{1, 2}})
+		_, err := service.SplitAndZipPdfByFixedRange(context.TODO(), "zip.pdf", input, [][]int{{1, 2}}, domain.SplitNaming{})
 
 		assert.Error(t, err)
 	})
@@ -160,7 +283,7 @@ func TestPageCount(t *testing.T) {
 		input, _ := os.Open("./resource/test.pdf")
 		defer input.Close()
 
-		mockPdfRepo.On("PageCount", mock.Anything).Return(12, nil).Once()
+		mockPdfRepo.On("PageCount", mock.Anything, mock.Anything).Return(12, nil).Once()
 
 		actual, err := service.PageCount(context.TODO(), input)
 
@@ -173,10 +296,33 @@ func TestPageCount(t *testing.T) {
 		input, _ := os.Open("./resource/test.pdf")
 		defer input.Close()
 
-		mockPdfRepo.On("PageCount", mock.Anything).Return(0, fmt.Errorf("Page Count Error")).Once()
+		mockPdfRepo.On("PageCount", mock.Anything, mock.Anything).Return(0, fmt.Errorf("Page Count Error")).Once()
 
 		_, err := service.PageCount(context.TODO(), input)
 
 		assert.Error(t, err)
 	})
 }
+
+func TestMergePdf(t *testing.T) {
+	mockPdfRepo := new(mocks.PdfRepository)
+	service := pdf.NewService(mockPdfRepo)
+
+	t.Run("when merge success should be return PdfFile", func(t *testing.T) {
+		files := []io.ReadSeeker{bytes.NewReader([]byte("a")), bytes.NewReader([]byte("b"))}
+		mockPdfRepo.On("Merge", mock.Anything, files).Return(io.NopCloser(bytes.NewReader([]byte{1})), int64(1), nil).Once()
+
+		actual, err := service.MergePdf(context.TODO(), "merged.pdf", files)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "merged.pdf", actual.Name)
+		assert.Equal(t, int64(1), actual.Size)
+	})
+
+	t.Run("when there is nothing to merge should be return ErrMissingFile", func(t *testing.T) {
+		_, err := service.MergePdf(context.TODO(), "merged.pdf", nil)
+
+		assert.ErrorIs(t, err, domain.ErrMissingFile)
+		mockPdfRepo.AssertNumberOfCalls(t, "Merge", 1)
+	})
+}
@@ -0,0 +1,171 @@
+// Code generated by mockery v2.50.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+
+	domain "github.com/bxcodec/go-clean-arch/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UploadStore is an autogenerated mock type for the UploadStore type
+type UploadStore struct {
+	mock.Mock
+}
+
+// Append provides a mock function with given fields: ctx, id, offset, content
+func (_m *UploadStore) Append(ctx context.Context, id string, offset int64, content io.Reader) (int64, error) {
+	ret := _m.Called(ctx, id, offset, content)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Append")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, io.Reader) (int64, error)); ok {
+		return rf(ctx, id, offset, content)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, io.Reader) int64); ok {
+		r0 = rf(ctx, id, offset, content)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, io.Reader) error); ok {
+		r1 = rf(ctx, id, offset, content)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, upload
+func (_m *UploadStore) Create(ctx context.Context, upload domain.ResumableUpload) error {
+	ret := _m.Called(ctx, upload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.ResumableUpload) error); ok {
+		r0 = rf(ctx, upload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *UploadStore) Delete(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, id
+func (_m *UploadStore) Get(ctx context.Context, id string) (domain.ResumableUpload, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 domain.ResumableUpload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.ResumableUpload, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.ResumableUpload); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.ResumableUpload)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Open provides a mock function with given fields: ctx, id
+func (_m *UploadStore) Open(ctx context.Context, id string) (domain.SpooledContent, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Open")
+	}
+
+	var r0 domain.SpooledContent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.SpooledContent, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.SpooledContent); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(domain.SpooledContent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Truncate provides a mock function with given fields: ctx, id, offset
+func (_m *UploadStore) Truncate(ctx context.Context, id string, offset int64) error {
+	ret := _m.Called(ctx, id, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Truncate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) error); ok {
+		r0 = rf(ctx, id, offset)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewUploadStore creates a new instance of UploadStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUploadStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UploadStore {
+	mock := &UploadStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
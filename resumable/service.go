@@ -0,0 +1,247 @@
+package resumable
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// UploadStore represent the storage backend of resumable uploads. The offset of an
+// upload is the number of bytes stored for it.
+//
+//go:generate mockery --name UploadStore
+type UploadStore interface {
+	Create(ctx context.Context, upload domain.ResumableUpload) error
+	// Get returns the upload with its offset and the time of its latest write
+	Get(ctx context.Context, id string) (domain.ResumableUpload, error)
+	// Append writes content at offset, which has to be the offset of the upload, and
+	// returns the new offset. What arrived before content failed is kept.
+	Append(ctx context.Context, id string, offset int64, content io.Reader) (int64, error)
+	// Truncate drops every byte from offset on
+	Truncate(ctx context.Context, id string, offset int64) error
+	Open(ctx context.Context, id string) (domain.SpooledContent, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// ChecksumAlgorithms are the algorithms a chunk checksum may use
+var ChecksumAlgorithms = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+}
+
+// Config limits resumable uploads
+type Config struct {
+	// MaxSize caps the length of an upload, zero means no limit
+	MaxSize int64
+	// TTL is how long an upload is kept after its latest write, zero means forever
+	TTL time.Duration
+}
+
+type Service struct {
+	store  UploadStore
+	config Config
+	now    func() time.Time
+
+	mu      sync.Mutex
+	writing map[string]struct{}
+}
+
+// NewService will create a service whose uploads expire config.TTL after their latest
+// write. Removing them is up to the store.
+func NewService(store UploadStore, config Config) *Service {
+	return &Service{
+		store:   store,
+		config:  config,
+		now:     time.Now,
+		writing: make(map[string]struct{}),
+	}
+}
+
+// Create registers an upload of length bytes that is filled with Append
+func (s *Service) Create(ctx context.Context, length int64, metadata map[string]string) (domain.ResumableUpload, error) {
+	if length < 0 {
+		return domain.ResumableUpload{}, domain.ErrBadParamInput
+	}
+	if s.config.MaxSize > 0 && length > s.config.MaxSize {
+		return domain.ResumableUpload{}, domain.ErrFileTooLarge
+	}
+
+	upload := domain.ResumableUpload{
+		ID:        uuid.NewString(),
+		Length:    length,
+		Metadata:  metadata,
+		UpdatedAt: s.now(),
+	}
+	if err := s.store.Create(ctx, upload); err != nil {
+		return domain.ResumableUpload{}, fmt.Errorf("failed to create upload: %w", err)
+	}
+	return s.withExpiry(upload), nil
+}
+
+// Get returns an upload that has not expired yet, the store may still hold it for a
+// while after
+func (s *Service) Get(ctx context.Context, id string) (domain.ResumableUpload, error) {
+	upload, err := s.store.Get(ctx, id)
+	if err != nil {
+		return domain.ResumableUpload{}, err
+	}
+
+	upload = s.withExpiry(upload)
+	if !upload.ExpiresAt.IsZero() && s.now().After(upload.ExpiresAt) {
+		return domain.ResumableUpload{}, domain.ErrNotFound
+	}
+	return upload, nil
+}
+
+// Append writes the chunk in content at offset. Without a checksum whatever arrived is
+// kept, so a client that lost its connection resumes from there. A chunk with a
+// checksum, or one that runs past the length of the upload, is kept whole or not at
+// all. The upload is returned with its new offset, also on error.
+func (s *Service) Append(ctx context.Context, id string, offset int64, content io.Reader, checksum *domain.Checksum) (domain.ResumableUpload, error) {
+	var digest hash.Hash
+	if checksum != nil {
+		newHash, ok := ChecksumAlgorithms[checksum.Algorithm]
+		if !ok {
+			return domain.ResumableUpload{}, fmt.Errorf("%w: unsupported checksum algorithm %q", domain.ErrBadParamInput, checksum.Algorithm)
+		}
+		digest = newHash()
+		content = io.TeeReader(content, digest)
+	}
+
+	release, err := s.lock(id)
+	if err != nil {
+		return domain.ResumableUpload{}, err
+	}
+	defer release()
+
+	upload, err := s.Get(ctx, id)
+	if err != nil {
+		return domain.ResumableUpload{}, err
+	}
+	if offset != upload.Offset {
+		return upload, domain.ErrOffsetMismatch
+	}
+
+	chunk := &chunkReader{r: content, remaining: upload.Length - offset}
+	newOffset, err := s.store.Append(ctx, id, offset, chunk)
+	if err == nil && chunk.exceeded {
+		err = domain.ErrFileTooLarge
+	}
+	if err == nil && digest != nil && !bytes.Equal(digest.Sum(nil), checksum.Sum) {
+		err = domain.ErrChecksumMismatch
+	}
+	if err != nil && (digest != nil || errors.Is(err, domain.ErrFileTooLarge)) {
+		if truncateErr := s.store.Truncate(ctx, id, offset); truncateErr != nil {
+			return upload, errors.Join(err, fmt.Errorf("failed to drop chunk: %w", truncateErr))
+		}
+		newOffset = offset
+	}
+
+	upload.Offset = newOffset
+	upload.UpdatedAt = s.now()
+	return s.withExpiry(upload), err
+}
+
+// Open returns a complete upload as a document to process. It stays available until
+// it expires or is deleted, so it can be processed more than once.
+func (s *Service) Open(ctx context.Context, id string) (domain.SourceFile, error) {
+	upload, err := s.Get(ctx, id)
+	if err != nil {
+		return domain.SourceFile{}, err
+	}
+	if !upload.Complete() {
+		return domain.SourceFile{}, domain.ErrUploadIncomplete
+	}
+
+	content, err := s.store.Open(ctx, id)
+	if err != nil {
+		return domain.SourceFile{}, fmt.Errorf("failed to open upload: %w", err)
+	}
+
+	name := upload.Metadata["filename"]
+	if name == "" {
+		name = upload.ID
+	}
+	return domain.SourceFile{
+		Name:        name,
+		ContentType: upload.Metadata["filetype"],
+		Content:     content,
+		Size:        upload.Length,
+	}, nil
+}
+
+// Delete removes an upload, unless a chunk is being written to it
+func (s *Service) Delete(ctx context.Context, id string) error {
+	release, err := s.lock(id)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	return s.store.Delete(ctx, id)
+}
+
+// withExpiry sets when upload expires, never without a TTL
+func (s *Service) withExpiry(upload domain.ResumableUpload) domain.ResumableUpload {
+	if s.config.TTL > 0 {
+		upload.ExpiresAt = upload.UpdatedAt.Add(s.config.TTL)
+	}
+	return upload
+}
+
+// lock makes sure a single request writes to upload id at a time
+func (s *Service) lock(id string) (func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, busy := s.writing[id]; busy {
+		return nil, domain.ErrUploadLocked
+	}
+	s.writing[id] = struct{}{}
+
+	return func() {
+		s.mu.Lock()
+		delete(s.writing, id)
+		s.mu.Unlock()
+	}, nil
+}
+
+// chunkReader reads up to remaining bytes and notes whether content went past them
+type chunkReader struct {
+	r         io.Reader
+	remaining int64
+	exceeded  bool
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	if c.remaining <= 0 {
+		var probe [1]byte
+		if n, _ := c.r.Read(probe[:]); n > 0 {
+			c.exceeded = true
+		}
+		return 0, io.EOF
+	}
+
+	if int64(len(p)) > c.remaining {
+		p = p[:c.remaining]
+	}
+	n, err := c.r.Read(p)
+	c.remaining -= int64(n)
+	return n, err
+}
@@ -0,0 +1,222 @@
+package resumable_test
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/resumable"
+	"github.com/bxcodec/go-clean-arch/resumable/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreate(t *testing.T) {
+	t.Run("when length fits should store the upload", func(t *testing.T) {
+		mockStore := new(mocks.UploadStore)
+		service := resumable.NewService(mockStore, resumable.Config{MaxSize: 10, TTL: time.Hour})
+
+		mockStore.On("Create", mock.Anything, mock.MatchedBy(func(u domain.ResumableUpload) bool {
+			return u.ID != "" && u.Length == 10 && u.Metadata["filename"] == "scan.pdf"
+		})).Return(nil).Once()
+
+		actual, err := service.Create(context.TODO(), 10, map[string]string{"filename": "scan.pdf"})
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, actual.ID)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), actual.ExpiresAt, time.Minute)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("when length exceeds the limit should return ErrFileTooLarge", func(t *testing.T) {
+		service := resumable.NewService(new(mocks.UploadStore), resumable.Config{MaxSize: 10})
+
+		_, err := service.Create(context.TODO(), 11, nil)
+
+		assert.ErrorIs(t, err, domain.ErrFileTooLarge)
+	})
+}
+
+func TestGet(t *testing.T) {
+	t.Run("when the upload was not written to for the TTL should return ErrNotFound", func(t *testing.T) {
+		mockStore := new(mocks.UploadStore)
+		service := resumable.NewService(mockStore, resumable.Config{TTL: time.Hour})
+
+		mockStore.On("Get", mock.Anything, "abc").Return(domain.ResumableUpload{
+			ID: "abc", Length: 10, UpdatedAt: time.Now().Add(-2 * time.Hour),
+		}, nil).Once()
+
+		_, err := service.Get(context.TODO(), "abc")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func TestAppend(t *testing.T) {
+	stored := func(offset int64) domain.ResumableUpload {
+		return domain.ResumableUpload{ID: "abc", Length: 10, Offset: offset, UpdatedAt: time.Now()}
+	}
+	// appendAll stores what is read from content, like the store does
+	appendAll := func(_ context.Context, _ string, offset int64, content io.Reader) (int64, error) {
+		n, err := io.Copy(io.Discard, content)
+		return offset + n, err
+	}
+
+	t.Run("when the chunk starts at the offset should return the new offset", func(t *testing.T) {
+		mockStore := new(mocks.UploadStore)
+		service := resumable.NewService(mockStore, resumable.Config{TTL: time.Hour})
+
+		mockStore.On("Get", mock.Anything, "abc").Return(stored(4), nil).Once()
+		mockStore.On("Append", mock.Anything, "abc", int64(4), mock.Anything).Return(appendAll).Once()
+
+		actual, err := service.Append(context.TODO(), "abc", 4, strings.NewReader("123"), nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), actual.Offset)
+		assert.False(t, actual.Complete())
+	})
+
+	t.Run("when the chunk starts elsewhere should return ErrOffsetMismatch", func(t *testing.T) {
+		mockStore := new(mocks.UploadStore)
+		service := resumable.NewService(mockStore, resumable.Config{})
+
+		mockStore.On("Get", mock.Anything, "abc").Return(stored(4), nil).Once()
+
+		actual, err := service.Append(context.TODO(), "abc", 2, strings.NewReader("123"), nil)
+
+		assert.ErrorIs(t, err, domain.ErrOffsetMismatch)
+		assert.Equal(t, int64(4), actual.Offset)
+		mockStore.AssertNotCalled(t, "Append", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("when the checksum matches should keep the chunk", func(t *testing.T) {
+		mockStore := new(mocks.UploadStore)
+		service := resumable.NewService(mockStore, resumable.Config{})
+		sum := sha1.Sum([]byte("123"))
+
+		mockStore.On("Get", mock.Anything, "abc").Return(stored(0), nil).Once()
+		mockStore.On("Append", mock.Anything, "abc", int64(0), mock.Anything).Return(appendAll).Once()
+
+		actual, err := service.Append(context.TODO(), "abc", 0, strings.NewReader("123"), &domain.Checksum{Algorithm: "sha1", Sum: sum[:]})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), actual.Offset)
+	})
+
+	t.Run("when the checksum does not match should drop the chunk", func(t *testing.T) {
+		mockStore := new(mocks.UploadStore)
+		service := resumable.NewService(mockStore, resumable.Config{})
+		sum := sha1.Sum([]byte("124"))
+
+		mockStore.On("Get", mock.Anything, "abc").Return(stored(4), nil).Once()
+		mockStore.On("Append", mock.Anything, "abc", int64(4), mock.Anything).Return(appendAll).Once()
+		mockStore.On("Truncate", mock.Anything, "abc", int64(4)).Return(nil).Once()
+
+		actual, err := service.Append(context.TODO(), "abc", 4, strings.NewReader("123"), &domain.Checksum{Algorithm: "sha1", Sum: sum[:]})
+
+		assert.ErrorIs(t, err, domain.ErrChecksumMismatch)
+		assert.Equal(t, int64(4), actual.Offset)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("when the checksum algorithm is unknown should return ErrBadParamInput", func(t *testing.T) {
+		service := resumable.NewService(new(mocks.UploadStore), resumable.Config{})
+
+		_, err := service.Append(context.TODO(), "abc", 0, strings.NewReader("123"), &domain.Checksum{Algorithm: "crc32"})
+
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	})
+
+	t.Run("when the chunk runs past the length should drop it", func(t *testing.T) {
+		mockStore := new(mocks.UploadStore)
+		service := resumable.NewService(mockStore, resumable.Config{})
+
+		mockStore.On("Get", mock.Anything, "abc").Return(stored(8), nil).Once()
+		mockStore.On("Append", mock.Anything, "abc", int64(8), mock.Anything).Return(appendAll).Once()
+		mockStore.On("Truncate", mock.Anything, "abc", int64(8)).Return(nil).Once()
+
+		actual, err := service.Append(context.TODO(), "abc", 8, strings.NewReader("123"), nil)
+
+		assert.ErrorIs(t, err, domain.ErrFileTooLarge)
+		assert.Equal(t, int64(8), actual.Offset)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("when the connection drops without a checksum should keep what arrived", func(t *testing.T) {
+		mockStore := new(mocks.UploadStore)
+		service := resumable.NewService(mockStore, resumable.Config{})
+
+		mockStore.On("Get", mock.Anything, "abc").Return(stored(0), nil).Once()
+		mockStore.On("Append", mock.Anything, "abc", int64(0), mock.Anything).Return(int64(5), fmt.Errorf("unexpected EOF")).Once()
+
+		actual, err := service.Append(context.TODO(), "abc", 0, strings.NewReader("12345"), nil)
+
+		assert.Error(t, err)
+		assert.Equal(t, int64(5), actual.Offset)
+		mockStore.AssertNotCalled(t, "Truncate", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("when another chunk is being written should return ErrUploadLocked", func(t *testing.T) {
+		mockStore := new(mocks.UploadStore)
+		service := resumable.NewService(mockStore, resumable.Config{})
+		writing, resume := make(chan struct{}), make(chan struct{})
+
+		mockStore.On("Get", mock.Anything, "abc").Return(stored(0), nil).Once()
+		mockStore.On("Append", mock.Anything, "abc", int64(0), mock.Anything).
+			Run(func(mock.Arguments) { close(writing); <-resume }).
+			Return(int64(3), nil).Once()
+
+		done := make(chan error)
+		go func() {
+			_, err := service.Append(context.TODO(), "abc", 0, strings.NewReader("123"), nil)
+			done <- err
+		}()
+		<-writing
+
+		_, err := service.Append(context.TODO(), "abc", 0, strings.NewReader("123"), nil)
+		assert.ErrorIs(t, err, domain.ErrUploadLocked)
+
+		close(resume)
+		assert.NoError(t, <-done)
+	})
+}
+
+func TestOpen(t *testing.T) {
+	t.Run("when the upload is complete should return it with its metadata", func(t *testing.T) {
+		mockStore := new(mocks.UploadStore)
+		service := resumable.NewService(mockStore, resumable.Config{})
+		content, err := os.CreateTemp(t.TempDir(), "upload")
+		require.NoError(t, err)
+
+		mockStore.On("Get", mock.Anything, "abc").Return(domain.ResumableUpload{
+			ID: "abc", Length: 3, Offset: 3, Metadata: map[string]string{"filename": "scan.pdf", "filetype": "application/pdf"},
+		}, nil).Once()
+		mockStore.On("Open", mock.Anything, "abc").Return(content, nil).Once()
+
+		actual, err := service.Open(context.TODO(), "abc")
+
+		require.NoError(t, err)
+		assert.Equal(t, "scan.pdf", actual.Name)
+		assert.Equal(t, "application/pdf", actual.ContentType)
+		assert.Equal(t, int64(3), actual.Size)
+		assert.NoError(t, actual.Content.Close())
+	})
+
+	t.Run("when the upload is not complete should return ErrUploadIncomplete", func(t *testing.T) {
+		mockStore := new(mocks.UploadStore)
+		service := resumable.NewService(mockStore, resumable.Config{})
+
+		mockStore.On("Get", mock.Anything, "abc").Return(domain.ResumableUpload{ID: "abc", Length: 3, Offset: 1}, nil).Once()
+
+		_, err := service.Open(context.TODO(), "abc")
+
+		assert.ErrorIs(t, err, domain.ErrUploadIncomplete)
+	})
+}
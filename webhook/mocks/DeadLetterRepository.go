@@ -0,0 +1,47 @@
+// Code generated by mockery v2.50.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/bxcodec/go-clean-arch/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DeadLetterRepository is an autogenerated mock type for the DeadLetterRepository type
+type DeadLetterRepository struct {
+	mock.Mock
+}
+
+// Store provides a mock function with given fields: ctx, letter
+func (_m *DeadLetterRepository) Store(ctx context.Context, letter *domain.WebhookDeadLetter) error {
+	ret := _m.Called(ctx, letter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Store")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.WebhookDeadLetter) error); ok {
+		r0 = rf(ctx, letter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewDeadLetterRepository creates a new instance of DeadLetterRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeadLetterRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeadLetterRepository {
+	mock := &DeadLetterRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,47 @@
+// Code generated by mockery v2.50.4. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	http "net/http"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Poster is an autogenerated mock type for the Poster type
+type Poster struct {
+	mock.Mock
+}
+
+// Post provides a mock function with given fields: ctx, url, header, body
+func (_m *Poster) Post(ctx context.Context, url string, header http.Header, body []byte) error {
+	ret := _m.Called(ctx, url, header, body)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Post")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, http.Header, []byte) error); ok {
+		r0 = rf(ctx, url, header, body)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPoster creates a new instance of Poster. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPoster(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Poster {
+	mock := &Poster{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+)
+
+// Headers a receiver needs to verify a webhook. The signature is the hex HMAC-SHA256
+// of the timestamp, a dot and the body, prefixed with "sha256=".
+const (
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Poster represent the transport of webhooks
+//
+//go:generate mockery --name Poster
+type Poster interface {
+	Post(ctx context.Context, url string, header http.Header, body []byte) error
+}
+
+// DeadLetterRepository represent the storage of webhooks that could not be delivered
+//
+//go:generate mockery --name DeadLetterRepository
+type DeadLetterRepository interface {
+	Store(ctx context.Context, letter *domain.WebhookDeadLetter) error
+}
+
+// Config tunes delivery. Retry n waits BaseDelay * 2^(n-1) before it is sent, up to
+// MaxDelay.
+type Config struct {
+	Secret      []byte
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+type Service struct {
+	poster      Poster
+	deadLetters DeadLetterRepository
+	config      Config
+	now         func() time.Time
+}
+
+// NewService will create a webhook service that signs payloads with config.Secret
+func NewService(poster Poster, deadLetters DeadLetterRepository, config Config) *Service {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 1
+	}
+	return &Service{
+		poster:      poster,
+		deadLetters: deadLetters,
+		config:      config,
+		now:         time.Now,
+	}
+}
+
+// Deliver posts result to callbackURL until it is accepted or every attempt failed,
+// in which case a dead letter is stored. Every attempt is signed with a fresh
+// timestamp so receivers can reject replays.
+func (s *Service) Deliver(ctx context.Context, callbackURL string, result domain.JobResult) error {
+	body, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook: %w", err)
+	}
+
+	var lastErr error
+	attempts := 0
+	for attempts < s.config.MaxAttempts {
+		if attempts > 0 {
+			if err := s.wait(ctx, attempts); err != nil {
+				lastErr = err
+				break
+			}
+		}
+
+		attempts++
+		lastErr = s.poster.Post(ctx, callbackURL, s.header(body), body)
+		if lastErr == nil {
+			return nil
+		}
+		logrus.Warnf("webhook for job %s failed on attempt %d: %s", result.JobID, attempts, lastErr)
+	}
+
+	letter := &domain.WebhookDeadLetter{
+		JobID:       result.JobID,
+		CallbackURL: callbackURL,
+		Payload:     string(body),
+		Attempts:    attempts,
+		LastError:   lastErr.Error(),
+		CreatedAt:   s.now(),
+	}
+	// the dead letter is kept even if the delivery was given up on
+	if err := s.deadLetters.Store(context.WithoutCancel(ctx), letter); err != nil {
+		return fmt.Errorf("failed to store dead letter: %w", err)
+	}
+	return fmt.Errorf("webhook for job %s not delivered: %w", result.JobID, lastErr)
+}
+
+func (s *Service) header(body []byte) http.Header {
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	mac := hmac.New(sha256.New, s.config.Secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+
+	header := http.Header{}
+	header.Set(HeaderTimestamp, timestamp)
+	header.Set(HeaderSignature, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return header
+}
+
+func (s *Service) wait(ctx context.Context, retry int) error {
+	delay := s.config.BaseDelay << (retry - 1)
+	if delay <= 0 || (s.config.MaxDelay > 0 && delay > s.config.MaxDelay) {
+		delay = s.config.MaxDelay
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
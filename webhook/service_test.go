@@ -0,0 +1,95 @@
+package webhook_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/bxcodec/go-clean-arch/domain"
+	"github.com/bxcodec/go-clean-arch/webhook"
+	"github.com/bxcodec/go-clean-arch/webhook/mocks"
+)
+
+func TestDeliver(t *testing.T) {
+	config := webhook.Config{
+		Secret:      []byte("secret"),
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    2 * time.Millisecond,
+	}
+	result := domain.JobResult{JobID: "job-1", Status: domain.JobSucceeded, Size: 1, Checksum: "abc"}
+
+	t.Run("when receiver accepts should post a signed payload once", func(t *testing.T) {
+		mockPoster := new(mocks.Poster)
+		mockPoster.On("Post", mock.Anything, "https://partner.example/hook", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			header := args.Get(2).(http.Header)
+			body := args.Get(3).([]byte)
+
+			mac := hmac.New(sha256.New, []byte("secret"))
+			mac.Write([]byte(header.Get(webhook.HeaderTimestamp) + "."))
+			mac.Write(body)
+			assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), header.Get(webhook.HeaderSignature))
+			assert.JSONEq(t, `{"job_id":"job-1","status":"succeeded","size":1,"checksum":"abc","finished_at":"0001-01-01T00:00:00Z"}`, string(body))
+		}).Return(nil).Once()
+		svc := webhook.NewService(mockPoster, new(mocks.DeadLetterRepository), config)
+
+		err := svc.Deliver(context.TODO(), "https://partner.example/hook", result)
+
+		assert.NoError(t, err)
+		mockPoster.AssertExpectations(t)
+	})
+
+	t.Run("when receiver recovers should retry until accepted", func(t *testing.T) {
+		mockPoster := new(mocks.Poster)
+		mockPoster.On("Post", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("status 503")).Twice()
+		mockPoster.On("Post", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+		svc := webhook.NewService(mockPoster, new(mocks.DeadLetterRepository), config)
+
+		err := svc.Deliver(context.TODO(), "https://partner.example/hook", result)
+
+		assert.NoError(t, err)
+		mockPoster.AssertNumberOfCalls(t, "Post", 3)
+	})
+
+	t.Run("when every attempt fails should store a dead letter", func(t *testing.T) {
+		mockPoster := new(mocks.Poster)
+		mockPoster.On("Post", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("status 503")).Times(3)
+		mockDeadLetters := new(mocks.DeadLetterRepository)
+		mockDeadLetters.On("Store", mock.Anything, mock.MatchedBy(func(letter *domain.WebhookDeadLetter) bool {
+			return letter.JobID == "job-1" && letter.Attempts == 3 && letter.LastError == "status 503" &&
+				letter.CallbackURL == "https://partner.example/hook"
+		})).Return(nil).Once()
+		svc := webhook.NewService(mockPoster, mockDeadLetters, config)
+
+		err := svc.Deliver(context.TODO(), "https://partner.example/hook", result)
+
+		assert.Error(t, err)
+		mockDeadLetters.AssertExpectations(t)
+	})
+
+	t.Run("when context ends between attempts should store a dead letter", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		mockPoster := new(mocks.Poster)
+		mockPoster.On("Post", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(mock.Arguments) {
+			cancel()
+		}).Return(errors.New("status 503")).Once()
+		mockDeadLetters := new(mocks.DeadLetterRepository)
+		mockDeadLetters.On("Store", mock.Anything, mock.MatchedBy(func(letter *domain.WebhookDeadLetter) bool {
+			return letter.Attempts == 1
+		})).Return(nil).Once()
+		svc := webhook.NewService(mockPoster, mockDeadLetters, webhook.Config{MaxAttempts: 3, BaseDelay: time.Hour})
+
+		err := svc.Deliver(ctx, "https://partner.example/hook", result)
+
+		assert.ErrorIs(t, err, context.Canceled)
+		mockDeadLetters.AssertExpectations(t)
+	})
+}